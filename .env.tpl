@@ -24,6 +24,23 @@ MELI_APP_ID=op://Personal/Dolly Dotenv/Mercado Livre/MELI_APP_ID
 MELI_ENDPOINT=op://Personal/Dolly Dotenv/Mercado Livre/MELI_ENDPOINT
 MELI_REDIRECT_URL=op://Personal/Dolly Dotenv/Mercado Livre/MELI_REDIRECT_URL
 MELI_SECRET_KEY=op://Personal/Dolly Dotenv/Mercado Livre/MELI_SECRET_KEY
+MELI_AUTH_ENDPOINT=op://Personal/Dolly Dotenv/Mercado Livre/MELI_AUTH_ENDPOINT
+MELI_WEBHOOK_ALLOWED_IPS=op://Personal/Dolly Dotenv/Mercado Livre/MELI_WEBHOOK_ALLOWED_IPS
+TRUSTED_PROXIES=op://Personal/Dolly Dotenv/Mercado Livre/TRUSTED_PROXIES
+
+# OAuth
+OAUTH_STATE_SECRET=op://Personal/Dolly Dotenv/OAuth/OAUTH_STATE_SECRET
+OAUTH_FRONTEND_REDIRECT_URL=op://Personal/Dolly Dotenv/OAuth/OAUTH_FRONTEND_REDIRECT_URL
+
+# Credentials encryption
+CREDENTIALS_KEYS=op://Personal/Dolly Dotenv/Credentials/CREDENTIALS_KEYS
+
+# Stock alerts
+SMTP_HOST=op://Personal/Dolly Dotenv/SMTP/SMTP_HOST
+SMTP_PORT=op://Personal/Dolly Dotenv/SMTP/SMTP_PORT
+SMTP_USERNAME=op://Personal/Dolly Dotenv/SMTP/SMTP_USERNAME
+SMTP_PASSWORD=op://Personal/Dolly Dotenv/SMTP/SMTP_PASSWORD
+SMTP_FROM=op://Personal/Dolly Dotenv/SMTP/SMTP_FROM
 
 # AWS
 ## Queue
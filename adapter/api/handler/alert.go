@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the alert operations
+func writeAlertError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, alert.ErrInvalidWebhookURL):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_webhook_url", "The webhook URL must be https and resolve to a public address")
+	case errors.Is(err, alert.ErrInvalidEmail):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_email", "Invalid email")
+	case errors.Is(err, alert.ErrInvalidThreshold):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_threshold", "Invalid threshold")
+	case errors.Is(err, alert.ErrThresholdNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "threshold_not_found", "Threshold not found")
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func getAlertSettings(service alert.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the alert settings"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		settings, err := service.GetSettings(storeId)
+		if err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		output := &presenter.AlertSettings{
+			WebhookURL:       settings.WebhookURL,
+			Email:            settings.Email,
+			DefaultThreshold: settings.DefaultThreshold,
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func updateAlertSettings(service alert.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to update the alert settings"
+		input := &alert.UpdateSettingsDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		if err := service.UpdateSettings(*input); err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getThresholds(service alert.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the thresholds"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		thresholds, err := service.ListThresholds(storeId)
+		if err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []presenter.StockThreshold{}
+		for _, t := range thresholds {
+			output = append(output, presenter.StockThreshold{Sku: t.Sku, Threshold: t.Threshold})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func setThreshold(service alert.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to set the threshold"
+		input := &alert.SetThresholdDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+		input.Sku = chi.URLParam(r, "sku")
+
+		if err := service.SetThreshold(*input); err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func removeThreshold(service alert.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to remove the threshold"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		if err := service.RemoveThreshold(storeId, chi.URLParam(r, "sku")); err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getOpenAlerts(service alert.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the alerts"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		alerts, err := service.ListOpenAlerts(storeId)
+		if err != nil {
+			writeAlertError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []presenter.StockAlert{}
+		for _, a := range alerts {
+			output = append(output, presenter.StockAlert{
+				ID:        a.ID,
+				Sku:       a.Sku,
+				Level:     string(a.Level),
+				Quantity:  a.Quantity,
+				Threshold: a.Threshold,
+				CreatedAt: a.CreatedAt,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func MakeAlertHandlers(r chi.Router, service alert.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/alerts", func(r chi.Router) {
+		r.Get("/", getOpenAlerts(service, logger))
+		r.Get("/settings", getAlertSettings(service, logger))
+		r.Put("/settings", updateAlertSettings(service, validate, logger))
+		r.Get("/thresholds", getThresholds(service, logger))
+		r.Put("/thresholds/{sku}", setThreshold(service, validate, logger))
+		r.Delete("/thresholds/{sku}", removeThreshold(service, logger))
+	})
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alias"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the SKU mapping operations
+func writeAliasError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidSkuMapping):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_sku_mapping", "The mapping must have a SKU and at least one alias")
+	case errors.Is(err, entity.ErrInvalidSkuAlias):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_sku_alias", "Each alias must have a distinct account and a SKU or listings")
+	case errors.Is(err, alias.ErrUnknownAccount):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "unknown_account", "The account of an alias doesn't belong to the store")
+	case errors.Is(err, alias.ErrInvalidSku):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_sku", "Invalid SKU")
+	case errors.Is(err, alias.ErrSkuAlreadyMapped):
+		presenter.WriteProblem(w, r, http.StatusConflict, "sku_already_mapped", "A SKU of the mapping belongs to another mapping")
+	case errors.Is(err, alias.ErrMappingNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "sku_mapping_not_found", "SKU mapping not found")
+	case errors.Is(err, alias.ErrSkuNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "sku_not_found", "SKU not found")
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func toSkuMappingPresenter(m *entity.SkuMapping) *presenter.SkuMapping {
+	output := &presenter.SkuMapping{
+		ID:        m.ID,
+		Sku:       m.Sku,
+		Aliases:   []presenter.SkuAlias{},
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	for _, a := range m.Aliases {
+		listings := a.ListingIDs
+		if listings == nil {
+			listings = []string{}
+		}
+		output.Aliases = append(output.Aliases, presenter.SkuAlias{AccountID: a.AccountID, Sku: a.Sku, ListingIDs: listings})
+	}
+	return output
+}
+
+// Parses the ID of the mapping in the URL, writing the response when it's invalid
+func mappingIDFromURL(w http.ResponseWriter, r *http.Request) (entity.ID, bool) {
+	mappingId, err := entity.StringToID(chi.URLParam(r, "id"))
+	if err != nil {
+		presenter.WriteProblem(w, r, http.StatusNotFound, "sku_mapping_not_found", "SKU mapping not found")
+		return mappingId, false
+	}
+	return mappingId, true
+}
+
+func createSkuMapping(service alias.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to create the SKU mapping"
+		input := &alias.CreateMappingDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		m, err := service.CreateMapping(*input)
+		if err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(toSkuMappingPresenter(m)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func updateSkuMapping(service alias.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to update the SKU mapping"
+		input := &alias.UpdateMappingDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		mappingId, ok := mappingIDFromURL(w, r)
+		if !ok {
+			return
+		}
+		input.Store = storeId
+		input.ID = mappingId
+
+		m, err := service.UpdateMapping(*input)
+		if err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toSkuMappingPresenter(m)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func getSkuMapping(service alias.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the SKU mapping"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		mappingId, ok := mappingIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		m, err := service.GetMapping(storeId, mappingId)
+		if err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toSkuMappingPresenter(m)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listSkuMappings(service alias.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the SKU mappings"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		mappings, err := service.ListMappings(storeId)
+		if err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []*presenter.SkuMapping{}
+		for i := range mappings {
+			output = append(output, toSkuMappingPresenter(&mappings[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func deleteSkuMapping(service alias.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to delete the SKU mapping"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		mappingId, ok := mappingIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteMapping(storeId, mappingId); err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func suggestSkuAliases(service alias.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to suggest the aliases"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		suggestions, err := service.SuggestAliases(storeId, r.URL.Query().Get("sku"))
+		if err != nil {
+			writeAliasError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []presenter.SkuSuggestion{}
+		for _, s := range suggestions {
+			suggestion := presenter.SkuSuggestion{
+				AnnouncementID: s.AnnouncementID,
+				Title:          s.Title,
+				Sku:            s.Sku,
+				Reason:         string(s.Reason),
+				Score:          s.Score,
+			}
+			suggestion.Account.ID = s.AccountID
+			suggestion.Account.Name = s.AccountName
+			output = append(output, suggestion)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func MakeAliasHandlers(r chi.Router, service alias.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/sku-mappings", func(r chi.Router) {
+		r.Post("/", createSkuMapping(service, validate, logger))
+		r.Get("/", listSkuMappings(service, logger))
+		r.Get("/suggestions", suggestSkuAliases(service, logger))
+		r.Get("/{id}", getSkuMapping(service, logger))
+		r.Put("/{id}", updateSkuMapping(service, validate, logger))
+		r.Delete("/{id}", deleteSkuMapping(service, logger))
+	})
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/allocation"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the allocation policy operations
+func writeAllocationError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidAllocationPolicy):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_allocation_policy", "The policy must have the rules of at least one account")
+	case errors.Is(err, entity.ErrInvalidAccountAllocation):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_account_allocation", "Each rule must have a distinct account, a non-negative buffer and cap, and a percentage from 1 to 100")
+	case errors.Is(err, allocation.ErrUnknownAccount):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "unknown_account", "An account of the policy doesn't belong to the store")
+	case errors.Is(err, allocation.ErrPolicyAlreadyExists):
+		presenter.WriteProblem(w, r, http.StatusConflict, "allocation_policy_already_exists", "The store already has a policy for the SKU")
+	case errors.Is(err, allocation.ErrPolicyNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "allocation_policy_not_found", "Allocation policy not found")
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func toAllocationPolicyPresenter(p *entity.AllocationPolicy) *presenter.AllocationPolicy {
+	output := &presenter.AllocationPolicy{
+		ID:              p.ID,
+		Sku:             p.Sku,
+		PriorityAccount: p.PriorityAccount,
+		Accounts:        []presenter.AccountAllocation{},
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+	for _, a := range p.Accounts {
+		output.Accounts = append(output.Accounts, presenter.AccountAllocation{
+			AccountID:   a.AccountID,
+			Buffer:      a.Buffer,
+			Percentage:  a.Percentage,
+			MaxQuantity: a.MaxQuantity,
+		})
+	}
+	return output
+}
+
+// Parses the ID of the policy in the URL, writing the response when it's invalid
+func policyIDFromURL(w http.ResponseWriter, r *http.Request) (entity.ID, bool) {
+	policyId, err := entity.StringToID(chi.URLParam(r, "id"))
+	if err != nil {
+		presenter.WriteProblem(w, r, http.StatusNotFound, "allocation_policy_not_found", "Allocation policy not found")
+		return policyId, false
+	}
+	return policyId, true
+}
+
+func createAllocationPolicy(service allocation.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to create the allocation policy"
+		input := &allocation.CreatePolicyDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		p, err := service.CreatePolicy(*input)
+		if err != nil {
+			writeAllocationError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(toAllocationPolicyPresenter(p)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func updateAllocationPolicy(service allocation.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to update the allocation policy"
+		input := &allocation.UpdatePolicyDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		policyId, ok := policyIDFromURL(w, r)
+		if !ok {
+			return
+		}
+		input.Store = storeId
+		input.ID = policyId
+
+		p, err := service.UpdatePolicy(*input)
+		if err != nil {
+			writeAllocationError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toAllocationPolicyPresenter(p)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func getAllocationPolicy(service allocation.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the allocation policy"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		policyId, ok := policyIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		p, err := service.GetPolicy(storeId, policyId)
+		if err != nil {
+			writeAllocationError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toAllocationPolicyPresenter(p)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listAllocationPolicies(service allocation.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the allocation policies"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		policies, err := service.ListPolicies(storeId)
+		if err != nil {
+			writeAllocationError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []*presenter.AllocationPolicy{}
+		for i := range policies {
+			output = append(output, toAllocationPolicyPresenter(&policies[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func deleteAllocationPolicy(service allocation.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to delete the allocation policy"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		policyId, ok := policyIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeletePolicy(storeId, policyId); err != nil {
+			writeAllocationError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func MakeAllocationHandlers(r chi.Router, service allocation.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/allocation-policies", func(r chi.Router) {
+		r.Post("/", createAllocationPolicy(service, validate, logger))
+		r.Get("/", listAllocationPolicies(service, logger))
+		r.Get("/{id}", getAllocationPolicy(service, logger))
+		r.Put("/{id}", updateAllocationPolicy(service, validate, logger))
+		r.Delete("/{id}", deleteAllocationPolicy(service, logger))
+	})
+}
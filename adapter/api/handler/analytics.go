@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/analytics"
+	"github.com/go-chi/chi/v5"
+)
+
+func getSalesReport(service analytics.UseCase, dimension analytics.Dimension, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the sales report"
+		query := r.URL.Query()
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		input := analytics.SalesReportDtoInput{
+			Store:     storeId,
+			Dimension: dimension,
+			Interval:  analytics.Interval(query.Get("interval")),
+			Sku:       query.Get("sku"),
+		}
+
+		if id := query.Get("account_id"); id != "" {
+			accountId, err := entity.StringToID(id)
+			if err != nil {
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid account_id")
+				return
+			}
+			input.Account = &accountId
+		}
+
+		if input.From, err = parseDateParam(r, "from"); err != nil {
+			presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid from, it must be a RFC 3339 date")
+			return
+		}
+		if input.To, err = parseDateParam(r, "to"); err != nil {
+			presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid to, it must be a RFC 3339 date")
+			return
+		}
+
+		report, err := service.SalesReport(input)
+		if err != nil {
+			switch {
+			case errors.Is(err, analytics.ErrInvalidInterval):
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid interval, it must be day, week or month")
+			case errors.Is(err, analytics.ErrInvalidPeriod):
+				presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_period", "Invalid period, from must be before to and the period can't be longer than a year")
+			default:
+				writeError(w, r, err, errorMessage)
+			}
+			return
+		}
+
+		output := &presenter.SalesReport{
+			Dimension: string(report.Dimension),
+			Interval:  string(report.Interval),
+			From:      report.From,
+			To:        report.To,
+			Buckets:   []presenter.SalesBucket{},
+		}
+		for _, b := range report.Buckets {
+			output.Buckets = append(output.Buckets, presenter.SalesBucket{
+				Period:     b.Period,
+				Key:        b.Key,
+				Units:      b.Units,
+				Revenue:    b.Revenue,
+				CurrencyID: b.CurrencyID,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func MakeAnalyticsHandlers(r chi.Router, service analytics.UseCase, logger metrics.Logger) {
+	r.Route("/analytics/sales", func(r chi.Router) {
+		r.Get("/sku", getSalesReport(service, analytics.BySku, logger))
+		r.Get("/account", getSalesReport(service, analytics.ByAccount, logger))
+		r.Get("/listing-type", getSalesReport(service, analytics.ByListingType, logger))
+	})
+}
@@ -11,50 +11,43 @@ import (
 	"github.com/Vractos/kloni/usecases/announcement"
 	"github.com/Vractos/kloni/usecases/store"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
-func cloneAnnouncement(service announcement.UseCase, store store.UseCase, logger metrics.Logger) http.HandlerFunc {
+func cloneAnnouncement(service announcement.UseCase, store store.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		errorMessage := "Error to clone the announcement"
 		input := &announcement.CloneAnnouncementDtoInput{}
-		err := json.NewDecoder(r.Body).Decode(input)
-		if err != nil {
-			logger.Error("Error to decode body", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+		if !decodeBody(w, r, validate, input, logger) {
 			return
 		}
 
 		storeId, err := contexttools.RetrieveStoreIDFromCtx(r.Context())
 		if err != nil {
 			logger.Error("Fail to retrieve the storeID from the context", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		strUUID, err := entity.StringToID(storeId)
 		if err != nil {
 			logger.Error("Fail to convert storeID from a string to an entity ID", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		credentials, err := store.RetrieveMeliCredentialsFromStoreID(strUUID)
 		if err != nil {
 			logger.Error("Couldn't retrieve meli's credentials", err, zap.String("store_id", storeId))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		err = service.CloneAnnouncement(*input, credentials)
 		if err != nil {
 			logger.Error("Error to clone announcement", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
@@ -62,47 +55,39 @@ func cloneAnnouncement(service announcement.UseCase, store store.UseCase, logger
 	}
 }
 
-func importAnnouncement(service announcement.UseCase, store store.UseCase, logger metrics.Logger) http.HandlerFunc {
+func importAnnouncement(service announcement.UseCase, store store.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		errorMessage := "Error to import the announcement"
 		input := &announcement.ImportAnnouncementDtoInput{}
-		err := json.NewDecoder(r.Body).Decode(input)
-		if err != nil {
-			logger.Error("Error to decode body", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+		if !decodeBody(w, r, validate, input, logger) {
 			return
 		}
 
 		storeId, err := contexttools.RetrieveStoreIDFromCtx(r.Context())
 		if err != nil {
 			logger.Error("Fail to retrieve the storeID from the context", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		strUUID, err := entity.StringToID(storeId)
 		if err != nil {
 			logger.Error("Fail to convert storeID from a string to an entity ID", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		credentials, err := store.RetrieveMeliCredentialsFromStoreID(strUUID)
 		if err != nil {
 			logger.Error("Couldn't retrieve meli's credentials", err, zap.String("store_id", storeId))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		err = service.ImportAnnouncement(*input, credentials)
 		if err != nil {
 			logger.Error("Error to import announcement", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
@@ -119,35 +104,31 @@ func getAnnouncements(announce announcement.UseCase, store store.UseCase, logger
 		storeId, err := contexttools.RetrieveStoreIDFromCtx(r.Context())
 		if err != nil {
 			logger.Error("Fail to retrieve the storeID from the context", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
+			return
 		}
 
 		id, err := entity.StringToID(storeId)
 		if err != nil {
 			logger.Error("Fail to convert storeID from a string to an entity ID", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		credential, err := store.RetrieveMeliCredentialsFromStoreID(id)
 		if err != nil {
 			logger.Error("Couldn't retrieve meli's credentials", err, zap.String("store_id", storeId))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
 		anns, err := announce.RetrieveAnnouncementsFromAllAccounts(sku, credential)
 		if err != nil {
 			logger.Error("Fail to retrieve announcements", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		} else if anns == nil {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(notFound))
+			presenter.WriteProblem(w, r, http.StatusNotFound, "announcement_not_found", notFound)
 			return
 		}
 
@@ -181,17 +162,15 @@ func getAnnouncements(announce announcement.UseCase, store store.UseCase, logger
 
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(output); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
-			return
+			logger.Error("Fail to encode the response", err)
 		}
 	}
 }
 
-func MakeAnnouncementHandlers(r chi.Router, announceService announcement.UseCase, storeService store.UseCase, logger metrics.Logger) {
+func MakeAnnouncementHandlers(r chi.Router, announceService announcement.UseCase, storeService store.UseCase, validate *validator.Validate, logger metrics.Logger) {
 	r.Route("/announcement", func(r chi.Router) {
-		r.Post("/", cloneAnnouncement(announceService, storeService, logger))
+		r.Post("/", cloneAnnouncement(announceService, storeService, validate, logger))
 		r.Get("/{sku}", getAnnouncements(announceService, storeService, logger))
-		r.Post("/import", importAnnouncement(announceService, storeService, logger))
+		r.Post("/import", importAnnouncement(announceService, storeService, validate, logger))
 	})
 }
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+// Renders the OpenAPI document with Redoc. Its version is pinned, so the page doesn't run
+// a bundle that changed on the CDN, make redoc_integrity prints the hash of the bundle
+const docsPage = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>Kloni API</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+  </head>
+  <body>
+    <redoc spec-url="/docs/openapi.json"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js" crossorigin="anonymous"></script>
+  </body>
+</html>
+`
+
+func getDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(docsPage))
+}
+
+func getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.Spec)
+}
+
+// MakeDocsHandlers serves the OpenAPI document of the API and a page that renders it, both public
+func MakeDocsHandlers(r chi.Router) {
+	r.Route("/docs", func(r chi.Router) {
+		r.Get("/", getDocs)
+		r.Get("/openapi.json", getOpenAPI)
+	})
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/health"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// checkHealth responds with the report of the checks, 503 when a component is down,
+// so the orchestrator stops routing to the instance
+func checkHealth(checker *health.Checker, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Run(r.Context())
+
+		output := &presenter.Health{
+			Status:     report.Status,
+			Components: make(map[string]presenter.HealthComponent, len(report.Components)),
+		}
+		for name, component := range report.Components {
+			output.Components[name] = presenter.HealthComponent{
+				Status:    component.Status,
+				Error:     component.Error,
+				LatencyMs: float64(component.Latency) / float64(time.Millisecond),
+			}
+			if component.Status != health.StatusUp {
+				logger.Warn("Health check failed", zap.String("component", name), zap.String("error", component.Error))
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Up() {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the health report", err)
+		}
+	}
+}
+
+// MakeHealthHandlers registers the probes, the liveness checks that the instance responds
+// and the readiness checks the dependencies of the instance
+func MakeHealthHandlers(r chi.Router, liveness, readiness *health.Checker, logger metrics.Logger) {
+	r.Get("/healthz", checkHealth(liveness, logger))
+	r.Get("/readyz", checkHealth(readiness, logger))
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/kit"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the kit operations
+func writeKitError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidKit):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_kit", "The kit must have a SKU and at least one component")
+	case errors.Is(err, entity.ErrInvalidComponent):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_kit_component", "The components must have distinct SKUs, other than the kit, and positive quantities")
+	case errors.Is(err, kit.ErrNestedKit):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "nested_kit", "Kits can't be components of other kits")
+	case errors.Is(err, kit.ErrKitAlreadyExists):
+		presenter.WriteProblem(w, r, http.StatusConflict, "kit_already_exists", "A kit with this SKU already exists")
+	case errors.Is(err, kit.ErrKitNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "kit_not_found", "Kit not found")
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func toKitPresenter(k *entity.Kit) *presenter.Kit {
+	output := &presenter.Kit{
+		ID:         k.ID,
+		Sku:        k.Sku,
+		Title:      k.Title,
+		Components: []presenter.KitComponent{},
+		CreatedAt:  k.CreatedAt,
+		UpdatedAt:  k.UpdatedAt,
+	}
+	for _, c := range k.Components {
+		output.Components = append(output.Components, presenter.KitComponent{Sku: c.Sku, Quantity: c.Quantity})
+	}
+	return output
+}
+
+// Parses the ID of the kit in the URL, writing the response when it's invalid
+func kitIDFromURL(w http.ResponseWriter, r *http.Request) (entity.ID, bool) {
+	kitId, err := entity.StringToID(chi.URLParam(r, "id"))
+	if err != nil {
+		presenter.WriteProblem(w, r, http.StatusNotFound, "kit_not_found", "Kit not found")
+		return kitId, false
+	}
+	return kitId, true
+}
+
+func createKit(service kit.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to create the kit"
+		input := &kit.CreateKitDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		k, err := service.CreateKit(*input)
+		if err != nil {
+			writeKitError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(toKitPresenter(k)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func updateKit(service kit.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to update the kit"
+		input := &kit.UpdateKitDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		kitId, ok := kitIDFromURL(w, r)
+		if !ok {
+			return
+		}
+		input.Store = storeId
+		input.ID = kitId
+
+		k, err := service.UpdateKit(*input)
+		if err != nil {
+			writeKitError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toKitPresenter(k)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func getKit(service kit.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the kit"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		kitId, ok := kitIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		k, err := service.GetKit(storeId, kitId)
+		if err != nil {
+			writeKitError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toKitPresenter(k)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listKits(service kit.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the kits"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		kits, err := service.ListKits(storeId)
+		if err != nil {
+			writeKitError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []*presenter.Kit{}
+		for i := range kits {
+			output = append(output, toKitPresenter(&kits[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func deleteKit(service kit.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to delete the kit"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		kitId, ok := kitIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteKit(storeId, kitId); err != nil {
+			writeKitError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func MakeKitHandlers(r chi.Router, service kit.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/kits", func(r chi.Router) {
+		r.Post("/", createKit(service, validate, logger))
+		r.Get("/", listKits(service, logger))
+		r.Get("/{id}", getKit(service, logger))
+		r.Put("/{id}", updateKit(service, validate, logger))
+		r.Delete("/{id}", deleteKit(service, logger))
+	})
+}
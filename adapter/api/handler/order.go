@@ -2,25 +2,29 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	mdw "github.com/Vractos/kloni/adapter/api/middleware"
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/pkg/metrics"
 	"github.com/Vractos/kloni/usecases/order"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
-func receiveMeliOrderNotification(service order.UseCase, logger metrics.Logger) http.HandlerFunc {
+func receiveMeliOrderNotification(service order.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		input := &order.OrderWebhookDtoInput{}
-		err := json.NewDecoder(r.Body).Decode(input)
-		if err != nil {
-			logger.Error("Error to decode body", err)
-			w.WriteHeader(http.StatusInternalServerError)
+		if !decodeBody(w, r, validate, input, logger) {
 			return
 		}
 
-		if err := service.ProcessWebhook(*input); err != nil {
+		if err := service.ProcessWebhook(r.Context(), *input); err != nil {
 			logger.Error(
 				"Fail to process webhook",
 				err,
@@ -29,15 +33,237 @@ func receiveMeliOrderNotification(service order.UseCase, logger metrics.Logger)
 				zap.Int("attempts", input.Attempts),
 				zap.String("sent", input.Sent),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, r, err, "Error to process the notification")
 			return
 		}
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
-func MakeOrderHandlers(r chi.Router, service order.UseCase, logger metrics.Logger) {
+func toOrderPresenter(o *entity.Order) presenter.Order {
+	output := presenter.Order{
+		ID:            o.ID,
+		AccountID:     o.AccountID,
+		MarketplaceID: o.MarketplaceID,
+		Status:        o.Status.String(),
+		DateCreated:   o.DateCreated,
+		DateClosed:    o.DateClosed,
+		LastUpdated:   o.LastUpdated,
+		TotalAmount:   o.TotalAmount,
+		PaidAmount:    o.PaidAmount,
+		CurrencyID:    o.CurrencyID,
+		PackID:        o.PackID,
+		PackSize:      o.PackSize,
+		ShippingID:    o.ShippingID,
+		BuyerNickname: o.BuyerNickname,
+		Items:         []presenter.OrderItem{},
+	}
+	for _, i := range o.Items {
+		output.Items = append(output.Items, presenter.OrderItem{
+			ID:            i.ID,
+			Title:         i.Title,
+			Sku:           i.Sku,
+			Quantity:      i.Quantity,
+			VariationID:   i.VariationID,
+			UnitPrice:     i.UnitPrice,
+			CurrencyID:    i.CurrencyID,
+			ListingTypeID: i.ListingTypeID,
+		})
+	}
+	for _, a := range o.SyncActions {
+		output.SyncActions = append(output.SyncActions, presenter.SyncAction{
+			ID:             a.ID,
+			Kind:           string(a.Kind),
+			AccountID:      a.AccountID,
+			AnnouncementID: a.AnnouncementID,
+			VariationID:    a.VariationID,
+			Sku:            a.Sku,
+			Quantity:       a.Quantity,
+			Delta:          a.Delta,
+			Status:         string(a.Status),
+			Error:          a.Error,
+			Attempts:       a.Attempts,
+			CreatedAt:      a.CreatedAt,
+			ExecutedAt:     a.ExecutedAt,
+		})
+	}
+	if len(o.SyncActions) > 0 {
+		progress := entity.NewSyncProgress(o.SyncActions)
+		output.SyncProgress = &presenter.SyncProgress{
+			Status:     string(progress.Status),
+			Total:      progress.Total,
+			Done:       progress.Done,
+			Pending:    progress.Pending,
+			Failed:     progress.Failed,
+			Superseded: progress.Superseded,
+		}
+	}
+	return output
+}
+
+// Parses an optional RFC 3339 date of the query
+func parseDateParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func listOrders(service order.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to list the orders"
+		query := r.URL.Query()
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		input := order.ListOrdersDtoInput{
+			Store:  storeId,
+			Status: query.Get("status"),
+			Sku:    query.Get("sku"),
+			Pack:   query.Get("pack"),
+			Cursor: query.Get("cursor"),
+		}
+
+		if id := query.Get("account_id"); id != "" {
+			accountId, err := entity.StringToID(id)
+			if err != nil {
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid account_id")
+				return
+			}
+			input.Account = &accountId
+		}
+
+		if input.From, err = parseDateParam(r, "from"); err != nil {
+			presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid from, it must be a RFC 3339 date")
+			return
+		}
+		if input.To, err = parseDateParam(r, "to"); err != nil {
+			presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid to, it must be a RFC 3339 date")
+			return
+		}
+
+		if limit := query.Get("limit"); limit != "" {
+			input.Limit, err = strconv.Atoi(limit)
+			if err != nil || input.Limit <= 0 {
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid limit")
+				return
+			}
+		}
+
+		page, err := service.ListOrders(input)
+		if err != nil {
+			if errors.Is(err, order.ErrInvalidCursor) {
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidParameter, "Invalid cursor")
+				return
+			}
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		output := &presenter.OrderPage{
+			Orders:     []presenter.Order{},
+			NextCursor: page.NextCursor,
+		}
+		for i := range page.Orders {
+			output.Orders = append(output.Orders, toOrderPresenter(&page.Orders[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func getOrder(service order.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the order"
+
+		orderId, err := entity.StringToID(chi.URLParam(r, "id"))
+		if err != nil {
+			presenter.WriteProblem(w, r, http.StatusNotFound, "order_not_found", "Order not found")
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		odr, err := service.GetOrderDetail(storeId, orderId)
+		if err != nil {
+			if errors.Is(err, order.ErrOrderNotFound) {
+				presenter.WriteProblem(w, r, http.StatusNotFound, "order_not_found", "Order not found")
+				return
+			}
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toOrderPresenter(odr)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func resumeOrderSync(service order.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to resume the sync of the order"
+
+		orderId, err := entity.StringToID(chi.URLParam(r, "id"))
+		if err != nil {
+			presenter.WriteProblem(w, r, http.StatusNotFound, "order_not_found", "Order not found")
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		odr, err := service.ResumeSync(storeId, orderId)
+		if err != nil {
+			if errors.Is(err, order.ErrOrderNotFound) {
+				presenter.WriteProblem(w, r, http.StatusNotFound, "order_not_found", "Order not found")
+				return
+			}
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toOrderPresenter(odr)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+// The webhook middlewares verify the source of the notifications, the idempotent ones
+// wrap the authenticated routes
+func MakeOrderHandlers(r chi.Router, service order.UseCase, webhook, idempotent chi.Middlewares, validate *validator.Validate, logger metrics.Logger) {
 	r.Route("/order", func(r chi.Router) {
-		r.Post("/meli-notification", receiveMeliOrderNotification(service, logger))
+		r.With(webhook...).Post("/meli-notification", receiveMeliOrderNotification(service, validate, logger))
+		r.Group(func(r chi.Router) {
+			r.Use(mdw.EnsureValidToken(logger))
+			r.Use(mdw.AddStoreIDToCtx)
+			r.Use(idempotent...)
+			r.Get("/", listOrders(service, logger))
+			r.Get("/{id}", getOrder(service, logger))
+			r.Post("/{id}/resume-sync", resumeOrderSync(service, logger))
+		})
 	})
 }
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/order"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// Codes of the errors shared by the operations, the codes of the domain errors are
+// written by the writeXError functions of each handler
+const (
+	codeInvalidBody         = "invalid_body"
+	codeValidationFailed    = "validation_failed"
+	codeInvalidParameter    = "invalid_parameter"
+	codeCredentialsNotFound = "credentials_not_found"
+	codeMarketplaceError    = "marketplace_error"
+	codeMarketplaceBusy     = "marketplace_unavailable"
+	codeTimeout             = "timeout"
+	codeInternal            = "internal_error"
+)
+
+// writeError writes the response for the errors that aren't specific to an operation,
+// the failures of Mercado Livre and the stores without credentials. Other errors are internal.
+func writeError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	var annErr *announcement.AnnouncementError
+	switch {
+	case errors.As(err, &annErr) && annErr.IsAbleToRetry:
+		w.Header().Set("Retry-After", "30")
+		presenter.WriteProblem(w, r, http.StatusServiceUnavailable, codeMarketplaceBusy, annErr.Message)
+	case errors.As(err, &annErr):
+		presenter.WriteProblem(w, r, http.StatusBadGateway, codeMarketplaceError, annErr.Message)
+	case errors.Is(err, order.ErrCredentialsNotFound):
+		presenter.WriteProblem(w, r, http.StatusConflict, codeCredentialsNotFound, "The store doesn't have Mercado Livre credentials")
+	case errors.Is(err, context.DeadlineExceeded):
+		presenter.WriteProblem(w, r, http.StatusGatewayTimeout, codeTimeout, errorMessage)
+	default:
+		presenter.WriteProblem(w, r, http.StatusInternalServerError, codeInternal, errorMessage)
+	}
+}
+
+// decodeBody decodes the JSON body of the request into input and validates it with the
+// validate tags of the DTO. The problem is written when the body is malformed or invalid.
+//
+// Returns:
+//   - bool: Whether the input is valid, the handler returns otherwise
+func decodeBody(w http.ResponseWriter, r *http.Request, validate *validator.Validate, input interface{}, logger metrics.Logger) bool {
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil {
+		logger.Warn("Error to decode body", zap.Error(err))
+		presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidBody, "The body must be a valid JSON document")
+		return false
+	}
+
+	err := validate.Struct(input)
+	if err == nil {
+		return true
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		logger.Error("Fail to validate the body", err)
+		presenter.WriteProblem(w, r, http.StatusInternalServerError, codeInternal, "Fail to validate the body")
+		return false
+	}
+
+	problem := &presenter.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusUnprocessableEntity),
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "The body has invalid fields",
+		Instance: r.URL.Path,
+		Code:     codeValidationFailed,
+		Errors:   make([]presenter.FieldError, len(validationErrors)),
+	}
+	for i, fe := range validationErrors {
+		problem.Errors[i] = presenter.FieldError{
+			Field: fieldPath(fe),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+		}
+	}
+	presenter.WriteProblemDocument(w, problem)
+	return false
+}
+
+// fieldPath is the path of the field in the body, e.g. components[0].sku,
+// the namespace of the error without the name of the DTO
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// JSONFieldName names the fields of the validation errors after their JSON keys,
+// see validator.Validate.RegisterTagNameFunc
+func JSONFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/pkg/spreadsheet"
+	"github.com/Vractos/kloni/usecases/stock"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the stock operations
+func writeStockError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, stock.ErrInvalidSku):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_sku", "Invalid SKU")
+	case errors.Is(err, stock.ErrInvalidAdjustment):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_adjustment", "Inform either a non-negative quantity or a delta")
+	case errors.Is(err, stock.ErrSkuNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "sku_not_found", "SKU not found")
+	case errors.Is(err, stock.ErrMissingColumns):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "missing_columns", err.Error())
+	case errors.Is(err, stock.ErrTooManyRows):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "too_many_rows", err.Error())
+	case errors.Is(err, spreadsheet.ErrUnsupportedFormat):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "unsupported_format", err.Error())
+	case errors.Is(err, spreadsheet.ErrEmptyFile):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "empty_file", err.Error())
+	case errors.Is(err, stock.ErrImportNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "import_not_found", "Import not found")
+	case errors.Is(err, stock.ErrImportValidating):
+		presenter.WriteProblem(w, r, http.StatusConflict, "import_validating", err.Error())
+	case errors.Is(err, stock.ErrImportNotPreviewed):
+		presenter.WriteProblem(w, r, http.StatusConflict, "import_already_confirmed", err.Error())
+	case errors.Is(err, stock.ErrImportExpired):
+		presenter.WriteProblem(w, r, http.StatusConflict, "import_expired", err.Error())
+	case errors.Is(err, stock.ErrNothingToImport):
+		presenter.WriteProblem(w, r, http.StatusConflict, "nothing_to_import", err.Error())
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func adjustStock(service stock.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to adjust the stock"
+		input := &stock.AdjustStockDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		adjustment, err := service.AdjustStock(*input)
+		if err != nil {
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		output := &presenter.StockAdjustment{
+			Sku:      adjustment.Sku,
+			Stock:    adjustment.Stock,
+			Listings: []presenter.ListingAdjustment{},
+		}
+		for _, l := range adjustment.Listings {
+			output.Listings = append(output.Listings, presenter.ListingAdjustment{
+				AccountID:        l.AccountID,
+				AccountName:      l.AccountName,
+				AnnouncementID:   l.AnnouncementID,
+				VariationID:      l.VariationID,
+				PreviousQuantity: l.PreviousQuantity,
+				Quantity:         l.Quantity,
+				Status:           string(l.Status),
+				Error:            l.Error,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+// Maximum size of the uploaded stock files
+const maxStockFileSize = 10 << 20
+
+func toStockImportPresenter(i *stock.Import) *presenter.StockImport {
+	output := &presenter.StockImport{
+		ID:          i.ID,
+		FileName:    i.FileName,
+		Status:      string(i.Status),
+		Counts:      make(map[string]int, len(i.Counts)),
+		CreatedAt:   i.CreatedAt,
+		ConfirmedAt: i.ConfirmedAt,
+		FinishedAt:  i.FinishedAt,
+	}
+	for status, total := range i.Counts {
+		output.Counts[string(status)] = total
+	}
+	for _, row := range i.Rows {
+		output.Rows = append(output.Rows, presenter.StockImportRow{
+			Line:     row.Line,
+			Sku:      row.Sku,
+			Quantity: row.Quantity,
+			Status:   string(row.Status),
+			Error:    row.Error,
+			Listings: row.Listings,
+			Updated:  row.Updated,
+			Failed:   row.Failed,
+		})
+	}
+	return output
+}
+
+// Parses the ID of the import in the URL, writing the response when it's invalid
+func importIDFromURL(w http.ResponseWriter, r *http.Request) (entity.ID, bool) {
+	importId, err := entity.StringToID(chi.URLParam(r, "id"))
+	if err != nil {
+		presenter.WriteProblem(w, r, http.StatusNotFound, "import_not_found", "Import not found")
+		return importId, false
+	}
+	return importId, true
+}
+
+func previewStockImport(service stock.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to import the stock file"
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxStockFileSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			logger.Error("Error to read the stock file", err)
+			presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_file", "Send a CSV or XLSX file of at most 10 MB in the file field")
+			return
+		}
+		defer file.Close()
+
+		records, err := spreadsheet.Read(header.Filename, file)
+		if err != nil {
+			if !errors.Is(err, spreadsheet.ErrUnsupportedFormat) && !errors.Is(err, spreadsheet.ErrEmptyFile) {
+				logger.Error("Error to parse the stock file", err)
+				presenter.WriteProblem(w, r, http.StatusBadRequest, "malformed_file", "The stock file is malformed")
+				return
+			}
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		i, err := service.PreviewImport(stock.PreviewImportDtoInput{
+			Store:    storeId,
+			FileName: header.Filename,
+			Records:  records,
+		})
+		if err != nil {
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(toStockImportPresenter(i)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func confirmStockImport(service stock.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to confirm the import"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		importId, ok := importIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		i, err := service.ConfirmImport(storeId, importId)
+		if err != nil {
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(toStockImportPresenter(i)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func getStockImport(service stock.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the import"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		importId, ok := importIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		i, err := service.GetImport(storeId, importId)
+		if err != nil {
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toStockImportPresenter(i)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listStockImports(service stock.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the imports"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		imports, err := service.ListImports(storeId)
+		if err != nil {
+			writeStockError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []*presenter.StockImport{}
+		for i := range imports {
+			output = append(output, toStockImportPresenter(&imports[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func MakeStockHandlers(r chi.Router, service stock.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/stock", func(r chi.Router) {
+		r.Post("/adjust", adjustStock(service, validate, logger))
+		r.Post("/imports", previewStockImport(service, logger))
+		r.Get("/imports", listStockImports(service, logger))
+		r.Get("/imports/{id}", getStockImport(service, logger))
+		r.Post("/imports/{id}/confirm", confirmStockImport(service, logger))
+	})
+}
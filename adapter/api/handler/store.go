@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 
 	mdw "github.com/Vractos/kloni/adapter/api/middleware"
 	"github.com/Vractos/kloni/adapter/api/presenter"
@@ -11,18 +13,15 @@ import (
 	"github.com/Vractos/kloni/pkg/metrics"
 	"github.com/Vractos/kloni/usecases/store"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 )
 
-func registerStore(service store.UseCase, logger metrics.Logger) http.HandlerFunc {
+func registerStore(service store.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		errorMessage := "Error adding store"
 		input := &store.RegisterStoreDtoInput{}
-		err := json.NewDecoder(r.Body).Decode(input)
-		if err != nil {
-			logger.Error("Error to decode body", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+		if !decodeBody(w, r, validate, input, logger) {
 			return
 		}
 
@@ -34,8 +33,7 @@ func registerStore(service store.UseCase, logger metrics.Logger) http.HandlerFun
 				zap.String("name", input.Name),
 				zap.String("email", input.Email),
 			)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 		output := &presenter.Store{
@@ -50,59 +48,284 @@ func registerStore(service store.UseCase, logger metrics.Logger) http.HandlerFun
 			zap.String("id", id.String()),
 		)
 		if err := json.NewEncoder(w).Encode(output); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(errorMessage))
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+// Retrieves the store ID, added to the context by the auth middleware
+func storeIDFromCtx(r *http.Request, logger metrics.Logger) (entity.ID, error) {
+	storeId, err := contexttools.RetrieveStoreIDFromCtx(r.Context())
+	if err != nil {
+		logger.Error("Fail to retrieve the storeID from the context", err)
+		return entity.ID{}, err
+	}
+
+	id, err := entity.StringToID(storeId)
+	if err != nil {
+		logger.Error("Fail to convert storeID from a string to an entity ID", err)
+		return entity.ID{}, err
+	}
+	return id, nil
+}
+
+func toAccountPresenter(a *store.Account) *presenter.Account {
+	return &presenter.Account{
+		ID:                 a.ID,
+		Name:               a.AccountName,
+		UserID:             a.UserID,
+		SiteID:             a.SiteID,
+		Status:             string(a.Status),
+		CreatedAt:          a.CreatedAt,
+		TokenUpdatedAt:     a.UpdatedAt,
+		TokenAgeSeconds:    int64(a.TokenAge.Seconds()),
+		LastRefreshError:   a.LastRefreshError,
+		LastRefreshErrorAt: a.LastRefreshErrorAt,
+	}
+}
+
+// Writes the response for the errors of the account operations
+func writeAccountError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	status, code, detail, ok := accountProblem(err)
+	if !ok {
+		writeError(w, r, err, errorMessage)
+		return
+	}
+	presenter.WriteProblem(w, r, status, code, detail)
+}
+
+// accountProblem maps the errors of the accounts to their problem, ok is false for the unexpected errors
+func accountProblem(err error) (status int, code, detail string, ok bool) {
+	switch {
+	case errors.Is(err, store.ErrAccountNotFound):
+		return http.StatusNotFound, "account_not_found", "Account not found", true
+	case errors.Is(err, store.ErrInvalidAccountName):
+		return http.StatusBadRequest, "invalid_account_name", "Invalid account name", true
+	case errors.Is(err, store.ErrAccountMismatch):
+		return http.StatusConflict, "account_mismatch", "The authorization belongs to another Mercado Livre account", true
+	case errors.Is(err, store.ErrAccountAlreadyLinked):
+		return http.StatusConflict, "account_already_linked", "The Mercado Livre account is already linked", true
+	case errors.Is(err, store.ErrInvalidSite):
+		return http.StatusBadRequest, "invalid_site", "Invalid site", true
+	case errors.Is(err, store.ErrInvalidState):
+		return http.StatusBadRequest, "invalid_authorization_state", "Invalid or expired authorization", true
+	}
+	return 0, "", "", false
+}
+
+func getAccounts(service store.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get accounts"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		accounts, err := service.RetrieveAccounts(storeId)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []*presenter.Account{}
+		for i := range *accounts {
+			output = append(output, toAccountPresenter(&(*accounts)[i]))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func renameAccount(service store.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to rename the account"
+		input := &store.RenameAccountDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		accountId, err := entity.StringToID(chi.URLParam(r, "id"))
+		if err != nil {
+			writeAccountError(w, r, store.ErrAccountNotFound, errorMessage)
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		input.Store = storeId
+		input.Account = accountId
+
+		if err := service.RenameAccount(*input); err != nil {
+			writeAccountError(w, r, err, errorMessage)
+			return
+		}
+
+		account, err := service.RetrieveAccount(storeId, accountId)
+		if err != nil {
+			writeAccountError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toAccountPresenter(account)); err != nil {
+			writeError(w, r, err, errorMessage)
 			return
 		}
 	}
 }
 
-func registerMeliCredentials(service store.UseCase, logger metrics.Logger) http.HandlerFunc {
+func disconnectAccount(service store.UseCase, logger metrics.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		erroMessage := "Error to get credentials"
-		input := &store.RegisterMeliCredentialsDtoInput{}
-		err := json.NewDecoder(r.Body).Decode(input)
+		errorMessage := "Error to disconnect the account"
+
+		accountId, err := entity.StringToID(chi.URLParam(r, "id"))
 		if err != nil {
-			logger.Error("Error to decode body", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(erroMessage))
+			writeAccountError(w, r, store.ErrAccountNotFound, errorMessage)
 			return
 		}
 
-		storeId, err := contexttools.RetrieveStoreIDFromCtx(r.Context())
+		storeId, err := storeIDFromCtx(r, logger)
 		if err != nil {
-			logger.Error("Fail to retrieve the storeID from the context", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(erroMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
-		store, err := entity.StringToID(storeId)
+		if err := service.DisconnectAccount(storeId, accountId); err != nil {
+			writeAccountError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func startMeliAuthorization(service store.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to start the authorization"
+
+		storeId, err := storeIDFromCtx(r, logger)
 		if err != nil {
-			logger.Error("Fail to convert storeID from a string to an entity ID", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(erroMessage))
+			writeError(w, r, err, errorMessage)
 			return
 		}
 
-		input.Store = store
+		input := store.StartMeliAuthorizationDtoInput{
+			Store:       storeId,
+			AccountName: r.URL.Query().Get("account_name"),
+			Site:        r.URL.Query().Get("site_id"),
+		}
+		// Re-authorization of an account that is already linked
+		if id := r.URL.Query().Get("account_id"); id != "" {
+			accountId, err := entity.StringToID(id)
+			if err != nil {
+				writeAccountError(w, r, store.ErrAccountNotFound, errorMessage)
+				return
+			}
+			input.Account = &accountId
+		}
 
-		err = service.RegisterMeliCredentials(*input)
+		authorizationUrl, err := service.StartMeliAuthorization(input)
 		if err != nil {
-			logger.Error("Fail to register meli's credentials", err, zap.String("store_id", storeId))
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(erroMessage))
+			writeAccountError(w, r, err, errorMessage)
 			return
 		}
+
+		output := &presenter.MeliAuthorization{URL: authorizationUrl}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+// Mercado Livre redirects the seller here after the authorization.
+// When a frontend URL is configured, the seller is redirected to it with the result,
+// otherwise the result is returned as the response. The reason of a failure is the code
+// of its problem, the errors themselves aren't exposed in the URL.
+func meliAuthorizationCallback(service store.UseCase, frontendUrl string, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to complete the authorization"
+		query := r.URL.Query()
+
+		var (
+			state  *store.AuthorizationState
+			err    error
+			denied bool
+		)
+		if reason := query.Get("error"); reason != "" {
+			logger.Warn("Meli authorization was denied", zap.String("error", reason))
+			err = store.ErrInvalidState
+			denied = true
+		} else {
+			state, err = service.CompleteMeliAuthorization(store.CompleteMeliAuthorizationDtoInput{
+				Code:  query.Get("code"),
+				State: query.Get("state"),
+			})
+		}
+
+		if frontendUrl != "" {
+			redirect := url.Values{}
+			if err != nil {
+				_, reason, _, ok := accountProblem(err)
+				switch {
+				case denied:
+					reason = "authorization_denied"
+				case !ok:
+					reason = "authorization_failed"
+				}
+				redirect.Set("status", "error")
+				redirect.Set("reason", reason)
+			} else {
+				redirect.Set("status", "success")
+				redirect.Set("account_id", state.Account.String())
+			}
+			http.Redirect(w, r, frontendUrl+"?"+redirect.Encode(), http.StatusFound)
+			return
+		}
+
+		if err != nil {
+			writeAccountError(w, r, err, errorMessage)
+			return
+		}
+
+		output := &presenter.Account{ID: *state.Account}
+		if state.AccountName != "" {
+			output.Name = &state.AccountName
+		}
+
 		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
 	}
 }
 
-func MakeStoreHandlers(r chi.Router, service store.UseCase, logger metrics.Logger) {
+// The idempotent middlewares wrap the authenticated routes
+func MakeStoreHandlers(r chi.Router, service store.UseCase, authRedirectUrl string, idempotent chi.Middlewares, validate *validator.Validate, logger metrics.Logger) {
 	r.Route("/store", func(r chi.Router) {
-		r.Post("/", registerStore(service, logger))
-		// That isn't the best approach to passing an auth middleware to only one route inside the route maker.
-		// TODO Improve how this middleware is passed
-		r.With(mdw.EnsureValidToken(logger)).With(mdw.AddStoreIDToCtx).Post("/meli-credentials", registerMeliCredentials(service, logger))
+		r.Post("/", registerStore(service, validate, logger))
+		r.Route("/meli", func(r chi.Router) {
+			r.With(mdw.EnsureValidToken(logger)).With(mdw.AddStoreIDToCtx).Get("/authorize", startMeliAuthorization(service, logger))
+			// Public, the store comes from the signed state
+			r.Get("/callback", meliAuthorizationCallback(service, authRedirectUrl, logger))
+		})
+		r.Route("/accounts", func(r chi.Router) {
+			r.Use(mdw.EnsureValidToken(logger))
+			r.Use(mdw.AddStoreIDToCtx)
+			r.Use(idempotent...)
+			r.Get("/", getAccounts(service, logger))
+			r.Patch("/{id}", renameAccount(service, validate, logger))
+			r.Delete("/{id}", disconnectAccount(service, logger))
+		})
 	})
 }
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/webhook"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Writes the response for the errors of the webhook operations
+func writeWebhookError(w http.ResponseWriter, r *http.Request, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidURL):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_url", "The URL must be https and resolve to a public address")
+	case errors.Is(err, webhook.ErrInvalidEvents):
+		presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_events", "Invalid events")
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		presenter.WriteProblem(w, r, http.StatusNotFound, "subscription_not_found", "Subscription not found")
+	default:
+		writeError(w, r, err, errorMessage)
+	}
+}
+
+func toSubscriptionPresenter(s webhook.Subscription) presenter.WebhookSubscription {
+	events := make([]string, len(s.Events))
+	for i, event := range s.Events {
+		events[i] = string(event)
+	}
+	return presenter.WebhookSubscription{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    events,
+		Secret:    s.Secret,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func toDeliveryPresenter(d webhook.Delivery) presenter.WebhookDelivery {
+	output := presenter.WebhookDelivery{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == webhook.DeliveryPending {
+		output.NextAttemptAt = &d.NextAttemptAt
+	}
+	return output
+}
+
+// Parses the ID of the subscription in the URL, writing the response when it's invalid
+func subscriptionIDFromURL(w http.ResponseWriter, r *http.Request) (entity.ID, bool) {
+	subscriptionId, err := entity.StringToID(chi.URLParam(r, "id"))
+	if err != nil {
+		presenter.WriteProblem(w, r, http.StatusNotFound, "subscription_not_found", "Subscription not found")
+		return subscriptionId, false
+	}
+	return subscriptionId, true
+}
+
+func registerSubscription(service webhook.UseCase, validate *validator.Validate, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to register the subscription"
+		input := &webhook.RegisterSubscriptionDtoInput{}
+		if !decodeBody(w, r, validate, input, logger) {
+			return
+		}
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		input.Store = storeId
+
+		subscription, err := service.RegisterSubscription(*input)
+		if err != nil {
+			writeWebhookError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(toSubscriptionPresenter(*subscription)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listSubscriptions(service webhook.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the subscriptions"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+
+		subscriptions, err := service.ListSubscriptions(storeId)
+		if err != nil {
+			writeWebhookError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []presenter.WebhookSubscription{}
+		for _, s := range subscriptions {
+			output = append(output, toSubscriptionPresenter(s))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func disableSubscription(service webhook.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to disable the subscription"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		subscriptionId, ok := subscriptionIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DisableSubscription(storeId, subscriptionId); err != nil {
+			writeWebhookError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testSubscription(service webhook.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to test the subscription"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		subscriptionId, ok := subscriptionIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		delivery, err := service.TestSubscription(storeId, subscriptionId)
+		if err != nil {
+			writeWebhookError(w, r, err, errorMessage)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toDeliveryPresenter(*delivery)); err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+	}
+}
+
+func listDeliveries(service webhook.UseCase, logger metrics.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		errorMessage := "Error to get the deliveries"
+
+		storeId, err := storeIDFromCtx(r, logger)
+		if err != nil {
+			writeError(w, r, err, errorMessage)
+			return
+		}
+		subscriptionId, ok := subscriptionIDFromURL(w, r)
+		if !ok {
+			return
+		}
+
+		deliveries, err := service.ListDeliveries(storeId, subscriptionId)
+		if err != nil {
+			writeWebhookError(w, r, err, errorMessage)
+			return
+		}
+
+		output := []presenter.WebhookDelivery{}
+		for _, d := range deliveries {
+			output = append(output, toDeliveryPresenter(d))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			logger.Error("Fail to encode the response", err)
+		}
+	}
+}
+
+func MakeWebhookHandlers(r chi.Router, service webhook.UseCase, validate *validator.Validate, logger metrics.Logger) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Post("/", registerSubscription(service, validate, logger))
+		r.Get("/", listSubscriptions(service, logger))
+		r.Delete("/{id}", disableSubscription(service, logger))
+		r.Post("/{id}/test", testSubscription(service, logger))
+		r.Get("/{id}/deliveries", listDeliveries(service, logger))
+	})
+}
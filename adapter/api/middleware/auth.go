@@ -60,9 +60,10 @@ func EnsureValidToken(logger metrics.Logger) func(next http.Handler) http.Handle
 	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
 		logger.Warn("Encountered error while validating JWT", zap.NamedError("reason", err))
 
-		w.Header().Set("Content-Type", "application/json")
+		// Same problem document as the errors of the handlers
+		w.Header().Set("Content-Type", "application/problem+json")
 		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"message":"Failed to validate JWT."}`))
+		w.Write([]byte(`{"type":"about:blank","title":"Unauthorized","status":401,"detail":"Failed to validate JWT.","code":"unauthorized"}`))
 	}
 	middleware := jwtmiddleware.New(
 		jwtValidator.ValidateToken,
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/contexttools"
+	"github.com/Vractos/kloni/pkg/idempotency"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// Set on the responses that were recorded by a previous request with the key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// The stock files are the largest bodies of the API, up to 10 MB
+const maxIdempotentBodySize = 12 << 20
+
+// Headers of the response that are recorded with its status and body
+var recordedHeaders = []string{"Content-Type", "Location"}
+
+// IdempotencyKeys makes the mutating requests sent with an Idempotency-Key header idempotent.
+// The response of the first request of a key is recorded for ttl, and the same request sent
+// again with the key gets it back instead of running again, e.g. a double-clicked clone
+// doesn't publish the listings twice.
+//
+// The keys are scoped by the store, so it runs after AddStoreIDToCtx. While the first request
+// runs, the key is locked for at most lockTTL and the other requests with it are rejected,
+// as are the requests that reuse a key with another method, URI or body. The server errors
+// aren't recorded, so the request can be retried with the same key.
+func IdempotencyKeys(store idempotency.Store, lockTTL, ttl time.Duration, logger metrics.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !idempotency.ValidKey(key) {
+				presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_idempotency_key",
+					fmt.Sprintf("The %s must have from 1 to %d printable ASCII characters", IdempotencyKeyHeader, idempotency.MaxKeyLength))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+			if err != nil {
+				logger.Warn("Error to read the body", zap.Error(err))
+				presenter.WriteProblem(w, r, http.StatusBadRequest, "invalid_body", "Error to read the body")
+				return
+			}
+			if len(body) > maxIdempotentBodySize {
+				presenter.WriteProblem(w, r, http.StatusRequestEntityTooLarge, "body_too_large", "The body is too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			storeId, _ := contexttools.RetrieveStoreIDFromCtx(r.Context())
+			scopedKey := storeId + ":" + key
+			fingerprint := idempotency.Fingerprint(r.Method, r.URL.RequestURI(), body)
+
+			record, err := store.Reserve(r.Context(), scopedKey, fingerprint, lockTTL)
+			if err != nil {
+				logger.Error("Fail to reserve the idempotency key", err, zap.String("store_id", storeId))
+				presenter.WriteProblem(w, r, http.StatusServiceUnavailable, "idempotency_unavailable", "The idempotency keys are unavailable, retry later")
+				return
+			}
+			if record != nil {
+				switch {
+				case record.Fingerprint != fingerprint:
+					presenter.WriteProblem(w, r, http.StatusUnprocessableEntity, "idempotency_key_reused", "The key was already used by another request")
+				case !record.Completed():
+					w.Header().Set("Retry-After", "1")
+					presenter.WriteProblem(w, r, http.StatusConflict, "idempotency_key_in_use", "The request with the key is still running")
+				default:
+					replay(w, record)
+				}
+				return
+			}
+
+			// The request can outlive the client, the response is recorded anyway
+			ctx := context.Background()
+			recorded := false
+			defer func() {
+				if recorded {
+					return
+				}
+				if err := store.Release(ctx, scopedKey); err != nil {
+					logger.Error("Fail to release the idempotency key", err, zap.String("store_id", storeId))
+				}
+			}()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			response := &bytes.Buffer{}
+			ww.Tee(response)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			record = &idempotency.Record{
+				Fingerprint: fingerprint,
+				Status:      status,
+				Header:      http.Header{},
+				Body:        response.Bytes(),
+			}
+			for _, name := range recordedHeaders {
+				if values := ww.Header().Values(name); len(values) > 0 {
+					record.Header[name] = values
+				}
+			}
+			// When the response can't be recorded, the key stays locked until lockTTL,
+			// so the request doesn't run twice
+			recorded = true
+			if err := store.Complete(ctx, scopedKey, record, ttl); err != nil {
+				logger.Error("Fail to record the response of the idempotency key", err, zap.String("store_id", storeId))
+			}
+		})
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// replay writes the recorded response
+func replay(w http.ResponseWriter, record *idempotency.Record) {
+	for name, values := range record.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vractos/kloni/pkg/contexttools"
+	"github.com/Vractos/kloni/pkg/idempotency"
+	"github.com/Vractos/kloni/pkg/metrics"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+	err     error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[string]*idempotency.Record{}}
+}
+
+func (s *memoryStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if record, ok := s.records[key]; ok {
+		return record, nil
+	}
+	s.records[key] = &idempotency.Record{Fingerprint: fingerprint}
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, key string, record *idempotency.Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// cloneHandler counts the clones and responds with the status
+func cloneHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Not-Recorded", "1")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"clone":%d,"body":%q}`, *calls, body)
+	})
+}
+
+func send(h http.Handler, storeId, method, path, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), contexttools.ContextKeyStoreId, storeId))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	logger := *metrics.NewLogger("fatal")
+	body := `{"root_id":"MLB1","titles":["A"]}`
+
+	t.Run("replays the response of the key", func(t *testing.T) {
+		calls := 0
+		h := IdempotencyKeys(newMemoryStore(), time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusCreated))
+
+		first := send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		second := send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+
+		if calls != 1 {
+			t.Fatalf("the handler ran %d times, want 1", calls)
+		}
+		if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+			t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+		}
+		if second.Header().Get(IdempotentReplayedHeader) != "true" || first.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("only the replay must have the %s header", IdempotentReplayedHeader)
+		}
+		if second.Header().Get("Content-Type") != "application/json" || second.Header().Get("X-Not-Recorded") != "" {
+			t.Errorf("replay headers = %v, want only the recorded ones", second.Header())
+		}
+	})
+
+	t.Run("keys are scoped by the store", func(t *testing.T) {
+		calls := 0
+		h := IdempotencyKeys(newMemoryStore(), time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusCreated))
+
+		send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		send(h, "store-2", http.MethodPost, "/announcement", "key-1", body)
+		if calls != 2 {
+			t.Errorf("the handler ran %d times, want once for each store", calls)
+		}
+	})
+
+	t.Run("key reused by another request", func(t *testing.T) {
+		calls := 0
+		h := IdempotencyKeys(newMemoryStore(), time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusCreated))
+
+		send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		rec := send(h, "store-1", http.MethodPost, "/announcement", "key-1", `{"root_id":"MLB2"}`)
+		if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "idempotency_key_reused") {
+			t.Errorf("got %d %s, want 422 idempotency_key_reused", rec.Code, rec.Body)
+		}
+		if calls != 1 {
+			t.Errorf("the handler ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("request with the key still running", func(t *testing.T) {
+		store := newMemoryStore()
+		started, finish := make(chan struct{}), make(chan struct{})
+		h := IdempotencyKeys(store, time.Minute, time.Hour, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-finish
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		done := make(chan struct{})
+		go func() {
+			send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+			close(done)
+		}()
+		<-started
+		rec := send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		close(finish)
+		<-done
+
+		if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") == "" {
+			t.Errorf("got %d, want 409 with Retry-After", rec.Code)
+		}
+	})
+
+	t.Run("server errors aren't recorded", func(t *testing.T) {
+		calls := 0
+		h := IdempotencyKeys(newMemoryStore(), time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusServiceUnavailable))
+
+		send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		if calls != 2 {
+			t.Errorf("the handler ran %d times, want the retry to run again", calls)
+		}
+	})
+
+	t.Run("key released when the handler panics", func(t *testing.T) {
+		store := newMemoryStore()
+		h := IdempotencyKeys(store, time.Minute, time.Hour, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("clone failed")
+		}))
+
+		func() {
+			defer func() { recover() }()
+			send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		}()
+		if len(store.records) != 0 {
+			t.Errorf("records = %v, want the key released", store.records)
+		}
+	})
+
+	t.Run("requests without a key or safe aren't recorded", func(t *testing.T) {
+		calls := 0
+		store := newMemoryStore()
+		h := IdempotencyKeys(store, time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusOK))
+
+		send(h, "store-1", http.MethodPost, "/announcement", "", body)
+		send(h, "store-1", http.MethodPost, "/announcement", "", body)
+		send(h, "store-1", http.MethodGet, "/announcement/SKU1", "key-1", "")
+		send(h, "store-1", http.MethodGet, "/announcement/SKU1", "key-1", "")
+		if calls != 4 || len(store.records) != 0 {
+			t.Errorf("the handler ran %d times with %d records, want 4 without records", calls, len(store.records))
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		calls := 0
+		h := IdempotencyKeys(newMemoryStore(), time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusCreated))
+
+		rec := send(h, "store-1", http.MethodPost, "/announcement", strings.Repeat("k", idempotency.MaxKeyLength+1), body)
+		if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != "application/problem+json" || calls != 0 {
+			t.Errorf("got %d %s, want a 400 problem without running the handler", rec.Code, rec.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("store unavailable", func(t *testing.T) {
+		calls := 0
+		store := newMemoryStore()
+		store.err = errors.New("connection refused")
+		h := IdempotencyKeys(store, time.Minute, time.Hour, logger)(cloneHandler(&calls, http.StatusCreated))
+
+		rec := send(h, "store-1", http.MethodPost, "/announcement", "key-1", body)
+		if rec.Code != http.StatusServiceUnavailable || calls != 0 {
+			t.Errorf("got %d, want 503 without running the handler", rec.Code)
+		}
+	})
+}
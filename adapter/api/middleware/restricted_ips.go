@@ -1,3 +1,117 @@
 package middleware
 
-// TODO Implement a middleware that create a whitelist of IPs to specific routes (e.g routes that listing webhooks)
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// ParseCIDRs parses a comma separated list of CIDRs. Plain IPs are accepted as
+// single host networks.
+func ParseCIDRs(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP of the client that made the request.
+//
+// The X-Forwarded-For and X-Real-IP headers are only taken into account when the
+// request comes from a trusted proxy, otherwise anyone could spoof them. The
+// X-Forwarded-For is read from right to left, skipping the trusted proxies, so the
+// first untrusted address is the client.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote := net.ParseIP(host)
+	if remote == nil || !contains(trustedProxies, remote) {
+		return remote
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// A malformed hop can't be trusted, neither anything on its left
+				return remote
+			}
+			if !contains(trustedProxies, ip) {
+				return ip
+			}
+		}
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP
+	}
+
+	return remote
+}
+
+// RestrictIPs only lets through requests whose client IP belongs to one of the
+// allowed networks, e.g. the IPs that Mercado Livre sends the notifications from.
+//
+// An empty allowlist lets every request through.
+func RestrictIPs(allowed, trustedProxies []*net.IPNet, logger metrics.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r, trustedProxies)
+			if ip == nil || !contains(allowed, ip) {
+				logger.Warn(
+					"Request from a forbidden IP",
+					zap.String("ip", ip.String()),
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.String("path", r.URL.Path),
+				)
+				presenter.WriteProblem(w, r, http.StatusForbidden, codeForbiddenSource, "The requests from the IP aren't accepted")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vractos/kloni/pkg/metrics"
+)
+
+func mustParseCIDRs(t *testing.T, list string) []*net.IPNet {
+	t.Helper()
+	networks, err := ParseCIDRs(list)
+	if err != nil {
+		t.Fatalf("ParseCIDRs(%q) error = %v", list, err)
+	}
+	return networks
+}
+
+func TestParseCIDRs(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", list: "", want: []string{}},
+		{name: "networks", list: "10.0.0.0/8, 2001:db8::/32", want: []string{"10.0.0.0/8", "2001:db8::/32"}},
+		{name: "plain IPs", list: "54.88.218.97,::1", want: []string{"54.88.218.97/32", "::1/128"}},
+		{name: "blank entries", list: " ,10.0.0.1,, ", want: []string{"10.0.0.1/32"}},
+		{name: "network with host bits", list: "10.1.2.3/8", want: []string{"10.0.0.0/8"}},
+		{name: "malformed IP", list: "10.0.0.1,not-an-ip", wantErr: true},
+		{name: "malformed CIDR", list: "10.0.0.0/33", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := ParseCIDRs(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCIDRs(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(networks) != len(tt.want) {
+				t.Fatalf("ParseCIDRs(%q) = %v, want %v", tt.list, networks, tt.want)
+			}
+			for i, network := range networks {
+				if network.String() != tt.want[i] {
+					t.Errorf("ParseCIDRs(%q)[%d] = %s, want %s", tt.list, i, network, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := mustParseCIDRs(t, "10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{
+			name:       "direct request",
+			remoteAddr: "54.88.218.97:4321",
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "forwarded headers of an untrusted remote are ignored",
+			remoteAddr: "54.88.218.97:4321",
+			forwarded:  []string{"1.2.3.4"},
+			realIP:     "5.6.7.8",
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "client forwarded by a trusted proxy",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"54.88.218.97"},
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "chain of trusted proxies",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"54.88.218.97, 10.0.0.3, 10.0.0.2"},
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "spoofed hops on the left of the client are ignored",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"1.2.3.4, 54.88.218.97, 10.0.0.2"},
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "chain split across headers",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"1.2.3.4, 54.88.218.97", "10.0.0.2"},
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "malformed hop",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"54.88.218.97, garbage, 10.0.0.2"},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "only trusted hops falls back to the real IP",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  []string{"10.0.0.2"},
+			realIP:     "54.88.218.97",
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "real IP of a trusted proxy",
+			remoteAddr: "10.0.0.1:4321",
+			realIP:     "54.88.218.97",
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.1:4321",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "remote without a port",
+			remoteAddr: "54.88.218.97",
+			want:       "54.88.218.97",
+		},
+		{
+			name:       "ipv6 remote",
+			remoteAddr: "[2001:db8::1]:4321",
+			want:       "2001:db8::1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/webhook/order", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := ClientIP(r, trusted); got.String() != tt.want {
+				t.Errorf("ClientIP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("malformed remote", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/webhook/order", nil)
+		r.RemoteAddr = "garbage"
+		if got := ClientIP(r, trusted); got != nil {
+			t.Errorf("ClientIP() = %s, want nil", got)
+		}
+	})
+}
+
+func TestRestrictIPs(t *testing.T) {
+	logger := *metrics.NewLogger("fatal")
+	allowed := mustParseCIDRs(t, "54.88.218.97,18.215.140.160/31")
+	trusted := mustParseCIDRs(t, "10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		allowed    []*net.IPNet
+		remoteAddr string
+		forwarded  string
+		want       int
+	}{
+		{name: "allowed IP", allowed: allowed, remoteAddr: "54.88.218.97:4321", want: http.StatusOK},
+		{name: "allowed network", allowed: allowed, remoteAddr: "18.215.140.161:4321", want: http.StatusOK},
+		{name: "forbidden IP", allowed: allowed, remoteAddr: "1.2.3.4:4321", want: http.StatusForbidden},
+		{name: "allowed IP behind a trusted proxy", allowed: allowed, remoteAddr: "10.0.0.1:4321", forwarded: "54.88.218.97", want: http.StatusOK},
+		{name: "spoofed allowed IP", allowed: allowed, remoteAddr: "1.2.3.4:4321", forwarded: "54.88.218.97", want: http.StatusForbidden},
+		{name: "malformed remote", allowed: allowed, remoteAddr: "garbage", want: http.StatusForbidden},
+		{name: "empty allowlist", allowed: nil, remoteAddr: "1.2.3.4:4321", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RestrictIPs(tt.allowed, trusted, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/webhook/order", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusForbidden {
+				assertProblem(t, rec, "forbidden_source")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Mercado Livre notifications are small, anything bigger isn't one
+const maxNotificationSize = 64 << 10
+
+// Codes of the problems of the rejected notifications
+const (
+	codeForbiddenSource     = "forbidden_source"
+	codeInvalidNotification = "invalid_notification"
+)
+
+// AccountChecker checks if a Mercado Livre user is linked to a store
+type AccountChecker interface {
+	IsMeliUserLinked(userId string) (bool, error)
+}
+
+// VerifyMeliNotification rejects notifications that weren't sent to our
+// application or that belong to a Mercado Livre user that isn't linked.
+// A notification without an application is rejected too.
+//
+// The body is restored, so the handler can decode it again.
+func VerifyMeliNotification(applicationId string, accounts AccountChecker, logger metrics.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxNotificationSize+1))
+			if err != nil {
+				logger.Error("Error to read the notification body", err)
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidNotification, "Error to read the notification")
+				return
+			}
+			if len(body) > maxNotificationSize {
+				logger.Warn("Notification body is too large", zap.String("path", r.URL.Path))
+				presenter.WriteProblem(w, r, http.StatusRequestEntityTooLarge, "body_too_large", "The notification is too large")
+				return
+			}
+
+			notification := struct {
+				UserID        json.Number `json:"user_id"`
+				ApplicationID json.Number `json:"application_id"`
+			}{}
+			if err := json.Unmarshal(body, &notification); err != nil {
+				logger.Warn("Malformed notification", zap.Error(err))
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidNotification, "The notification must be a valid JSON document")
+				return
+			}
+
+			if appId := notification.ApplicationID.String(); appId == "" || appId != applicationId {
+				logger.Warn(
+					"Notification sent to another application",
+					zap.String("application_id", appId),
+				)
+				presenter.WriteProblem(w, r, http.StatusForbidden, codeForbiddenSource, "The notification was sent to another application")
+				return
+			}
+
+			userId := notification.UserID.String()
+			if _, err := strconv.ParseInt(userId, 10, 64); err != nil {
+				logger.Warn("Notification without a valid user", zap.String("user_id", userId))
+				presenter.WriteProblem(w, r, http.StatusBadRequest, codeInvalidNotification, "The notification doesn't have a valid user")
+				return
+			}
+
+			linked, err := accounts.IsMeliUserLinked(userId)
+			if err != nil {
+				logger.Error("Fail to check if the meli user is linked", err, zap.String("user_id", userId))
+				presenter.WriteProblem(w, r, http.StatusInternalServerError, "internal_error", "Fail to verify the notification")
+				return
+			}
+			if !linked {
+				logger.Warn("Notification from a meli user that isn't linked", zap.String("user_id", userId))
+				presenter.WriteProblem(w, r, http.StatusForbidden, codeForbiddenSource, "The notification belongs to a user that isn't linked")
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/pkg/metrics"
+)
+
+// linkedUsers checks the users against a fixed set
+type linkedUsers struct {
+	users map[string]bool
+	err   error
+}
+
+func (l linkedUsers) IsMeliUserLinked(userId string) (bool, error) {
+	return l.users[userId], l.err
+}
+
+// assertProblem checks that the response is a problem with the code
+func assertProblem(t *testing.T, rec *httptest.ResponseRecorder, code string) {
+	t.Helper()
+	var problem presenter.Problem
+	if rec.Header().Get("Content-Type") != "application/problem+json" || json.NewDecoder(rec.Body).Decode(&problem) != nil {
+		t.Fatalf("response isn't a problem: %s", rec.Body.String())
+	}
+	if problem.Code != code || problem.Status != rec.Code {
+		t.Errorf("problem = %+v, want the code %s", problem, code)
+	}
+}
+
+func TestVerifyMeliNotification(t *testing.T) {
+	logger := *metrics.NewLogger("fatal")
+	accounts := linkedUsers{users: map[string]bool{"123456": true}}
+
+	tests := []struct {
+		name          string
+		applicationId string
+		accounts      AccountChecker
+		body          string
+		want          int
+		wantCode      string
+	}{
+		{
+			name:          "notification of a linked user",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"resource":"/orders/1","user_id":123456,"topic":"orders_v2","application_id":987}`,
+			want:          http.StatusOK,
+		},
+		{
+			name:          "another application",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":123456,"application_id":111}`,
+			want:          http.StatusForbidden,
+			wantCode:      "forbidden_source",
+		},
+		{
+			name:          "missing application",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":123456}`,
+			want:          http.StatusForbidden,
+			wantCode:      "forbidden_source",
+		},
+		{
+			name:          "missing application without a configured one",
+			applicationId: "",
+			accounts:      accounts,
+			body:          `{"user_id":123456}`,
+			want:          http.StatusForbidden,
+			wantCode:      "forbidden_source",
+		},
+		{
+			name:          "unlinked user",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":654321,"application_id":987}`,
+			want:          http.StatusForbidden,
+			wantCode:      "forbidden_source",
+		},
+		{
+			name:          "missing user",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"application_id":987}`,
+			want:          http.StatusBadRequest,
+			wantCode:      "invalid_notification",
+		},
+		{
+			name:          "malformed user",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":"12ab","application_id":987}`,
+			want:          http.StatusBadRequest,
+			wantCode:      "invalid_notification",
+		},
+		{
+			name:          "malformed body",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":`,
+			want:          http.StatusBadRequest,
+			wantCode:      "invalid_notification",
+		},
+		{
+			name:          "body too large",
+			applicationId: "987",
+			accounts:      accounts,
+			body:          `{"user_id":123456,"application_id":987,"padding":"` + strings.Repeat("a", maxNotificationSize) + `"}`,
+			want:          http.StatusRequestEntityTooLarge,
+			wantCode:      "body_too_large",
+		},
+		{
+			name:          "accounts unavailable",
+			applicationId: "987",
+			accounts:      linkedUsers{err: errors.New("connection refused")},
+			body:          `{"user_id":123456,"application_id":987}`,
+			want:          http.StatusInternalServerError,
+			wantCode:      "internal_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			h := VerifyMeliNotification(tt.applicationId, tt.accounts, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/order", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.wantCode != "" {
+				assertProblem(t, rec, tt.wantCode)
+			}
+			// The handler decodes the same body
+			if tt.want == http.StatusOK && received != tt.body {
+				t.Errorf("handler received %q, want %q", received, tt.body)
+			}
+		})
+	}
+}
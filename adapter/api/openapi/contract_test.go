@@ -0,0 +1,454 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vractos/kloni/adapter/api/handler"
+	"github.com/Vractos/kloni/adapter/api/openapi"
+	"github.com/Vractos/kloni/adapter/api/presenter"
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/health"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/Vractos/kloni/usecases/alias"
+	"github.com/Vractos/kloni/usecases/allocation"
+	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/kit"
+	"github.com/Vractos/kloni/usecases/order"
+	"github.com/Vractos/kloni/usecases/stock"
+	"github.com/Vractos/kloni/usecases/store"
+	"github.com/Vractos/kloni/usecases/webhook"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// Types of the bodies of the API, each one is described by the schema whose x-go-type is its name
+var goTypes = []interface{}{
+	presenter.Problem{},
+	presenter.FieldError{},
+	presenter.Health{},
+	presenter.HealthComponent{},
+	presenter.Store{},
+	presenter.Account{},
+	presenter.MeliAuthorization{},
+	presenter.Announcement{},
+	presenter.OrderItem{},
+	presenter.SyncAction{},
+	presenter.SyncProgress{},
+	presenter.Order{},
+	presenter.OrderPage{},
+	presenter.ListingAdjustment{},
+	presenter.StockAdjustment{},
+	presenter.StockImportRow{},
+	presenter.StockImport{},
+	presenter.KitComponent{},
+	presenter.Kit{},
+	presenter.SkuAlias{},
+	presenter.SkuMapping{},
+	presenter.SkuSuggestion{},
+	presenter.AccountAllocation{},
+	presenter.AllocationPolicy{},
+	presenter.AlertSettings{},
+	presenter.StockThreshold{},
+	presenter.StockAlert{},
+	presenter.SalesBucket{},
+	presenter.SalesReport{},
+	presenter.WebhookSubscription{},
+	presenter.WebhookDelivery{},
+	entity.EventType(""),
+	store.RegisterStoreDtoInput{},
+	store.RenameAccountDtoInput{},
+	announcement.CloneAnnouncementDtoInput{},
+	announcement.ImportAnnouncementDtoInput{},
+	order.OrderWebhookDtoInput{},
+	stock.AdjustStockDtoInput{},
+	kit.KitComponentDtoInput{},
+	kit.CreateKitDtoInput{},
+	kit.UpdateKitDtoInput{},
+	alias.SkuAliasDtoInput{},
+	alias.CreateMappingDtoInput{},
+	alias.UpdateMappingDtoInput{},
+	allocation.AccountAllocationDtoInput{},
+	allocation.CreatePolicyDtoInput{},
+	allocation.UpdatePolicyDtoInput{},
+	alert.UpdateSettingsDtoInput{},
+	alert.SetThresholdDtoInput{},
+	webhook.RegisterSubscriptionDtoInput{},
+}
+
+var methods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+func loadSpec(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var spec map[string]interface{}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatalf("the spec isn't valid JSON: %v", err)
+	}
+	return spec
+}
+
+// newRouter registers all the handlers as main does, the services aren't called
+func newRouter(t *testing.T) chi.Router {
+	t.Helper()
+	// The auth middleware only needs the configuration, the keys are fetched on the first request
+	t.Setenv("AUTH0_DOMAIN", "example.com")
+	t.Setenv("AUTH0_AUDIENCE", "kloni")
+
+	logger := *metrics.NewLogger("fatal")
+	validate := validator.New()
+	r := chi.NewRouter()
+	handler.MakeStoreHandlers(r, nil, "", nil, validate, logger)
+	handler.MakeOrderHandlers(r, nil, nil, nil, validate, logger)
+	handler.MakeAnnouncementHandlers(r, nil, nil, validate, logger)
+	handler.MakeAnalyticsHandlers(r, nil, logger)
+	handler.MakeAlertHandlers(r, nil, validate, logger)
+	handler.MakeWebhookHandlers(r, nil, validate, logger)
+	handler.MakeStockHandlers(r, nil, validate, logger)
+	handler.MakeKitHandlers(r, nil, validate, logger)
+	handler.MakeAliasHandlers(r, nil, validate, logger)
+	handler.MakeAllocationHandlers(r, nil, validate, logger)
+	handler.MakeHealthHandlers(r, health.NewChecker(time.Second), health.NewChecker(time.Second), logger)
+	handler.MakeDocsHandlers(r)
+	return r
+}
+
+// normalizePath removes the trailing slash of the routes at the root of a subrouter, e.g. /kits/
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	spec := loadSpec(t)
+
+	documented := map[string]bool{}
+	for path, item := range spec["paths"].(map[string]interface{}) {
+		for _, method := range methods {
+			if _, ok := item.(map[string]interface{})[method]; ok {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	routed := map[string]bool{}
+	err := chi.Walk(newRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routed[method+" "+normalizePath(route)] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	for _, route := range sortedKeys(routed) {
+		if !documented[route] {
+			t.Errorf("%s is routed but isn't in the spec", route)
+		}
+	}
+	for _, route := range sortedKeys(documented) {
+		if !routed[route] {
+			t.Errorf("%s is in the spec but isn't routed", route)
+		}
+	}
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func TestSpecPathParameters(t *testing.T) {
+	spec := loadSpec(t)
+
+	for path, item := range spec["paths"].(map[string]interface{}) {
+		item := item.(map[string]interface{})
+		want := map[string]bool{}
+		for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+			want[match[1]] = true
+		}
+
+		for _, method := range methods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			got := map[string]bool{}
+			for _, parameters := range []interface{}{item["parameters"], operation["parameters"]} {
+				list, _ := parameters.([]interface{})
+				for _, p := range list {
+					p := resolve(t, spec, p.(map[string]interface{}))
+					if p["in"] == "path" {
+						got[p["name"].(string)] = true
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %s has the path parameters %v, want %v", strings.ToUpper(method), path, sortedKeys(got), sortedKeys(want))
+			}
+		}
+	}
+}
+
+func TestSpecReferences(t *testing.T) {
+	spec := loadSpec(t)
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := node["$ref"].(string); ok {
+				if _, err := lookup(spec, ref); err != nil {
+					t.Error(err)
+				}
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+}
+
+func TestSpecSchemasMatchGoTypes(t *testing.T) {
+	spec := loadSpec(t)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	types := map[string]reflect.Type{}
+	for _, v := range goTypes {
+		typ := reflect.TypeOf(v)
+		types[typ.String()] = typ
+	}
+
+	described := map[string]bool{}
+	for _, name := range sortedKeys(schemas) {
+		schema := schemas[name].(map[string]interface{})
+		goType, _ := schema["x-go-type"].(string)
+		typ, ok := types[goType]
+		if !ok {
+			t.Errorf("schema %s has the unknown x-go-type %q", name, goType)
+			continue
+		}
+		described[goType] = true
+
+		c := &schemaChecker{t: t, schemas: schemas, request: !strings.HasSuffix(typ.PkgPath(), "/presenter")}
+		c.compareType(name, schema, typ)
+	}
+
+	for _, goType := range sortedKeys(types) {
+		if !described[goType] {
+			t.Errorf("%s isn't described by a schema", goType)
+		}
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	r := newRouter(t)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("GET /docs/openapi.json = %d %s, want 200 application/json", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !bytes.Equal(rec.Body.Bytes(), openapi.Spec) {
+		t.Error("GET /docs/openapi.json doesn't return the spec")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/docs/openapi.json") {
+		t.Errorf("GET /docs = %d, want 200 with the page that loads the spec", rec.Code)
+	}
+}
+
+var (
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaChecker compares the schemas with the JSON encoding of the Go types.
+// The fields that are always encoded are required in the responses,
+// in the requests the fields validated as required are.
+type schemaChecker struct {
+	t       *testing.T
+	schemas map[string]interface{}
+	request bool
+}
+
+func (c *schemaChecker) compareType(path string, schema map[string]interface{}, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		target, _ := c.schemas[name].(map[string]interface{})
+		if goType, _ := target["x-go-type"].(string); goType != typ.String() {
+			c.t.Errorf("%s refers to %s, a schema of %q, want a schema of %s", path, name, goType, typ)
+		}
+		return
+	}
+
+	var want string
+	switch {
+	case reflect.PointerTo(typ).Implements(textMarshaler):
+		// IDs and times
+		want = "string"
+	case typ.Implements(jsonMarshaler):
+		// Raw JSON, any value
+		return
+	default:
+		switch typ.Kind() {
+		case reflect.String:
+			want = "string"
+		case reflect.Bool:
+			want = "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			want = "integer"
+		case reflect.Float32, reflect.Float64:
+			want = "number"
+		case reflect.Slice, reflect.Array:
+			want = "array"
+		case reflect.Map, reflect.Struct:
+			want = "object"
+		default:
+			c.t.Errorf("%s has the unsupported type %s", path, typ)
+			return
+		}
+	}
+	if got, _ := schema["type"].(string); got != want {
+		c.t.Errorf("%s has the type %q, want %q for %s", path, got, want, typ)
+		return
+	}
+
+	switch want {
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		c.compareType(path+"[]", items, typ.Elem())
+	case "object":
+		if typ.Kind() == reflect.Map {
+			values, _ := schema["additionalProperties"].(map[string]interface{})
+			c.compareType(path+"{}", values, typ.Elem())
+			return
+		}
+		c.compareFields(path, schema, typ)
+	}
+}
+
+func (c *schemaChecker) compareFields(path string, schema map[string]interface{}, typ reflect.Type) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	list, _ := schema["required"].([]interface{})
+	for _, name := range list {
+		required[name.(string)] = true
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := handler.JSONFieldName(field)
+		if name == "" {
+			continue
+		}
+		fields[name] = true
+		fieldPath := path + "." + name
+
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			c.t.Errorf("%s is missing from the spec", fieldPath)
+			continue
+		}
+		c.compareType(fieldPath, property, field.Type)
+
+		omitempty := strings.Contains(field.Tag.Get("json"), ",omitempty")
+		wantRequired := !omitempty
+		if c.request {
+			wantRequired = validatedAsRequired(field)
+		}
+		if required[name] != wantRequired {
+			c.t.Errorf("%s is required = %t in the spec, want %t", fieldPath, required[name], wantRequired)
+		}
+		if !c.request && field.Type.Kind() == reflect.Pointer && !omitempty && property["nullable"] != true {
+			c.t.Errorf("%s can be null, it must be nullable in the spec", fieldPath)
+		}
+	}
+
+	for _, name := range sortedKeys(properties) {
+		if !fields[name] {
+			c.t.Errorf("%s.%s is in the spec but isn't a field of %s", path, name, typ)
+		}
+	}
+	for _, name := range sortedKeys(required) {
+		if !fields[name] {
+			c.t.Errorf("%s.%s is required in the spec but isn't a field of %s", path, name, typ)
+		}
+	}
+}
+
+// validatedAsRequired reports whether the field itself is required, the rules after dive apply to its elements
+func validatedAsRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		switch rule {
+		case "dive":
+			return false
+		case "required":
+			return true
+		}
+	}
+	return false
+}
+
+// resolve follows the reference of a parameter
+func resolve(t *testing.T, spec map[string]interface{}, node map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	target, err := lookup(spec, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+// lookup finds the object of a local reference, e.g. #/components/schemas/Kit
+func lookup(spec map[string]interface{}, ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("%s isn't a local reference", ref)
+	}
+	node := spec
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := node[key].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s doesn't exist", ref)
+		}
+		node = next
+	}
+	return node, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,11 @@
+// Package openapi embeds the OpenAPI document of the HTTP API.
+// The contract test keeps it in line with the routes of the handlers and the
+// JSON fields of the presenters and DTOs, so update it with them.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document of the API, in JSON
+//
+//go:embed openapi.json
+var Spec []byte
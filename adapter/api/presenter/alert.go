@@ -0,0 +1,27 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type AlertSettings struct {
+	WebhookURL       string `json:"webhook_url"`
+	Email            string `json:"email"`
+	DefaultThreshold *int   `json:"default_threshold"`
+}
+
+type StockThreshold struct {
+	Sku       string `json:"sku"`
+	Threshold int    `json:"threshold"`
+}
+
+type StockAlert struct {
+	ID        entity.ID `json:"id"`
+	Sku       string    `json:"sku"`
+	Level     string    `json:"level"`
+	Quantity  int       `json:"quantity"`
+	Threshold int       `json:"threshold"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type SkuAlias struct {
+	AccountID  entity.ID `json:"account_id"`
+	Sku        string    `json:"sku,omitempty"`
+	ListingIDs []string  `json:"listing_ids"`
+}
+
+type SkuMapping struct {
+	ID        entity.ID  `json:"id"`
+	Sku       string     `json:"sku"`
+	Aliases   []SkuAlias `json:"aliases"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type SkuSuggestion struct {
+	Account struct {
+		ID   entity.ID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"account"`
+	AnnouncementID string  `json:"announcement_id"`
+	Title          string  `json:"title"`
+	Sku            string  `json:"sku"`
+	Reason         string  `json:"reason"`
+	Score          float64 `json:"score"`
+}
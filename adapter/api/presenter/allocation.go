@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type AccountAllocation struct {
+	AccountID   entity.ID `json:"account_id"`
+	Buffer      int       `json:"buffer"`
+	Percentage  int       `json:"percentage"`
+	MaxQuantity *int      `json:"max_quantity"`
+}
+
+type AllocationPolicy struct {
+	ID              entity.ID           `json:"id"`
+	Sku             string              `json:"sku"`
+	PriorityAccount *entity.ID          `json:"priority_account_id"`
+	Accounts        []AccountAllocation `json:"accounts"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
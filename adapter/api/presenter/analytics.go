@@ -0,0 +1,19 @@
+package presenter
+
+import "time"
+
+type SalesBucket struct {
+	Period     time.Time `json:"period"`
+	Key        string    `json:"key"`
+	Units      int       `json:"units"`
+	Revenue    float64   `json:"revenue"`
+	CurrencyID string    `json:"currency_id"`
+}
+
+type SalesReport struct {
+	Dimension string        `json:"dimension"`
+	Interval  string        `json:"interval"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Buckets   []SalesBucket `json:"buckets"`
+}
@@ -0,0 +1,12 @@
+package presenter
+
+type Health struct {
+	Status     string                     `json:"status"`
+	Components map[string]HealthComponent `json:"components"`
+}
+
+type HealthComponent struct {
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
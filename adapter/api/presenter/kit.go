@@ -0,0 +1,21 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type KitComponent struct {
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type Kit struct {
+	ID         entity.ID      `json:"id"`
+	Sku        string         `json:"sku"`
+	Title      string         `json:"title"`
+	Components []KitComponent `json:"components"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
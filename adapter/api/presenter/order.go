@@ -0,0 +1,69 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type OrderItem struct {
+	ID            entity.ID `json:"id"`
+	Title         string    `json:"title"`
+	Sku           string    `json:"sku"`
+	Quantity      int       `json:"quantity"`
+	VariationID   int       `json:"variation_id,omitempty"`
+	UnitPrice     float64   `json:"unit_price"`
+	CurrencyID    string    `json:"currency_id,omitempty"`
+	ListingTypeID string    `json:"listing_type_id,omitempty"`
+}
+
+type SyncAction struct {
+	ID             entity.ID  `json:"id"`
+	Kind           string     `json:"kind"`
+	AccountID      entity.ID  `json:"account_id"`
+	AnnouncementID string     `json:"announcement_id"`
+	VariationID    int        `json:"variation_id,omitempty"`
+	Sku            string     `json:"sku,omitempty"`
+	Quantity       int        `json:"quantity"`
+	Delta          int        `json:"delta,omitempty"`
+	Status         string     `json:"status"`
+	Error          string     `json:"error,omitempty"`
+	Attempts       int        `json:"attempts"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExecutedAt     *time.Time `json:"executed_at,omitempty"`
+}
+
+type SyncProgress struct {
+	Status     string `json:"status"`
+	Total      int    `json:"total"`
+	Done       int    `json:"done"`
+	Pending    int    `json:"pending"`
+	Failed     int    `json:"failed"`
+	Superseded int    `json:"superseded"`
+}
+
+type Order struct {
+	ID            entity.ID    `json:"id"`
+	AccountID     entity.ID    `json:"account_id"`
+	MarketplaceID string       `json:"marketplace_id"`
+	Status        string       `json:"status"`
+	DateCreated   time.Time    `json:"date_created"`
+	DateClosed    *time.Time   `json:"date_closed,omitempty"`
+	LastUpdated   *time.Time   `json:"last_updated,omitempty"`
+	TotalAmount   float64      `json:"total_amount"`
+	PaidAmount    float64      `json:"paid_amount"`
+	CurrencyID    string       `json:"currency_id,omitempty"`
+	PackID        string       `json:"pack_id,omitempty"`
+	PackSize      int          `json:"pack_size,omitempty"`
+	ShippingID    string       `json:"shipping_id,omitempty"`
+	BuyerNickname string       `json:"buyer_nickname,omitempty"`
+	Items         []OrderItem  `json:"items"`
+	SyncActions   []SyncAction `json:"sync_actions,omitempty"`
+	// Only on the detail of an order with sync actions
+	SyncProgress *SyncProgress `json:"sync_progress,omitempty"`
+}
+
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,45 @@
+package presenter
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is an error response in the format of RFC 7807 (application/problem+json).
+// Code is an extension member, it's stable, so the clients can rely on it instead of the detail.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a field of the request body that failed the validation
+type FieldError struct {
+	Field string `json:"field"`
+	// Rule that failed, e.g. required
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
+
+// WriteProblem writes an error response as a problem
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	WriteProblemDocument(w, &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	})
+}
+
+// WriteProblemDocument writes a problem with its extension members, e.g. the fields that failed the validation
+func WriteProblemDocument(w http.ResponseWriter, problem *Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,47 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type ListingAdjustment struct {
+	AccountID        entity.ID `json:"account_id"`
+	AccountName      string    `json:"account_name"`
+	AnnouncementID   string    `json:"announcement_id"`
+	VariationID      int       `json:"variation_id,omitempty"`
+	PreviousQuantity int       `json:"previous_quantity"`
+	Quantity         int       `json:"quantity"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+}
+
+type StockAdjustment struct {
+	Sku      string              `json:"sku"`
+	Stock    *int                `json:"stock,omitempty"`
+	Listings []ListingAdjustment `json:"listings"`
+}
+
+type StockImportRow struct {
+	Line     int    `json:"line"`
+	Sku      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Listings int    `json:"listings"`
+	Updated  int    `json:"updated"`
+	Failed   int    `json:"failed"`
+}
+
+type StockImport struct {
+	ID          entity.ID      `json:"id"`
+	FileName    string         `json:"file_name"`
+	Status      string         `json:"status"`
+	Counts      map[string]int `json:"counts"`
+	CreatedAt   time.Time      `json:"created_at"`
+	ConfirmedAt *time.Time     `json:"confirmed_at,omitempty"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+	// Not returned when the imports are listed
+	Rows []StockImportRow `json:"rows,omitempty"`
+}
@@ -1,6 +1,8 @@
 package presenter
 
 import (
+	"time"
+
 	"github.com/Vractos/kloni/entity"
 )
 
@@ -8,3 +10,20 @@ type Store struct {
 	ID          entity.ID `json:"id"`
 	ErroMessage string    `json:"error,omitempty"`
 }
+
+type Account struct {
+	ID                 entity.ID  `json:"id"`
+	Name               *string    `json:"name"`
+	UserID             string     `json:"meli_user_id"`
+	SiteID             string     `json:"site_id"`
+	Status             string     `json:"status"`
+	CreatedAt          time.Time  `json:"created_at"`
+	TokenUpdatedAt     time.Time  `json:"token_updated_at"`
+	TokenAgeSeconds    int64      `json:"token_age_seconds"`
+	LastRefreshError   *string    `json:"last_refresh_error,omitempty"`
+	LastRefreshErrorAt *time.Time `json:"last_refresh_error_at,omitempty"`
+}
+
+type MeliAuthorization struct {
+	URL string `json:"url"`
+}
@@ -0,0 +1,32 @@
+package presenter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type WebhookSubscription struct {
+	ID     entity.ID `json:"id"`
+	URL    string    `json:"url"`
+	Events []string  `json:"events"`
+	// Only returned when the subscription is registered
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             entity.ID       `json:"id"`
+	EventID        entity.ID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Vractos/kloni/pkg/idempotency"
+	"github.com/go-redis/redis/v8"
+)
+
+const idempotencyPrefix = "idempotency:"
+
+type IdempotencyRedis struct {
+	rdb *redis.Client
+}
+
+func NewIdempotencyRedis(rdb *redis.Client) *IdempotencyRedis {
+	return &IdempotencyRedis{
+		rdb: rdb,
+	}
+}
+
+// Reserve implements idempotency.Store
+func (c *IdempotencyRedis) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*idempotency.Record, error) {
+	data, err := json.Marshal(&idempotency.Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	// The record can expire between SETNX and GET, then the key is reserved again
+	for {
+		reserved, err := c.rdb.SetNX(ctx, idempotencyPrefix+key, data, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		value, err := c.rdb.Get(ctx, idempotencyPrefix+key).Bytes()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		record := &idempotency.Record{}
+		if err := json.Unmarshal(value, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+}
+
+// Complete implements idempotency.Store
+func (c *IdempotencyRedis) Complete(ctx context.Context, key string, record *idempotency.Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, idempotencyPrefix+key, data, ttl).Err()
+}
+
+// Release implements idempotency.Store
+func (c *IdempotencyRedis) Release(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, idempotencyPrefix+key).Err()
+}
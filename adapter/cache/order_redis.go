@@ -19,13 +19,13 @@ func NewOrderRedis(rdb *redis.Client) *OrderRedis {
 }
 
 // SetOrder implements order.Cache
-func (c *OrderRedis) SetOrder(o *entity.Order) error {
-	return c.rdb.Set(context.Background(), o.MarketplaceID, o.Status.String(), time.Hour*10).Err()
+func (c *OrderRedis) SetOrder(ctx context.Context, o *entity.Order) error {
+	return c.rdb.Set(ctx, o.MarketplaceID, o.Status.String(), time.Hour*10).Err()
 }
 
 // GetOrder implements order.Cache
-func (c *OrderRedis) GetOrder(orderId string) (*entity.OrderStatus, error) {
-	value, err := c.rdb.Get(context.Background(), orderId).Result()
+func (c *OrderRedis) GetOrder(ctx context.Context, orderId string) (*entity.OrderStatus, error) {
+	value, err := c.rdb.Get(ctx, orderId).Result()
 	if err == redis.Nil {
 		return nil, nil
 	} else if err != nil {
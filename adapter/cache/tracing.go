@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingHook starts a span for each command sent to Redis within a trace
+type TracingHook struct{}
+
+func NewTracingHook() *TracingHook {
+	return &TracingHook{}
+}
+
+// BeforeProcess implements redis.Hook
+func (h *TracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if !metrics.IsTraced(ctx) {
+		return ctx, nil
+	}
+	ctx, _ = metrics.StartSpan(ctx, "redis "+cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationKey.String(cmd.Name())),
+	)
+	return ctx, nil
+}
+
+// AfterProcess implements redis.Hook
+func (h *TracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endCommandSpan(ctx, cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline implements redis.Hook
+func (h *TracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !metrics.IsTraced(ctx) {
+		return ctx, nil
+	}
+	ctx, _ = metrics.StartSpan(ctx, "redis pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.Int("db.redis.commands", len(cmds))),
+	)
+	return ctx, nil
+}
+
+// AfterProcessPipeline implements redis.Hook
+func (h *TracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	endCommandSpan(ctx, err)
+	return nil
+}
+
+// endCommandSpan ends the span of a command, a missing key isn't an error
+func endCommandSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if err == redis.Nil {
+		err = nil
+	}
+	metrics.EndSpan(span, err)
+}
@@ -2,6 +2,7 @@ package mercadolivre
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"sync"
 
@@ -77,8 +80,59 @@ func (m *MercadoLivre) GetAnnouncementsIDsViaSKU(sku string, userId string, acce
 	return queryAnnouncementResult.Results, nil
 }
 
+func (m *MercadoLivre) SearchAnnouncementsIDs(query string, userId string, accessToken string) ([]string, error) {
+	urlPath := fmt.Sprintf("%s/users/%s/items/search?q=%s", m.Endpoint, userId, url.QueryEscape(query))
+
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	resp, err := m.HttpClient.Do(req)
+	if err != nil {
+		m.Logger.Error(
+			"Error to make a request to Mercado Livre",
+			err,
+			zap.String("query", query),
+			zap.String("user_id", userId),
+		)
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		searchError := &MeliError{}
+		if err := json.NewDecoder(resp.Body).Decode(searchError); err != nil {
+			m.Logger.Error(
+				"Error to decode response body",
+				err,
+			)
+			return nil, err
+		}
+		m.Logger.Warn(
+			"Couldn't search the announcements",
+			zap.String("query", query),
+			zap.String("meli_message", searchError.Message),
+			zap.String("meli_erro", searchError.Error),
+			zap.Int("status_code", resp.StatusCode),
+		)
+		return nil, errors.New("error to search announcements")
+	}
+
+	searchResult := &QueryAnnouncementViaSku{}
+	if err := json.NewDecoder(resp.Body).Decode(searchResult); err != nil {
+		return nil, err
+	}
+
+	return searchResult.Results, nil
+}
+
 func (m *MercadoLivre) GetAnnouncements(ids []string, accessToken string) (*[]common.MeliAnnouncement, error) {
-	urlPath := fmt.Sprintf("%s/items?ids=%s", m.Endpoint, strings.Join(ids, ","))
+	// The attributes of the variations carry their SELLER_SKU
+	urlPath := fmt.Sprintf("%s/items?ids=%s&include_attributes=all", m.Endpoint, strings.Join(ids, ","))
 
 	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
 	if err != nil {
@@ -144,24 +198,27 @@ func (m *MercadoLivre) GetAnnouncements(ids []string, accessToken string) (*[]co
 			}
 		}
 
-		var variations []struct {
-			ID                int
-			AvailableQuantity int
-		}
+		var variations []common.MeliVariation
 		for _, v := range a.Body.Variations {
-			{
-				variations = append(variations, struct {
-					ID                int
-					AvailableQuantity int
-				}{
-					ID:                v.ID,
-					AvailableQuantity: v.AvailableQuantity,
-				})
+			variation := common.MeliVariation{
+				ID:                v.ID,
+				AvailableQuantity: v.AvailableQuantity,
+			}
+			for _, attr := range v.Attributes {
+				if attr.ID == "SELLER_SKU" {
+					variation.Sku = attr.ValueName
+					break
+				}
 			}
+			for _, c := range v.AttributeCombinations {
+				variation.Attributes = append(variation.Attributes, common.MeliVariationAttribute{ID: c.ID, ValueName: c.ValueName})
+			}
+			variations = append(variations, variation)
 		}
 
 		meliAnnouncement[i] = common.MeliAnnouncement{
 			ID:           a.Body.ID,
+			SiteID:       a.Body.SiteID,
 			Title:        a.Body.Title,
 			Quantity:     a.Body.AvailableQuantity,
 			Price:        a.Body.Price,
@@ -176,7 +233,7 @@ func (m *MercadoLivre) GetAnnouncements(ids []string, accessToken string) (*[]co
 	return &meliAnnouncement, nil
 }
 
-func (m *MercadoLivre) UpdateQuantity(quantity int, announcementId, accessToken string, variationIDs ...int) error {
+func (m *MercadoLivre) UpdateQuantity(ctx context.Context, quantity int, announcementId, accessToken string, variationIDs ...int) error {
 	urlPath := fmt.Sprintf("%s/items/%s", m.Endpoint, announcementId)
 
 	var bodyRequest map[string]interface{}
@@ -209,7 +266,7 @@ func (m *MercadoLivre) UpdateQuantity(quantity int, announcementId, accessToken
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, urlPath, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, urlPath, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
@@ -225,6 +282,9 @@ func (m *MercadoLivre) UpdateQuantity(quantity int, announcementId, accessToken
 			zap.String("announcement_id", announcementId),
 			zap.String("path", "/"+urlPath),
 		)
+		if os.IsTimeout(err) {
+			return fmt.Errorf("%w: %v", common.ErrMeliTimeout, err)
+		}
 		return err
 	}
 
@@ -328,6 +388,7 @@ func (m *MercadoLivre) GetAnnouncement(id string, accessToken string) (*common.M
 
 	return &common.MeliAnnouncement{
 		ID:            aR.ID,
+		SiteID:        aR.SiteID,
 		Title:         aR.Title,
 		Quantity:      aR.AvailableQuantity,
 		Price:         aR.Price,
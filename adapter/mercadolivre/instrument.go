@@ -0,0 +1,69 @@
+package mercadolivre
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Vractos/kloni/pkg/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentedTransport records the latency and the errors of the requests to Mercado Livre.
+// The requests made within a trace get a client span.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	endpoint := endpointLabel(req.URL.Path)
+
+	var span trace.Span
+	if metrics.IsTraced(req.Context()) {
+		ctx, s := metrics.StartSpan(req.Context(), "meli "+req.Method+" "+endpoint,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(req)...),
+		)
+		span = s
+		defer span.End()
+		req = req.Clone(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveMeliRequest(req.Method, endpoint, status, time.Since(start))
+
+	if span != nil {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+			span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindClient))
+		}
+	}
+	return resp, err
+}
+
+// endpointLabel replaces the IDs of the path, so the requests to the same endpoint
+// are aggregated, e.g. /items/MLB123/description becomes /items/:id/description
+func endpointLabel(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -13,19 +13,24 @@ type MercadoLivre struct {
 	ClientSecret string
 	RedirectUrl  string
 	Endpoint     string
+	AuthEndpoint string
 	Validate     *validator.Validate
 	HttpClient   *http.Client
 	Logger       metrics.Logger
 }
 
-func NewMercadoLivre(clientId, clientSecret, redirectUrl, endpoint string, validator *validator.Validate, logger metrics.Logger) *MercadoLivre {
+func NewMercadoLivre(clientId, clientSecret, redirectUrl, endpoint, authEndpoint string, validator *validator.Validate, logger metrics.Logger) *MercadoLivre {
 	return &MercadoLivre{
 		ClientId:     clientId,
 		ClientSecret: clientSecret,
 		RedirectUrl:  redirectUrl,
 		Endpoint:     endpoint,
+		AuthEndpoint: authEndpoint,
 		Validate:     validator,
-		HttpClient:   &http.Client{Timeout: time.Second * 60},
-		Logger:       logger,
+		HttpClient: &http.Client{
+			Timeout:   time.Second * 60,
+			Transport: &instrumentedTransport{next: http.DefaultTransport},
+		},
+		Logger: logger,
 	}
 }
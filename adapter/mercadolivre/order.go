@@ -1,6 +1,7 @@
 package mercadolivre
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,10 +14,10 @@ import (
 )
 
 // FetchOrder implements common.MercadoLivre
-func (m *MercadoLivre) FetchOrder(orderId string, accessToken string) (*common.MeliOrder, error) {
+func (m *MercadoLivre) FetchOrder(ctx context.Context, orderId string, accessToken string) (*common.MeliOrder, error) {
 	urlPath := fmt.Sprintf("%s/orders/%s", m.Endpoint, orderId)
 
-	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,13 +83,92 @@ func (m *MercadoLivre) FetchOrder(orderId string, accessToken string) (*common.M
 		items[i].Quantity = o.Quantity
 		items[i].Sku = o.Item.SellerSku
 		items[i].VariationID = o.Item.VariationID
+		items[i].UnitPrice = o.UnitPrice
+		items[i].CurrencyID = o.CurrencyID
+		items[i].ListingTypeID = o.ListingTypeID
+		for _, attr := range o.Item.VariationAttributes {
+			items[i].VariationAttributes = append(items[i].VariationAttributes, common.MeliVariationAttribute{ID: attr.ID, ValueName: attr.ValueName})
+		}
+	}
+
+	meliOrder := &common.MeliOrder{
+		ID:            strconv.FormatUint(order.ID, 10),
+		DateCreated:   order.DateCreated,
+		DateClosed:    order.DateClosed,
+		LastUpdated:   order.LastUpdated,
+		Status:        common.OrderStatus(order.Status),
+		Items:         items,
+		TotalAmount:   order.TotalAmount,
+		PaidAmount:    order.PaidAmount,
+		CurrencyID:    order.CurrencyID,
+		BuyerNickname: order.Buyer.Nickname,
 	}
+	if order.PackID != 0 {
+		meliOrder.PackID = strconv.FormatInt(order.PackID, 10)
+	}
+	if order.Shipping.ID != 0 {
+		meliOrder.ShippingID = strconv.FormatInt(order.Shipping.ID, 10)
+	}
+
+	return meliOrder, nil
 
-	return &common.MeliOrder{
-		ID:          strconv.FormatUint(order.ID, 10),
-		DateCreated: order.DateCreated,
-		Status:      common.OrderStatus(order.Status),
-		Items:       items,
-	}, nil
+}
+
+// FetchPack implements common.MercadoLivre
+func (m *MercadoLivre) FetchPack(ctx context.Context, packId string, accessToken string) (*common.MeliPack, error) {
+	urlPath := fmt.Sprintf("%s/packs/%s", m.Endpoint, packId)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
 
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	resp, err := m.HttpClient.Do(req)
+	if err != nil {
+		m.Logger.Error(
+			"Error to make a request to Mercado Livre",
+			err,
+			zap.String("pack_id", packId),
+			zap.String("path", "/"+urlPath),
+		)
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		queryPackError := &MeliError{}
+		if err := json.NewDecoder(resp.Body).Decode(queryPackError); err != nil {
+			m.Logger.Error(
+				"Error to decode response body",
+				err,
+			)
+			return nil, err
+		}
+		m.Logger.Warn(
+			"Couldn't retrieve the pack",
+			zap.String("pack_id", packId),
+			zap.String("meli_message", queryPackError.Message),
+			zap.String("meli_erro", queryPackError.Error),
+			zap.Any("cause", queryPackError.Cause),
+			zap.Int("status_code", resp.StatusCode),
+		)
+		return nil, errors.New("error to fetch pack")
+	}
+
+	pack := &Pack{}
+	if err := json.NewDecoder(resp.Body).Decode(pack); err != nil {
+		return nil, err
+	}
+
+	meliPack := &common.MeliPack{
+		ID:       strconv.FormatUint(pack.ID, 10),
+		OrderIDs: make([]string, len(pack.Orders)),
+	}
+	for i, o := range pack.Orders {
+		meliPack.OrderIDs[i] = strconv.FormatUint(o.ID, 10)
+	}
+	return meliPack, nil
 }
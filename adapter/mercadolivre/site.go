@@ -0,0 +1,205 @@
+package mercadolivre
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/common"
+	"go.uber.org/zap"
+)
+
+// Authorization domain of each site
+var authEndpoints = map[entity.Site]string{
+	entity.Brazil:    "https://auth.mercadolivre.com.br",
+	entity.Argentina: "https://auth.mercadolibre.com.ar",
+	entity.Mexico:    "https://auth.mercadolibre.com.mx",
+	entity.Chile:     "https://auth.mercadolibre.cl",
+	entity.Colombia:  "https://auth.mercadolibre.com.co",
+	entity.Uruguay:   "https://auth.mercadolibre.com.uy",
+	entity.Peru:      "https://auth.mercadolibre.com.pe",
+}
+
+// authEndpoint returns the authorization domain of the site.
+// The configured endpoint is used when the site is empty or unknown.
+func (m *MercadoLivre) authEndpoint(siteId string) string {
+	if endpoint, ok := authEndpoints[entity.Site(siteId)]; ok {
+		return endpoint
+	}
+	if m.AuthEndpoint != "" {
+		return m.AuthEndpoint
+	}
+	return authEndpoints[entity.DefaultSite]
+}
+
+// GetUser implements common.MercadoLivre
+func (m *MercadoLivre) GetUser(accessToken string) (*common.MeliUser, error) {
+	urlPath := fmt.Sprintf("%s/users/me", m.Endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+	resp, err := m.HttpClient.Do(req)
+	if err != nil {
+		m.Logger.Error(
+			"Error to make a request to Mercado Livre",
+			err,
+			zap.String("path", "/"+urlPath),
+		)
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		getUserError := &MeliError{}
+		if err := json.NewDecoder(resp.Body).Decode(getUserError); err != nil {
+			m.Logger.Error(
+				"Error to decode response body",
+				err,
+			)
+			return nil, err
+		}
+		m.Logger.Warn(
+			"Couldn't retrieve the user",
+			zap.String("meli_message", getUserError.Message),
+			zap.String("meli_erro", getUserError.Error),
+			zap.Any("cause", getUserError.Cause),
+			zap.Int("status_code", resp.StatusCode),
+		)
+		return nil, errors.New("error to fetch user")
+	}
+
+	user := &User{}
+	if err := json.NewDecoder(resp.Body).Decode(user); err != nil {
+		return nil, err
+	}
+
+	return &common.MeliUser{
+		ID:        strconv.Itoa(user.ID),
+		Nickname:  user.Nickname,
+		SiteID:    user.SiteID,
+		CountryID: user.CountryID,
+	}, nil
+}
+
+// PredictCategory implements common.MercadoLivre
+func (m *MercadoLivre) PredictCategory(siteId, title string) (string, error) {
+	urlPath := fmt.Sprintf("%s/sites/%s/domain_discovery/search?limit=1&q=%s", m.Endpoint, siteId, url.QueryEscape(title))
+
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	resp, err := m.HttpClient.Do(req)
+	if err != nil {
+		m.Logger.Error(
+			"Error to make a request to Mercado Livre",
+			err,
+			zap.String("site_id", siteId),
+			zap.String("path", "/"+urlPath),
+		)
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		predictCategoryError := &MeliError{}
+		if err := json.NewDecoder(resp.Body).Decode(predictCategoryError); err != nil {
+			m.Logger.Error(
+				"Error to decode response body",
+				err,
+			)
+			return "", err
+		}
+		m.Logger.Warn(
+			"Couldn't predict the category",
+			zap.String("site_id", siteId),
+			zap.String("meli_message", predictCategoryError.Message),
+			zap.String("meli_erro", predictCategoryError.Error),
+			zap.Any("cause", predictCategoryError.Cause),
+			zap.Int("status_code", resp.StatusCode),
+		)
+		return "", errors.New("error to predict category")
+	}
+
+	domains := DomainDiscovery{}
+	if err := json.NewDecoder(resp.Body).Decode(&domains); err != nil {
+		return "", err
+	}
+	if len(domains) == 0 {
+		return "", errors.New("no category found")
+	}
+
+	return domains[0].CategoryID, nil
+}
+
+// Convert implements common.PriceConverter, using the conversion ratio published by Mercado Livre
+func (m *MercadoLivre) Convert(price float64, from, to string) (float64, error) {
+	if from == to {
+		return price, nil
+	}
+
+	urlPath := fmt.Sprintf("%s/currency_conversions/search?from=%s&to=%s", m.Endpoint, from, to)
+
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	resp, err := m.HttpClient.Do(req)
+	if err != nil {
+		m.Logger.Error(
+			"Error to make a request to Mercado Livre",
+			err,
+			zap.String("from", from),
+			zap.String("to", to),
+			zap.String("path", "/"+urlPath),
+		)
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conversionError := &MeliError{}
+		if err := json.NewDecoder(resp.Body).Decode(conversionError); err != nil {
+			m.Logger.Error(
+				"Error to decode response body",
+				err,
+			)
+			return 0, err
+		}
+		m.Logger.Warn(
+			"Couldn't retrieve the currency conversion",
+			zap.String("from", from),
+			zap.String("to", to),
+			zap.String("meli_message", conversionError.Message),
+			zap.String("meli_erro", conversionError.Error),
+			zap.Int("status_code", resp.StatusCode),
+		)
+		return 0, errors.New("error to fetch currency conversion")
+	}
+
+	conversion := &CurrencyConversion{}
+	if err := json.NewDecoder(resp.Body).Decode(conversion); err != nil {
+		return 0, err
+	}
+	if conversion.Ratio <= 0 {
+		return 0, errors.New("invalid currency conversion ratio")
+	}
+
+	return price * conversion.Ratio, nil
+}
@@ -6,15 +6,30 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
+	"github.com/Vractos/kloni/pkg/oauthstate"
 	"github.com/Vractos/kloni/usecases/common"
 	"go.uber.org/zap"
 )
 
+// AuthorizationURL implements common.MercadoLivre
+func (m *MercadoLivre) AuthorizationURL(siteId, state, codeVerifier string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", m.ClientId)
+	query.Set("redirect_uri", m.RedirectUrl)
+	query.Set("state", state)
+	query.Set("code_challenge", oauthstate.CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	return fmt.Sprintf("%s/authorization?%s", m.authEndpoint(siteId), query.Encode())
+}
+
 // RegisterCredential implements common.MercadoLivre
-func (m *MercadoLivre) RegisterCredential(code string) (*common.MeliCredential, error) {
+func (m *MercadoLivre) RegisterCredential(code, codeVerifier string) (*common.MeliCredential, error) {
 	urlPath := fmt.Sprintf("%s/oauth/token", m.Endpoint)
 	bodyRequest := map[string]interface{}{
 		"client_id":     m.ClientId,
@@ -23,6 +38,9 @@ func (m *MercadoLivre) RegisterCredential(code string) (*common.MeliCredential,
 		"redirect_uri":  m.RedirectUrl,
 		"grant_type":    "authorization_code",
 	}
+	if codeVerifier != "" {
+		bodyRequest["code_verifier"] = codeVerifier
+	}
 
 	jsonBody, err := json.Marshal(bodyRequest)
 	if err != nil {
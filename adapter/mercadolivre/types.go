@@ -11,6 +11,13 @@ type Credentials struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type Pack struct {
+	ID     uint64 `json:"id"`
+	Orders []struct {
+		ID uint64 `json:"id"`
+	} `json:"orders"`
+}
+
 type Order struct {
 	ID                      uint64      `json:"id,omitempty" validate:"required"`
 	DateCreated             string      `json:"date_created,omitempty" validate:"required"`
@@ -292,6 +299,11 @@ type AnnouncementsMultiGet []struct {
 			SaleTerms         []any    `json:"sale_terms,omitempty"`
 			PictureIds        []string `json:"picture_ids,omitempty"`
 			CatalogProductID  any      `json:"catalog_product_id,omitempty"`
+			// Only returned with include_attributes=all
+			Attributes []struct {
+				ID        string `json:"id,omitempty"`
+				ValueName string `json:"value_name,omitempty"`
+			} `json:"attributes,omitempty"`
 		} `json:"variations,omitempty"`
 		Status              string        `json:"status,omitempty"`
 		SubStatus           []interface{} `json:"sub_status,omitempty"`
@@ -516,3 +528,23 @@ type CompatibilitiesProduct struct {
 		Universal          bool   `json:"universal"`
 	} `json:"products"`
 }
+
+type User struct {
+	ID        int    `json:"id"`
+	Nickname  string `json:"nickname"`
+	SiteID    string `json:"site_id"`
+	CountryID string `json:"country_id"`
+}
+
+type DomainDiscovery []struct {
+	DomainID     string `json:"domain_id"`
+	DomainName   string `json:"domain_name"`
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+}
+
+type CurrencyConversion struct {
+	CurrencyBase  string  `json:"currency_base"`
+	CurrencyQuote string  `json:"currency_quote"`
+	Ratio         float64 `json:"ratio"`
+}
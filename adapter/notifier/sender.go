@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Vractos/kloni/pkg/netguard"
+)
+
+type HTTPSender struct {
+	httpClient *http.Client
+}
+
+func NewHTTPSender() *HTTPSender {
+	return &HTTPSender{
+		httpClient: newGuardedClient(),
+	}
+}
+
+// newGuardedClient creates the client of the requests sent to the URLs of the stores,
+// it doesn't connect to the internal network, even when a host resolves there after its URL was
+// checked or a response redirects there. The proxy of the environment isn't used, it would dial instead.
+func newGuardedClient() *http.Client {
+	return &http.Client{
+		Timeout: time.Second * 10,
+		Transport: &http.Transport{
+			DialContext:         netguard.DialContext(&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}),
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Send implements webhook.Sender
+func (s *HTTPSender) Send(url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drains the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier creates a notifier that sends the alerts by email,
+// the authentication is skipped when the username is empty.
+func NewSMTPNotifier(host, port, username, password, from string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+// Notify implements alert.Notifier
+func (n *SMTPNotifier) Notify(target string, alert entity.StockAlert) error {
+	// The SKU goes in the headers, line breaks would inject new ones
+	sku := strings.NewReplacer("\r", "", "\n", "").Replace(alert.Sku)
+
+	subject := fmt.Sprintf("[Kloni] %s is running low", sku)
+	body := fmt.Sprintf(
+		"The SKU %s has %d units available, its threshold is %d.\r\n",
+		sku, alert.Quantity, alert.Threshold,
+	)
+	if alert.Level == entity.OutOfStock {
+		subject = fmt.Sprintf("[Kloni] %s is out of stock", sku)
+		body = fmt.Sprintf("The SKU %s is out of stock on every account.\r\n", sku)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + target,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{target}, []byte(msg))
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+// Body of the requests sent to the webhooks of the stores
+type stockAlertPayload struct {
+	Event     string    `json:"event"`
+	AlertID   entity.ID `json:"alert_id"`
+	StoreID   entity.ID `json:"store_id"`
+	Sku       string    `json:"sku"`
+	Level     string    `json:"level"`
+	Quantity  int       `json:"quantity"`
+	Threshold int       `json:"threshold"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookNotifier struct {
+	httpClient *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		httpClient: newGuardedClient(),
+	}
+}
+
+// Notify implements alert.Notifier
+func (n *WebhookNotifier) Notify(target string, alert entity.StockAlert) error {
+	body, err := json.Marshal(stockAlertPayload{
+		Event:     "stock_alert",
+		AlertID:   alert.ID,
+		StoreID:   alert.StoreID,
+		Sku:       alert.Sku,
+		Level:     string(alert.Level),
+		Quantity:  alert.Quantity,
+		Threshold: alert.Threshold,
+		CreatedAt: alert.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
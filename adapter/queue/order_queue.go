@@ -6,7 +6,9 @@ import (
 	"errors"
 	"regexp"
 	"strconv"
+	"time"
 
+	"github.com/Vractos/kloni/pkg/health"
 	"github.com/Vractos/kloni/pkg/metrics"
 	"github.com/Vractos/kloni/usecases/order"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,6 +21,8 @@ type OrderSQSQueue struct {
 	client *sqs.Client
 	url    string
 	logger metrics.Logger
+	// Records the successful polls, for the readiness of the instance
+	polls *health.Heartbeat
 }
 
 func NewOrderQueue(client *sqs.Client, url string, logger metrics.Logger) *OrderSQSQueue {
@@ -26,11 +30,12 @@ func NewOrderQueue(client *sqs.Client, url string, logger metrics.Logger) *Order
 		client: client,
 		url:    url,
 		logger: logger,
+		polls:  health.NewHeartbeat(),
 	}
 }
 
 // PostOrderNotification implements order.Queue
-func (q *OrderSQSQueue) PostOrderNotification(input order.OrderWebhookDtoInput) error {
+func (q *OrderSQSQueue) PostOrderNotification(ctx context.Context, input order.OrderWebhookDtoInput) error {
 	msgBody, err := json.Marshal(input)
 	if err != nil {
 		q.logger.Error(
@@ -70,7 +75,17 @@ func (q *OrderSQSQueue) PostOrderNotification(input order.OrderWebhookDtoInput)
 		MessageGroupId:         aws.String("order-notification"),
 	}
 
-	resp, err := q.client.SendMessage(context.TODO(), mgsInput)
+	// The trace of the webhook follows the notification through its processing
+	traceContext := map[string]string{}
+	metrics.InjectTraceContext(ctx, traceContext)
+	for key, value := range traceContext {
+		mgsInput.MessageAttributes[key] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
+	resp, err := q.client.SendMessage(ctx, mgsInput)
 	if err != nil {
 		q.logger.Error(
 			"Failure to send the order message",
@@ -90,6 +105,10 @@ func (q *OrderSQSQueue) ConsumeOrderNotification() []order.OrderMessage {
 		MessageAttributeNames: []string{
 			string(types.QueueAttributeNameAll),
 		},
+		// Used to measure the lag of the queue
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeName(types.MessageSystemAttributeNameSentTimestamp),
+		},
 		QueueUrl:            &q.url,
 		WaitTimeSeconds:     int32(20),
 		MaxNumberOfMessages: 10,
@@ -102,7 +121,9 @@ func (q *OrderSQSQueue) ConsumeOrderNotification() []order.OrderMessage {
 			err,
 		)
 		return nil
-	} else if resp.Messages == nil {
+	}
+	q.polls.Beat()
+	if resp.Messages == nil {
 		return nil
 	}
 
@@ -112,6 +133,15 @@ func (q *OrderSQSQueue) ConsumeOrderNotification() []order.OrderMessage {
 		orderMessages[i].OrderId = regexp.MustCompile(`\w+$`).FindString(*e.MessageAttributes["ResourcePath"].StringValue)
 		orderMessages[i].Attempts, _ = strconv.Atoi(*e.MessageAttributes["Attempts"].StringValue)
 		orderMessages[i].ReceiptHandle = *e.ReceiptHandle
+		orderMessages[i].TraceContext = map[string]string{}
+		for _, key := range metrics.TraceContextFields() {
+			if attr, ok := e.MessageAttributes[key]; ok && attr.StringValue != nil {
+				orderMessages[i].TraceContext[key] = *attr.StringValue
+			}
+		}
+		if sent, err := strconv.ParseInt(e.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+			metrics.ObserveQueueLag(time.Since(time.UnixMilli(sent)))
+		}
 	}
 
 	return orderMessages
@@ -137,3 +167,17 @@ func (q *OrderSQSQueue) DeleteOrderNotification(receiptHandle string) error {
 
 	return nil
 }
+
+// Ping checks that the queue is reachable, by retrieving its attributes
+func (q *OrderSQSQueue) Ping(ctx context.Context) error {
+	_, err := q.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       &q.url,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	return err
+}
+
+// PollCheck fails when the queue wasn't polled successfully within maxAge
+func (q *OrderSQSQueue) PollCheck(maxAge time.Duration) health.Check {
+	return q.polls.Check(maxAge)
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type AlertPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewAlertPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *AlertPostgreSQL {
+	return &AlertPostgreSQL{db: db, logger: logger}
+}
+
+// SaveSettings implements alert.Repository
+func (r *AlertPostgreSQL) SaveSettings(settings *alert.Settings) error {
+	_, err := r.db.Exec(context.Background(), `
+  INSERT INTO alert_settings(store_id, webhook_url, email, default_threshold, updated_at)
+  VALUES($1, NULLIF($2, ''), NULLIF($3, ''), $4, NOW())
+  ON CONFLICT (store_id) DO UPDATE SET
+  webhook_url = EXCLUDED.webhook_url,
+  email = EXCLUDED.email,
+  default_threshold = EXCLUDED.default_threshold,
+  updated_at = EXCLUDED.updated_at
+  `, settings.Store, settings.WebhookURL, settings.Email, settings.DefaultThreshold)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// GetSettings implements alert.Repository
+func (r *AlertPostgreSQL) GetSettings(storeId entity.ID) (*alert.Settings, error) {
+	settings := alert.Settings{Store: storeId}
+	err := r.db.QueryRow(context.Background(), `
+  SELECT COALESCE(webhook_url, ''), COALESCE(email, ''), default_threshold
+  FROM alert_settings
+  WHERE store_id = $1
+  `, storeId).Scan(&settings.WebhookURL, &settings.Email, &settings.DefaultThreshold)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveThreshold implements alert.Repository
+func (r *AlertPostgreSQL) SaveThreshold(storeId entity.ID, threshold alert.Threshold) error {
+	_, err := r.db.Exec(context.Background(), `
+  INSERT INTO stock_thresholds(store_id, sku, threshold)
+  VALUES($1, $2, $3)
+  ON CONFLICT (store_id, sku) DO UPDATE SET threshold = EXCLUDED.threshold
+  `, storeId, threshold.Sku, threshold.Threshold)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteThreshold implements alert.Repository
+func (r *AlertPostgreSQL) DeleteThreshold(storeId entity.ID, sku string) error {
+	tag, err := r.db.Exec(context.Background(), `
+  DELETE FROM stock_thresholds WHERE store_id = $1 AND sku = $2
+  `, storeId, sku)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return alert.ErrThresholdNotFound
+	}
+	return nil
+}
+
+// ListThresholds implements alert.Repository
+func (r *AlertPostgreSQL) ListThresholds(storeId entity.ID) ([]alert.Threshold, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT sku, threshold FROM stock_thresholds WHERE store_id = $1 ORDER BY sku
+  `, storeId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	thresholds := []alert.Threshold{}
+	for rows.Next() {
+		var t alert.Threshold
+		if err := rows.Scan(&t.Sku, &t.Threshold); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		thresholds = append(thresholds, t)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+// GetThreshold implements alert.Repository
+func (r *AlertPostgreSQL) GetThreshold(storeId entity.ID, sku string) (*int, error) {
+	var threshold int
+	err := r.db.QueryRow(context.Background(), `
+  SELECT threshold FROM stock_thresholds WHERE store_id = $1 AND sku = $2
+  `, storeId, sku).Scan(&threshold)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return &threshold, nil
+}
+
+// RegisterAlert implements alert.Repository
+func (r *AlertPostgreSQL) RegisterAlert(a *entity.StockAlert) error {
+	_, err := r.db.Exec(context.Background(), `
+  INSERT INTO stock_alerts(id, store_id, sku, level, quantity, threshold, created_at)
+  VALUES($1, $2, $3, $4, $5, $6, $7)
+  `, a.ID, a.StoreID, a.Sku, a.Level, a.Quantity, a.Threshold, a.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return alert.ErrAlertAlreadyOpen
+		}
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateAlert implements alert.Repository
+func (r *AlertPostgreSQL) UpdateAlert(a *entity.StockAlert) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE stock_alerts SET level = $2, quantity = $3 WHERE id = $1 AND resolved_at IS NULL
+  `, a.ID, a.Level, a.Quantity)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// ResolveAlert implements alert.Repository
+func (r *AlertPostgreSQL) ResolveAlert(a *entity.StockAlert) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE stock_alerts SET resolved_at = $2 WHERE id = $1 AND resolved_at IS NULL
+  `, a.ID, a.ResolvedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+const stockAlertColumns = `id, store_id, sku, level, quantity, threshold, created_at, resolved_at`
+
+func scanStockAlert(row pgx.Row) (*entity.StockAlert, error) {
+	var a entity.StockAlert
+	err := row.Scan(&a.ID, &a.StoreID, &a.Sku, &a.Level, &a.Quantity, &a.Threshold, &a.CreatedAt, &a.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetOpenAlert implements alert.Repository
+func (r *AlertPostgreSQL) GetOpenAlert(storeId entity.ID, sku string) (*entity.StockAlert, error) {
+	a, err := scanStockAlert(r.db.QueryRow(context.Background(), `
+  SELECT `+stockAlertColumns+`
+  FROM stock_alerts
+  WHERE store_id = $1 AND sku = $2 AND resolved_at IS NULL
+  `, storeId, sku))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListOpenAlerts implements alert.Repository
+func (r *AlertPostgreSQL) ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT `+stockAlertColumns+`
+  FROM stock_alerts
+  WHERE store_id = $1 AND resolved_at IS NULL
+  ORDER BY created_at DESC
+  `, storeId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []entity.StockAlert{}
+	for rows.Next() {
+		a, err := scanStockAlert(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		alerts = append(alerts, *a)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// ScanTargets implements alert.Repository
+func (r *AlertPostgreSQL) ScanTargets() ([]alert.ScanTarget, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT s.store_id, array_agg(DISTINCT skus.sku ORDER BY skus.sku)
+  FROM alert_settings s
+  JOIN (
+    SELECT store_id, sku FROM stock_thresholds
+    UNION
+    SELECT store_id, sku FROM stock_alerts WHERE resolved_at IS NULL
+    UNION
+    SELECT mc.owner_id, oi.sku
+    FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    JOIN mercadolivre_credentials mc ON mc.id = o.account_id
+    WHERE o.date_created >= NOW() - INTERVAL '30 days' AND mc.disconnected_at IS NULL
+  ) skus ON skus.store_id = s.store_id
+  WHERE skus.sku <> ''
+  GROUP BY s.store_id
+  `)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := []alert.ScanTarget{}
+	for rows.Next() {
+		var t alert.ScanTarget
+		if err := rows.Scan(&t.Store, &t.Skus); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return targets, nil
+}
+
+func (r *AlertPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the stock alerts", err)
+}
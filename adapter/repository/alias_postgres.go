@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alias"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Columns of a SKU mapping, with its aliases aggregated as JSON in the fields of entity.SkuAlias
+const skuMappingColumns = `m.id, m.store_id, m.sku, m.created_at, m.updated_at,
+  COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+      'AccountID', a.account_id, 'Sku', COALESCE(a.sku, ''), 'ListingIDs', a.listing_ids
+    ) ORDER BY a.account_id)
+    FROM sku_aliases a WHERE a.mapping_id = m.id
+  ), '[]'::jsonb)`
+
+type AliasPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewAliasPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *AliasPostgreSQL {
+	return &AliasPostgreSQL{db: db, logger: logger}
+}
+
+func scanSkuMapping(row pgx.Row) (*entity.SkuMapping, error) {
+	var m entity.SkuMapping
+	if err := row.Scan(&m.ID, &m.StoreID, &m.Sku, &m.CreatedAt, &m.UpdatedAt, &m.Aliases); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// RegisterMapping implements alias.Repository
+func (r *AliasPostgreSQL) RegisterMapping(m *entity.SkuMapping) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+  INSERT INTO sku_mappings(id, store_id, sku, created_at, updated_at)
+  VALUES($1, $2, $3, $4, $5)
+  `, m.ID, m.StoreID, m.Sku, m.CreatedAt, m.UpdatedAt)
+	if err != nil {
+		return r.mappingError(err)
+	}
+
+	if err := r.insertAliases(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateMapping implements alias.Repository
+func (r *AliasPostgreSQL) UpdateMapping(m *entity.SkuMapping) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+  UPDATE sku_mappings SET sku = $3, updated_at = $4
+  WHERE id = $1 AND store_id = $2
+  `, m.ID, m.StoreID, m.Sku, m.UpdatedAt)
+	if err != nil {
+		return r.mappingError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return alias.ErrMappingNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM sku_aliases WHERE mapping_id = $1`, m.ID); err != nil {
+		r.logError(err)
+		return err
+	}
+	if err := r.insertAliases(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteMapping implements alias.Repository
+func (r *AliasPostgreSQL) DeleteMapping(storeId, mappingId entity.ID) error {
+	tag, err := r.db.Exec(context.Background(), `
+  DELETE FROM sku_mappings WHERE id = $1 AND store_id = $2
+  `, mappingId, storeId)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return alias.ErrMappingNotFound
+	}
+	return nil
+}
+
+// GetMapping implements alias.Repository
+func (r *AliasPostgreSQL) GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error) {
+	m, err := scanSkuMapping(r.db.QueryRow(context.Background(), `
+  SELECT `+skuMappingColumns+`
+  FROM sku_mappings m
+  WHERE m.id = $1 AND m.store_id = $2
+  `, mappingId, storeId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return m, nil
+}
+
+// FindMappings implements alias.Repository
+func (r *AliasPostgreSQL) FindMappings(storeId entity.ID, skus []string) ([]entity.SkuMapping, error) {
+	return r.listMappings(`
+  SELECT `+skuMappingColumns+`
+  FROM sku_mappings m
+  WHERE m.store_id = $1
+  AND (m.sku = ANY($2) OR EXISTS (SELECT 1 FROM sku_aliases a WHERE a.mapping_id = m.id AND a.sku = ANY($2)))
+  ORDER BY m.sku
+  `, storeId, skus)
+}
+
+// ListMappings implements alias.Repository
+func (r *AliasPostgreSQL) ListMappings(storeId entity.ID) ([]entity.SkuMapping, error) {
+	return r.listMappings(`
+  SELECT `+skuMappingColumns+`
+  FROM sku_mappings m
+  WHERE m.store_id = $1
+  ORDER BY m.sku
+  `, storeId)
+}
+
+func (r *AliasPostgreSQL) listMappings(query string, args ...any) ([]entity.SkuMapping, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := []entity.SkuMapping{}
+	for rows.Next() {
+		m, err := scanSkuMapping(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		mappings = append(mappings, *m)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (r *AliasPostgreSQL) insertAliases(ctx context.Context, tx pgx.Tx, m *entity.SkuMapping) error {
+	for _, a := range m.Aliases {
+		_, err := tx.Exec(ctx, `
+    INSERT INTO sku_aliases(mapping_id, account_id, sku, listing_ids) VALUES($1, $2, NULLIF($3, ''), $4)
+    `, m.ID, a.AccountID, a.Sku, a.ListingIDs)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// mappingError maps the violation of the unique canonical SKU of the store to alias.ErrSkuAlreadyMapped
+func (r *AliasPostgreSQL) mappingError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return alias.ErrSkuAlreadyMapped
+	}
+	r.logError(err)
+	return err
+}
+
+func (r *AliasPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the sku mappings", err)
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/allocation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Columns of an allocation policy, with its rules aggregated as JSON in the fields of entity.AccountAllocation
+const allocationPolicyColumns = `p.id, p.store_id, p.sku, p.priority_account_id, p.created_at, p.updated_at,
+  COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+      'AccountID', a.account_id, 'Buffer', a.buffer, 'Percentage', a.percentage, 'MaxQuantity', a.max_quantity
+    ) ORDER BY a.account_id)
+    FROM account_allocations a WHERE a.policy_id = p.id
+  ), '[]'::jsonb)`
+
+type AllocationPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewAllocationPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *AllocationPostgreSQL {
+	return &AllocationPostgreSQL{db: db, logger: logger}
+}
+
+func scanAllocationPolicy(row pgx.Row) (*entity.AllocationPolicy, error) {
+	var p entity.AllocationPolicy
+	if err := row.Scan(&p.ID, &p.StoreID, &p.Sku, &p.PriorityAccount, &p.CreatedAt, &p.UpdatedAt, &p.Accounts); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// RegisterPolicy implements allocation.Repository
+func (r *AllocationPostgreSQL) RegisterPolicy(p *entity.AllocationPolicy) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+  INSERT INTO allocation_policies(id, store_id, sku, priority_account_id, created_at, updated_at)
+  VALUES($1, $2, $3, $4, $5, $6)
+  `, p.ID, p.StoreID, p.Sku, p.PriorityAccount, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return r.policyError(err)
+	}
+
+	if err := r.insertAccounts(ctx, tx, p); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePolicy implements allocation.Repository
+func (r *AllocationPostgreSQL) UpdatePolicy(p *entity.AllocationPolicy) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+  UPDATE allocation_policies SET sku = $3, priority_account_id = $4, updated_at = $5
+  WHERE id = $1 AND store_id = $2
+  `, p.ID, p.StoreID, p.Sku, p.PriorityAccount, p.UpdatedAt)
+	if err != nil {
+		return r.policyError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return allocation.ErrPolicyNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM account_allocations WHERE policy_id = $1`, p.ID); err != nil {
+		r.logError(err)
+		return err
+	}
+	if err := r.insertAccounts(ctx, tx, p); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// DeletePolicy implements allocation.Repository
+func (r *AllocationPostgreSQL) DeletePolicy(storeId, policyId entity.ID) error {
+	tag, err := r.db.Exec(context.Background(), `
+  DELETE FROM allocation_policies WHERE id = $1 AND store_id = $2
+  `, policyId, storeId)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return allocation.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// SetStock implements allocation.Repository
+func (r *AllocationPostgreSQL) SetStock(storeId entity.ID, sku string, quantity int) error {
+	_, err := r.db.Exec(context.Background(), `
+  INSERT INTO stock_levels(store_id, sku, quantity, updated_at) VALUES($1, $2, $3, $4)
+  ON CONFLICT (store_id, sku) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+  `, storeId, sku, quantity, time.Now().UTC())
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// AddStock implements allocation.Repository
+func (r *AllocationPostgreSQL) AddStock(storeId entity.ID, sku string, delta, initial int) (int, error) {
+	var quantity int
+	err := r.db.QueryRow(context.Background(), `
+  INSERT INTO stock_levels(store_id, sku, quantity, updated_at) VALUES($1, $2, GREATEST($4::int + $3::int, 0), $5)
+  ON CONFLICT (store_id, sku) DO UPDATE
+  SET quantity = GREATEST(stock_levels.quantity + $3::int, 0), updated_at = EXCLUDED.updated_at
+  RETURNING quantity
+  `, storeId, sku, delta, initial, time.Now().UTC()).Scan(&quantity)
+	if err != nil {
+		r.logError(err)
+		return 0, err
+	}
+	return quantity, nil
+}
+
+// GetPolicy implements allocation.Repository
+func (r *AllocationPostgreSQL) GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error) {
+	return r.findPolicy(`
+  SELECT `+allocationPolicyColumns+`
+  FROM allocation_policies p
+  WHERE p.id = $1 AND p.store_id = $2
+  `, policyId, storeId)
+}
+
+// FindPolicy implements allocation.Repository
+func (r *AllocationPostgreSQL) FindPolicy(storeId entity.ID, sku string) (*entity.AllocationPolicy, error) {
+	// The policy of the SKU comes before the default one, which has an empty SKU
+	return r.findPolicy(`
+  SELECT `+allocationPolicyColumns+`
+  FROM allocation_policies p
+  WHERE p.store_id = $1 AND p.sku IN ($2, '')
+  ORDER BY p.sku DESC
+  LIMIT 1
+  `, storeId, sku)
+}
+
+// ListPolicies implements allocation.Repository
+func (r *AllocationPostgreSQL) ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT `+allocationPolicyColumns+`
+  FROM allocation_policies p
+  WHERE p.store_id = $1
+  ORDER BY p.sku
+  `, storeId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []entity.AllocationPolicy{}
+	for rows.Next() {
+		p, err := scanAllocationPolicy(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *AllocationPostgreSQL) findPolicy(query string, args ...any) (*entity.AllocationPolicy, error) {
+	p, err := scanAllocationPolicy(r.db.QueryRow(context.Background(), query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *AllocationPostgreSQL) insertAccounts(ctx context.Context, tx pgx.Tx, p *entity.AllocationPolicy) error {
+	for _, a := range p.Accounts {
+		_, err := tx.Exec(ctx, `
+    INSERT INTO account_allocations(policy_id, account_id, buffer, percentage, max_quantity) VALUES($1, $2, $3, $4, $5)
+    `, p.ID, a.AccountID, a.Buffer, a.Percentage, a.MaxQuantity)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// policyError maps the violation of the unique SKU of the policies of the store to allocation.ErrPolicyAlreadyExists
+func (r *AllocationPostgreSQL) policyError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return allocation.ErrPolicyAlreadyExists
+	}
+	r.logError(err)
+	return err
+}
+
+func (r *AllocationPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the allocation policies", err)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/analytics"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type AnalyticsPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewAnalyticsPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *AnalyticsPostgreSQL {
+	return &AnalyticsPostgreSQL{db: db, logger: logger}
+}
+
+// Expressions of the keys of each dimension
+var salesKeys = map[analytics.Dimension]string{
+	analytics.BySku:         "oi.sku",
+	analytics.ByAccount:     "o.account_id::text",
+	analytics.ByListingType: "COALESCE(oi.listing_type_id, 'unknown')",
+}
+
+// Sales implements analytics.Repository
+func (r *AnalyticsPostgreSQL) Sales(filter analytics.SalesFilter) ([]analytics.SalesBucket, error) {
+	key, ok := salesKeys[filter.Dimension]
+	if !ok {
+		return nil, analytics.ErrInvalidDimension
+	}
+
+	conditions := []string{
+		"mc.owner_id = $1",
+		"o.date_created >= $2",
+		"o.date_created < $3",
+		"COALESCE(o.status, '') NOT IN ('cancelled', 'invalid')",
+	}
+	args := []any{filter.Store, filter.From, filter.To, string(filter.Interval)}
+	if filter.Account != nil {
+		args = append(args, *filter.Account)
+		conditions = append(conditions, fmt.Sprintf("o.account_id = $%d", len(args)))
+	}
+	if filter.Sku != "" {
+		args = append(args, filter.Sku)
+		conditions = append(conditions, fmt.Sprintf("oi.sku = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+  date_trunc($4, o.date_created AT TIME ZONE 'UTC') AS period,
+  %s AS key,
+  COALESCE(oi.currency_id, o.currency_id, '') AS currency,
+  SUM(oi.quantity)::int,
+  SUM(oi.quantity * COALESCE(oi.unit_price, 0))::float8
+  FROM orders o
+  JOIN mercadolivre_credentials mc ON mc.id = o.account_id
+  JOIN order_items oi ON oi.order_id = o.id
+  WHERE %s
+  GROUP BY period, key, currency
+  ORDER BY period, key, currency
+  `, key, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []analytics.SalesBucket{}
+	for rows.Next() {
+		var b analytics.SalesBucket
+		if err := rows.Scan(&b.Period, &b.Key, &b.CurrencyID, &b.Units, &b.Revenue); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		b.Period = b.Period.UTC()
+		buckets = append(buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func (r *AnalyticsPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to aggregate the sales", err)
+}
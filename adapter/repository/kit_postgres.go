@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/kit"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Columns of a kit, with its components aggregated as JSON
+const kitColumns = `k.id, k.store_id, k.sku, COALESCE(k.title, ''), k.created_at, k.updated_at,
+  COALESCE((
+    SELECT jsonb_agg(jsonb_build_object('sku', c.sku, 'quantity', c.quantity) ORDER BY c.sku)
+    FROM kit_components c WHERE c.kit_id = k.id
+  ), '[]'::jsonb)`
+
+type KitPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewKitPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *KitPostgreSQL {
+	return &KitPostgreSQL{db: db, logger: logger}
+}
+
+func scanKit(row pgx.Row) (*entity.Kit, error) {
+	var k entity.Kit
+	if err := row.Scan(&k.ID, &k.StoreID, &k.Sku, &k.Title, &k.CreatedAt, &k.UpdatedAt, &k.Components); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// RegisterKit implements kit.Repository
+func (r *KitPostgreSQL) RegisterKit(k *entity.Kit) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+  INSERT INTO kits(id, store_id, sku, title, created_at, updated_at)
+  VALUES($1, $2, $3, NULLIF($4, ''), $5, $6)
+  `, k.ID, k.StoreID, k.Sku, k.Title, k.CreatedAt, k.UpdatedAt)
+	if err != nil {
+		return r.kitError(err)
+	}
+
+	if err := r.insertComponents(ctx, tx, k); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateKit implements kit.Repository
+func (r *KitPostgreSQL) UpdateKit(k *entity.Kit) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+  UPDATE kits SET sku = $3, title = NULLIF($4, ''), updated_at = $5
+  WHERE id = $1 AND store_id = $2
+  `, k.ID, k.StoreID, k.Sku, k.Title, k.UpdatedAt)
+	if err != nil {
+		return r.kitError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return kit.ErrKitNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM kit_components WHERE kit_id = $1`, k.ID); err != nil {
+		r.logError(err)
+		return err
+	}
+	if err := r.insertComponents(ctx, tx, k); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteKit implements kit.Repository
+func (r *KitPostgreSQL) DeleteKit(storeId, kitId entity.ID) error {
+	tag, err := r.db.Exec(context.Background(), `
+  DELETE FROM kits WHERE id = $1 AND store_id = $2
+  `, kitId, storeId)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return kit.ErrKitNotFound
+	}
+	return nil
+}
+
+// ClaimSaleComponent implements kit.Repository
+func (r *KitPostgreSQL) ClaimSaleComponent(saleId entity.ID, sku string) (bool, error) {
+	tag, err := r.db.Exec(context.Background(), `
+  INSERT INTO kit_sale_components(sale_id, sku, claimed_at) VALUES($1, $2, NOW())
+  ON CONFLICT (sale_id, sku) DO NOTHING
+  `, saleId, sku)
+	if err != nil {
+		r.logError(err)
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// CompleteSaleComponent implements kit.Repository
+func (r *KitPostgreSQL) CompleteSaleComponent(saleId entity.ID, sku string) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE kit_sale_components SET applied_at = NOW() WHERE sale_id = $1 AND sku = $2
+  `, saleId, sku)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// GetKit implements kit.Repository
+func (r *KitPostgreSQL) GetKit(storeId, kitId entity.ID) (*entity.Kit, error) {
+	k, err := scanKit(r.db.QueryRow(context.Background(), `
+  SELECT `+kitColumns+`
+  FROM kits k
+  WHERE k.id = $1 AND k.store_id = $2
+  `, kitId, storeId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return k, nil
+}
+
+// GetKitBySku implements kit.Repository
+func (r *KitPostgreSQL) GetKitBySku(storeId entity.ID, sku string) (*entity.Kit, error) {
+	k, err := scanKit(r.db.QueryRow(context.Background(), `
+  SELECT `+kitColumns+`
+  FROM kits k
+  WHERE k.store_id = $1 AND k.sku = $2
+  `, storeId, sku))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return k, nil
+}
+
+// ListKits implements kit.Repository
+func (r *KitPostgreSQL) ListKits(storeId entity.ID) ([]entity.Kit, error) {
+	return r.listKits(`
+  SELECT `+kitColumns+`
+  FROM kits k
+  WHERE k.store_id = $1
+  ORDER BY k.sku
+  `, storeId)
+}
+
+// ListKitsContaining implements kit.Repository
+func (r *KitPostgreSQL) ListKitsContaining(storeId entity.ID, skus []string) ([]entity.Kit, error) {
+	return r.listKits(`
+  SELECT `+kitColumns+`
+  FROM kits k
+  WHERE k.store_id = $1
+  AND EXISTS (SELECT 1 FROM kit_components c WHERE c.kit_id = k.id AND c.sku = ANY($2))
+  ORDER BY k.sku
+  `, storeId, skus)
+}
+
+// ListSaleComponents implements kit.Repository
+func (r *KitPostgreSQL) ListSaleComponents(saleId entity.ID) (map[string]bool, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT sku, applied_at IS NOT NULL FROM kit_sale_components WHERE sale_id = $1
+  `, saleId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[string]bool)
+	for rows.Next() {
+		var sku string
+		var applied bool
+		if err := rows.Scan(&sku, &applied); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		components[sku] = applied
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return components, nil
+}
+
+func (r *KitPostgreSQL) listKits(query string, args ...any) ([]entity.Kit, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	kits := []entity.Kit{}
+	for rows.Next() {
+		k, err := scanKit(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		kits = append(kits, *k)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return kits, nil
+}
+
+func (r *KitPostgreSQL) insertComponents(ctx context.Context, tx pgx.Tx, k *entity.Kit) error {
+	for _, c := range k.Components {
+		_, err := tx.Exec(ctx, `
+    INSERT INTO kit_components(kit_id, sku, quantity) VALUES($1, $2, $3)
+    `, k.ID, c.Sku, c.Quantity)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// kitError maps the violation of the unique SKU of the store to kit.ErrKitAlreadyExists
+func (r *KitPostgreSQL) kitError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return kit.ErrKitAlreadyExists
+	}
+	r.logError(err)
+	return err
+}
+
+func (r *KitPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the kits", err)
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/order"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -22,8 +26,12 @@ func NewOrderPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *OrderPostgreSQ
 }
 
 // RegisterOrder implements order.Repository
-func (r *OrderPostgreSQL) RegisterOrder(o *entity.Order) error {
-	ctx := context.Background()
+func (r *OrderPostgreSQL) RegisterOrder(ctx context.Context, o *entity.Order) error {
+	return r.RegisterPack(ctx, []*entity.Order{o})
+}
+
+// RegisterPack implements order.Repository
+func (r *OrderPostgreSQL) RegisterPack(ctx context.Context, orders []*entity.Order) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
@@ -31,10 +39,28 @@ func (r *OrderPostgreSQL) RegisterOrder(o *entity.Order) error {
 
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, `
-  INSERT INTO orders(id, account_id, marketplace_id, date_created, status)
-  VALUES($1,$2,$3,$4,$5)
-  `, o.ID, o.AccountID, o.MarketplaceID, o.DateCreated, o.Status)
+	for _, o := range orders {
+		if err := r.insertOrder(ctx, tx, o); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error("Error to commit order", err)
+		return errors.New("error to commit order")
+	}
+
+	return nil
+}
+
+// insertOrder inserts the order with its items and sync actions, and applies its stock changes
+func (r *OrderPostgreSQL) insertOrder(ctx context.Context, tx pgx.Tx, o *entity.Order) error {
+	_, err := tx.Exec(ctx, `
+  INSERT INTO orders(id, account_id, marketplace_id, date_created, status, date_closed, last_updated,
+  total_amount, paid_amount, currency_id, pack_id, shipping_id, buyer_nickname)
+  VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,NULLIF($10, ''),NULLIF($11, ''),NULLIF($12, ''),NULLIF($13, ''))
+  `, o.ID, o.AccountID, o.MarketplaceID, o.DateCreated, o.Status, o.DateClosed, o.LastUpdated,
+		o.TotalAmount, o.PaidAmount, o.CurrencyID, o.PackID, o.ShippingID, o.BuyerNickname)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -45,9 +71,9 @@ func (r *OrderPostgreSQL) RegisterOrder(o *entity.Order) error {
 
 	for _, i := range o.Items {
 		_, err := tx.Exec(ctx, `
-    INSERT INTO order_items(id, title, sku, quantity, order_id)
-    VALUES($1,$2,$3,$4,$5)
-    `, i.ID, i.Title, i.Sku, i.Quantity, o.ID)
+    INSERT INTO order_items(id, title, sku, quantity, order_id, variation_id, unit_price, currency_id, listing_type_id)
+    VALUES($1,$2,$3,$4,$5,$6,$7,NULLIF($8, ''),NULLIF($9, ''))
+    `, i.ID, i.Title, i.Sku, i.Quantity, o.ID, i.VariationID, i.UnitPrice, i.CurrencyID, i.ListingTypeID)
 		if err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) {
@@ -57,19 +83,207 @@ func (r *OrderPostgreSQL) RegisterOrder(o *entity.Order) error {
 		}
 	}
 
+	for _, a := range o.SyncActions {
+		if err := r.insertSyncAction(ctx, tx, o.ID, &a); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range o.StockChanges {
+		_, err := tx.Exec(ctx, `
+    UPDATE stock_levels SET quantity = GREATEST(quantity + $3::int, 0), updated_at = NOW()
+    WHERE store_id = $1 AND sku = $2
+    `, c.StoreID, c.Sku, c.Delta)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimSyncAction implements order.Repository
+func (r *OrderPostgreSQL) ClaimSyncAction(action *entity.SyncAction, claim entity.ID, lease time.Duration) (bool, error) {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := r.lockSyncActionClaims(ctx, tx); err != nil {
+		return false, err
+	}
+	err = tx.QueryRow(ctx, `
+  UPDATE order_sync_actions a SET claim_id = $2, next_attempt_at = NOW() + $3 * INTERVAL '1 second'
+  WHERE a.id = $1 AND a.status = 'pending' AND a.next_attempt_at <= NOW() AND `+syncActionListingIdle+`
+  RETURNING a.attempts, a.quantity
+  `, action.ID, claim, int(lease.Seconds())).Scan(&action.Attempts, &action.Quantity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		r.logError(err)
+		return false, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
-		r.logger.Error("Error to commit order", err)
-		return errors.New("error to commit order")
+		r.logError(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// AcknowledgeSyncAction implements order.Repository
+func (r *OrderPostgreSQL) AcknowledgeSyncAction(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// The action isn't held by the claim when its lease expired and another dispatcher claimed it
+	tag, err := tx.Exec(ctx, `
+  UPDATE order_sync_actions
+  SET status = $2, error = NULLIF($3, ''), attempts = $4, next_attempt_at = $5, executed_at = $6, quantity = $8,
+  unconfirmed = $9, claim_id = NULL
+  WHERE id = $1 AND status = 'pending' AND claim_id = $7
+  `, action.ID, action.Status, action.Error, action.Attempts, action.NextAttemptAt, action.ExecutedAt, claim, action.Quantity,
+		action.Unconfirmed)
+	if err != nil {
+		r.logError(err)
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	for i := range followUps {
+		if err := r.insertSyncAction(ctx, tx, orderId, &followUps[i]); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ResumeSyncActions implements order.Repository
+func (r *OrderPostgreSQL) ResumeSyncActions(orderId entity.ID) (int, error) {
+	// The unconfirmed flag is kept, it tells the dispatcher that the quantity it computed
+	// may already be on the listing
+	tag, err := r.db.Exec(context.Background(), `
+  UPDATE order_sync_actions a
+  SET status = 'pending', error = NULL, attempts = 0, next_attempt_at = NOW(), executed_at = NULL
+  WHERE a.order_id = $1 AND a.status = 'failed' AND a.kind = 'quantity'
+  AND (a.delta <> 0 OR NOT EXISTS (
+    SELECT 1 FROM order_sync_actions n
+    WHERE n.kind = 'quantity' AND n.account_id = a.account_id AND n.announcement_id = a.announcement_id
+    AND n.variation_id = a.variation_id AND n.created_at > a.created_at
+  ))
+  `, orderId)
+	if err != nil {
+		r.logError(err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDueSyncActions implements order.Repository
+func (r *OrderPostgreSQL) ClaimDueSyncActions(claim entity.ID, limit int, lease time.Duration) ([]order.DueSyncAction, error) {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := r.lockSyncActionClaims(ctx, tx); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, `
+  WITH due AS (
+    SELECT a.id
+    FROM order_sync_actions a
+    WHERE a.status = 'pending' AND a.next_attempt_at <= NOW() AND `+syncActionListingIdle+`
+    ORDER BY a.next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+  )
+  UPDATE order_sync_actions a SET claim_id = $3, next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+  FROM due, orders o, mercadolivre_credentials mc
+  WHERE a.id = due.id AND o.id = a.order_id AND mc.id = o.account_id
+  RETURNING `+syncActionColumns+`, o.id, o.marketplace_id, mc.owner_id
+  `, limit, int(lease.Seconds()), claim)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []order.DueSyncAction{}
+	for rows.Next() {
+		var d order.DueSyncAction
+		if err := rows.Scan(append(syncActionFields(&d.SyncAction), &d.OrderID, &d.MarketplaceID, &d.StoreID)...); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	rows.Close()
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return due, nil
+}
+
+// lockSyncActionClaims serializes the claims of the sync actions until the transaction ends,
+// so two claims can't both find a listing without a quantity in flight
+func (r *OrderPostgreSQL) lockSyncActionClaims(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('order_sync_actions'))`); err != nil {
+		r.logError(err)
+		return err
 	}
+	return nil
+}
 
+func (r *OrderPostgreSQL) insertSyncAction(ctx context.Context, tx pgx.Tx, orderId entity.ID, a *entity.SyncAction) error {
+	var nextAttemptAt *time.Time
+	if a.Status == entity.SyncActionPending {
+		nextAttemptAt = &a.NextAttemptAt
+	}
+	_, err := tx.Exec(ctx, `
+  INSERT INTO order_sync_actions(id, order_id, kind, account_id, announcement_id, variation_id, sku, quantity, delta,
+  status, error, attempts, next_attempt_at, executed_at, created_at)
+  VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULLIF($11, ''),$12,$13,$14,$15)
+  `, a.ID, orderId, a.Kind, a.AccountID, a.AnnouncementID, a.VariationID, a.Sku, a.Quantity, a.Delta,
+		a.Status, a.Error, a.Attempts, nextAttemptAt, a.ExecutedAt, a.CreatedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
 	return nil
 }
 
 // GetOrder implements order.Repository
-func (r *OrderPostgreSQL) GetOrder(orderMarketplaceId string) (*entity.Order, error) {
+func (r *OrderPostgreSQL) GetOrder(ctx context.Context, orderMarketplaceId string) (*entity.Order, error) {
 	var order entity.Order
 
-	err := r.db.QueryRow(context.Background(), `
+	err := r.db.QueryRow(ctx, `
 	SELECT
   id,
   marketplace_id,
@@ -93,3 +307,244 @@ func (r *OrderPostgreSQL) GetOrder(orderMarketplaceId string) (*entity.Order, er
 
 	return &order, nil
 }
+
+// ListOrders implements order.Repository
+func (r *OrderPostgreSQL) ListOrders(filter order.OrderFilter) ([]entity.Order, error) {
+	ctx := context.Background()
+
+	conditions := []string{"mc.owner_id = $1"}
+	args := []any{filter.Store}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Account != nil {
+		addCondition("o.account_id = $%d", *filter.Account)
+	}
+	if filter.Status != "" {
+		addCondition("o.status = $%d", filter.Status)
+	}
+	if filter.Sku != "" {
+		addCondition("EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = o.id AND oi.sku = $%d)", filter.Sku)
+	}
+	if filter.Pack != "" {
+		addCondition("o.pack_id = $%d", filter.Pack)
+	}
+	if filter.From != nil {
+		addCondition("o.date_created >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("o.date_created < $%d", *filter.To)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.DateCreated, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(o.date_created, o.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+  SELECT %s
+  FROM orders o
+  JOIN mercadolivre_credentials mc ON mc.id = o.account_id
+  WHERE %s
+  ORDER BY o.date_created DESC, o.id DESC
+  LIMIT $%d
+  `, orderColumns, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []entity.Order{}
+	ids := []entity.ID{}
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		orders = append(orders, *o)
+		ids = append(ids, o.ID)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return orders, nil
+	}
+
+	items, err := r.getItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+	}
+
+	return orders, nil
+}
+
+// GetOrderDetail implements order.Repository
+func (r *OrderPostgreSQL) GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error) {
+	ctx := context.Background()
+
+	o, err := scanOrder(r.db.QueryRow(ctx, `
+  SELECT `+orderColumns+`
+  FROM orders o
+  JOIN mercadolivre_credentials mc ON mc.id = o.account_id
+  WHERE o.id = $1 AND mc.owner_id = $2
+  `, orderId, storeId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+
+	items, err := r.getItems(ctx, []entity.ID{o.ID})
+	if err != nil {
+		return nil, err
+	}
+	o.Items = items[o.ID]
+
+	rows, err := r.db.Query(ctx, `
+  SELECT `+syncActionColumns+`
+  FROM order_sync_actions a
+  WHERE a.order_id = $1
+  ORDER BY a.created_at
+  `, o.ID)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a entity.SyncAction
+		if err := rows.Scan(syncActionFields(&a)...); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		o.SyncActions = append(o.SyncActions, a)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// orderColumns are the columns read by scanOrder, the orders table is aliased as o
+const orderColumns = `o.id, o.account_id, o.marketplace_id, o.date_created, o.status, o.date_closed, o.last_updated,
+  COALESCE(o.total_amount, 0), COALESCE(o.paid_amount, 0), COALESCE(o.currency_id, ''),
+  COALESCE(o.pack_id, ''), COALESCE(o.shipping_id, ''), COALESCE(o.buyer_nickname, ''),
+  (SELECT COUNT(*) FROM orders p WHERE p.account_id = o.account_id AND p.pack_id = o.pack_id)`
+
+// syncActionColumns are the columns read by syncActionFields, the order_sync_actions table is aliased as a
+const syncActionColumns = `a.id, a.kind, a.account_id, a.announcement_id, a.variation_id, a.sku, a.quantity, a.delta,
+  a.unconfirmed, a.status, COALESCE(a.error, ''), a.attempts, COALESCE(a.next_attempt_at, a.created_at), a.executed_at, a.created_at`
+
+// syncActionListingIdle filters the sync actions, aliased as a, whose listing doesn't have a quantity
+// being set by another claim. The quantity of a sale is computed from the listing when it's executed,
+// so the quantities of a listing are set one at a time
+const syncActionListingIdle = `(a.kind <> 'quantity' OR NOT EXISTS (
+    SELECT 1 FROM order_sync_actions f
+    WHERE f.id <> a.id AND f.status = 'pending' AND f.kind = 'quantity' AND f.claim_id IS NOT NULL
+    AND f.next_attempt_at > NOW() AND f.account_id = a.account_id AND f.announcement_id = a.announcement_id
+    AND f.variation_id = a.variation_id
+  ))`
+
+func syncActionFields(a *entity.SyncAction) []any {
+	return []any{
+		&a.ID,
+		&a.Kind,
+		&a.AccountID,
+		&a.AnnouncementID,
+		&a.VariationID,
+		&a.Sku,
+		&a.Quantity,
+		&a.Delta,
+		&a.Unconfirmed,
+		&a.Status,
+		&a.Error,
+		&a.Attempts,
+		&a.NextAttemptAt,
+		&a.ExecutedAt,
+		&a.CreatedAt,
+	}
+}
+
+func scanOrder(row pgx.Row) (*entity.Order, error) {
+	var o entity.Order
+	err := row.Scan(
+		&o.ID,
+		&o.AccountID,
+		&o.MarketplaceID,
+		&o.DateCreated,
+		&o.Status,
+		&o.DateClosed,
+		&o.LastUpdated,
+		&o.TotalAmount,
+		&o.PaidAmount,
+		&o.CurrencyID,
+		&o.PackID,
+		&o.ShippingID,
+		&o.BuyerNickname,
+		&o.PackSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// getItems retrieves the items of the orders, grouped by the order ID
+func (r *OrderPostgreSQL) getItems(ctx context.Context, orderIds []entity.ID) (map[entity.ID][]entity.OrderItem, error) {
+	rows, err := r.db.Query(ctx, `
+  SELECT id, order_id, COALESCE(title, ''), sku, quantity, variation_id,
+  COALESCE(unit_price, 0), COALESCE(currency_id, ''), COALESCE(listing_type_id, '')
+  FROM order_items
+  WHERE order_id = ANY($1)
+  `, orderIds)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[entity.ID][]entity.OrderItem)
+	for rows.Next() {
+		var (
+			item    entity.OrderItem
+			orderId entity.ID
+		)
+		if err := rows.Scan(&item.ID, &orderId, &item.Title, &item.Sku, &item.Quantity, &item.VariationID, &item.UnitPrice, &item.CurrencyID, &item.ListingTypeID); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		items[orderId] = append(items[orderId], item)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *OrderPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query orders", err)
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/stock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type StockPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewStockPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *StockPostgreSQL {
+	return &StockPostgreSQL{db: db, logger: logger}
+}
+
+// RegisterImport implements stock.Repository
+func (r *StockPostgreSQL) RegisterImport(i *stock.Import) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+  INSERT INTO stock_imports(id, store_id, file_name, status, created_at, updated_at)
+  VALUES($1, $2, $3, $4, $5, $5)
+  `, i.ID, i.Store, i.FileName, i.Status, i.CreatedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+
+	for _, row := range i.Rows {
+		_, err := tx.Exec(ctx, `
+    INSERT INTO stock_import_rows(import_id, line, sku, quantity, status, error, listings)
+    VALUES($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+    `, i.ID, row.Line, row.Sku, row.Quantity, row.Status, row.Error, row.Listings)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// ConfirmImport implements stock.Repository
+func (r *StockPostgreSQL) ConfirmImport(importId entity.ID, confirmedAt time.Time) error {
+	tag, err := r.db.Exec(context.Background(), `
+  UPDATE stock_imports SET status = 'confirmed', confirmed_at = $2, updated_at = $2
+  WHERE id = $1 AND status = 'previewed'
+  `, importId, confirmedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return stock.ErrImportNotPreviewed
+	}
+	return nil
+}
+
+// UpdateImportRow implements stock.Repository
+func (r *StockPostgreSQL) UpdateImportRow(importId entity.ID, row *stock.ImportRow) error {
+	_, err := r.db.Exec(context.Background(), `
+  WITH touched AS (
+    UPDATE stock_imports SET updated_at = NOW() WHERE id = $1
+  )
+  UPDATE stock_import_rows SET status = $3, error = NULLIF($4, ''), listings = $5, updated = $6, failed = $7
+  WHERE import_id = $1 AND line = $2
+  `, importId, row.Line, row.Status, row.Error, row.Listings, row.Updated, row.Failed)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// FinishImport implements stock.Repository
+func (r *StockPostgreSQL) FinishImport(importId entity.ID, finishedAt time.Time) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE stock_imports SET status = 'done', finished_at = $2, updated_at = $2 WHERE id = $1
+  `, importId, finishedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// FinishValidation implements stock.Repository
+func (r *StockPostgreSQL) FinishValidation(importId entity.ID) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE stock_imports SET status = 'previewed', updated_at = NOW() WHERE id = $1
+  `, importId)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+const stockImportColumns = `id, store_id, file_name, status, created_at, confirmed_at, finished_at`
+
+func scanStockImport(row pgx.Row) (*stock.Import, error) {
+	var i stock.Import
+	if err := row.Scan(&i.ID, &i.Store, &i.FileName, &i.Status, &i.CreatedAt, &i.ConfirmedAt, &i.FinishedAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// getRows retrieves the rows of an import, ordered by their lines
+func (r *StockPostgreSQL) getRows(importId entity.ID) ([]stock.ImportRow, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT line, sku, quantity, status, COALESCE(error, ''), listings, updated, failed
+  FROM stock_import_rows
+  WHERE import_id = $1
+  ORDER BY line
+  `, importId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	importRows := []stock.ImportRow{}
+	for rows.Next() {
+		var row stock.ImportRow
+		if err := rows.Scan(&row.Line, &row.Sku, &row.Quantity, &row.Status, &row.Error, &row.Listings, &row.Updated, &row.Failed); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		importRows = append(importRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return importRows, nil
+}
+
+// GetImport implements stock.Repository
+func (r *StockPostgreSQL) GetImport(storeId, importId entity.ID) (*stock.Import, error) {
+	i, err := scanStockImport(r.db.QueryRow(context.Background(), `
+  SELECT `+stockImportColumns+`
+  FROM stock_imports
+  WHERE id = $2 AND store_id = $1
+  `, storeId, importId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+
+	if i.Rows, err = r.getRows(i.ID); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// ListImports implements stock.Repository
+func (r *StockPostgreSQL) ListImports(storeId entity.ID) ([]stock.Import, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT i.id, i.store_id, i.file_name, i.status, i.created_at, i.confirmed_at, i.finished_at,
+  COALESCE(c.counts, '{}'::jsonb)
+  FROM stock_imports i
+  LEFT JOIN LATERAL (
+    SELECT jsonb_object_agg(status, total) AS counts
+    FROM (SELECT status, COUNT(*) AS total FROM stock_import_rows WHERE import_id = i.id GROUP BY status) s
+  ) c ON TRUE
+  WHERE i.store_id = $1
+  ORDER BY i.created_at DESC
+  LIMIT 100
+  `, storeId)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	imports := []stock.Import{}
+	for rows.Next() {
+		var i stock.Import
+		var counts map[string]int
+		if err := rows.Scan(&i.ID, &i.Store, &i.FileName, &i.Status, &i.CreatedAt, &i.ConfirmedAt, &i.FinishedAt, &counts); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		i.Counts = make(map[stock.RowStatus]int, len(counts))
+		for status, total := range counts {
+			i.Counts[stock.RowStatus(status)] = total
+		}
+		imports = append(imports, i)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return imports, nil
+}
+
+// ClaimImport implements stock.Repository
+func (r *StockPostgreSQL) ClaimImport(staleAfter time.Duration) (*stock.Import, error) {
+	i, err := scanStockImport(r.db.QueryRow(context.Background(), `
+  UPDATE stock_imports SET status = 'running', updated_at = NOW()
+  WHERE id = (
+    SELECT id FROM stock_imports
+    WHERE status = 'confirmed' OR (status = 'running' AND updated_at < NOW() - $1 * INTERVAL '1 second')
+    ORDER BY confirmed_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING `+stockImportColumns+`
+  `, int(staleAfter.Seconds())))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+
+	if i.Rows, err = r.getRows(i.ID); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// ClaimValidation implements stock.Repository
+func (r *StockPostgreSQL) ClaimValidation(staleAfter time.Duration) (*stock.Import, error) {
+	i, err := scanStockImport(r.db.QueryRow(context.Background(), `
+  UPDATE stock_imports SET status = 'validating', updated_at = NOW()
+  WHERE id = (
+    SELECT id FROM stock_imports
+    WHERE status = 'uploaded' OR (status = 'validating' AND updated_at < NOW() - $1 * INTERVAL '1 second')
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING `+stockImportColumns+`
+  `, int(staleAfter.Seconds())))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+
+	if i.Rows, err = r.getRows(i.ID); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *StockPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the stock imports", err)
+}
@@ -6,20 +6,54 @@ import (
 
 	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/pkg/secrets"
 	"github.com/Vractos/kloni/usecases/common"
 	"github.com/Vractos/kloni/usecases/store"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type StorePostgreSQL struct {
-	db     *pgxpool.Pool
-	logger metrics.Logger
+	db       *pgxpool.Pool
+	envelope *secrets.Envelope
+	logger   metrics.Logger
 }
 
-func NewStorePostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *StorePostgreSQL {
-	return &StorePostgreSQL{db: db, logger: logger}
+func NewStorePostgreSQL(db *pgxpool.Pool, envelope *secrets.Envelope, logger metrics.Logger) *StorePostgreSQL {
+	return &StorePostgreSQL{db: db, envelope: envelope, logger: logger}
+}
+
+// sealTokens encrypts the access and refresh tokens with a new data key
+func (r *StorePostgreSQL) sealTokens(c *common.MeliCredential) (*secrets.Sealed, error) {
+	sealed, err := r.envelope.Seal(c.AccessToken, c.RefreshToken)
+	if err != nil {
+		r.logger.Error("Fail to encrypt meli's credentials", err)
+		return nil, err
+	}
+	return sealed, nil
+}
+
+// openTokens decrypts the access and refresh tokens of a credential.
+// Rows without a key ID were stored before the encryption was introduced and are returned as they are.
+func (r *StorePostgreSQL) openTokens(keyID, dataKey *string, c *common.MeliCredential) error {
+	if keyID == nil || dataKey == nil {
+		return nil
+	}
+
+	tokens, err := r.envelope.Open(&secrets.Sealed{
+		KeyID:   *keyID,
+		DataKey: *dataKey,
+		Values:  []string{c.AccessToken, c.RefreshToken},
+	})
+	if err != nil {
+		r.logger.Error("Fail to decrypt meli's credentials", err, zap.String("key_id", *keyID))
+		return err
+	}
+
+	c.AccessToken, c.RefreshToken = tokens[0], tokens[1]
+	return nil
 }
 
 // Get implements store.Repository
@@ -56,13 +90,22 @@ func (r *StorePostgreSQL) Update(e *entity.Store) error {
 
 // RegisterMeliCredential implements store.Repository
 func (r *StorePostgreSQL) RegisterMeliCredential(id entity.ID, owner_id entity.ID, c *common.MeliCredential, account_name string) error {
-	_, err := r.db.Exec(context.Background(), `
-  INSERT INTO mercadolivre_credentials(id, owner_id, access_token, expires_in, user_id, refresh_token, updated_at, account_name)
-  VALUES($1,$2,$3,$4,$5,$6, $7, $8)
-  `, id, owner_id, c.AccessToken, c.ExpiresIn, c.UserID, c.RefreshToken, c.UpdatedAt, account_name)
+	sealed, err := r.sealTokens(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(context.Background(), `
+  INSERT INTO mercadolivre_credentials(id, owner_id, access_token, expires_in, user_id, refresh_token, updated_at, account_name, key_id, data_key, site_id)
+  VALUES($1,$2,$3,$4,$5,$6, $7, $8, $9, $10, $11)
+  `, id, owner_id, sealed.Values[0], c.ExpiresIn, c.UserID, sealed.Values[1], c.UpdatedAt, account_name, sealed.KeyID, sealed.DataKey, entity.SiteOrDefault(c.SiteID))
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
+			// A connected account with the same meli user already exists
+			if pgErr.Code == "23505" && pgErr.ConstraintName == "mercadolivre_credentials_connected_user_id_key" {
+				return store.ErrAccountAlreadyLinked
+			}
 			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
 		}
 		return err
@@ -81,11 +124,15 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromStoreID(id entity.ID) (*[]s
 		  mc.user_id AS mercadolivre_user_id,
 			mc.access_token,
 			mc.refresh_token,
-			mc.updated_at
+			mc.updated_at,
+			mc.key_id,
+			mc.data_key,
+			mc.site_id
 		FROM
 			mercadolivre_credentials mc
 		WHERE
 			mc.owner_id = $1
+			AND mc.disconnected_at IS NULL
     `, id)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -95,8 +142,11 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromStoreID(id entity.ID) (*[]s
 		return nil, err
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
 		credential := store.Credentials{MeliCredential: &common.MeliCredential{}}
+		var keyID, dataKey *string
 
 		err := rows.Scan(
 			&credential.ID,
@@ -105,11 +155,18 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromStoreID(id entity.ID) (*[]s
 			&credential.AccessToken,
 			&credential.RefreshToken,
 			&credential.UpdatedAt,
+			&keyID,
+			&dataKey,
+			&credential.SiteID,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		if err := r.openTokens(keyID, dataKey, credential.MeliCredential); err != nil {
+			return nil, err
+		}
+
 		credentials = append(credentials, credential)
 	}
 
@@ -124,7 +181,7 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromMeliUserID(accountId string
   WITH target_owner AS (
     SELECT owner_id
     FROM mercadolivre_credentials
-    WHERE user_id=$1
+    WHERE user_id=$1 AND disconnected_at IS NULL
   )
 	SELECT
 		mc.id AS account_id,
@@ -133,10 +190,15 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromMeliUserID(accountId string
 		mc.access_token,
     mc.user_id,
 		mc.refresh_token,
-		mc.updated_at
+		mc.updated_at,
+		mc.key_id,
+		mc.data_key,
+		mc.site_id
   	FROM
    		mercadolivre_credentials mc
     INNER JOIN target_owner to_id ON mc.owner_id = to_id.owner_id
+	WHERE
+		mc.disconnected_at IS NULL
 	`, accountId)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -146,8 +208,11 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromMeliUserID(accountId string
 		return nil, err
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
 		credential := store.Credentials{MeliCredential: &common.MeliCredential{}}
+		var keyID, dataKey *string
 
 		err := rows.Scan(
 			&credential.ID,
@@ -157,11 +222,18 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromMeliUserID(accountId string
 			&credential.UserID,
 			&credential.RefreshToken,
 			&credential.UpdatedAt,
+			&keyID,
+			&dataKey,
+			&credential.SiteID,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		if err := r.openTokens(keyID, dataKey, credential.MeliCredential); err != nil {
+			return nil, err
+		}
+
 		credentials = append(credentials, credential)
 	}
 
@@ -170,16 +242,193 @@ func (r *StorePostgreSQL) RetrieveMeliCredentialsFromMeliUserID(accountId string
 
 // UpdateMeliCredentials implements store.Repository
 func (r *StorePostgreSQL) UpdateMeliCredentials(accountId entity.ID, c *common.MeliCredential) error {
-	_, err := r.db.Exec(context.Background(), `
+	sealed, err := r.sealTokens(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(context.Background(), `
     UPDATE
     	mercadolivre_credentials
     SET
     	access_token=$1,
      	refresh_token=$2,
-      	updated_at=$3
+      	updated_at=$3,
+      	key_id=$4,
+      	data_key=$5,
+      	last_refresh_error=NULL,
+      	last_refresh_error_at=NULL
+    WHERE
+    	id=$6
+    `, sealed.Values[0], sealed.Values[1], c.UpdatedAt, sealed.KeyID, sealed.DataKey, accountId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return err
+	}
+	return nil
+}
+
+const accountColumns = `
+	mc.id,
+	mc.owner_id,
+	mc.account_name,
+	mc.user_id,
+	mc.created_at,
+	mc.updated_at,
+	mc.last_refresh_error,
+	mc.last_refresh_error_at,
+	mc.disconnected_at,
+	mc.site_id
+`
+
+func scanAccount(row pgx.Row) (*store.Account, error) {
+	account := &store.Account{}
+	err := row.Scan(
+		&account.ID,
+		&account.OwnerID,
+		&account.AccountName,
+		&account.UserID,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&account.LastRefreshError,
+		&account.LastRefreshErrorAt,
+		&account.DisconnectedAt,
+		&account.SiteID,
+	)
+	return account, err
+}
+
+// RetrieveAccounts implements store.Repository
+func (r *StorePostgreSQL) RetrieveAccounts(ownerId entity.ID) (*[]store.Account, error) {
+	accounts := []store.Account{}
+
+	rows, err := r.db.Query(context.Background(), `
+		SELECT`+accountColumns+`
+		FROM
+			mercadolivre_credentials mc
+		WHERE
+			mc.owner_id = $1
+			AND mc.disconnected_at IS NULL
+		ORDER BY
+			mc.created_at
+		`, ownerId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+
+	return &accounts, rows.Err()
+}
+
+// RetrieveAccount implements store.Repository
+func (r *StorePostgreSQL) RetrieveAccount(ownerId, accountId entity.ID) (*store.Account, error) {
+	account, err := scanAccount(r.db.QueryRow(context.Background(), `
+		SELECT`+accountColumns+`
+		FROM
+			mercadolivre_credentials mc
+		WHERE
+			mc.id = $1
+			AND mc.owner_id = $2
+			AND mc.disconnected_at IS NULL
+		`, accountId, ownerId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, store.ErrAccountNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// RetrieveAccountFromMeliUserID implements store.Repository
+func (r *StorePostgreSQL) RetrieveAccountFromMeliUserID(userId string) (*store.Account, error) {
+	account, err := scanAccount(r.db.QueryRow(context.Background(), `
+		SELECT`+accountColumns+`
+		FROM
+			mercadolivre_credentials mc
+		WHERE
+			mc.user_id = $1
+			AND mc.disconnected_at IS NULL
+		`, userId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, store.ErrAccountNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// RegisterRefreshError implements store.Repository
+func (r *StorePostgreSQL) RegisterRefreshError(accountId entity.ID, refreshErr string) (bool, error) {
+	// The row is locked before the previous error is read, so only one of the concurrent refreshes
+	// finds the account healthy
+	var failing bool
+	err := r.db.QueryRow(context.Background(), `
+    WITH previous AS (
+      SELECT id, last_refresh_error FROM mercadolivre_credentials WHERE id=$2 FOR UPDATE
+    )
+    UPDATE
+      mercadolivre_credentials mc
+    SET
+      last_refresh_error=$1,
+      last_refresh_error_at=now()
+    FROM previous
     WHERE
-    	id=$4
-    `, c.AccessToken, c.RefreshToken, c.UpdatedAt, accountId)
+      mc.id=previous.id
+    RETURNING previous.last_refresh_error IS NULL
+    `, refreshErr, accountId).Scan(&failing)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return false, err
+	}
+	return failing, nil
+}
+
+// RenameAccount implements store.Repository
+func (r *StorePostgreSQL) RenameAccount(ownerId, accountId entity.ID, accountName string) error {
+	tag, err := r.db.Exec(context.Background(), `
+    UPDATE
+      mercadolivre_credentials
+    SET
+      account_name=$1
+    WHERE
+      id=$2
+      AND owner_id=$3
+      AND disconnected_at IS NULL
+    `, accountName, accountId, ownerId)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -187,5 +436,135 @@ func (r *StorePostgreSQL) UpdateMeliCredentials(accountId entity.ID, c *common.M
 		}
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrAccountNotFound
+	}
 	return nil
 }
+
+// DisconnectAccount implements store.Repository
+//
+// The row isn't deleted because orders.account_id references it, so the order
+// history of the account is kept. The tokens are wiped, so they can't be used anymore.
+func (r *StorePostgreSQL) DisconnectAccount(ownerId, accountId entity.ID) error {
+	tag, err := r.db.Exec(context.Background(), `
+    UPDATE
+      mercadolivre_credentials
+    SET
+      access_token='',
+      refresh_token='',
+      key_id=NULL,
+      data_key=NULL,
+      disconnected_at=now()
+    WHERE
+      id=$1
+      AND owner_id=$2
+      AND disconnected_at IS NULL
+    `, accountId, ownerId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrAccountNotFound
+	}
+	return nil
+}
+
+// ReEncryptMeliCredentials encrypts, with the primary key, every credential that is
+// still stored in plaintext or that was encrypted with a key that has been rotated.
+// It's safe to run it more than once, only the outdated rows are touched.
+//
+// Returns the number of credentials that were re-encrypted.
+func (r *StorePostgreSQL) ReEncryptMeliCredentials() (int, error) {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+    SELECT
+      id,
+      access_token,
+      refresh_token,
+      key_id,
+      data_key
+    FROM
+      mercadolivre_credentials
+    WHERE
+      (key_id IS NULL OR key_id <> $1)
+      AND disconnected_at IS NULL
+    FOR UPDATE
+    `, r.envelope.PrimaryKeyID())
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		}
+		return 0, err
+	}
+
+	type outdated struct {
+		id         entity.ID
+		credential common.MeliCredential
+	}
+	var outdatedCredentials []outdated
+
+	for rows.Next() {
+		var o outdated
+		var keyID, dataKey *string
+		if err := rows.Scan(&o.id, &o.credential.AccessToken, &o.credential.RefreshToken, &keyID, &dataKey); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		if err := r.openTokens(keyID, dataKey, &o.credential); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		outdatedCredentials = append(outdatedCredentials, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, o := range outdatedCredentials {
+		sealed, err := r.sealTokens(&o.credential)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec(ctx, `
+      UPDATE
+        mercadolivre_credentials
+      SET
+        access_token=$1,
+        refresh_token=$2,
+        key_id=$3,
+        data_key=$4
+      WHERE
+        id=$5
+      `, sealed.Values[0], sealed.Values[1], sealed.KeyID, sealed.DataKey, o.id)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+			}
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logger.Error("Error to commit the re-encrypted credentials", err)
+		return 0, errors.New("error to commit the re-encrypted credentials")
+	}
+
+	return len(outdatedCredentials), nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type WebhookPostgreSQL struct {
+	db     *pgxpool.Pool
+	logger metrics.Logger
+}
+
+func NewWebhookPostgreSQL(db *pgxpool.Pool, logger metrics.Logger) *WebhookPostgreSQL {
+	return &WebhookPostgreSQL{db: db, logger: logger}
+}
+
+// RegisterSubscription implements webhook.Repository
+func (r *WebhookPostgreSQL) RegisterSubscription(s *webhook.Subscription) error {
+	events := make([]string, len(s.Events))
+	for i, event := range s.Events {
+		events[i] = string(event)
+	}
+
+	_, err := r.db.Exec(context.Background(), `
+  INSERT INTO webhook_subscriptions(id, store_id, url, secret, events, active, created_at)
+  VALUES($1, $2, $3, $4, $5, $6, $7)
+  `, s.ID, s.Store, s.URL, s.Secret, events, s.Active, s.CreatedAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// DisableSubscription implements webhook.Repository
+func (r *WebhookPostgreSQL) DisableSubscription(storeId, subscriptionId entity.ID) error {
+	var disabled int
+	err := r.db.QueryRow(context.Background(), `
+  WITH disabled AS (
+    UPDATE webhook_subscriptions SET active = FALSE, disabled_at = COALESCE(disabled_at, NOW())
+    WHERE id = $2 AND store_id = $1
+    RETURNING id
+  ), cancelled AS (
+    UPDATE webhook_deliveries SET status = 'failed', last_error = 'subscription disabled'
+    WHERE subscription_id IN (SELECT id FROM disabled) AND status = 'pending'
+  )
+  SELECT COUNT(*) FROM disabled
+  `, storeId, subscriptionId).Scan(&disabled)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	if disabled == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+const subscriptionColumns = `id, store_id, url, secret, events, active, created_at`
+
+func scanSubscription(row pgx.Row) (*webhook.Subscription, error) {
+	var s webhook.Subscription
+	var events []string
+	if err := row.Scan(&s.ID, &s.Store, &s.URL, &s.Secret, &events, &s.Active, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	s.Events = make([]entity.EventType, len(events))
+	for i, event := range events {
+		s.Events[i] = entity.EventType(event)
+	}
+	return &s, nil
+}
+
+func (r *WebhookPostgreSQL) querySubscriptions(query string, args ...any) ([]webhook.Subscription, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []webhook.Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *s)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ListSubscriptions implements webhook.Repository
+func (r *WebhookPostgreSQL) ListSubscriptions(storeId entity.ID) ([]webhook.Subscription, error) {
+	return r.querySubscriptions(`
+  SELECT `+subscriptionColumns+`
+  FROM webhook_subscriptions
+  WHERE store_id = $1
+  ORDER BY created_at DESC
+  `, storeId)
+}
+
+// SubscribedTo implements webhook.Repository
+func (r *WebhookPostgreSQL) SubscribedTo(storeId entity.ID, eventType entity.EventType) ([]webhook.Subscription, error) {
+	return r.querySubscriptions(`
+  SELECT `+subscriptionColumns+`
+  FROM webhook_subscriptions
+  WHERE store_id = $1 AND active AND $2 = ANY(events)
+  `, storeId, string(eventType))
+}
+
+// GetSubscription implements webhook.Repository
+func (r *WebhookPostgreSQL) GetSubscription(storeId, subscriptionId entity.ID) (*webhook.Subscription, error) {
+	s, err := scanSubscription(r.db.QueryRow(context.Background(), `
+  SELECT `+subscriptionColumns+`
+  FROM webhook_subscriptions
+  WHERE id = $2 AND store_id = $1
+  `, storeId, subscriptionId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logError(err)
+		return nil, err
+	}
+	return s, nil
+}
+
+// RegisterDeliveries implements webhook.Repository
+func (r *WebhookPostgreSQL) RegisterDeliveries(deliveries []webhook.Delivery) error {
+	ctx := context.Background()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for _, d := range deliveries {
+		_, err := tx.Exec(ctx, `
+    INSERT INTO webhook_deliveries(id, subscription_id, event_id, event_type, payload, status, attempts,
+    last_status_code, last_error, next_attempt_at, created_at, delivered_at)
+    VALUES($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0), NULLIF($9, ''), $10, $11, $12)
+    `, d.ID, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts,
+			d.LastStatusCode, d.LastError, d.NextAttemptAt, d.CreatedAt, d.DeliveredAt)
+		if err != nil {
+			r.logError(err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateDelivery implements webhook.Repository
+func (r *WebhookPostgreSQL) UpdateDelivery(d *webhook.Delivery) error {
+	_, err := r.db.Exec(context.Background(), `
+  UPDATE webhook_deliveries SET
+  status = $2, attempts = $3, last_status_code = NULLIF($4, 0), last_error = NULLIF($5, ''),
+  next_attempt_at = $6, delivered_at = $7
+  WHERE id = $1 AND status = 'pending'
+  `, d.ID, d.Status, d.Attempts, d.LastStatusCode, d.LastError, d.NextAttemptAt, d.DeliveredAt)
+	if err != nil {
+		r.logError(err)
+		return err
+	}
+	return nil
+}
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload::TEXT, d.status, d.attempts,
+  COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.next_attempt_at, d.created_at, d.delivered_at`
+
+func deliveryFields(d *webhook.Delivery, payload *string) []any {
+	return []any{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, payload, &d.Status, &d.Attempts,
+		&d.LastStatusCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt}
+}
+
+// ClaimDueDeliveries implements webhook.Repository
+func (r *WebhookPostgreSQL) ClaimDueDeliveries(limit int, lease time.Duration) ([]webhook.DueDelivery, error) {
+	rows, err := r.db.Query(context.Background(), `
+  WITH due AS (
+    SELECT d.id
+    FROM webhook_deliveries d
+    JOIN webhook_subscriptions s ON s.id = d.subscription_id
+    WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.active
+    ORDER BY d.next_attempt_at
+    LIMIT $1
+    FOR UPDATE OF d SKIP LOCKED
+  )
+  UPDATE webhook_deliveries d SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+  FROM due, webhook_subscriptions s
+  WHERE d.id = due.id AND s.id = d.subscription_id
+  RETURNING `+deliveryColumns+`, s.url, s.secret
+  `, limit, int(lease.Seconds()))
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []webhook.DueDelivery{}
+	for rows.Next() {
+		var d webhook.DueDelivery
+		var payload string
+		if err := rows.Scan(append(deliveryFields(&d.Delivery, &payload), &d.URL, &d.Secret)...); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		due = append(due, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return due, nil
+}
+
+// ListDeliveries implements webhook.Repository
+func (r *WebhookPostgreSQL) ListDeliveries(subscriptionId entity.ID, limit int) ([]webhook.Delivery, error) {
+	rows, err := r.db.Query(context.Background(), `
+  SELECT `+deliveryColumns+`
+  FROM webhook_deliveries d
+  WHERE d.subscription_id = $1
+  ORDER BY d.created_at DESC
+  LIMIT $2
+  `, subscriptionId, limit)
+	if err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		var d webhook.Delivery
+		var payload string
+		if err := rows.Scan(deliveryFields(&d, &payload)...); err != nil {
+			r.logError(err)
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.logError(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookPostgreSQL) logError(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		r.logger.Error(pgErr.Message, pgErr, zap.String("db_error_code", pgErr.Code))
+		return
+	}
+	r.logger.Error("Error to query the webhooks", err)
+}
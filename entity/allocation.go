@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidAllocationPolicy is returned when a policy doesn't have rules for any account
+	ErrInvalidAllocationPolicy = errors.New("invalid allocation policy")
+	// ErrInvalidAccountAllocation is returned when a rule doesn't have an account, is repeated,
+	// or has a negative buffer or cap, or a percentage out of 1-100
+	ErrInvalidAccountAllocation = errors.New("invalid account allocation")
+)
+
+// AccountAllocation is how much of the true stock of a SKU is published on the listings of an account
+type AccountAllocation struct {
+	AccountID ID
+	// Units kept in reserve, never published
+	Buffer int
+	// Share of the stock published, from 1 to 100
+	Percentage int
+	// Maximum quantity published, nil when there's no cap
+	MaxQuantity *int
+}
+
+// AllocationPolicy distributes the true stock of a SKU across the accounts of the store,
+// so the accounts don't oversell it. An empty SKU is the default policy of the store.
+type AllocationPolicy struct {
+	ID      ID
+	StoreID ID
+	Sku     string
+	// The priority account isn't subject to the buffers, it keeps selling the reserved units
+	PriorityAccount *ID
+	Accounts        []AccountAllocation
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func NewAllocationPolicy(store ID, sku string, priority *ID, accounts []AccountAllocation) (*AllocationPolicy, error) {
+	now := time.Now().UTC()
+	policy := &AllocationPolicy{
+		ID:        NewID(),
+		StoreID:   store,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := policy.Define(sku, priority, accounts); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Define replaces the SKU, the priority account and the rules of the policy.
+// A rule without a percentage publishes the whole stock.
+func (p *AllocationPolicy) Define(sku string, priority *ID, accounts []AccountAllocation) error {
+	if len(accounts) == 0 {
+		return ErrInvalidAllocationPolicy
+	}
+
+	seen := make(map[ID]bool, len(accounts))
+	cleaned := make([]AccountAllocation, len(accounts))
+	for i, a := range accounts {
+		if a.Percentage == 0 {
+			a.Percentage = 100
+		}
+		if a.AccountID == (ID{}) || seen[a.AccountID] || a.Buffer < 0 || a.Percentage < 0 || a.Percentage > 100 ||
+			(a.MaxQuantity != nil && *a.MaxQuantity < 0) {
+			return ErrInvalidAccountAllocation
+		}
+		seen[a.AccountID] = true
+		cleaned[i] = a
+	}
+	if priority != nil && *priority == (ID{}) {
+		priority = nil
+	}
+
+	p.Sku = strings.TrimSpace(sku)
+	p.PriorityAccount = priority
+	p.Accounts = cleaned
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Quantity is the quantity published on the listings of an account for a true stock.
+// The buffer is removed first, then the percentage and the cap are applied.
+// An account without a rule publishes the whole stock.
+func (p *AllocationPolicy) Quantity(account ID, stock int) int {
+	quantity := stock
+	for _, a := range p.Accounts {
+		if a.AccountID != account {
+			continue
+		}
+		if p.PriorityAccount == nil || *p.PriorityAccount != account {
+			quantity -= a.Buffer
+		}
+		quantity = quantity * a.Percentage / 100
+		if a.MaxQuantity != nil && quantity > *a.MaxQuantity {
+			quantity = *a.MaxQuantity
+		}
+		break
+	}
+	if quantity < 0 {
+		return 0
+	}
+	return quantity
+}
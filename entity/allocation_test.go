@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewAllocationPolicy(t *testing.T) {
+	account := NewID()
+	negative := -1
+	tests := []struct {
+		name     string
+		accounts []AccountAllocation
+		wantErr  error
+	}{
+		{name: "valid policy", accounts: []AccountAllocation{{AccountID: account, Buffer: 2, Percentage: 50}}},
+		{name: "without rules", wantErr: ErrInvalidAllocationPolicy},
+		{name: "rule without account", accounts: []AccountAllocation{{Buffer: 2}}, wantErr: ErrInvalidAccountAllocation},
+		{name: "negative buffer", accounts: []AccountAllocation{{AccountID: account, Buffer: -1}}, wantErr: ErrInvalidAccountAllocation},
+		{name: "percentage above 100", accounts: []AccountAllocation{{AccountID: account, Percentage: 120}}, wantErr: ErrInvalidAccountAllocation},
+		{name: "negative cap", accounts: []AccountAllocation{{AccountID: account, MaxQuantity: &negative}}, wantErr: ErrInvalidAccountAllocation},
+		{
+			name:     "repeated account",
+			accounts: []AccountAllocation{{AccountID: account, Buffer: 1}, {AccountID: account, Buffer: 2}},
+			wantErr:  ErrInvalidAccountAllocation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewAllocationPolicy(NewID(), " SKU-1 ", nil, tt.accounts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewAllocationPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && policy.Sku != "SKU-1" {
+				t.Errorf("NewAllocationPolicy() sku = %q, want %q", policy.Sku, "SKU-1")
+			}
+		})
+	}
+}
+
+func TestAllocationPolicyQuantity(t *testing.T) {
+	main, secondary, capped, other := NewID(), NewID(), NewID(), NewID()
+	five := 5
+	policy, err := NewAllocationPolicy(NewID(), "SKU-1", &main, []AccountAllocation{
+		{AccountID: main, Buffer: 2},
+		{AccountID: secondary, Buffer: 2, Percentage: 50},
+		{AccountID: capped, MaxQuantity: &five},
+	})
+	if err != nil {
+		t.Fatalf("NewAllocationPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		account ID
+		stock   int
+		want    int
+	}{
+		{name: "priority account ignores the buffer", account: main, stock: 10, want: 10},
+		{name: "buffer and percentage", account: secondary, stock: 10, want: 4},
+		{name: "stock within the buffer", account: secondary, stock: 1, want: 0},
+		{name: "capped", account: capped, stock: 10, want: 5},
+		{name: "below the cap", account: capped, stock: 3, want: 3},
+		{name: "account without rule", account: other, stock: 10, want: 10},
+		{name: "negative stock", account: other, stock: -3, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Quantity(tt.account, tt.stock); got != tt.want {
+				t.Errorf("Quantity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -98,7 +98,7 @@ func NewAnnouncement(rootAnn *common.MeliAnnouncement) (*Announcement, error) {
 		Title:             rootAnn.Title,
 		AvailableQuantity: rootAnn.Quantity,
 		Price:             rootAnn.Price,
-		CurrencyID:        "BRL",
+		CurrencyID:        SiteOrDefault(rootAnn.SiteID).Currency(),
 		BuyingMode:        "buy_it_now",
 		CategoryID:        rootAnn.CategoryID,
 		Condition:         rootAnn.Condition,
@@ -110,6 +110,21 @@ func NewAnnouncement(rootAnn *common.MeliAnnouncement) (*Announcement, error) {
 	}, nil
 }
 
+// ChangeSite adapts the announcement to be published in another site.
+// The price must already be converted to the currency of the site and the
+// category must be one of the site, since the category IDs differ between sites.
+func (a *Announcement) ChangeSite(site Site, price float64, categoryID string) {
+	a.CurrencyID = site.Currency()
+	a.ListingTypeID = site.ListingType(a.ListingTypeID)
+	a.CategoryID = categoryID
+	// Some currencies don't have cents
+	if site == Chile || site == Colombia {
+		a.Price = math.Round(price)
+	} else {
+		a.Price = math.Round(price*100) / 100
+	}
+}
+
 func (a *Announcement) ChangeTitle(title string) {
 	a.Title = title
 }
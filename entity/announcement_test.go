@@ -222,10 +222,7 @@ func TestNewAnnouncement(t *testing.T) {
 							ValueName: "Apple",
 						},
 					},
-					Variations: []struct {
-						ID                int
-						AvailableQuantity int
-					}{
+					Variations: []common.MeliVariation{
 						{
 							ID:                1,
 							AvailableQuantity: 1,
@@ -0,0 +1,103 @@
+package entity
+
+import "time"
+
+type EventType string
+
+const (
+	OrderProcessed EventType = "order.processed"
+	QuantitySynced EventType = "quantity.synced"
+	CloneCreated   EventType = "clone.created"
+	SyncFailed     EventType = "sync.failed"
+	// Sent once when an account stops being refreshed, until it's refreshed or re-authorized
+	CredentialExpired EventType = "credential.expired"
+	// Sent when a subscription is tested, subscriptions don't need to subscribe to it
+	Ping EventType = "ping"
+)
+
+// EventTypes are the events that can be subscribed
+var EventTypes = []EventType{OrderProcessed, QuantitySynced, CloneCreated, SyncFailed, CredentialExpired}
+
+func (t EventType) IsValid() bool {
+	for _, eventType := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened in a store, sent to the webhooks it subscribed
+type Event struct {
+	ID      ID
+	StoreID ID
+	Type    EventType
+	// Serialized as JSON
+	Data       any
+	OccurredAt time.Time
+}
+
+func NewEvent(store ID, eventType EventType, data any) *Event {
+	return &Event{
+		ID:         NewID(),
+		StoreID:    store,
+		Type:       eventType,
+		Data:       data,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+/*
+Data of the events
+*/
+
+type OrderProcessedData struct {
+	OrderID       ID               `json:"order_id"`
+	MarketplaceID string           `json:"marketplace_id"`
+	AccountID     ID               `json:"account_id"`
+	Status        OrderStatus      `json:"status"`
+	TotalAmount   float64          `json:"total_amount"`
+	CurrencyID    string           `json:"currency_id"`
+	Items         []EventOrderItem `json:"items"`
+}
+
+type EventOrderItem struct {
+	Sku         string `json:"sku"`
+	Title       string `json:"title"`
+	Quantity    int    `json:"quantity"`
+	VariationID int    `json:"variation_id,omitempty"`
+}
+
+type QuantitySyncedData struct {
+	MarketplaceID string             `json:"marketplace_id"`
+	Sku           string             `json:"sku"`
+	Clones        []EventSyncedClone `json:"clones"`
+}
+
+type EventSyncedClone struct {
+	AccountID      ID     `json:"account_id"`
+	AnnouncementID string `json:"announcement_id"`
+	VariationID    int    `json:"variation_id,omitempty"`
+	Quantity       int    `json:"quantity"`
+}
+
+type SyncFailedData struct {
+	MarketplaceID string `json:"marketplace_id"`
+	Sku           string `json:"sku"`
+	Error         string `json:"error"`
+}
+
+type CloneCreatedData struct {
+	AccountID      ID     `json:"account_id"`
+	RootID         string `json:"root_id"`
+	AnnouncementID string `json:"announcement_id"`
+	Title          string `json:"title"`
+	Sku            string `json:"sku"`
+}
+
+type CredentialExpiredData struct {
+	AccountID   ID     `json:"account_id"`
+	UserID      string `json:"user_id"`
+	AccountName string `json:"account_name,omitempty"`
+	Error       string `json:"error"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "testing"
+
+func TestEventTypeIsValid(t *testing.T) {
+	for _, eventType := range EventTypes {
+		if !eventType.IsValid() {
+			t.Errorf("%q should be valid", eventType)
+		}
+	}
+	for _, eventType := range []EventType{Ping, "", "order.created"} {
+		if eventType.IsValid() {
+			t.Errorf("%q shouldn't be valid", eventType)
+		}
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	store := NewID()
+	event := NewEvent(store, OrderProcessed, map[string]string{"order_id": "1"})
+	if event.StoreID != store || event.Type != OrderProcessed || event.OccurredAt.IsZero() {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if event.ID == NewEvent(store, OrderProcessed, nil).ID {
+		t.Errorf("events should have different IDs")
+	}
+}
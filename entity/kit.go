@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidKit is returned when a kit doesn't have a SKU or components
+	ErrInvalidKit = errors.New("invalid kit")
+	// ErrInvalidComponent is returned when a component doesn't have a SKU, has a non-positive
+	// quantity, is repeated or is the kit itself
+	ErrInvalidComponent = errors.New("invalid kit component")
+)
+
+// KitComponent is a SKU that is part of a kit, with the units of it in each kit
+type KitComponent struct {
+	Sku      string
+	Quantity int
+}
+
+// Kit is a SKU composed of other SKUs, e.g. "2x filter + 1x oil".
+// Its stock depends on the stock of its components.
+type Kit struct {
+	ID         ID
+	StoreID    ID
+	Sku        string
+	Title      string
+	Components []KitComponent
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewKit(store ID, sku, title string, components []KitComponent) (*Kit, error) {
+	now := time.Now().UTC()
+	kit := &Kit{
+		ID:        NewID(),
+		StoreID:   store,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := kit.Define(sku, title, components); err != nil {
+		return nil, err
+	}
+	return kit, nil
+}
+
+// Define replaces the SKU, the title and the components of the kit
+func (k *Kit) Define(sku, title string, components []KitComponent) error {
+	sku = strings.TrimSpace(sku)
+	if sku == "" || len(components) == 0 {
+		return ErrInvalidKit
+	}
+
+	seen := make(map[string]bool, len(components))
+	cleaned := make([]KitComponent, len(components))
+	for i, c := range components {
+		c.Sku = strings.TrimSpace(c.Sku)
+		if c.Sku == "" || c.Sku == sku || c.Quantity <= 0 || seen[c.Sku] {
+			return ErrInvalidComponent
+		}
+		seen[c.Sku] = true
+		cleaned[i] = c
+	}
+
+	k.Sku = sku
+	k.Title = strings.TrimSpace(title)
+	k.Components = cleaned
+	k.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Contains reports if the SKU is a component of the kit
+func (k *Kit) Contains(sku string) bool {
+	for _, c := range k.Components {
+		if c.Sku == sku {
+			return true
+		}
+	}
+	return false
+}
+
+// Availability is the number of kits that can be assembled with the stock of the components,
+// a component missing from the stock has no units
+func (k *Kit) Availability(stock map[string]int) int {
+	available := -1
+	for _, c := range k.Components {
+		units := stock[c.Sku] / c.Quantity
+		if units < 0 {
+			units = 0
+		}
+		if available == -1 || units < available {
+			available = units
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
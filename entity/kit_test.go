@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewKit(t *testing.T) {
+	tests := []struct {
+		name       string
+		sku        string
+		components []KitComponent
+		wantErr    error
+	}{
+		{
+			name:       "valid kit",
+			sku:        " KIT-1 ",
+			components: []KitComponent{{Sku: "FILTER", Quantity: 2}, {Sku: "OIL", Quantity: 1}},
+		},
+		{name: "without sku", sku: " ", components: []KitComponent{{Sku: "OIL", Quantity: 1}}, wantErr: ErrInvalidKit},
+		{name: "without components", sku: "KIT-1", wantErr: ErrInvalidKit},
+		{name: "component without sku", sku: "KIT-1", components: []KitComponent{{Sku: "", Quantity: 1}}, wantErr: ErrInvalidComponent},
+		{name: "component without units", sku: "KIT-1", components: []KitComponent{{Sku: "OIL", Quantity: 0}}, wantErr: ErrInvalidComponent},
+		{name: "kit inside itself", sku: "KIT-1", components: []KitComponent{{Sku: "KIT-1", Quantity: 1}}, wantErr: ErrInvalidComponent},
+		{
+			name:       "repeated component",
+			sku:        "KIT-1",
+			components: []KitComponent{{Sku: "OIL", Quantity: 1}, {Sku: " OIL", Quantity: 2}},
+			wantErr:    ErrInvalidComponent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kit, err := NewKit(NewID(), tt.sku, "Kit", tt.components)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewKit() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && kit.Sku != "KIT-1" {
+				t.Errorf("NewKit() sku = %q, want %q", kit.Sku, "KIT-1")
+			}
+		})
+	}
+}
+
+func TestKitAvailability(t *testing.T) {
+	kit, err := NewKit(NewID(), "KIT-1", "Filter and oil", []KitComponent{
+		{Sku: "FILTER", Quantity: 2},
+		{Sku: "OIL", Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("NewKit() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		stock map[string]int
+		want  int
+	}{
+		{name: "limited by the filters", stock: map[string]int{"FILTER": 7, "OIL": 10}, want: 3},
+		{name: "limited by the oil", stock: map[string]int{"FILTER": 20, "OIL": 4}, want: 4},
+		{name: "missing component", stock: map[string]int{"FILTER": 20}, want: 0},
+		{name: "negative stock", stock: map[string]int{"FILTER": -2, "OIL": 4}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kit.Availability(tt.stock); got != tt.want {
+				t.Errorf("Availability() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if !kit.Contains("OIL") || kit.Contains("KIT-1") {
+		t.Errorf("Contains() should only report the components")
+	}
+}
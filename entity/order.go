@@ -51,27 +51,61 @@ type OrderItem struct {
 	Quantity    int
 	Sku         string
 	VariationID int
+	UnitPrice   float64
+	CurrencyID  string
+	// Listing type of the announcement when it was sold
+	ListingTypeID string
 }
 
 type Order struct {
 	ID            ID
 	AccountID     ID
 	MarketplaceID string
-	DateCreated   time.Time
-	Items         []OrderItem
-	Status        OrderStatus
+	// When the order was created on the marketplace
+	DateCreated time.Time
+	DateClosed  *time.Time
+	LastUpdated *time.Time
+	Items       []OrderItem
+	Status      OrderStatus
+	TotalAmount float64
+	PaidAmount  float64
+	CurrencyID  string
+	// Empty when the order isn't part of a pack
+	PackID string
+	// Number of registered orders of the pack, zero when the order isn't part of a pack
+	PackSize int
+	// Empty when the order doesn't have a shipment
+	ShippingID    string
+	BuyerNickname string
+	// Changes to the clones of the sold items, executed by the dispatcher once the order is registered
+	SyncActions []SyncAction
+	// Changes to the true stock of the sold SKUs, applied with the registration of the order
+	StockChanges []StockChange
 }
 
-func NewOrder(account ID, marketplace_id string, items []OrderItem, status OrderStatus) (*Order, error) {
+// StockChange is the change of the true stock of a SKU with an allocation policy
+type StockChange struct {
+	StoreID ID
+	Sku     string
+	Delta   int
+}
+
+// NewOrder creates an order, dateCreated is the creation date on the marketplace
+// and the current time is used when it's unknown.
+func NewOrder(account ID, marketplace_id string, items []OrderItem, status OrderStatus, dateCreated time.Time) (*Order, error) {
 	for i := range items {
 		items[i].ID = NewID()
 	}
 
+	if dateCreated.IsZero() {
+		dateCreated = time.Now()
+	}
+
 	return &Order{
 		ID:            NewID(),
 		AccountID:     account,
 		MarketplaceID: marketplace_id,
-		DateCreated:   time.Now().UTC(),
+		DateCreated:   dateCreated.UTC(),
 		Items:         items,
 		Status:        status,
 	}, nil
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -83,6 +84,7 @@ type NewOrderArguments struct {
 	MarketplaceID string
 	Items         []OrderItem
 	Status        OrderStatus
+	DateCreated   time.Time
 }
 
 func TestNewOrder(t *testing.T) {
@@ -166,6 +168,28 @@ func TestNewOrder(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Order with Marketplace Date",
+			input: NewOrderArguments{
+				AccountID:     storeID,
+				MarketplaceID: "MLB200004894",
+				Items: []OrderItem{
+					{Title: "Item 1", Quantity: 1, Sku: "SKU-1", UnitPrice: 10.5, CurrencyID: "BRL"},
+				},
+				Status:      Paid,
+				DateCreated: time.Date(2022, 10, 30, 16, 19, 20, 0, time.FixedZone("BRT", -4*60*60)),
+			},
+			want: &Order{
+				AccountID:     storeID,
+				MarketplaceID: "MLB200004894",
+				DateCreated:   time.Date(2022, 10, 30, 20, 19, 20, 0, time.UTC),
+				Items: []OrderItem{
+					{Title: "Item 1", Quantity: 1, Sku: "SKU-1", UnitPrice: 10.5, CurrencyID: "BRL"},
+				},
+				Status: Paid,
+			},
+			wantErr: false,
+		},
 		{
 			name: "Order with Empty SKU",
 			input: NewOrderArguments{
@@ -191,7 +215,7 @@ func TestNewOrder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewOrder(tt.input.AccountID, tt.input.MarketplaceID, tt.input.Items, tt.input.Status)
+			got, err := NewOrder(tt.input.AccountID, tt.input.MarketplaceID, tt.input.Items, tt.input.Status, tt.input.DateCreated)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewOrder() error = %v, wantErr %v", (err != nil), tt.wantErr)
@@ -217,7 +241,7 @@ func TestNewOrder(t *testing.T) {
 				t.Errorf("Order MarketplaceID = %v, want %v", got.MarketplaceID, tt.want.MarketplaceID)
 			}
 
-			if diff := got.DateCreated.Sub(tt.want.DateCreated); diff > time.Second {
+			if diff := got.DateCreated.Sub(tt.want.DateCreated); diff > time.Second || diff < -time.Second {
 				t.Errorf("Order DateCreated = %v, want %v", got.DateCreated.Format(time.RFC3339), tt.want.DateCreated.Format(time.RFC3339))
 			}
 
@@ -242,11 +266,102 @@ func TestNewOrder(t *testing.T) {
 				if item.VariationID != tt.want.Items[i].VariationID {
 					t.Errorf("Order Item VariationID = %v, want %v", item.VariationID, tt.want.Items[i].VariationID)
 				}
+				if item.UnitPrice != tt.want.Items[i].UnitPrice || item.CurrencyID != tt.want.Items[i].CurrencyID {
+					t.Errorf("Order Item price = %v %v, want %v %v", item.UnitPrice, item.CurrencyID, tt.want.Items[i].UnitPrice, tt.want.Items[i].CurrencyID)
+				}
 			}
 
 			if got.Status != tt.want.Status {
 				t.Errorf("Order Status = %v, want %v", got.Status, tt.want.Status)
 			}
+
+			if got.DateCreated.Location() != time.UTC {
+				t.Errorf("Order DateCreated = %v, want UTC", got.DateCreated)
+			}
+		})
+	}
+}
+
+func TestNewSyncAction(t *testing.T) {
+	account := ID(uuid.New())
+
+	done := NewSyncAction(account, "MLB123", 0, 4, nil)
+	if done.Status != SyncActionDone || done.Error != "" || done.Quantity != 4 || done.AccountID != account {
+		t.Errorf("unexpected sync action %+v", done)
+	}
+
+	failed := NewSyncAction(account, "MLB123", 10, 4, errors.New("item under review"))
+	if failed.Status != SyncActionFailed || failed.Error != "item under review" || failed.VariationID != 10 {
+		t.Errorf("unexpected sync action %+v", failed)
+	}
+}
+
+func TestSyncActionComplete(t *testing.T) {
+	account := ID(uuid.New())
+	retryAt := time.Now().Add(time.Minute)
+
+	done := NewPendingSyncAction(SyncQuantity, account, "MLB123", 0, "SKU-1", 4, time.Now())
+	done.Complete(nil, retryAt, false)
+	if done.Status != SyncActionDone || done.Attempts != 1 || done.ExecutedAt == nil {
+		t.Errorf("unexpected sync action %+v", done)
+	}
+
+	retried := NewPendingSyncAction(SyncQuantity, account, "MLB123", 0, "SKU-1", 4, time.Now())
+	retried.Complete(errors.New("timeout"), retryAt, false)
+	if retried.Status != SyncActionPending || retried.Error != "timeout" || !retried.NextAttemptAt.Equal(retryAt) || retried.ExecutedAt != nil {
+		t.Errorf("unexpected sync action %+v", retried)
+	}
+
+	retried.Complete(errors.New("timeout"), retryAt, true)
+	if retried.Status != SyncActionFailed || retried.Attempts != 2 || retried.ExecutedAt == nil {
+		t.Errorf("unexpected sync action %+v", retried)
+	}
+}
+
+func TestNewSyncProgress(t *testing.T) {
+	account := ID(uuid.New())
+	withStatus := func(status SyncActionStatus) SyncAction {
+		action := NewPendingSyncAction(SyncQuantity, account, "MLB123", 0, "SKU-1", 4, time.Now())
+		action.Status = status
+		return *action
+	}
+
+	tests := []struct {
+		name    string
+		actions []SyncAction
+		want    SyncProgress
+	}{
+		{
+			name: "without actions",
+			want: SyncProgress{Status: SyncProgressComplete},
+		},
+		{
+			name:    "complete",
+			actions: []SyncAction{withStatus(SyncActionDone), withStatus(SyncActionSuperseded)},
+			want:    SyncProgress{Status: SyncProgressComplete, Total: 2, Done: 1, Superseded: 1},
+		},
+		{
+			name:    "pending",
+			actions: []SyncAction{withStatus(SyncActionDone), withStatus(SyncActionFailed), withStatus(SyncActionPending)},
+			want:    SyncProgress{Status: SyncProgressPending, Total: 3, Done: 1, Pending: 1, Failed: 1},
+		},
+		{
+			name:    "partial",
+			actions: []SyncAction{withStatus(SyncActionDone), withStatus(SyncActionFailed)},
+			want:    SyncProgress{Status: SyncProgressPartial, Total: 2, Done: 1, Failed: 1},
+		},
+		{
+			name:    "failed",
+			actions: []SyncAction{withStatus(SyncActionFailed)},
+			want:    SyncProgress{Status: SyncProgressFailed, Total: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewSyncProgress(tt.actions); got != tt.want {
+				t.Errorf("NewSyncProgress() = %+v, want %+v", got, tt.want)
+			}
 		})
 	}
 }
@@ -0,0 +1,81 @@
+package entity
+
+// Site is a Mercado Livre marketplace, e.g. MLB (Brazil) or MLA (Argentina)
+type Site string
+
+const (
+	Brazil    Site = "MLB"
+	Argentina Site = "MLA"
+	Mexico    Site = "MLM"
+	Chile     Site = "MLC"
+	Colombia  Site = "MCO"
+	Uruguay   Site = "MLU"
+	Peru      Site = "MPE"
+)
+
+// The site of the accounts linked before the sites were stored
+const DefaultSite = Brazil
+
+var siteCurrencies = map[Site]string{
+	Brazil:    "BRL",
+	Argentina: "ARS",
+	Mexico:    "MXN",
+	Chile:     "CLP",
+	Colombia:  "COP",
+	Uruguay:   "UYU",
+	Peru:      "PEN",
+}
+
+// Listing types available in every site
+var commonListingTypes = []ListingType{
+	ListingType(Premium),
+	ListingType(Classic),
+	ListingType(Free),
+}
+
+// Sites that still offer listing types beyond the common ones
+var siteListingTypes = map[Site][]ListingType{
+	Chile:    {ListingType(Diamante), ListingType(Ouro), ListingType(Prata), ListingType(PaiBronzed)},
+	Colombia: {ListingType(Diamante), ListingType(Ouro), ListingType(Prata), ListingType(PaiBronzed)},
+	Uruguay:  {ListingType(Diamante), ListingType(Ouro), ListingType(Prata), ListingType(PaiBronzed)},
+	Peru:     {ListingType(Diamante), ListingType(Ouro), ListingType(Prata), ListingType(PaiBronzed)},
+}
+
+// SiteOrDefault returns the site, or the default one when it's empty
+func SiteOrDefault(site string) Site {
+	if site == "" {
+		return DefaultSite
+	}
+	return Site(site)
+}
+
+// IsValid checks if the site is supported
+func (s Site) IsValid() bool {
+	_, ok := siteCurrencies[s]
+	return ok
+}
+
+// Currency returns the currency of the site, or the one of the default site when
+// the site is unknown
+func (s Site) Currency() string {
+	if currency, ok := siteCurrencies[s]; ok {
+		return currency
+	}
+	return siteCurrencies[DefaultSite]
+}
+
+// ListingType returns the listing type to be used in the site. When the site
+// doesn't offer the given listing type, the classic one is used.
+func (s Site) ListingType(lt ListingType) ListingType {
+	for _, available := range commonListingTypes {
+		if available == lt {
+			return lt
+		}
+	}
+	for _, available := range siteListingTypes[s] {
+		if available == lt {
+			return lt
+		}
+	}
+	return ListingType(Classic)
+}
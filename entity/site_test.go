@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/Vractos/kloni/usecases/common"
+)
+
+func TestSite(t *testing.T) {
+	tests := []struct {
+		site            Site
+		wantCurrency    string
+		wantListingType ListingType
+	}{
+		{site: Brazil, wantCurrency: "BRL", wantListingType: ListingType(Classic)},
+		{site: Argentina, wantCurrency: "ARS", wantListingType: ListingType(Classic)},
+		{site: Mexico, wantCurrency: "MXN", wantListingType: ListingType(Classic)},
+		{site: Chile, wantCurrency: "CLP", wantListingType: ListingType(Ouro)},
+		{site: Site("XXX"), wantCurrency: "BRL", wantListingType: ListingType(Classic)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.site), func(t *testing.T) {
+			if got := tt.site.Currency(); got != tt.wantCurrency {
+				t.Errorf("Currency() = %s, want %s", got, tt.wantCurrency)
+			}
+			if got := tt.site.ListingType(ListingType(Premium)); got != ListingType(Premium) {
+				t.Errorf("ListingType(gold_pro) = %s, want gold_pro", got)
+			}
+			if got := tt.site.ListingType(ListingType(Ouro)); got != tt.wantListingType {
+				t.Errorf("ListingType(gold) = %s, want %s", got, tt.wantListingType)
+			}
+		})
+	}
+}
+
+func TestAnnouncementChangeSite(t *testing.T) {
+	ann := &Announcement{
+		Price:         100,
+		CurrencyID:    "BRL",
+		CategoryID:    "MLB5672",
+		ListingTypeID: ListingType(Prata),
+	}
+
+	ann.ChangeSite(Argentina, 18734.567, "MLA6041")
+	if ann.CurrencyID != "ARS" || ann.CategoryID != "MLA6041" || ann.Price != 18734.57 {
+		t.Errorf("unexpected announcement %+v", ann)
+	}
+	if ann.ListingTypeID != ListingType(Classic) {
+		t.Errorf("got listing type %s, want %s", ann.ListingTypeID, Classic)
+	}
+
+	ann.ChangeSite(Chile, 16789.6, "MLC1234")
+	if ann.CurrencyID != "CLP" || ann.Price != 16790 {
+		t.Errorf("unexpected announcement %+v", ann)
+	}
+}
+
+func TestNewAnnouncementCurrencyFromSite(t *testing.T) {
+	for site, want := range map[string]string{"": "BRL", "MLB": "BRL", "MLM": "MXN"} {
+		ann, err := NewAnnouncement(&common.MeliAnnouncement{SiteID: site})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ann.CurrencyID != want {
+			t.Errorf("site %q: got currency %s, want %s", site, ann.CurrencyID, want)
+		}
+	}
+}
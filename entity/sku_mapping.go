@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSkuMapping is returned when a mapping doesn't have a canonical SKU or aliases
+	ErrInvalidSkuMapping = errors.New("invalid sku mapping")
+	// ErrInvalidSkuAlias is returned when an alias doesn't have an account, has neither a SKU nor listings,
+	// or its account is repeated
+	ErrInvalidSkuAlias = errors.New("invalid sku alias")
+)
+
+// SkuAlias is how an account identifies the product of a mapping,
+// by its own SKU and, optionally, by listings pinned to the product
+type SkuAlias struct {
+	AccountID ID
+	// Empty when the account only has pinned listings
+	Sku        string
+	ListingIDs []string
+}
+
+// SkuMapping relates the canonical SKU of a product to the SKUs the accounts use for it
+type SkuMapping struct {
+	ID      ID
+	StoreID ID
+	// Canonical SKU of the product
+	Sku       string
+	Aliases   []SkuAlias
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewSkuMapping(store ID, sku string, aliases []SkuAlias) (*SkuMapping, error) {
+	now := time.Now().UTC()
+	mapping := &SkuMapping{
+		ID:        NewID(),
+		StoreID:   store,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := mapping.Define(sku, aliases); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// Define replaces the canonical SKU and the aliases of the mapping
+func (m *SkuMapping) Define(sku string, aliases []SkuAlias) error {
+	sku = strings.TrimSpace(sku)
+	if sku == "" || len(aliases) == 0 {
+		return ErrInvalidSkuMapping
+	}
+
+	accounts := make(map[ID]bool, len(aliases))
+	cleaned := make([]SkuAlias, len(aliases))
+	for i, a := range aliases {
+		a.Sku = strings.TrimSpace(a.Sku)
+		listings := []string{}
+		for _, id := range a.ListingIDs {
+			if id = strings.TrimSpace(id); id != "" {
+				listings = append(listings, id)
+			}
+		}
+		a.ListingIDs = listings
+
+		if a.AccountID == (ID{}) || (a.Sku == "" && len(a.ListingIDs) == 0) || accounts[a.AccountID] {
+			return ErrInvalidSkuAlias
+		}
+		accounts[a.AccountID] = true
+		cleaned[i] = a
+	}
+
+	m.Sku = sku
+	m.Aliases = cleaned
+	m.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SkuFor is the SKU the account uses for the product, the canonical one when the account doesn't have an alias
+func (m *SkuMapping) SkuFor(account ID) string {
+	for _, a := range m.Aliases {
+		if a.AccountID == account && a.Sku != "" {
+			return a.Sku
+		}
+	}
+	return m.Sku
+}
+
+// ListingsFor are the listings of the account pinned to the product
+func (m *SkuMapping) ListingsFor(account ID) []string {
+	for _, a := range m.Aliases {
+		if a.AccountID == account {
+			return a.ListingIDs
+		}
+	}
+	return nil
+}
+
+// Skus are the canonical SKU and the SKUs of the aliases, without repetitions
+func (m *SkuMapping) Skus() []string {
+	skus := []string{m.Sku}
+	seen := map[string]bool{m.Sku: true}
+	for _, a := range m.Aliases {
+		if a.Sku != "" && !seen[a.Sku] {
+			seen[a.Sku] = true
+			skus = append(skus, a.Sku)
+		}
+	}
+	return skus
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewSkuMapping(t *testing.T) {
+	account := NewID()
+	tests := []struct {
+		name    string
+		sku     string
+		aliases []SkuAlias
+		wantErr error
+	}{
+		{name: "alias sku", sku: " SKU-1 ", aliases: []SkuAlias{{AccountID: account, Sku: " ALT-1 "}}},
+		{name: "pinned listings", sku: "SKU-1", aliases: []SkuAlias{{AccountID: account, ListingIDs: []string{"MLB1", " "}}}},
+		{name: "without sku", sku: " ", aliases: []SkuAlias{{AccountID: account, Sku: "ALT-1"}}, wantErr: ErrInvalidSkuMapping},
+		{name: "without aliases", sku: "SKU-1", wantErr: ErrInvalidSkuMapping},
+		{name: "alias without account", sku: "SKU-1", aliases: []SkuAlias{{Sku: "ALT-1"}}, wantErr: ErrInvalidSkuAlias},
+		{name: "empty alias", sku: "SKU-1", aliases: []SkuAlias{{AccountID: account, ListingIDs: []string{" "}}}, wantErr: ErrInvalidSkuAlias},
+		{
+			name:    "repeated account",
+			sku:     "SKU-1",
+			aliases: []SkuAlias{{AccountID: account, Sku: "ALT-1"}, {AccountID: account, Sku: "ALT-2"}},
+			wantErr: ErrInvalidSkuAlias,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := NewSkuMapping(NewID(), tt.sku, tt.aliases)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSkuMapping() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && mapping.Sku != "SKU-1" {
+				t.Errorf("NewSkuMapping() sku = %q, want %q", mapping.Sku, "SKU-1")
+			}
+		})
+	}
+}
+
+func TestSkuMappingResolution(t *testing.T) {
+	aliased, pinned, other := NewID(), NewID(), NewID()
+	mapping, err := NewSkuMapping(NewID(), "SKU-1", []SkuAlias{
+		{AccountID: aliased, Sku: "ALT-1"},
+		{AccountID: pinned, ListingIDs: []string{"MLB2"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSkuMapping() error = %v", err)
+	}
+
+	if got := mapping.SkuFor(aliased); got != "ALT-1" {
+		t.Errorf("SkuFor(aliased) = %q, want %q", got, "ALT-1")
+	}
+	if got := mapping.SkuFor(pinned); got != "SKU-1" {
+		t.Errorf("SkuFor(pinned) = %q, want %q", got, "SKU-1")
+	}
+	if got := mapping.SkuFor(other); got != "SKU-1" {
+		t.Errorf("SkuFor(other) = %q, want %q", got, "SKU-1")
+	}
+	if diff := cmp.Diff([]string{"MLB2"}, mapping.ListingsFor(pinned)); diff != "" {
+		t.Errorf("ListingsFor(pinned) mismatch (-want +got):\n%s", diff)
+	}
+	if mapping.ListingsFor(other) != nil {
+		t.Errorf("ListingsFor(other) should be nil")
+	}
+	if diff := cmp.Diff([]string{"SKU-1", "ALT-1"}, mapping.Skus()); diff != "" {
+		t.Errorf("Skus() mismatch (-want +got):\n%s", diff)
+	}
+}
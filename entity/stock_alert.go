@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+type StockLevel string
+
+const (
+	// The quantity is above the threshold
+	InStock    StockLevel = ""
+	LowStock   StockLevel = "low_stock"
+	OutOfStock StockLevel = "out_of_stock"
+)
+
+// EvaluateStock compares the quantity of a SKU with its threshold
+func EvaluateStock(quantity, threshold int) StockLevel {
+	switch {
+	case quantity <= 0:
+		return OutOfStock
+	case quantity <= threshold:
+		return LowStock
+	}
+	return InStock
+}
+
+// StockAlert is raised when the quantity of a SKU reaches its threshold.
+// It stays open, and isn't raised again, until the SKU is restocked.
+type StockAlert struct {
+	ID        ID
+	StoreID   ID
+	Sku       string
+	Level     StockLevel
+	Quantity  int
+	Threshold int
+	CreatedAt time.Time
+	// Nil while the SKU isn't restocked
+	ResolvedAt *time.Time
+}
+
+func NewStockAlert(store ID, sku string, level StockLevel, quantity, threshold int) *StockAlert {
+	return &StockAlert{
+		ID:        NewID(),
+		StoreID:   store,
+		Sku:       sku,
+		Level:     level,
+		Quantity:  quantity,
+		Threshold: threshold,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Escalates reports if the level is worse than the one of the alert
+func (a *StockAlert) Escalates(level StockLevel) bool {
+	return a.Level == LowStock && level == OutOfStock
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "testing"
+
+func TestEvaluateStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int
+		threshold int
+		want      StockLevel
+	}{
+		{name: "above threshold", quantity: 10, threshold: 5, want: InStock},
+		{name: "at threshold", quantity: 5, threshold: 5, want: LowStock},
+		{name: "below threshold", quantity: 1, threshold: 5, want: LowStock},
+		{name: "sold out", quantity: 0, threshold: 5, want: OutOfStock},
+		{name: "sold out without threshold", quantity: 0, threshold: 0, want: OutOfStock},
+		{name: "negative quantity", quantity: -1, threshold: 0, want: OutOfStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateStock(tt.quantity, tt.threshold); got != tt.want {
+				t.Errorf("EvaluateStock(%d, %d) = %q, want %q", tt.quantity, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockAlertEscalates(t *testing.T) {
+	low := NewStockAlert(NewID(), "SKU-1", LowStock, 2, 5)
+	if !low.Escalates(OutOfStock) {
+		t.Errorf("a low stock alert should escalate to out of stock")
+	}
+	if low.Escalates(LowStock) {
+		t.Errorf("a low stock alert shouldn't escalate to low stock")
+	}
+
+	out := NewStockAlert(NewID(), "SKU-1", OutOfStock, 0, 5)
+	if out.Escalates(LowStock) || out.Escalates(OutOfStock) {
+		t.Errorf("an out of stock alert shouldn't escalate")
+	}
+	if out.ResolvedAt != nil {
+		t.Errorf("a new alert should be open")
+	}
+}
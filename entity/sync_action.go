@@ -0,0 +1,165 @@
+package entity
+
+import "time"
+
+type SyncActionStatus string
+
+const (
+	// The action is waiting to be executed by the dispatcher
+	SyncActionPending SyncActionStatus = "pending"
+	SyncActionDone    SyncActionStatus = "done"
+	SyncActionFailed  SyncActionStatus = "failed"
+	// A newer order set the quantity of the same listing before the action was executed,
+	// only the actions planned with an absolute quantity were superseded
+	SyncActionSuperseded SyncActionStatus = "superseded"
+)
+
+type SyncActionKind string
+
+const (
+	// Sets the quantity of an announcement, or of one of its variations
+	SyncQuantity SyncActionKind = "quantity"
+	// Propagates the sale of a SKU to the kits of the store
+	SyncKitSale SyncActionKind = "kit_sale"
+)
+
+// SyncAction is a change made to an announcement while an order was processed,
+// e.g. the quantity of a clone that was updated after a sale.
+type SyncAction struct {
+	ID             ID
+	Kind           SyncActionKind
+	AccountID      ID
+	AnnouncementID string
+	// Zero when the announcement doesn't have variations
+	VariationID int
+	// SKU of the announcement, set on the actions planned by the orders
+	Sku string
+	// Quantity set on the announcement, or the units sold for a kit sale
+	Quantity int
+	// Change of the quantity of the announcement, applied to the quantity it has when the action
+	// is executed. Zero for the actions planned with an absolute quantity
+	Delta int
+	// The last attempt sent the quantity but Mercado Livre didn't answer it in time,
+	// so the announcement may already have it
+	Unconfirmed bool
+	Status      SyncActionStatus
+	Error       string
+	Attempts    int
+	CreatedAt   time.Time
+	// When a pending action is attempted again
+	NextAttemptAt time.Time
+	// Nil while the action is pending
+	ExecutedAt *time.Time
+}
+
+// NewSyncAction creates an action that was already executed
+func NewSyncAction(account ID, announcementId string, variationId, quantity int, err error) *SyncAction {
+	now := time.Now().UTC()
+	action := &SyncAction{
+		ID:             NewID(),
+		Kind:           SyncQuantity,
+		AccountID:      account,
+		AnnouncementID: announcementId,
+		VariationID:    variationId,
+		Quantity:       quantity,
+		Status:         SyncActionDone,
+		Attempts:       1,
+		CreatedAt:      now,
+		ExecutedAt:     &now,
+	}
+	if err != nil {
+		action.Status = SyncActionFailed
+		action.Error = err.Error()
+	}
+	return action
+}
+
+// NewPendingSyncAction creates an action to be executed by the dispatcher,
+// which isn't attempted before the given time
+func NewPendingSyncAction(kind SyncActionKind, account ID, announcementId string, variationId int, sku string, quantity int, attemptAt time.Time) *SyncAction {
+	return &SyncAction{
+		ID:             NewID(),
+		Kind:           kind,
+		AccountID:      account,
+		AnnouncementID: announcementId,
+		VariationID:    variationId,
+		Sku:            sku,
+		Quantity:       quantity,
+		Status:         SyncActionPending,
+		CreatedAt:      time.Now().UTC(),
+		NextAttemptAt:  attemptAt,
+	}
+}
+
+// Complete records the result of an attempt to execute the action.
+// A failed attempt keeps the action pending until retryAt, unless it's the last one.
+func (a *SyncAction) Complete(err error, retryAt time.Time, last bool) {
+	now := time.Now().UTC()
+	a.Attempts++
+	if err == nil {
+		a.Status = SyncActionDone
+		a.Error = ""
+		a.ExecutedAt = &now
+		return
+	}
+
+	a.Error = err.Error()
+	if last {
+		a.Status = SyncActionFailed
+		a.ExecutedAt = &now
+		return
+	}
+	a.NextAttemptAt = retryAt
+}
+
+type SyncProgressStatus string
+
+const (
+	// Every sync action was executed, or superseded by a newer order
+	SyncProgressComplete SyncProgressStatus = "complete"
+	// Some sync actions are waiting to be executed or retried
+	SyncProgressPending SyncProgressStatus = "pending"
+	// Some sync actions failed and others were executed
+	SyncProgressPartial SyncProgressStatus = "partial"
+	// No sync action was executed
+	SyncProgressFailed SyncProgressStatus = "failed"
+)
+
+// SyncProgress summarizes the sync actions of an order, so a partially synchronized order can be told apart
+type SyncProgress struct {
+	Status     SyncProgressStatus
+	Total      int
+	Done       int
+	Pending    int
+	Failed     int
+	Superseded int
+}
+
+// NewSyncProgress summarizes the sync actions of an order
+func NewSyncProgress(actions []SyncAction) SyncProgress {
+	progress := SyncProgress{Total: len(actions)}
+	for _, a := range actions {
+		switch a.Status {
+		case SyncActionDone:
+			progress.Done++
+		case SyncActionPending:
+			progress.Pending++
+		case SyncActionFailed:
+			progress.Failed++
+		case SyncActionSuperseded:
+			progress.Superseded++
+		}
+	}
+
+	switch {
+	case progress.Pending > 0:
+		progress.Status = SyncProgressPending
+	case progress.Failed > 0 && progress.Done > 0:
+		progress.Status = SyncProgressPartial
+	case progress.Failed > 0:
+		progress.Status = SyncProgressFailed
+	default:
+		progress.Status = SyncProgressComplete
+	}
+	return progress
+}
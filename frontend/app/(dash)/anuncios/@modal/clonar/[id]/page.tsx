@@ -27,16 +27,20 @@ export default function CloneForm({
   params: { id: string };
   searchParams?: { [key: string]: string | string[] | undefined };
 }) {
-  const { q: sku } = searchParams as { [key: string]: string };
+  const { q: sku, account } = searchParams as { [key: string]: string };
 
   const [inputs, setInputs] = useState([""]);
   const [open, setOpen] = useState(true);
   const [failMessage, setFailMessage] = useState(false);
+  // Same key for every submit of the form, so a double click clones once
+  const [idempotencyKey, setIdempotencyKey] = useState(() => crypto.randomUUID());
   const [state, formAction] = useFormState(clone, initialState);
   const router = useRouter();
 
   useEffect(() => {
     if (state?.fails) {
+      // The titles may be changed before the next try
+      setIdempotencyKey(crypto.randomUUID());
       setFailMessage(true);
       setTimeout(() => {
         setFailMessage(false);
@@ -101,6 +105,8 @@ export default function CloneForm({
         >
           <input type="hidden" name="id" value={params.id} />
           <input type="hidden" name="sku" value={sku} />
+          <input type="hidden" name="account_id" value={account} />
+          <input type="hidden" name="idempotency_key" value={idempotencyKey} />
           <div className="absolute right-0 top-0 hidden pr-4 pt-4 sm:block">
             <button
               type="button"
@@ -11,10 +11,12 @@ export async function clone(preState: any, formData: FormData) {
   let success = false
   const titles = formData.getAll('title') as string[]
   const rootID = formData.get('id') as string
+  const accountID = formData.get('account_id') as string
   const sku = formData.get('sku') as string
+  const idempotencyKey = formData.get('idempotency_key') as string
 
   try {
-    await cloneAnnouncement(rootID, titles, accessToken!)
+    await cloneAnnouncement(rootID, accountID, titles, accessToken!, idempotencyKey)
     success = true
     // Block execution for 1 second to prevent abuse
     await new Promise(resolve => setTimeout(resolve, 5000))
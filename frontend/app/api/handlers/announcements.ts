@@ -1,6 +1,7 @@
 import 'server-only';
 
-import { IAnnouncement, Status } from '../../../lib/interfaces/announcements'
+import { IAnnouncement } from '../../../lib/interfaces/announcements'
+import type { components, paths } from '../../../lib/api/schema'
 import { getAccessToken } from '@auth0/nextjs-auth0/edge';
 
 
@@ -23,7 +24,7 @@ async function getAnnouncements(sku: string): Promise<IAnnouncement[]> {
       }
     }
 
-    const data = await res.json() as IAnnouncement[]
+    const data = await res.json() as paths['/announcement/{sku}']['get']['responses'][200]['content']['application/json']
 
     if (!data || data.length === 0) {
       return [];
@@ -54,9 +55,11 @@ async function getAnnouncements(sku: string): Promise<IAnnouncement[]> {
   }
 }
 
-async function cloneAnnouncement(rootID: string, titles: string[], accessToken: string): Promise<void> {
-  const body = {
+// The idempotency key makes a form sent twice, e.g. double-clicked, clone only once
+async function cloneAnnouncement(rootID: string, accountID: string, titles: string[], accessToken: string, idempotencyKey?: string): Promise<void> {
+  const body: components['schemas']['CloneAnnouncementInput'] = {
     root_id: rootID,
+    account_id: accountID,
     titles: titles,
   }
 
@@ -65,7 +68,7 @@ async function cloneAnnouncement(rootID: string, titles: string[], accessToken:
       method: 'POST',
       headers: {
         'Authorization': `Bearer ${accessToken}`,
-
+        ...(idempotencyKey && { 'Idempotency-Key': idempotencyKey }),
       },
       body: JSON.stringify(body)
     })
@@ -0,0 +1,1862 @@
+/**
+ * This file was auto-generated by openapi-typescript.
+ * Do not make direct changes to the file.
+ */
+
+
+export interface paths {
+  "/store": {
+    /** Register a store */
+    post: operations["registerStore"];
+  };
+  "/store/meli/authorize": {
+    /** Start the authorization of a Mercado Livre account */
+    get: operations["startMeliAuthorization"];
+  };
+  "/store/meli/callback": {
+    /** Complete the authorization, Mercado Livre redirects the seller here */
+    get: operations["completeMeliAuthorization"];
+  };
+  "/store/accounts": {
+    /** List the Mercado Livre accounts of the store */
+    get: operations["listAccounts"];
+  };
+  "/store/accounts/{id}": {
+    /** Disconnect an account */
+    delete: operations["disconnectAccount"];
+    /** Rename an account */
+    patch: operations["renameAccount"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/announcement": {
+    /** Clone a listing into other accounts */
+    post: operations["cloneAnnouncement"];
+  };
+  "/announcement/import": {
+    /** Import a listing of an account into another */
+    post: operations["importAnnouncement"];
+  };
+  "/announcement/{sku}": {
+    /** List the listings of a SKU in all the accounts */
+    get: operations["getAnnouncements"];
+    parameters: {
+      path: {
+        sku: components["parameters"]["Sku"];
+      };
+    };
+  };
+  "/order/meli-notification": {
+    /**
+     * Receive a notification of an order from Mercado Livre
+     * @description Only accepted from the addresses of Mercado Livre and for the application of the API.
+     */
+    post: operations["receiveMeliOrderNotification"];
+  };
+  "/order": {
+    /** List the orders of the store, the most recent first */
+    get: operations["listOrders"];
+  };
+  "/order/{id}": {
+    /** Get an order with its sync actions */
+    get: operations["getOrder"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/order/{id}/resume-sync": {
+    /** Retry the failed sync actions of an order */
+    post: operations["resumeOrderSync"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/stock/adjust": {
+    /** Set or change the quantity of a SKU in all the accounts */
+    post: operations["adjustStock"];
+  };
+  "/stock/imports": {
+    /** List the imports of the store */
+    get: operations["listStockImports"];
+    /** Upload a CSV or XLSX file with the quantities, its SKUs are searched in the background before it's previewed */
+    post: operations["previewStockImport"];
+  };
+  "/stock/imports/{id}": {
+    /** Get an import with its rows */
+    get: operations["getStockImport"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/stock/imports/{id}/confirm": {
+    /** Confirm a previewed import, the quantities are applied in the background */
+    post: operations["confirmStockImport"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/kits": {
+    /** List the kits of the store */
+    get: operations["listKits"];
+    /** Create a kit */
+    post: operations["createKit"];
+  };
+  "/kits/{id}": {
+    /** Get a kit */
+    get: operations["getKit"];
+    /** Replace a kit */
+    put: operations["updateKit"];
+    /** Delete a kit */
+    delete: operations["deleteKit"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/sku-mappings": {
+    /** List the mappings of the store */
+    get: operations["listSkuMappings"];
+    /** Map the SKU of the store to the SKUs or listings of the accounts */
+    post: operations["createSkuMapping"];
+  };
+  "/sku-mappings/suggestions": {
+    /** Suggest the listings of the other accounts that may be the same product */
+    get: operations["suggestSkuAliases"];
+  };
+  "/sku-mappings/{id}": {
+    /** Get a mapping */
+    get: operations["getSkuMapping"];
+    /** Replace a mapping */
+    put: operations["updateSkuMapping"];
+    /** Delete a mapping */
+    delete: operations["deleteSkuMapping"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/allocation-policies": {
+    /** List the allocation policies of the store */
+    get: operations["listAllocationPolicies"];
+    /** Create the allocation policy of a SKU, or the default policy of the store */
+    post: operations["createAllocationPolicy"];
+  };
+  "/allocation-policies/{id}": {
+    /** Get an allocation policy */
+    get: operations["getAllocationPolicy"];
+    /** Replace an allocation policy */
+    put: operations["updateAllocationPolicy"];
+    /** Delete an allocation policy */
+    delete: operations["deleteAllocationPolicy"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/alerts": {
+    /** List the open low stock alerts */
+    get: operations["listOpenAlerts"];
+  };
+  "/alerts/settings": {
+    /** Get where the alerts are sent and the default threshold */
+    get: operations["getAlertSettings"];
+    /** Replace the alert settings */
+    put: operations["updateAlertSettings"];
+  };
+  "/alerts/thresholds": {
+    /** List the thresholds of the SKUs */
+    get: operations["listThresholds"];
+  };
+  "/alerts/thresholds/{sku}": {
+    /** Set the threshold of a SKU */
+    put: operations["setThreshold"];
+    /** Remove the threshold of a SKU, the default threshold applies */
+    delete: operations["removeThreshold"];
+    parameters: {
+      path: {
+        sku: components["parameters"]["Sku"];
+      };
+    };
+  };
+  "/analytics/sales/sku": {
+    /** Sales of the period by SKU */
+    get: operations["getSalesBySku"];
+  };
+  "/analytics/sales/account": {
+    /** Sales of the period by account */
+    get: operations["getSalesByAccount"];
+  };
+  "/analytics/sales/listing-type": {
+    /** Sales of the period by listing type */
+    get: operations["getSalesByListingType"];
+  };
+  "/webhooks": {
+    /** List the subscriptions of the store */
+    get: operations["listSubscriptions"];
+    /** Subscribe a URL to events of the store */
+    post: operations["registerSubscription"];
+  };
+  "/webhooks/{id}": {
+    /** Disable a subscription */
+    delete: operations["disableSubscription"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/webhooks/{id}/test": {
+    /** Deliver a ping event to the subscription */
+    post: operations["testSubscription"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/webhooks/{id}/deliveries": {
+    /** List the recent deliveries of a subscription */
+    get: operations["listDeliveries"];
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+  };
+  "/healthz": {
+    /** Liveness probe, up while the instance responds */
+    get: operations["checkLiveness"];
+  };
+  "/readyz": {
+    /** Readiness probe, checks the database, Redis and the order queue */
+    get: operations["checkReadiness"];
+  };
+  "/docs": {
+    /** Reference documentation of the API */
+    get: operations["getDocs"];
+  };
+  "/docs/openapi.json": {
+    /** This document */
+    get: operations["getOpenAPI"];
+  };
+}
+
+export type webhooks = Record<string, never>;
+
+export interface components {
+  schemas: {
+    Problem: {
+      /** @example about:blank */
+      type: string;
+      title: string;
+      status: number;
+      detail?: string;
+      instance?: string;
+      /**
+       * @description Stable code of the error
+       * @example kit_not_found
+       */
+      code: string;
+      errors?: (components["schemas"]["FieldError"])[];
+    };
+    FieldError: {
+      /** @example components[0].sku */
+      field: string;
+      /** @example required */
+      rule: string;
+      param?: string;
+    };
+    Health: {
+      /** @enum {string} */
+      status: "up" | "down";
+      components: {
+        [key: string]: components["schemas"]["HealthComponent"] | undefined;
+      };
+    };
+    HealthComponent: {
+      /** @enum {string} */
+      status: "up" | "down";
+      error?: string;
+      latency_ms: number;
+    };
+    Store: {
+      /** Format: uuid */
+      id: string;
+      error?: string;
+    };
+    Account: {
+      /** Format: uuid */
+      id: string;
+      name: string | null;
+      meli_user_id: string;
+      site_id: string;
+      status: string;
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      token_updated_at: string;
+      /** Format: int64 */
+      token_age_seconds: number;
+      last_refresh_error?: string;
+      /** Format: date-time */
+      last_refresh_error_at?: string;
+    };
+    MeliAuthorization: {
+      /** Format: uri */
+      url: string;
+    };
+    Announcement: {
+      /** @example MLB1234567890 */
+      id: string;
+      title: string;
+      quantity: number;
+      price: number;
+      status: string;
+      /** Format: uri */
+      picture: string;
+      sku: string;
+      /** Format: uri */
+      link: string;
+      account: {
+        /** Format: uuid */
+        id: string;
+        name: string;
+      };
+    };
+    OrderItem: {
+      /** Format: uuid */
+      id: string;
+      title: string;
+      sku: string;
+      quantity: number;
+      variation_id?: number;
+      unit_price: number;
+      currency_id?: string;
+      listing_type_id?: string;
+    };
+    SyncAction: {
+      /** Format: uuid */
+      id: string;
+      kind: string;
+      /** Format: uuid */
+      account_id: string;
+      announcement_id: string;
+      variation_id?: number;
+      sku?: string;
+      quantity: number;
+      delta?: number;
+      /** @enum {string} */
+      status: "pending" | "done" | "failed" | "superseded";
+      error?: string;
+      attempts: number;
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      executed_at?: string;
+    };
+    SyncProgress: {
+      /** @enum {string} */
+      status: "complete" | "pending" | "partial" | "failed";
+      total: number;
+      done: number;
+      pending: number;
+      failed: number;
+      superseded: number;
+    };
+    Order: {
+      /** Format: uuid */
+      id: string;
+      /** Format: uuid */
+      account_id: string;
+      marketplace_id: string;
+      status: string;
+      /** Format: date-time */
+      date_created: string;
+      /** Format: date-time */
+      date_closed?: string;
+      /** Format: date-time */
+      last_updated?: string;
+      total_amount: number;
+      paid_amount: number;
+      currency_id?: string;
+      pack_id?: string;
+      pack_size?: number;
+      shipping_id?: string;
+      buyer_nickname?: string;
+      items: (components["schemas"]["OrderItem"])[];
+      sync_actions?: (components["schemas"]["SyncAction"])[];
+      sync_progress?: components["schemas"]["SyncProgress"];
+    };
+    OrderPage: {
+      orders: (components["schemas"]["Order"])[];
+      /** @description Absent on the last page */
+      next_cursor?: string;
+    };
+    ListingAdjustment: {
+      /** Format: uuid */
+      account_id: string;
+      account_name: string;
+      announcement_id: string;
+      variation_id?: number;
+      previous_quantity: number;
+      quantity: number;
+      /** @enum {string} */
+      status: "pending" | "valid" | "invalid" | "not_found" | "applied" | "failed";
+      error?: string;
+    };
+    StockAdjustment: {
+      sku: string;
+      stock?: number;
+      listings: (components["schemas"]["ListingAdjustment"])[];
+    };
+    StockImportRow: {
+      line: number;
+      sku: string;
+      quantity: number;
+      /** @enum {string} */
+      status: "pending" | "valid" | "invalid" | "not_found" | "applied" | "failed";
+      error?: string;
+      listings: number;
+      updated: number;
+      failed: number;
+    };
+    StockImport: {
+      /** Format: uuid */
+      id: string;
+      file_name: string;
+      /**
+       * @description The import can be confirmed once it's previewed
+       * @enum {string}
+       */
+      status: "uploaded" | "validating" | "previewed" | "confirmed" | "running" | "done";
+      /** @description Number of rows by status */
+      counts: {
+        [key: string]: number | undefined;
+      };
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      confirmed_at?: string;
+      /** Format: date-time */
+      finished_at?: string;
+      /** @description Not returned when the imports are listed */
+      rows?: (components["schemas"]["StockImportRow"])[];
+    };
+    KitComponent: {
+      sku: string;
+      quantity: number;
+    };
+    Kit: {
+      /** Format: uuid */
+      id: string;
+      sku: string;
+      title: string;
+      components: (components["schemas"]["KitComponent"])[];
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      updated_at: string;
+    };
+    SkuAlias: {
+      /** Format: uuid */
+      account_id: string;
+      sku?: string;
+      listing_ids: (string)[];
+    };
+    SkuMapping: {
+      /** Format: uuid */
+      id: string;
+      sku: string;
+      aliases: (components["schemas"]["SkuAlias"])[];
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      updated_at: string;
+    };
+    SkuSuggestion: {
+      account: {
+        /** Format: uuid */
+        id: string;
+        name: string;
+      };
+      announcement_id: string;
+      title: string;
+      sku: string;
+      reason: string;
+      score: number;
+    };
+    AccountAllocation: {
+      /** Format: uuid */
+      account_id: string;
+      buffer: number;
+      percentage: number;
+      max_quantity: number | null;
+    };
+    AllocationPolicy: {
+      /** Format: uuid */
+      id: string;
+      /** @description Empty for the default policy of the store */
+      sku: string;
+      /** Format: uuid */
+      priority_account_id: string | null;
+      accounts: (components["schemas"]["AccountAllocation"])[];
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      updated_at: string;
+    };
+    AlertSettings: {
+      webhook_url: string;
+      email: string;
+      default_threshold: number | null;
+    };
+    StockThreshold: {
+      sku: string;
+      threshold: number;
+    };
+    StockAlert: {
+      /** Format: uuid */
+      id: string;
+      sku: string;
+      level: string;
+      quantity: number;
+      threshold: number;
+      /** Format: date-time */
+      created_at: string;
+    };
+    SalesBucket: {
+      /** Format: date-time */
+      period: string;
+      key: string;
+      units: number;
+      revenue: number;
+      currency_id: string;
+    };
+    SalesReport: {
+      dimension: string;
+      /** @enum {string} */
+      interval: "day" | "week" | "month";
+      /** Format: date-time */
+      from: string;
+      /** Format: date-time */
+      to: string;
+      buckets: (components["schemas"]["SalesBucket"])[];
+    };
+    WebhookSubscription: {
+      /** Format: uuid */
+      id: string;
+      /** Format: uri */
+      url: string;
+      events: (string)[];
+      /** @description Only returned when the subscription is registered */
+      secret?: string;
+      active: boolean;
+      /** Format: date-time */
+      created_at: string;
+    };
+    WebhookDelivery: {
+      /** Format: uuid */
+      id: string;
+      /** Format: uuid */
+      event_id: string;
+      event_type: string;
+      /** @description Body sent to the subscription */
+      payload: Record<string, never>;
+      status: string;
+      attempts: number;
+      last_status_code?: number;
+      last_error?: string;
+      /** Format: date-time */
+      next_attempt_at?: string;
+      /** Format: date-time */
+      created_at: string;
+      /** Format: date-time */
+      delivered_at?: string;
+    };
+    /** @enum {string} */
+    EventType: "order.processed" | "quantity.synced" | "clone.created" | "sync.failed" | "credential.expired" | "ping";
+    RegisterStoreInput: {
+      /** Format: email */
+      email: string;
+      name: string;
+    };
+    RenameAccountInput: {
+      account_name?: string;
+    };
+    CloneAnnouncementInput: {
+      /** @description Listing to clone */
+      root_id: string;
+      /** @description A clone is created for each title */
+      titles?: (string)[];
+      /**
+       * Format: uuid
+       * @description Account of the listing
+       */
+      account_id: string;
+      /** @description Accounts that receive the clones */
+      destiny_accounts?: (string)[];
+    };
+    ImportAnnouncementInput: {
+      announcement_id: string;
+      /** Format: uuid */
+      account_id_origin: string;
+      /** Format: uuid */
+      account_id_destiny: string;
+    };
+    OrderWebhookInput: {
+      _id?: string;
+      /** @example /orders/2000003508419013 */
+      resource: string;
+      user_id: number;
+      /** @example orders_v2 */
+      topic: string;
+      /** Format: int64 */
+      application_id?: number;
+      attempts?: number;
+      /** Format: date-time */
+      sent?: string;
+      /** Format: date-time */
+      received?: string;
+    };
+    /** @description Either the absolute quantity or the delta is informed */
+    AdjustStockInput: {
+      sku: string;
+      /** @description Restricts the adjustment to a variation, otherwise the variations with the SKU of the product in their account are adjusted */
+      variation_id?: number;
+      quantity?: number | null;
+      delta?: number | null;
+    };
+    KitComponentInput: {
+      sku: string;
+      quantity?: number;
+    };
+    CreateKitInput: {
+      sku: string;
+      title?: string;
+      components: (components["schemas"]["KitComponentInput"])[];
+    };
+    UpdateKitInput: {
+      sku: string;
+      title?: string;
+      components: (components["schemas"]["KitComponentInput"])[];
+    };
+    /** @description Either the SKU or the listings of the account */
+    SkuAliasInput: {
+      /** Format: uuid */
+      account_id: string;
+      sku?: string;
+      listing_ids?: (string)[];
+    };
+    CreateMappingInput: {
+      sku: string;
+      aliases: (components["schemas"]["SkuAliasInput"])[];
+    };
+    UpdateMappingInput: {
+      sku: string;
+      aliases: (components["schemas"]["SkuAliasInput"])[];
+    };
+    AccountAllocationInput: {
+      /** Format: uuid */
+      account_id: string;
+      buffer?: number;
+      percentage?: number;
+      max_quantity?: number | null;
+    };
+    CreatePolicyInput: {
+      /** @description Empty for the default policy of the store */
+      sku?: string;
+      /** Format: uuid */
+      priority_account_id?: string | null;
+      accounts: (components["schemas"]["AccountAllocationInput"])[];
+    };
+    UpdatePolicyInput: {
+      /** @description Empty for the default policy of the store */
+      sku?: string;
+      /** Format: uuid */
+      priority_account_id?: string | null;
+      accounts: (components["schemas"]["AccountAllocationInput"])[];
+    };
+    UpdateSettingsInput: {
+      /** Format: uri */
+      webhook_url?: string;
+      /** Format: email */
+      email?: string;
+      default_threshold?: number | null;
+    };
+    SetThresholdInput: {
+      threshold?: number;
+    };
+    RegisterSubscriptionInput: {
+      /** Format: uri */
+      url: string;
+      events: (components["schemas"]["EventType"])[];
+    };
+  };
+  responses: {
+    /** @description Unexpected error */
+    Problem: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Malformed body or invalid parameter */
+    BadRequest: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Missing or invalid access token */
+    Unauthorized: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description The request doesn't come from Mercado Livre */
+    Forbidden: {
+      content: never;
+    };
+    /** @description The resource doesn't exist in the store */
+    NotFound: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Conflicts with the state of the store, e.g. the store has no Mercado Livre credentials */
+    Conflict: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description The body has invalid fields, listed in errors */
+    ValidationFailed: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Mercado Livre refused the request */
+    MarketplaceError: {
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Mercado Livre is unavailable, retry after the Retry-After header */
+    MarketplaceUnavailable: {
+      headers: {
+        "Retry-After"?: number;
+      };
+      content: {
+        "application/problem+json": components["schemas"]["Problem"];
+      };
+    };
+    /** @description Sales report */
+    SalesReport: {
+      content: {
+        "application/json": components["schemas"]["SalesReport"];
+      };
+    };
+    /** @description Status of the instance and of each component, 503 when a component is down */
+    Health: {
+      content: {
+        "application/json": components["schemas"]["Health"];
+      };
+    };
+  };
+  parameters: {
+    /** @description Unique key of the request, e.g. a UUID. The response of the first request with the key is replayed for 24 hours, with the Idempotent-Replayed header, instead of running the request again. The key is rejected with 409 while its first request runs and with 422 when it's reused by another request. Server errors aren't replayed. */
+    IdempotencyKey: string;
+    ID: string;
+    Sku: string;
+    SkuFilter: string;
+    AccountFilter: string;
+    From: string;
+    To: string;
+    Interval: "day" | "week" | "month";
+  };
+  requestBodies: {
+    RegisterStoreInput: {
+      content: {
+        "application/json": components["schemas"]["RegisterStoreInput"];
+      };
+    };
+    RenameAccountInput: {
+      content: {
+        "application/json": components["schemas"]["RenameAccountInput"];
+      };
+    };
+    CloneAnnouncementInput: {
+      content: {
+        "application/json": components["schemas"]["CloneAnnouncementInput"];
+      };
+    };
+    ImportAnnouncementInput: {
+      content: {
+        "application/json": components["schemas"]["ImportAnnouncementInput"];
+      };
+    };
+    OrderWebhookInput: {
+      content: {
+        "application/json": components["schemas"]["OrderWebhookInput"];
+      };
+    };
+    AdjustStockInput: {
+      content: {
+        "application/json": components["schemas"]["AdjustStockInput"];
+      };
+    };
+    CreateKitInput: {
+      content: {
+        "application/json": components["schemas"]["CreateKitInput"];
+      };
+    };
+    UpdateKitInput: {
+      content: {
+        "application/json": components["schemas"]["UpdateKitInput"];
+      };
+    };
+    CreateMappingInput: {
+      content: {
+        "application/json": components["schemas"]["CreateMappingInput"];
+      };
+    };
+    UpdateMappingInput: {
+      content: {
+        "application/json": components["schemas"]["UpdateMappingInput"];
+      };
+    };
+    CreatePolicyInput: {
+      content: {
+        "application/json": components["schemas"]["CreatePolicyInput"];
+      };
+    };
+    UpdatePolicyInput: {
+      content: {
+        "application/json": components["schemas"]["UpdatePolicyInput"];
+      };
+    };
+    UpdateSettingsInput: {
+      content: {
+        "application/json": components["schemas"]["UpdateSettingsInput"];
+      };
+    };
+    SetThresholdInput: {
+      content: {
+        "application/json": components["schemas"]["SetThresholdInput"];
+      };
+    };
+    RegisterSubscriptionInput: {
+      content: {
+        "application/json": components["schemas"]["RegisterSubscriptionInput"];
+      };
+    };
+  };
+  headers: never;
+  pathItems: never;
+}
+
+export type $defs = Record<string, never>;
+
+export type external = Record<string, never>;
+
+export interface operations {
+
+  /** Register a store */
+  registerStore: {
+    requestBody: components["requestBodies"]["RegisterStoreInput"];
+    responses: {
+      /** @description Store registered */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Store"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Start the authorization of a Mercado Livre account */
+  startMeliAuthorization: {
+    parameters: {
+      query?: {
+        account_name?: string;
+        /** @example MLB */
+        site_id?: string;
+        /** @description Account to re-authorize */
+        account_id?: string;
+      };
+    };
+    responses: {
+      /** @description URL of the authorization page of Mercado Livre */
+      200: {
+        content: {
+          "application/json": components["schemas"]["MeliAuthorization"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Complete the authorization, Mercado Livre redirects the seller here */
+  completeMeliAuthorization: {
+    parameters: {
+      query?: {
+        code?: string;
+        state?: string;
+        /** @description Set when the seller denied the authorization */
+        error?: string;
+      };
+    };
+    responses: {
+      /** @description Account linked, when no frontend URL is configured */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Account"];
+        };
+      };
+      /** @description Redirect to the frontend with the result: status=success and the account_id, or status=error and the reason, the code of the problem, authorization_denied or authorization_failed */
+      302: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      409: components["responses"]["Conflict"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the Mercado Livre accounts of the store */
+  listAccounts: {
+    responses: {
+      /** @description Accounts of the store */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["Account"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Disconnect an account */
+  disconnectAccount: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Account disconnected */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Rename an account */
+  renameAccount: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    requestBody: components["requestBodies"]["RenameAccountInput"];
+    responses: {
+      /** @description Account renamed */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Account"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Clone a listing into other accounts */
+  cloneAnnouncement: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["CloneAnnouncementInput"];
+    responses: {
+      /** @description Clones created */
+      201: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      502: components["responses"]["MarketplaceError"];
+      503: components["responses"]["MarketplaceUnavailable"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Import a listing of an account into another */
+  importAnnouncement: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["ImportAnnouncementInput"];
+    responses: {
+      /** @description Listing imported */
+      201: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      502: components["responses"]["MarketplaceError"];
+      503: components["responses"]["MarketplaceUnavailable"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the listings of a SKU in all the accounts */
+  getAnnouncements: {
+    parameters: {
+      path: {
+        sku: components["parameters"]["Sku"];
+      };
+    };
+    responses: {
+      /** @description Listings of the SKU */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["Announcement"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      502: components["responses"]["MarketplaceError"];
+      503: components["responses"]["MarketplaceUnavailable"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /**
+   * Receive a notification of an order from Mercado Livre
+   * @description Only accepted from the addresses of Mercado Livre and for the application of the API.
+   */
+  receiveMeliOrderNotification: {
+    requestBody: components["requestBodies"]["OrderWebhookInput"];
+    responses: {
+      /** @description Notification queued */
+      200: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      403: components["responses"]["Forbidden"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the orders of the store, the most recent first */
+  listOrders: {
+    parameters: {
+      query?: {
+        status?: string;
+        sku?: string;
+        pack?: string;
+        account_id?: components["parameters"]["AccountFilter"];
+        from?: components["parameters"]["From"];
+        to?: components["parameters"]["To"];
+        limit?: number;
+        /** @description next_cursor of the previous page */
+        cursor?: string;
+      };
+    };
+    responses: {
+      /** @description Page of orders */
+      200: {
+        content: {
+          "application/json": components["schemas"]["OrderPage"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get an order with its sync actions */
+  getOrder: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Order */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Order"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Retry the failed sync actions of an order */
+  resumeOrderSync: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Order after the retry */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Order"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Set or change the quantity of a SKU in all the accounts */
+  adjustStock: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["AdjustStockInput"];
+    responses: {
+      /** @description Result of the adjustment of each listing */
+      200: {
+        content: {
+          "application/json": components["schemas"]["StockAdjustment"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the imports of the store */
+  listStockImports: {
+    responses: {
+      /** @description Imports, without their rows */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["StockImport"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Upload a CSV or XLSX file with the quantities, its SKUs are searched in the background before it's previewed */
+  previewStockImport: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: {
+      content: {
+        "multipart/form-data": {
+          /**
+           * Format: binary
+           * @description At most 10 MB
+           */
+          file: string;
+        };
+      };
+    };
+    responses: {
+      /** @description Uploaded import, its pending rows are validated in the background */
+      201: {
+        content: {
+          "application/json": components["schemas"]["StockImport"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get an import with its rows */
+  getStockImport: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Import */
+      200: {
+        content: {
+          "application/json": components["schemas"]["StockImport"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Confirm a previewed import, the quantities are applied in the background */
+  confirmStockImport: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Import confirmed */
+      202: {
+        content: {
+          "application/json": components["schemas"]["StockImport"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the kits of the store */
+  listKits: {
+    responses: {
+      /** @description Kits */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["Kit"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Create a kit */
+  createKit: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["CreateKitInput"];
+    responses: {
+      /** @description Kit created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["Kit"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get a kit */
+  getKit: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Kit */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Kit"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Replace a kit */
+  updateKit: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    requestBody: components["requestBodies"]["UpdateKitInput"];
+    responses: {
+      /** @description Kit updated */
+      200: {
+        content: {
+          "application/json": components["schemas"]["Kit"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Delete a kit */
+  deleteKit: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Kit deleted */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the mappings of the store */
+  listSkuMappings: {
+    responses: {
+      /** @description Mappings */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["SkuMapping"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Map the SKU of the store to the SKUs or listings of the accounts */
+  createSkuMapping: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["CreateMappingInput"];
+    responses: {
+      /** @description Mapping created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["SkuMapping"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Suggest the listings of the other accounts that may be the same product */
+  suggestSkuAliases: {
+    parameters: {
+      query: {
+        sku: string;
+      };
+    };
+    responses: {
+      /** @description Suggestions, the most likely first */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["SkuSuggestion"])[];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get a mapping */
+  getSkuMapping: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Mapping */
+      200: {
+        content: {
+          "application/json": components["schemas"]["SkuMapping"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Replace a mapping */
+  updateSkuMapping: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    requestBody: components["requestBodies"]["UpdateMappingInput"];
+    responses: {
+      /** @description Mapping updated */
+      200: {
+        content: {
+          "application/json": components["schemas"]["SkuMapping"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Delete a mapping */
+  deleteSkuMapping: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Mapping deleted */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the allocation policies of the store */
+  listAllocationPolicies: {
+    responses: {
+      /** @description Policies */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["AllocationPolicy"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Create the allocation policy of a SKU, or the default policy of the store */
+  createAllocationPolicy: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["CreatePolicyInput"];
+    responses: {
+      /** @description Policy created */
+      201: {
+        content: {
+          "application/json": components["schemas"]["AllocationPolicy"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get an allocation policy */
+  getAllocationPolicy: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Policy */
+      200: {
+        content: {
+          "application/json": components["schemas"]["AllocationPolicy"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Replace an allocation policy */
+  updateAllocationPolicy: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    requestBody: components["requestBodies"]["UpdatePolicyInput"];
+    responses: {
+      /** @description Policy updated */
+      200: {
+        content: {
+          "application/json": components["schemas"]["AllocationPolicy"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      409: components["responses"]["Conflict"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Delete an allocation policy */
+  deleteAllocationPolicy: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Policy deleted */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the open low stock alerts */
+  listOpenAlerts: {
+    responses: {
+      /** @description Open alerts */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["StockAlert"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Get where the alerts are sent and the default threshold */
+  getAlertSettings: {
+    responses: {
+      /** @description Settings */
+      200: {
+        content: {
+          "application/json": components["schemas"]["AlertSettings"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Replace the alert settings */
+  updateAlertSettings: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["UpdateSettingsInput"];
+    responses: {
+      /** @description Settings updated */
+      204: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the thresholds of the SKUs */
+  listThresholds: {
+    responses: {
+      /** @description Thresholds */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["StockThreshold"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Set the threshold of a SKU */
+  setThreshold: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        sku: components["parameters"]["Sku"];
+      };
+    };
+    requestBody: components["requestBodies"]["SetThresholdInput"];
+    responses: {
+      /** @description Threshold set */
+      204: {
+        content: never;
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Remove the threshold of a SKU, the default threshold applies */
+  removeThreshold: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        sku: components["parameters"]["Sku"];
+      };
+    };
+    responses: {
+      /** @description Threshold removed */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Sales of the period by SKU */
+  getSalesBySku: {
+    parameters: {
+      query?: {
+        interval?: components["parameters"]["Interval"];
+        sku?: components["parameters"]["SkuFilter"];
+        account_id?: components["parameters"]["AccountFilter"];
+        from?: components["parameters"]["From"];
+        to?: components["parameters"]["To"];
+      };
+    };
+    responses: {
+      200: components["responses"]["SalesReport"];
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Sales of the period by account */
+  getSalesByAccount: {
+    parameters: {
+      query?: {
+        interval?: components["parameters"]["Interval"];
+        sku?: components["parameters"]["SkuFilter"];
+        account_id?: components["parameters"]["AccountFilter"];
+        from?: components["parameters"]["From"];
+        to?: components["parameters"]["To"];
+      };
+    };
+    responses: {
+      200: components["responses"]["SalesReport"];
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Sales of the period by listing type */
+  getSalesByListingType: {
+    parameters: {
+      query?: {
+        interval?: components["parameters"]["Interval"];
+        sku?: components["parameters"]["SkuFilter"];
+        account_id?: components["parameters"]["AccountFilter"];
+        from?: components["parameters"]["From"];
+        to?: components["parameters"]["To"];
+      };
+    };
+    responses: {
+      200: components["responses"]["SalesReport"];
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the subscriptions of the store */
+  listSubscriptions: {
+    responses: {
+      /** @description Subscriptions */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["WebhookSubscription"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Subscribe a URL to events of the store */
+  registerSubscription: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+    };
+    requestBody: components["requestBodies"]["RegisterSubscriptionInput"];
+    responses: {
+      /** @description Subscription registered, the secret that signs the deliveries is only returned here */
+      201: {
+        content: {
+          "application/json": components["schemas"]["WebhookSubscription"];
+        };
+      };
+      400: components["responses"]["BadRequest"];
+      401: components["responses"]["Unauthorized"];
+      422: components["responses"]["ValidationFailed"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Disable a subscription */
+  disableSubscription: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Subscription disabled */
+      204: {
+        content: never;
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Deliver a ping event to the subscription */
+  testSubscription: {
+    parameters: {
+      header?: {
+        "Idempotency-Key"?: components["parameters"]["IdempotencyKey"];
+      };
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Delivery of the ping */
+      200: {
+        content: {
+          "application/json": components["schemas"]["WebhookDelivery"];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** List the recent deliveries of a subscription */
+  listDeliveries: {
+    parameters: {
+      path: {
+        id: components["parameters"]["ID"];
+      };
+    };
+    responses: {
+      /** @description Deliveries */
+      200: {
+        content: {
+          "application/json": (components["schemas"]["WebhookDelivery"])[];
+        };
+      };
+      401: components["responses"]["Unauthorized"];
+      404: components["responses"]["NotFound"];
+      default: components["responses"]["Problem"];
+    };
+  };
+  /** Liveness probe, up while the instance responds */
+  checkLiveness: {
+    responses: {
+      200: components["responses"]["Health"];
+      503: components["responses"]["Health"];
+    };
+  };
+  /** Readiness probe, checks the database, Redis and the order queue */
+  checkReadiness: {
+    responses: {
+      200: components["responses"]["Health"];
+      503: components["responses"]["Health"];
+    };
+  };
+  /** Reference documentation of the API */
+  getDocs: {
+    responses: {
+      /** @description HTML page rendering this document */
+      200: {
+        content: {
+          "text/html": string;
+        };
+      };
+    };
+  };
+  /** This document */
+  getOpenAPI: {
+    responses: {
+      /** @description OpenAPI document */
+      200: {
+        content: {
+          "application/json": Record<string, never>;
+        };
+      };
+    };
+  };
+}
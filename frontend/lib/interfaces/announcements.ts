@@ -1,20 +1,9 @@
+import type { components } from '../api/schema'
+
 export enum Status {
   Ativo = "active",
   Inativo = "closed",
   Pausado = "paused",
 }
 
-export interface IAnnouncement {
-  id: string,
-  title: string;
-  quantity: number;
-  status: Status;
-  price: number;
-  picture: string;
-  sku: string;
-  link: string;
-  account: {
-    id: string;
-    name: string;
-  };
-}
\ No newline at end of file
+export type IAnnouncement = components['schemas']['Announcement']
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 
@@ -13,12 +15,24 @@ import (
 	mdw "github.com/Vractos/kloni/adapter/api/middleware"
 	"github.com/Vractos/kloni/adapter/cache"
 	"github.com/Vractos/kloni/adapter/mercadolivre"
+	"github.com/Vractos/kloni/adapter/notifier"
 	"github.com/Vractos/kloni/adapter/queue"
 	"github.com/Vractos/kloni/adapter/repository"
+	"github.com/Vractos/kloni/pkg/health"
 	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/pkg/netguard"
+	"github.com/Vractos/kloni/pkg/oauthstate"
+	"github.com/Vractos/kloni/pkg/secrets"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/Vractos/kloni/usecases/alias"
+	"github.com/Vractos/kloni/usecases/allocation"
+	"github.com/Vractos/kloni/usecases/analytics"
 	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/kit"
 	"github.com/Vractos/kloni/usecases/order"
+	"github.com/Vractos/kloni/usecases/stock"
 	"github.com/Vractos/kloni/usecases/store"
+	"github.com/Vractos/kloni/usecases/webhook"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/go-chi/chi/v5"
@@ -46,10 +60,22 @@ func main() {
 	// Log
 	logger := metrics.NewLogger("info")
 	defer logger.Sync()
+
 	// Tracer
+	openTel := metrics.NewOpenTel()
+	openTel.ServiceName = "kloni"
+	openTel.Environment = os.Getenv("APP_ENV")
+	openTel.Exporter = os.Getenv("OTEL_TRACES_EXPORTER")
+	shutdownTracer, err := openTel.Start(context.Background())
+	if err != nil {
+		logger.Panic("Failed to start the tracer: "+err.Error(), err)
+	}
+	defer shutdownTracer(context.Background())
 
 	// Validator package
 	validate := validator.New()
+	// The validation errors of the requests name the fields after their JSON keys
+	validate.RegisterTagNameFunc(handler.JSONFieldName)
 
 	// AWS SDK
 	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(os.Getenv("AWS_REGION")))
@@ -62,7 +88,13 @@ func main() {
 
 	// PostgreSQL
 	dataSourceName := fmt.Sprintf("postgresql://%s:%s@%s:5432/%s", os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_HOST"), os.Getenv("POSTGRES_DB_NAME"))
-	dbpool, err := pgxpool.New(context.Background(), dataSourceName)
+	dbConfig, err := pgxpool.ParseConfig(dataSourceName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to parse the database config: %v\n", err)
+		os.Exit(1)
+	}
+	dbConfig.ConnConfig.Tracer = repository.NewQueryTracer()
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), dbConfig)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to create connection pool: %v\n", err)
 		os.Exit(1)
@@ -75,34 +107,137 @@ func main() {
 		Password: "",
 		DB:       0,
 	})
-	pong, err := rdb.Ping(rdb.Context()).Result()
-	logger.Warn(pong,
-		zap.Error(err),
-	)
+	rdb.AddHook(cache.NewTracingHook())
+	// The instance starts without Redis, it isn't ready until Redis is reachable
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		logger.Error("Redis is unreachable", err)
+	}
+
+	// Credentials encryption
+	var keyProvider secrets.KeyProvider
+	if keyFile := os.Getenv("CREDENTIALS_KEY_FILE"); keyFile != "" {
+		keyProvider, err = secrets.NewLocalKeyProviderFromFile(keyFile)
+	} else {
+		keyProvider, err = secrets.NewLocalKeyProvider(os.Getenv("CREDENTIALS_KEYS"))
+	}
+	if err != nil {
+		logger.Fatal("Failed to load the credentials encryption keys", err)
+	}
+	envelope := secrets.NewEnvelope(keyProvider)
+
+	// OAuth state
+	stateSigner, err := oauthstate.NewHMACSigner([]byte(os.Getenv("OAUTH_STATE_SECRET")), 10*time.Minute)
+	if err != nil {
+		logger.Fatal("Failed to create the OAuth state signer", err)
+	}
 
 	// Order Queue
 	orderChan := make(chan []order.OrderMessage)
 	orderQueue := queue.NewOrderQueue(client, os.Getenv("ORDER_QUEUE_URL"), *logger)
 
 	// Mercado Livre
-	mercadoLivre := mercadolivre.NewMercadoLivre(os.Getenv("MELI_APP_ID"), os.Getenv("MELI_SECRET_KEY"), os.Getenv("MELI_REDIRECT_URL"), os.Getenv("MELI_ENDPOINT"), validate, *logger)
+	meliAppId := os.Getenv("MELI_APP_ID")
+	if meliAppId == "" {
+		logger.Fatal("Failed to configure Mercado Livre", errors.New("MELI_APP_ID is empty, the notifications can't be verified"))
+	}
+	mercadoLivre := mercadolivre.NewMercadoLivre(meliAppId, os.Getenv("MELI_SECRET_KEY"), os.Getenv("MELI_REDIRECT_URL"), os.Getenv("MELI_ENDPOINT"), os.Getenv("MELI_AUTH_ENDPOINT"), validate, *logger)
 	// Repositories
-	storeRepo := repository.NewStorePostgreSQL(dbpool, *logger)
+	storeRepo := repository.NewStorePostgreSQL(dbpool, envelope, *logger)
 	orderRepo := repository.NewOrderPostgreSQL(dbpool, *logger)
+	analyticsRepo := repository.NewAnalyticsPostgreSQL(dbpool, *logger)
+	alertRepo := repository.NewAlertPostgreSQL(dbpool, *logger)
+	webhookRepo := repository.NewWebhookPostgreSQL(dbpool, *logger)
+	stockRepo := repository.NewStockPostgreSQL(dbpool, *logger)
+	kitRepo := repository.NewKitPostgreSQL(dbpool, *logger)
+	aliasRepo := repository.NewAliasPostgreSQL(dbpool, *logger)
+	allocationRepo := repository.NewAllocationPostgreSQL(dbpool, *logger)
+	// Encrypt plaintext credentials and the ones encrypted with rotated keys
+	go func() {
+		count, err := storeRepo.ReEncryptMeliCredentials()
+		if err != nil {
+			logger.Error("Fail to re-encrypt meli's credentials", err)
+			return
+		}
+		logger.Info("Meli's credentials re-encrypted", zap.Int("count", count))
+	}()
+	// The URLs given by the stores can't reach the internal network
+	urlGuard := netguard.NewGuard(net.DefaultResolver)
+	// Stock alert notifiers
+	webhookNotifier := notifier.NewWebhookNotifier()
+	var emailNotifier alert.Notifier
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		emailNotifier = notifier.NewSMTPNotifier(smtpHost, os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	} else {
+		logger.Warn("SMTP_HOST is empty, stock alerts aren't sent by email")
+	}
+	alertScanInterval := time.Hour
+	if interval := os.Getenv("ALERT_SCAN_INTERVAL"); interval != "" {
+		alertScanInterval, err = time.ParseDuration(interval)
+		if err != nil || alertScanInterval <= 0 {
+			logger.Fatal("Failed to parse the alert scan interval", err)
+		}
+	}
+	webhookDeliveryInterval := 30 * time.Second
+	if interval := os.Getenv("WEBHOOK_DELIVERY_INTERVAL"); interval != "" {
+		webhookDeliveryInterval, err = time.ParseDuration(interval)
+		if err != nil || webhookDeliveryInterval <= 0 {
+			logger.Fatal("Failed to parse the webhook delivery interval", err)
+		}
+	}
+	syncDispatchInterval := 30 * time.Second
+	if interval := os.Getenv("SYNC_DISPATCH_INTERVAL"); interval != "" {
+		syncDispatchInterval, err = time.ParseDuration(interval)
+		if err != nil || syncDispatchInterval <= 0 {
+			logger.Fatal("Failed to parse the sync dispatch interval", err)
+		}
+	}
+	stockImportThrottle := 500 * time.Millisecond
+	if throttle := os.Getenv("STOCK_IMPORT_THROTTLE"); throttle != "" {
+		stockImportThrottle, err = time.ParseDuration(throttle)
+		if err != nil || stockImportThrottle < 0 {
+			logger.Fatal("Failed to parse the stock import throttle", err)
+		}
+	}
+	queuePollMaxAge := 3 * time.Minute
+	if maxAge := os.Getenv("QUEUE_POLL_MAX_AGE"); maxAge != "" {
+		queuePollMaxAge, err = time.ParseDuration(maxAge)
+		if err != nil || queuePollMaxAge <= 0 {
+			logger.Fatal("Failed to parse the max age of the queue poll", err)
+		}
+	}
+	idempotencyTTL := 24 * time.Hour
+	if ttl := os.Getenv("IDEMPOTENCY_TTL"); ttl != "" {
+		idempotencyTTL, err = time.ParseDuration(ttl)
+		if err != nil || idempotencyTTL <= 0 {
+			logger.Fatal("Failed to parse the TTL of the idempotency keys", err)
+		}
+	}
 	// Caches
 	orderCache := cache.NewOrderRedis(rdb)
+	idempotencyCache := cache.NewIdempotencyRedis(rdb)
 	// Services
-	storeService := store.NewStoreService(storeRepo, mercadoLivre, logger)
-	announceService := announcement.NewAnnouncementService(mercadoLivre, storeService, *logger)
+	webhookService := webhook.NewWebhookService(webhookRepo, notifier.NewHTTPSender(), urlGuard, logger)
+	storeService := store.NewStoreService(storeRepo, mercadoLivre, stateSigner, webhookService, logger)
+	aliasService := alias.NewAliasService(aliasRepo, mercadoLivre, storeService, logger)
+	announceService := announcement.NewAnnouncementService(mercadoLivre, storeService, mercadoLivre, webhookService, aliasService, *logger)
+	alertService := alert.NewAlertService(alertRepo, storeService, announceService, webhookNotifier, emailNotifier, urlGuard, logger)
+	allocationService := allocation.NewAllocationService(allocationRepo, storeService, logger)
+	stockService := stock.NewStockService(stockRepo, mercadoLivre, storeService, announceService, allocationService, stockImportThrottle, logger)
+	kitService := kit.NewKitService(kitRepo, storeService, announceService, stockService, logger)
 	orderService := order.NewOrderService(
 		orderQueue,
 		mercadoLivre,
 		storeService,
 		announceService,
+		alertService,
+		kitService,
+		allocationService,
+		webhookService,
 		orderRepo,
 		orderCache,
 		logger,
 	)
+	analyticsService := analytics.NewAnalyticsService(analyticsRepo, logger)
 
 	// Pull messages from queue
 	go func() {
@@ -112,51 +247,148 @@ func main() {
 		}
 	}()
 
+	// Scan the stock of the stores with alerts
+	go func() {
+		ticker := time.Tick(alertScanInterval)
+		for range ticker {
+			alertService.ScanStock()
+		}
+	}()
+
+	// Send the pending deliveries of the webhooks
+	go func() {
+		ticker := time.Tick(webhookDeliveryInterval)
+		for range ticker {
+			webhookService.DeliverPending()
+		}
+	}()
+
+	// Dispatch the sync actions of the orders that weren't acknowledged
+	go func() {
+		ticker := time.Tick(syncDispatchInterval)
+		for range ticker {
+			orderService.DispatchSyncActions()
+		}
+	}()
+
+	// Search the SKUs of the uploaded stock imports
+	go func() {
+		ticker := time.Tick(5 * time.Second)
+		for range ticker {
+			stockService.ValidateImports()
+		}
+	}()
+
+	// Apply the confirmed stock imports
+	go func() {
+		ticker := time.Tick(30 * time.Second)
+		for range ticker {
+			stockService.RunImports()
+		}
+	}()
+
 	// Process messages
 	go func() {
 		for msgs := range orderChan {
 			for _, msg := range msgs {
-				orderService.ProcessOrder(msg)
+				metrics.CountQueueMessage(orderService.ProcessOrder(msg))
 			}
 		}
 	}()
 
+	// Webhooks
+	allowedIPs, err := mdw.ParseCIDRs(os.Getenv("MELI_WEBHOOK_ALLOWED_IPS"))
+	if err != nil {
+		logger.Fatal("Failed to parse the webhook allowed IPs", err)
+	}
+	if len(allowedIPs) == 0 {
+		logger.Warn("MELI_WEBHOOK_ALLOWED_IPS is empty, notifications are accepted from any IP")
+	}
+	trustedProxies, err := mdw.ParseCIDRs(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		logger.Fatal("Failed to parse the trusted proxies", err)
+	}
+	webhookMiddlewares := chi.Middlewares{
+		mdw.RestrictIPs(allowedIPs, trustedProxies, *logger),
+		mdw.VerifyMeliNotification(meliAppId, storeService, *logger),
+	}
+
 	// Router
 	// TODO Make our own router from scratch, based in Radix Tree
 	r := chi.NewRouter()
+	r.Use(mdw.Trace)
 	r.Use(mdw.NewStructuredLogger(logger))
+	r.Use(mdw.RecordMetrics)
 	// r.Use(middleware.Logger)
 	r.Use(cors.Handler(cors.Options{
 		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins: []string{"https://*", "http://*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", mdw.IdempotencyKeyHeader},
+		ExposedHeaders:   []string{"Link", "Retry-After", mdw.IdempotentReplayedHeader},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
-	// Public Routes
+	// A request with an Idempotency-Key runs once, the clones take the longest.
+	// The keys are scoped by the store, so it wraps the authenticated routes
+	idempotentMiddlewares := chi.Middlewares{mdw.IdempotencyKeys(idempotencyCache, 5*time.Minute, idempotencyTTL, *logger)}
+
+	// Public Routes, with their authenticated subroutes
 	r.Group(func(r chi.Router) {
 		// "/store"
-		handler.MakeStoreHandlers(r, storeService, *logger)
+		handler.MakeStoreHandlers(r, storeService, os.Getenv("OAUTH_FRONTEND_REDIRECT_URL"), idempotentMiddlewares, validate, *logger)
 		// "/order"
-		handler.MakeOrderHandlers(r, orderService, *logger)
+		handler.MakeOrderHandlers(r, orderService, webhookMiddlewares, idempotentMiddlewares, validate, *logger)
 	})
 
 	// Private Routes
 	r.Group(func(r chi.Router) {
 		r.Use(mdw.EnsureValidToken(*logger))
 		r.Use(mdw.AddStoreIDToCtx)
+		r.Use(idempotentMiddlewares...)
 
-		handler.MakeAnnouncementHandlers(r, announceService, storeService, *logger)
+		handler.MakeAnnouncementHandlers(r, announceService, storeService, validate, *logger)
+		handler.MakeAnalyticsHandlers(r, analyticsService, *logger)
+		handler.MakeAlertHandlers(r, alertService, validate, *logger)
+		handler.MakeWebhookHandlers(r, webhookService, validate, *logger)
+		handler.MakeStockHandlers(r, stockService, validate, *logger)
+		handler.MakeKitHandlers(r, kitService, validate, *logger)
+		handler.MakeAliasHandlers(r, aliasService, validate, *logger)
+		handler.MakeAllocationHandlers(r, allocationService, validate, *logger)
 	})
 
 	r.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
+	// Probes
+	// The liveness only tells the instance responds, a stuck poller or a dependency that is down
+	// takes it out of the rotation instead of restarting it
+	liveness := health.NewChecker(2 * time.Second)
+	readiness := health.NewChecker(2 * time.Second)
+	readiness.Register("postgres", dbpool.Ping)
+	readiness.Register("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+	readiness.Register("queue", orderQueue.Ping)
+	readiness.Register("queue_poll", orderQueue.PollCheck(queuePollMaxAge))
+	handler.MakeHealthHandlers(r, liveness, readiness, *logger)
+	handler.MakeDocsHandlers(r)
+
+	// Metrics
+	// Served on their own listener, kept off the public port
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+			logger.Fatal("Failed to serve the metrics", err)
+		}
+	}()
+
 	logger.Info("Listing on 80")
 	PORT := ":80"
 	if env := os.Getenv("APP_ENV"); env == "" || env == "development" {
@@ -0,0 +1,18 @@
+-- The tokens must be decrypted before rolling back, otherwise they become unusable.
+-- The rollback is refused while encrypted tokens remain, dropping their keys would lose them,
+-- the accounts can be disconnected instead, which wipes their tokens.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM mercadolivre_credentials WHERE key_id IS NOT NULL) THEN
+    RAISE EXCEPTION 'mercadolivre_credentials has encrypted tokens, decrypt them or disconnect their accounts before rolling back';
+  END IF;
+END
+$$;
+
+ALTER TABLE mercadolivre_credentials
+DROP COLUMN IF EXISTS key_id,
+DROP COLUMN IF EXISTS data_key;
+
+ALTER TABLE mercadolivre_credentials
+ALTER COLUMN access_token TYPE VARCHAR(80),
+ALTER COLUMN refresh_token TYPE VARCHAR(80);
@@ -0,0 +1,9 @@
+-- Encrypted tokens don't fit in VARCHAR(80) anymore
+ALTER TABLE mercadolivre_credentials
+ALTER COLUMN access_token TYPE TEXT,
+ALTER COLUMN refresh_token TYPE TEXT;
+
+-- Rows without key_id are still in plaintext and are encrypted by the application on startup
+ALTER TABLE mercadolivre_credentials
+ADD COLUMN key_id VARCHAR(80),
+ADD COLUMN data_key TEXT;
\ No newline at end of file
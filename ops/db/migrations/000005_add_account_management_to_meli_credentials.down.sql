@@ -0,0 +1,5 @@
+ALTER TABLE mercadolivre_credentials
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS last_refresh_error,
+DROP COLUMN IF EXISTS last_refresh_error_at,
+DROP COLUMN IF EXISTS disconnected_at;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Disconnected accounts are kept, since orders reference them
+ALTER TABLE mercadolivre_credentials
+ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+ADD COLUMN last_refresh_error TEXT,
+ADD COLUMN last_refresh_error_at TIMESTAMPTZ,
+ADD COLUMN disconnected_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS mercadolivre_credentials_connected_user_id_key;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- A meli user can be linked to only one store. Disconnected accounts are kept for the order history
+CREATE UNIQUE INDEX IF NOT EXISTS mercadolivre_credentials_connected_user_id_key
+ON mercadolivre_credentials (user_id)
+WHERE disconnected_at IS NULL;
\ No newline at end of file
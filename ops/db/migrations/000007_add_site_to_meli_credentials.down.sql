@@ -0,0 +1 @@
+ALTER TABLE mercadolivre_credentials DROP COLUMN IF EXISTS site_id;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Kloni only supported Brazil before the site was stored
+ALTER TABLE mercadolivre_credentials ADD COLUMN site_id VARCHAR(3) NOT NULL DEFAULT 'MLB';
\ No newline at end of file
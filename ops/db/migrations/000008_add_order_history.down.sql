@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS order_items_sku_idx;
+DROP INDEX IF EXISTS order_items_order_id_idx;
+DROP INDEX IF EXISTS orders_account_id_date_created_idx;
+DROP TABLE IF EXISTS order_sync_actions;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Changes made to the clones while the orders were processed
+CREATE TABLE IF NOT EXISTS order_sync_actions(
+  id UUID NOT NULL PRIMARY KEY,
+  order_id UUID REFERENCES orders(id) NOT NULL,
+  account_id UUID REFERENCES mercadolivre_credentials(id) NOT NULL,
+  announcement_id VARCHAR(80) NOT NULL,
+  variation_id BIGINT NOT NULL DEFAULT 0,
+  quantity INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_sync_actions_order_id_idx ON order_sync_actions(order_id);
+
+-- Keyset pagination of the order history
+CREATE INDEX IF NOT EXISTS orders_account_id_date_created_idx ON orders(account_id, date_created DESC, id DESC);
+CREATE INDEX IF NOT EXISTS order_items_order_id_idx ON order_items(order_id);
+CREATE INDEX IF NOT EXISTS order_items_sku_idx ON order_items(sku);
\ No newline at end of file
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS orders_pack_id_idx;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS currency_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_price;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variation_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS buyer_nickname;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS pack_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS last_updated;
+ALTER TABLE orders DROP COLUMN IF EXISTS date_closed;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- Marketplace data of the orders, kept for the sales reports
+ALTER TABLE orders ADD COLUMN date_closed TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN last_updated TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN total_amount NUMERIC(14, 2);
+ALTER TABLE orders ADD COLUMN paid_amount NUMERIC(14, 2);
+ALTER TABLE orders ADD COLUMN currency_id VARCHAR(3);
+ALTER TABLE orders ADD COLUMN pack_id VARCHAR(30);
+ALTER TABLE orders ADD COLUMN shipping_id VARCHAR(30);
+ALTER TABLE orders ADD COLUMN buyer_nickname VARCHAR(80);
+
+ALTER TABLE order_items ADD COLUMN variation_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN unit_price NUMERIC(14, 2);
+ALTER TABLE order_items ADD COLUMN currency_id VARCHAR(3);
+
+CREATE INDEX IF NOT EXISTS orders_pack_id_idx ON orders(pack_id) WHERE pack_id IS NOT NULL;
\ No newline at end of file
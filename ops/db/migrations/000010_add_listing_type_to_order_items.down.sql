@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_date_created_idx;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS listing_type_id;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Listing type of the sold announcements, used by the sales analytics
+ALTER TABLE order_items ADD COLUMN listing_type_id VARCHAR(30);
+
+CREATE INDEX IF NOT EXISTS orders_date_created_idx ON orders(date_created);
\ No newline at end of file
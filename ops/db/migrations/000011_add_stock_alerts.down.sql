@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS stock_alerts_open_idx;
+DROP TABLE IF EXISTS stock_alerts;
+DROP TABLE IF EXISTS stock_thresholds;
+DROP TABLE IF EXISTS alert_settings;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- Where the stock alerts of a store are sent
+CREATE TABLE IF NOT EXISTS alert_settings(
+  store_id UUID REFERENCES store(id) NOT NULL PRIMARY KEY,
+  webhook_url TEXT,
+  email VARCHAR(254),
+  default_threshold INTEGER CHECK (default_threshold >= 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS stock_thresholds(
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  threshold INTEGER NOT NULL CHECK (threshold >= 0),
+  PRIMARY KEY (store_id, sku)
+);
+
+CREATE TABLE IF NOT EXISTS stock_alerts(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  level VARCHAR(20) NOT NULL,
+  quantity INTEGER NOT NULL,
+  threshold INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  resolved_at TIMESTAMPTZ
+);
+
+-- An alert isn't raised again until the SKU is restocked
+CREATE UNIQUE INDEX IF NOT EXISTS stock_alerts_open_idx ON stock_alerts(store_id, sku) WHERE resolved_at IS NULL;
\ No newline at end of file
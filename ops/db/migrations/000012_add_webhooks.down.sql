@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS webhook_deliveries_subscription_idx;
+DROP INDEX IF EXISTS webhook_deliveries_due_idx;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS webhook_subscriptions_store_idx;
+DROP TABLE IF EXISTS webhook_subscriptions;
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- URLs of the stores that receive the Kloni events
+CREATE TABLE IF NOT EXISTS webhook_subscriptions(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  url TEXT NOT NULL,
+  secret VARCHAR(100) NOT NULL,
+  events TEXT[] NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL,
+  disabled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_store_idx ON webhook_subscriptions(store_id) WHERE active;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+  id UUID NOT NULL PRIMARY KEY,
+  subscription_id UUID REFERENCES webhook_subscriptions(id) NOT NULL,
+  event_id UUID NOT NULL,
+  event_type VARCHAR(50) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_status_code INTEGER,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  delivered_at TIMESTAMPTZ
+);
+
+-- Deliveries waiting to be sent
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries(subscription_id, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stock_import_rows;
+DROP INDEX IF EXISTS stock_imports_pending_idx;
+DROP INDEX IF EXISTS stock_imports_store_idx;
+DROP TABLE IF EXISTS stock_imports;
\ No newline at end of file
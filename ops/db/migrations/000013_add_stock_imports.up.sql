@@ -0,0 +1,28 @@
+-- Stock files uploaded by the stores
+CREATE TABLE IF NOT EXISTS stock_imports(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  file_name TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  finished_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stock_imports_store_idx ON stock_imports(store_id, created_at DESC);
+-- Imports waiting for a worker
+CREATE INDEX IF NOT EXISTS stock_imports_pending_idx ON stock_imports(updated_at) WHERE status IN ('confirmed', 'running');
+
+CREATE TABLE IF NOT EXISTS stock_import_rows(
+  import_id UUID REFERENCES stock_imports(id) ON DELETE CASCADE NOT NULL,
+  line INTEGER NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  quantity INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  error TEXT,
+  listings INTEGER NOT NULL DEFAULT 0,
+  updated INTEGER NOT NULL DEFAULT 0,
+  failed INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (import_id, line)
+);
\ No newline at end of file
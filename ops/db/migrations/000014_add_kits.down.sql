@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS kit_components_sku_idx;
+DROP TABLE IF EXISTS kit_components;
+DROP TABLE IF EXISTS kits;
\ No newline at end of file
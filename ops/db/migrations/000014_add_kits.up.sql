@@ -0,0 +1,20 @@
+-- Kits are SKUs composed of other SKUs, their stock depends on the stock of the components
+CREATE TABLE IF NOT EXISTS kits(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  title VARCHAR(255),
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  UNIQUE (store_id, sku)
+);
+
+CREATE TABLE IF NOT EXISTS kit_components(
+  kit_id UUID REFERENCES kits(id) ON DELETE CASCADE NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (kit_id, sku)
+);
+
+-- The kits of a sold component are searched on every order
+CREATE INDEX IF NOT EXISTS kit_components_sku_idx ON kit_components(sku);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS sku_aliases_sku_idx;
+DROP TABLE IF EXISTS sku_aliases;
+DROP TABLE IF EXISTS sku_mappings;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Relates the canonical SKU of a product to the SKUs and the listings the accounts use for it
+CREATE TABLE IF NOT EXISTS sku_mappings(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  UNIQUE (store_id, sku)
+);
+
+CREATE TABLE IF NOT EXISTS sku_aliases(
+  mapping_id UUID REFERENCES sku_mappings(id) ON DELETE CASCADE NOT NULL,
+  account_id UUID REFERENCES mercadolivre_credentials(id) ON DELETE CASCADE NOT NULL,
+  sku VARCHAR(80),
+  listing_ids TEXT[] NOT NULL DEFAULT '{}',
+  PRIMARY KEY (mapping_id, account_id)
+);
+
+-- The SKUs of the orders are resolved through the aliases
+CREATE INDEX IF NOT EXISTS sku_aliases_sku_idx ON sku_aliases(sku);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_levels;
+DROP TABLE IF EXISTS account_allocations;
+DROP TABLE IF EXISTS allocation_policies;
\ No newline at end of file
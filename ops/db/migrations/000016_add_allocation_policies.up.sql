@@ -0,0 +1,28 @@
+-- Distributes the stock of a SKU across the accounts of a store, an empty SKU is the default policy of the store
+CREATE TABLE IF NOT EXISTS allocation_policies(
+  id UUID NOT NULL PRIMARY KEY,
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL DEFAULT '',
+  priority_account_id UUID REFERENCES mercadolivre_credentials(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  UNIQUE (store_id, sku)
+);
+
+CREATE TABLE IF NOT EXISTS account_allocations(
+  policy_id UUID REFERENCES allocation_policies(id) ON DELETE CASCADE NOT NULL,
+  account_id UUID REFERENCES mercadolivre_credentials(id) ON DELETE CASCADE NOT NULL,
+  buffer INTEGER NOT NULL DEFAULT 0 CHECK (buffer >= 0),
+  percentage INTEGER NOT NULL DEFAULT 100 CHECK (percentage BETWEEN 1 AND 100),
+  max_quantity INTEGER CHECK (max_quantity >= 0),
+  PRIMARY KEY (policy_id, account_id)
+);
+
+-- True stock of the SKUs with a policy, the listings publish only a share of it
+CREATE TABLE IF NOT EXISTS stock_levels(
+  store_id UUID REFERENCES store(id) NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  quantity INTEGER NOT NULL CHECK (quantity >= 0),
+  updated_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (store_id, sku)
+);
\ No newline at end of file
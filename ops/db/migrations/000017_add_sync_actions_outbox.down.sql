@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS order_sync_actions_pending_idx;
+ALTER TABLE order_sync_actions
+  DROP COLUMN IF EXISTS executed_at,
+  DROP COLUMN IF EXISTS next_attempt_at,
+  DROP COLUMN IF EXISTS attempts,
+  DROP COLUMN IF EXISTS sku,
+  DROP COLUMN IF EXISTS kind;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Sync actions of the orders as a transactional outbox, dispatched after the order is registered
+ALTER TABLE order_sync_actions
+  ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'quantity',
+  ADD COLUMN IF NOT EXISTS sku VARCHAR(80) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS executed_at TIMESTAMPTZ;
+
+-- The existing actions were executed before the order was registered
+UPDATE order_sync_actions SET executed_at = created_at WHERE executed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS order_sync_actions_pending_idx ON order_sync_actions(next_attempt_at) WHERE status = 'pending';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE order_sync_actions DROP COLUMN IF EXISTS claim_id;
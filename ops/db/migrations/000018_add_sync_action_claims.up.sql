@@ -0,0 +1,2 @@
+-- Claim of the dispatcher executing a pending sync action, its result is only acknowledged with it
+ALTER TABLE order_sync_actions ADD COLUMN IF NOT EXISTS claim_id UUID;
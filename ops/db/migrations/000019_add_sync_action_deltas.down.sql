@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS kit_sale_components;
+ALTER TABLE order_sync_actions DROP COLUMN IF EXISTS delta;
@@ -0,0 +1,11 @@
+-- The sales are planned as a change of the quantity of the listings, applied to the quantity
+-- they have when the action is executed. The existing actions keep their absolute quantity
+ALTER TABLE order_sync_actions ADD COLUMN IF NOT EXISTS delta INTEGER NOT NULL DEFAULT 0;
+
+-- Components decremented by the sale of a kit, so a retried sale doesn't decrement them again
+CREATE TABLE IF NOT EXISTS kit_sale_components(
+  sale_id UUID NOT NULL,
+  sku VARCHAR(80) NOT NULL,
+  applied_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (sale_id, sku)
+);
@@ -0,0 +1,4 @@
+-- The imports that weren't validated can't be previewed without the worker, they must be uploaded again
+DELETE FROM stock_imports WHERE status IN ('uploaded', 'validating');
+
+DROP INDEX IF EXISTS stock_imports_validation_idx;
@@ -0,0 +1,2 @@
+-- The SKUs of the imports are searched by a worker, the imports waiting for it are uploaded or validating
+CREATE INDEX IF NOT EXISTS stock_imports_validation_idx ON stock_imports(created_at) WHERE status IN ('uploaded', 'validating');
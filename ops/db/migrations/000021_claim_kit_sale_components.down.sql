@@ -0,0 +1,4 @@
+-- The claimed components may have been decremented, they aren't decremented again
+UPDATE kit_sale_components SET applied_at = claimed_at WHERE applied_at IS NULL;
+ALTER TABLE kit_sale_components ALTER COLUMN applied_at SET NOT NULL;
+ALTER TABLE kit_sale_components DROP COLUMN IF EXISTS claimed_at;
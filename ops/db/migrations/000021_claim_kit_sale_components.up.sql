@@ -0,0 +1,5 @@
+-- The components of a kit sale are claimed before their listings are updated,
+-- they're applied once every listing was updated
+ALTER TABLE kit_sale_components ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+UPDATE kit_sale_components SET claimed_at = applied_at;
+ALTER TABLE kit_sale_components ALTER COLUMN applied_at DROP NOT NULL;
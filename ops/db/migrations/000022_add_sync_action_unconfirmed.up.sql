@@ -0,0 +1,4 @@
+-- Only the sales whose update wasn't answered by Mercado Livre may already be on the listing.
+-- The failed sales recorded before are still treated as possibly applied
+ALTER TABLE order_sync_actions ADD COLUMN IF NOT EXISTS unconfirmed BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE order_sync_actions SET unconfirmed = TRUE WHERE delta <> 0 AND error IS NOT NULL AND status <> 'done';
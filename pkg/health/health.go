@@ -0,0 +1,161 @@
+// Package health checks the dependencies of the instance for the liveness and readiness probes
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status of a component and of the instance
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports whether a component works, the context carries the timeout of the check
+type Check func(ctx context.Context) error
+
+// Component is the result of the check of a component
+type Component struct {
+	Status  string
+	Error   string
+	Latency time.Duration
+}
+
+// Report is the result of the checks, the instance is up when all of its components are up
+type Report struct {
+	Status     string
+	Components map[string]Component
+}
+
+// Up reports whether all the components are up
+func (r *Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Checker runs the checks of the components concurrently
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// NewChecker creates a checker whose checks fail after the timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Register adds the check of a component, a component registered again replaces the previous check
+func (c *Checker) Register(name string, check Check) {
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Run checks all the components, each one within the timeout of the checker
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]Component, len(c.names)),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			component := run(ctx, check, c.timeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = component
+			if component.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+	return report
+}
+
+// run runs a check, a check that doesn't return within the timeout fails
+func run(ctx context.Context, check Check, timeout time.Duration) Component {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	component := Component{Status: StatusUp, Latency: time.Since(start)}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
+
+// ErrStale is returned by the check of an activity that didn't succeed recently
+var ErrStale = errors.New("stale")
+
+// Heartbeat records the last success of a recurring activity, e.g. the polls of a queue
+type Heartbeat struct {
+	mu      sync.RWMutex
+	started time.Time
+	last    time.Time
+}
+
+// NewHeartbeat creates a heartbeat, the activity has until the max age of its check to succeed for the first time
+func NewHeartbeat() *Heartbeat {
+	return &Heartbeat{started: time.Now()}
+}
+
+// Beat records a success of the activity
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = time.Now()
+}
+
+// Last is the time of the last success, zero if the activity didn't succeed yet
+func (h *Heartbeat) Last() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.last
+}
+
+// Check fails when the last success of the activity is older than maxAge
+func (h *Heartbeat) Check(maxAge time.Duration) Check {
+	return func(ctx context.Context) error {
+		last := h.Last()
+		if last.IsZero() {
+			if age := time.Since(h.started); age > maxAge {
+				return fmt.Errorf("%w: no success in %s", ErrStale, age.Round(time.Second))
+			}
+			return nil
+		}
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("%w: last success %s ago", ErrStale, age.Round(time.Second))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	t.Run("all components up", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Register("postgres", func(ctx context.Context) error { return nil })
+		checker.Register("redis", func(ctx context.Context) error { return nil })
+
+		report := checker.Run(context.Background())
+		if !report.Up() {
+			t.Errorf("got status %s, want %s", report.Status, StatusUp)
+		}
+		if len(report.Components) != 2 {
+			t.Fatalf("got %d components, want 2", len(report.Components))
+		}
+		for name, component := range report.Components {
+			if component.Status != StatusUp || component.Error != "" {
+				t.Errorf("component %s = %+v, want up", name, component)
+			}
+		}
+	})
+
+	t.Run("component down", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Register("postgres", func(ctx context.Context) error { return nil })
+		checker.Register("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		report := checker.Run(context.Background())
+		if report.Up() {
+			t.Errorf("got status %s, want %s", report.Status, StatusDown)
+		}
+		if got := report.Components["postgres"].Status; got != StatusUp {
+			t.Errorf("postgres = %s, want %s", got, StatusUp)
+		}
+		if got := report.Components["redis"]; got.Status != StatusDown || got.Error != "connection refused" {
+			t.Errorf("redis = %+v, want down with the error", got)
+		}
+	})
+
+	t.Run("check times out", func(t *testing.T) {
+		checker := NewChecker(10 * time.Millisecond)
+		checker.Register("queue", func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+
+		start := time.Now()
+		report := checker.Run(context.Background())
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Run() took %s, want it to stop at the timeout", elapsed)
+		}
+		if got := report.Components["queue"]; got.Status != StatusDown || got.Error != context.DeadlineExceeded.Error() {
+			t.Errorf("queue = %+v, want down with %v", got, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("register replaces the check", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Register("redis", func(ctx context.Context) error { return errors.New("down") })
+		checker.Register("redis", func(ctx context.Context) error { return nil })
+
+		report := checker.Run(context.Background())
+		if !report.Up() || len(report.Components) != 1 {
+			t.Errorf("got %+v, want only redis up", report)
+		}
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("within the max age", func(t *testing.T) {
+		heartbeat := NewHeartbeat()
+		heartbeat.Beat()
+
+		if err := heartbeat.Check(time.Minute)(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		heartbeat := NewHeartbeat()
+		heartbeat.Beat()
+		time.Sleep(5 * time.Millisecond)
+
+		if err := heartbeat.Check(time.Millisecond)(context.Background()); !errors.Is(err, ErrStale) {
+			t.Errorf("got %v, want %v", err, ErrStale)
+		}
+	})
+
+	t.Run("no success yet", func(t *testing.T) {
+		heartbeat := NewHeartbeat()
+		if !heartbeat.Last().IsZero() {
+			t.Errorf("got last %s, want zero", heartbeat.Last())
+		}
+		if err := heartbeat.Check(time.Minute)(context.Background()); err != nil {
+			t.Errorf("unexpected error during the first max age: %v", err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		if err := heartbeat.Check(time.Millisecond)(context.Background()); !errors.Is(err, ErrStale) {
+			t.Errorf("got %v, want %v", err, ErrStale)
+		}
+	})
+}
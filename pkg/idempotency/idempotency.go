@@ -0,0 +1,66 @@
+// Package idempotency records the responses of the requests sent with an idempotency key,
+// so a request sent again with the same key gets the original response instead of running again
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// MaxKeyLength is the length limit of a key, a UUID is recommended
+const MaxKeyLength = 255
+
+// Record is the request of a key and, once it completes, its response
+type Record struct {
+	// Identifies the request, a key can't be reused by another request
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Completed reports whether the response of the request was recorded, otherwise it's still running
+func (r *Record) Completed() bool {
+	return r.Status != 0
+}
+
+// Store keeps the records by key, the keys are scoped by the caller
+type Store interface {
+	// Reserve records the fingerprint of the request of a key for ttl, unless the key has a record.
+	//
+	// Returns:
+	//   - *Record: The existing record of the key, nil when the key was reserved
+	//   - error: Error to access the store
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, error)
+	// Complete replaces the reservation of a key with the response of its request for ttl
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release removes the record of a key, so the request can run again
+	Release(ctx context.Context, key string) error
+}
+
+// Fingerprint identifies a request by its method, URI and body
+func Fingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(uri))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ValidKey reports whether the key is non-empty, at most MaxKeyLength long and printable ASCII
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("POST", "/announcement", []byte(`{"root_id":"MLB1"}`))
+	if base != Fingerprint("POST", "/announcement", []byte(`{"root_id":"MLB1"}`)) {
+		t.Error("the same request has different fingerprints")
+	}
+
+	others := map[string]string{
+		"method": Fingerprint("PUT", "/announcement", []byte(`{"root_id":"MLB1"}`)),
+		"uri":    Fingerprint("POST", "/announcement/import", []byte(`{"root_id":"MLB1"}`)),
+		"body":   Fingerprint("POST", "/announcement", []byte(`{"root_id":"MLB2"}`)),
+		// The parts are delimited, moving bytes between them changes the fingerprint
+		"boundary": Fingerprint("POST", "/announcement{", []byte(`"root_id":"MLB1"}`)),
+	}
+	for name, fingerprint := range others {
+		if fingerprint == base {
+			t.Errorf("another %s has the same fingerprint", name)
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	tests := map[string]bool{
+		"3f1c2a9e-8d4b-4c1e-9a7f-2b6d5e8c1a40": true,
+		"clone-MLB1-1":                         true,
+		strings.Repeat("k", MaxKeyLength):      true,
+		"":                                     false,
+		strings.Repeat("k", MaxKeyLength+1):    false,
+		"with space":                           false,
+		"acentuação":                           false,
+	}
+	for key, want := range tests {
+		if got := ValidKey(key); got != want {
+			t.Errorf("ValidKey(%q) = %t, want %t", key, got, want)
+		}
+	}
+}
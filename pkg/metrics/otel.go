@@ -1,11 +1,14 @@
 package metrics
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"os"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
@@ -13,21 +16,50 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/Vractos/kloni"
+
+// Exporters of the traces
+const (
+	// Exports to an OpenTelemetry collector with OTLP over HTTP, configured by the
+	// standard variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterOTLP = "otlp"
+	// Writes the spans to the standard output, one JSON per line
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
 type OpenTel struct {
-	ServiceName      string
-	ServiceVersion   string
-	ExporterEndpoint string
-	Environment      string
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+	// ExporterOTLP, ExporterStdout or ExporterNone, the traces aren't exported when it's empty
+	Exporter string
 }
 
 func NewOpenTel() *OpenTel {
 	return &OpenTel{}
 }
 
-func (o *OpenTel) GetTracer() trace.Tracer {
-	exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(o.ExporterEndpoint)))
-	if err != nil {
-		log.Fatal(err)
+// Start sets the global tracer provider and the propagator of the trace context.
+// The propagator is set even when the traces aren't exported, so the trace context of
+// the requests still reaches the queue. The returned function flushes the pending spans.
+func (o *OpenTel) Start(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exp tracesdk.SpanExporter
+	switch o.Exporter {
+	case ExporterOTLP:
+		otlpExp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		exp = otlpExp
+	case ExporterStdout:
+		exp = newStdoutExporter(os.Stdout)
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", o.Exporter)
 	}
 
 	r, err := resource.Merge(
@@ -40,17 +72,53 @@ func (o *OpenTel) GetTracer() trace.Tracer {
 		),
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	tp := tracesdk.NewTracerProvider(
 		tracesdk.WithBatcher(exp),
 		tracesdk.WithResource(r),
 	)
-
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Tracer is the tracer of Kloni, from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan starts a span that is a child of the span of the context
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// EndSpan records the error on the span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// IsTraced reports whether the context carries a span, the spans of the adapters are
+// only started within a trace, so the calls made outside of one don't start new traces
+func IsTraced(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// InjectTraceContext writes the trace context of ctx to the carrier, e.g. the attributes of a message
+func InjectTraceContext(ctx context.Context, carrier map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(carrier))
+}
+
+// TraceContextFields are the keys written by InjectTraceContext
+func TraceContextFields() []string {
+	return otel.GetTextMapPropagator().Fields()
+}
 
-	tracer := otel.Tracer("kloni")
-	return tracer
+// ExtractTraceContext returns a context with the trace context read from the carrier
+func ExtractTraceContext(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
 }
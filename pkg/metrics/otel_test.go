@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	shutdown, err := (&OpenTel{Exporter: ExporterNone}).Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer shutdown(context.Background())
+
+	tp := tracesdk.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "webhook")
+	defer span.End()
+
+	carrier := map[string]string{}
+	InjectTraceContext(ctx, carrier)
+	if carrier["traceparent"] == "" {
+		t.Fatalf("InjectTraceContext() = %v, want a traceparent", carrier)
+	}
+
+	extracted := ExtractTraceContext(context.Background(), carrier)
+	if !IsTraced(extracted) {
+		t.Fatal("IsTraced() = false after extracting the trace context")
+	}
+	if got, want := trace.SpanContextFromContext(extracted).TraceID(), span.SpanContext().TraceID(); got != want {
+		t.Errorf("trace ID = %s, want %s", got, want)
+	}
+
+	if IsTraced(context.Background()) {
+		t.Error("IsTraced() = true without a span")
+	}
+}
+
+func TestStartUnknownExporter(t *testing.T) {
+	if _, err := (&OpenTel{Exporter: "jaeger"}).Start(context.Background()); err == nil {
+		t.Error("Start() error = nil, want an error for an unknown exporter")
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSyncer(newStdoutExporter(&buf)))
+	defer tp.Shutdown(context.Background())
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := StartSpan(context.Background(), "order.ProcessOrder")
+	_, child := StartSpan(ctx, "order.SyncItem")
+	EndSpan(child, errors.New("sync error"))
+	EndSpan(parent, nil)
+
+	dec := json.NewDecoder(&buf)
+	var spans []stdoutSpan
+	for dec.More() {
+		var s stdoutSpan
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		spans = append(spans, s)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	if spans[0].Name != "order.SyncItem" || spans[0].Status != "Error" || spans[0].StatusDesc != "sync error" {
+		t.Errorf("child span = %+v, want order.SyncItem with the error", spans[0])
+	}
+	if spans[0].ParentSpanID != spans[1].SpanID || spans[0].TraceID != spans[1].TraceID {
+		t.Errorf("child span isn't a child of %s", spans[1].SpanID)
+	}
+	if spans[1].Name != "order.ProcessOrder" || spans[1].ParentSpanID != "" {
+		t.Errorf("parent span = %+v, want order.ProcessOrder without a parent", spans[1])
+	}
+}
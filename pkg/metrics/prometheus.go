@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kloni"
+
+// Outcomes of the operations counted by the metrics
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the HTTP requests served by the API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	meliRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "meli_request_duration_seconds",
+		Help:      "Duration of the requests to the Mercado Livre API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint", "status"})
+
+	meliRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "meli_request_errors_total",
+		Help:      "Requests to the Mercado Livre API that failed or were answered with an error status.",
+	}, []string{"method", "endpoint"})
+
+	queueLag = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_lag_seconds",
+		Help:      "Time between an order notification being queued and consumed.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800},
+	})
+
+	queueMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_messages_total",
+		Help:      "Order notifications consumed from the queue, by result.",
+	}, []string{"result"})
+
+	syncOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_operations_total",
+		Help:      "Attempts of the sync actions of the orders, by kind and result.",
+	}, []string{"kind", "result"})
+
+	tokenRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_refreshes_total",
+		Help:      "Refreshes of the Mercado Livre access tokens, by result.",
+	}, []string{"result"})
+
+	cloneJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clone_jobs_total",
+		Help:      "Announcements cloned to other accounts, by result.",
+	}, []string{"result"})
+
+	cloneJobDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "clone_job_duration_seconds",
+		Help:      "Duration of the clone jobs.",
+		Buckets:   []float64{1, 2.5, 5, 10, 30, 60, 120},
+	})
+)
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Result is the outcome of an operation, ResultFailure when err isn't nil
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// ObserveHTTPRequest records a request served by the API, route is the pattern that matched it
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveMeliRequest records a request to the Mercado Livre API, status is zero when no response was received
+func ObserveMeliRequest(method, endpoint string, status int, duration time.Duration) {
+	meliRequestDuration.WithLabelValues(method, endpoint, strconv.Itoa(status)).Observe(duration.Seconds())
+	if status == 0 || status >= http.StatusBadRequest {
+		meliRequestErrors.WithLabelValues(method, endpoint).Inc()
+	}
+}
+
+// ObserveQueueLag records the time an order notification waited in the queue
+func ObserveQueueLag(lag time.Duration) {
+	queueLag.Observe(lag.Seconds())
+}
+
+// CountQueueMessage counts an order notification consumed from the queue
+func CountQueueMessage(err error) {
+	queueMessages.WithLabelValues(Result(err)).Inc()
+}
+
+// CountSyncOperation counts an attempt of a sync action of an order
+func CountSyncOperation(kind string, err error) {
+	syncOperations.WithLabelValues(kind, Result(err)).Inc()
+}
+
+// CountTokenRefresh counts a refresh of a Mercado Livre access token
+func CountTokenRefresh(err error) {
+	tokenRefreshes.WithLabelValues(Result(err)).Inc()
+}
+
+// ObserveCloneJob records a clone job and its duration
+func ObserveCloneJob(err error, duration time.Duration) {
+	cloneJobs.WithLabelValues(Result(err)).Inc()
+	cloneJobDuration.Observe(duration.Seconds())
+}
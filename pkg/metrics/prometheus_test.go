@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestResult(t *testing.T) {
+	if got := Result(nil); got != ResultSuccess {
+		t.Errorf("Result(nil) = %s, want %s", got, ResultSuccess)
+	}
+	if got := Result(errors.New("error")); got != ResultFailure {
+		t.Errorf("Result(err) = %s, want %s", got, ResultFailure)
+	}
+}
+
+func TestObserveMeliRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		status     int
+		wantErrors float64
+	}{
+		{name: "success", endpoint: "/items/:id", status: http.StatusOK},
+		{name: "error status", endpoint: "/orders/:id", status: http.StatusNotFound, wantErrors: 1},
+		{name: "no response", endpoint: "/packs/:id", wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ObserveMeliRequest(http.MethodGet, tt.endpoint, tt.status, time.Second)
+
+			if got := testutil.ToFloat64(meliRequestErrors.WithLabelValues(http.MethodGet, tt.endpoint)); got != tt.wantErrors {
+				t.Errorf("meli_request_errors_total = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestCounters(t *testing.T) {
+	fail := errors.New("error")
+	CountSyncOperation("quantity", nil)
+	CountSyncOperation("quantity", fail)
+	CountSyncOperation("quantity", fail)
+	CountTokenRefresh(fail)
+	CountQueueMessage(nil)
+
+	if got := testutil.ToFloat64(syncOperations.WithLabelValues("quantity", ResultFailure)); got != 2 {
+		t.Errorf("sync_operations_total = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(tokenRefreshes.WithLabelValues(ResultFailure)); got != 1 {
+		t.Errorf("token_refreshes_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(queueMessages.WithLabelValues(ResultSuccess)); got != 1 {
+		t.Errorf("queue_messages_total = %v, want 1", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ObserveCloneJob(nil, time.Second)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `kloni_clone_jobs_total{result="success"} 1`) {
+		t.Errorf("the clone jobs weren't exposed:\n%s", body)
+	}
+}
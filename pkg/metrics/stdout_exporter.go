@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// stdoutExporter writes the spans as JSON lines, for development and for
+// environments where the output is collected by the logging pipeline
+type stdoutExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+type stdoutSpan struct {
+	Name         string            `json:"name"`
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Kind         string            `json:"kind"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Status       string            `json:"status"`
+	StatusDesc   string            `json:"status_description,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+func newStdoutExporter(w io.Writer) *stdoutExporter {
+	return &stdoutExporter{enc: json.NewEncoder(w)}
+}
+
+// ExportSpans implements tracesdk.SpanExporter
+func (e *stdoutExporter) ExportSpans(ctx context.Context, spans []tracesdk.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range spans {
+		out := stdoutSpan{
+			Name:       s.Name(),
+			TraceID:    s.SpanContext().TraceID().String(),
+			SpanID:     s.SpanContext().SpanID().String(),
+			Kind:       s.SpanKind().String(),
+			Start:      s.StartTime(),
+			End:        s.EndTime(),
+			Status:     s.Status().Code.String(),
+			StatusDesc: s.Status().Description,
+		}
+		if s.Parent().IsValid() {
+			out.ParentSpanID = s.Parent().SpanID().String()
+		}
+		if attrs := s.Attributes(); len(attrs) > 0 {
+			out.Attributes = make(map[string]string, len(attrs))
+			for _, attr := range attrs {
+				out.Attributes[string(attr.Key)] = attr.Value.Emit()
+			}
+		}
+		if err := e.enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown implements tracesdk.SpanExporter
+func (e *stdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package netguard keeps the requests sent to the URLs given by the stores away from
+// the internal network, e.g. the database or the metadata service of the cloud provider
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInsecureURL      = errors.New("the url must be an absolute https url")
+	ErrForbiddenAddress = errors.New("the url must resolve to a public address")
+)
+
+// Ranges that aren't covered by the methods of net.IP
+var forbiddenNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // This network
+	"100.64.0.0/10", // Shared address space, used by some metadata services
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // Benchmarking
+	"240.0.0.0/4",   // Reserved, including the broadcast address
+	"64:ff9b::/96",  // IPv4/IPv6 translation, reaches any IPv4 address
+	"2002::/16",     // 6to4, reaches any IPv4 address
+)
+
+// Resolver resolves the host of a URL, net.DefaultResolver implements it
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type Guard struct {
+	resolver Resolver
+	timeout  time.Duration
+}
+
+func NewGuard(resolver Resolver) *Guard {
+	return &Guard{resolver: resolver, timeout: 5 * time.Second}
+}
+
+// Check implements common.URLGuard
+func (g *Guard) Check(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrInsecureURL
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !Allowed(ip) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	addrs, err := g.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return ErrForbiddenAddress
+	}
+	// Every address must be allowed, the one used by the request isn't known yet
+	for _, addr := range addrs {
+		if !Allowed(addr.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// Allowed reports whether the IP is a public unicast address, so it isn't a loopback,
+// private, link-local (which includes the metadata services), multicast or reserved address
+func Allowed(ip net.IP) bool {
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range forbiddenNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// DialContext wraps the dialer so it refuses to connect to the addresses that aren't Allowed.
+// The address is checked after it's resolved, so a host resolving to another address than when
+// its URL was checked can't reach the internal network.
+func DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	guarded := *dialer
+	control := dialer.Control
+	guarded.Control = func(network, address string, c syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if !Allowed(net.ParseIP(host)) {
+			return ErrForbiddenAddress
+		}
+		if control != nil {
+			return control(network, address, c)
+		}
+		return nil
+	}
+	return guarded.DialContext
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,117 @@
+// Package oauthstate implements signed and expiring OAuth state parameters
+package oauthstate
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidState = errors.New("invalid state")
+	ErrExpiredState = errors.New("expired state")
+	ErrShortSecret  = errors.New("the state secret must have at least 32 bytes")
+)
+
+const nonceSize = 16
+
+var encoding = base64.RawURLEncoding
+
+// HMACSigner signs states with HMAC-SHA256.
+//
+// A state has the format base64url(nonce | expiration | payload).base64url(mac),
+// the nonce makes every state unique and is also the seed of the PKCE code verifier.
+type HMACSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewHMACSigner(secret []byte, ttl time.Duration) (*HMACSigner, error) {
+	if len(secret) < 32 {
+		return nil, ErrShortSecret
+	}
+	return &HMACSigner{secret: secret, ttl: ttl, now: time.Now}, nil
+}
+
+func (s *HMACSigner) mac(purpose string, data []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(purpose))
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// Sign implements common.StateSigner
+func (s *HMACSigner) Sign(payload []byte) (string, error) {
+	data := make([]byte, nonceSize+8, nonceSize+8+len(payload))
+	if _, err := io.ReadFull(rand.Reader, data[:nonceSize]); err != nil {
+		return "", err
+	}
+	binary.BigEndian.PutUint64(data[nonceSize:], uint64(s.now().Add(s.ttl).Unix()))
+	data = append(data, payload...)
+
+	return encoding.EncodeToString(data) + "." + encoding.EncodeToString(s.mac("state", data)), nil
+}
+
+// Verify implements common.StateSigner
+func (s *HMACSigner) Verify(state string) ([]byte, error) {
+	data, mac, err := s.decode(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(mac, s.mac("state", data)) {
+		return nil, ErrInvalidState
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(data[nonceSize:nonceSize+8])), 0)
+	if s.now().After(expiresAt) {
+		return nil, ErrExpiredState
+	}
+
+	return data[nonceSize+8:], nil
+}
+
+// CodeVerifier implements common.StateSigner
+//
+// The verifier is derived from the nonce of the state, so it doesn't need to be
+// stored between the authorization and the callback. Only the code challenge,
+// computed from it, is sent to the authorization server.
+func (s *HMACSigner) CodeVerifier(state string) string {
+	data, _, err := s.decode(state)
+	if err != nil {
+		return ""
+	}
+	return encoding.EncodeToString(s.mac("pkce", data[:nonceSize]))
+}
+
+func (s *HMACSigner) decode(state string) (data []byte, mac []byte, err error) {
+	encodedData, encodedMac, found := strings.Cut(state, ".")
+	if !found {
+		return nil, nil, ErrInvalidState
+	}
+
+	data, err = encoding.DecodeString(encodedData)
+	if err != nil || len(data) < nonceSize+8 {
+		return nil, nil, ErrInvalidState
+	}
+
+	mac, err = encoding.DecodeString(encodedMac)
+	if err != nil {
+		return nil, nil, ErrInvalidState
+	}
+
+	return data, mac, nil
+}
+
+// CodeChallenge returns the S256 PKCE code challenge of a code verifier
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return encoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package oauthstate
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestHMACSigner(t *testing.T) {
+	signer, err := NewHMACSigner(secret, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error creating signer: %v", err)
+	}
+
+	t.Run("sign and verify", func(t *testing.T) {
+		state, err := signer.Sign([]byte(`{"store_id":"1"}`))
+		if err != nil {
+			t.Fatalf("unexpected error signing: %v", err)
+		}
+
+		payload, err := signer.Verify(state)
+		if err != nil {
+			t.Fatalf("unexpected error verifying: %v", err)
+		}
+		if !bytes.Equal(payload, []byte(`{"store_id":"1"}`)) {
+			t.Errorf("got payload %s", payload)
+		}
+	})
+
+	t.Run("tampered state", func(t *testing.T) {
+		state, _ := signer.Sign([]byte("payload"))
+		data, mac, _ := strings.Cut(state, ".")
+		tampered := data[:len(data)-1] + string(data[len(data)-1]^1) + "." + mac
+
+		if _, err := signer.Verify(tampered); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("got %v, want %v", err, ErrInvalidState)
+		}
+	})
+
+	t.Run("state signed with another secret", func(t *testing.T) {
+		other, _ := NewHMACSigner([]byte("another-secret-another-secret-12"), 10*time.Minute)
+		state, _ := other.Sign([]byte("payload"))
+
+		if _, err := signer.Verify(state); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("got %v, want %v", err, ErrInvalidState)
+		}
+	})
+
+	t.Run("expired state", func(t *testing.T) {
+		state, _ := signer.Sign([]byte("payload"))
+
+		expired := *signer
+		expired.now = func() time.Time { return time.Now().Add(11 * time.Minute) }
+		if _, err := expired.Verify(state); !errors.Is(err, ErrExpiredState) {
+			t.Errorf("got %v, want %v", err, ErrExpiredState)
+		}
+	})
+
+	t.Run("malformed state", func(t *testing.T) {
+		for _, state := range []string{"", "no-dot", "!!.!!", "c2hvcnQ.c2hvcnQ"} {
+			if _, err := signer.Verify(state); !errors.Is(err, ErrInvalidState) {
+				t.Errorf("%q: got %v, want %v", state, err, ErrInvalidState)
+			}
+		}
+	})
+
+	t.Run("code verifier is bound to the state", func(t *testing.T) {
+		first, _ := signer.Sign([]byte("payload"))
+		second, _ := signer.Sign([]byte("payload"))
+
+		verifier := signer.CodeVerifier(first)
+		if len(verifier) < 43 || len(verifier) > 128 {
+			t.Errorf("verifier must have between 43 and 128 characters, got %d", len(verifier))
+		}
+		if verifier != signer.CodeVerifier(first) {
+			t.Errorf("verifier isn't deterministic")
+		}
+		if verifier == signer.CodeVerifier(second) {
+			t.Errorf("different states must have different verifiers")
+		}
+	})
+}
+
+func TestNewHMACSignerShortSecret(t *testing.T) {
+	if _, err := NewHMACSigner([]byte("short"), time.Minute); !errors.Is(err, ErrShortSecret) {
+		t.Errorf("got %v, want %v", err, ErrShortSecret)
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// SHA-256 of "abc" is ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad
+	got := CodeChallenge("abc")
+	if got != "ungWv48Bz-pBQUDeXa4iI7ADYaOWF3qctBD_YfIAFa0" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+)
+
+// Sealed holds values encrypted with the same data key.
+//
+// DataKey is the data key wrapped by the key provider and KeyID identifies the
+// key-encryption key that wrapped it. Both must be stored with the values.
+type Sealed struct {
+	KeyID   string
+	DataKey string
+	Values  []string
+}
+
+// Envelope implements envelope encryption: every Seal call generates a fresh
+// data key, encrypts the values with it and wraps the data key with the
+// primary key of the KeyProvider.
+type Envelope struct {
+	provider KeyProvider
+}
+
+func NewEnvelope(provider KeyProvider) *Envelope {
+	return &Envelope{provider: provider}
+}
+
+// PrimaryKeyID returns the ID of the key currently used to wrap data keys.
+// Values sealed with any other key should be re-encrypted.
+func (e *Envelope) PrimaryKeyID() string {
+	return e.provider.PrimaryKeyID()
+}
+
+// Seal encrypts the given values with a new data key.
+// The encrypted values are returned base64 encoded, in the same order.
+func (e *Envelope) Seal(values ...string) (*Sealed, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	wrapped, keyID, err := e.provider.WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := &Sealed{
+		KeyID:   keyID,
+		DataKey: base64.StdEncoding.EncodeToString(wrapped),
+		Values:  make([]string, len(values)),
+	}
+
+	for i, v := range values {
+		ciphertext, err := seal(dataKey, []byte(v))
+		if err != nil {
+			return nil, err
+		}
+		sealed.Values[i] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	return sealed, nil
+}
+
+// Open decrypts values produced by Seal, returning them in the same order.
+func (e *Envelope) Open(s *Sealed) ([]string, error) {
+	wrapped, err := base64.StdEncoding.DecodeString(s.DataKey)
+	if err != nil {
+		return nil, ErrMalformedData
+	}
+
+	dataKey, err := e.provider.UnwrapKey(wrapped, s.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(s.Values))
+	for i, v := range s.Values {
+		ciphertext, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, ErrMalformedData
+		}
+
+		plaintext, err := open(dataKey, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = string(plaintext)
+	}
+
+	return values, nil
+}
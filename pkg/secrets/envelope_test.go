@@ -0,0 +1,160 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestNewLocalKeyProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        string
+		wantPrimary string
+		wantErr     error
+	}{
+		{
+			name:        "single key",
+			keys:        "key-1:" + newKey(t),
+			wantPrimary: "key-1",
+		},
+		{
+			name:        "comma separated keys",
+			keys:        "key-2:" + newKey(t) + ",key-1:" + newKey(t),
+			wantPrimary: "key-2",
+		},
+		{
+			name:        "one key per line with comments",
+			keys:        "# rotated on 2024-01-01\nkey-2:" + newKey(t) + "\nkey-1:" + newKey(t) + "\n",
+			wantPrimary: "key-2",
+		},
+		{
+			name:    "empty",
+			keys:    "",
+			wantErr: ErrNoKeys,
+		},
+		{
+			name:    "missing id",
+			keys:    newKey(t),
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "short key",
+			keys:    "key-1:" + base64.StdEncoding.EncodeToString([]byte("too-short")),
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "duplicated id",
+			keys:    "key-1:" + newKey(t) + ",key-1:" + newKey(t),
+			wantErr: ErrInvalidKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewLocalKeyProvider(tt.keys)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewLocalKeyProvider() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && p.PrimaryKeyID() != tt.wantPrimary {
+				t.Errorf("PrimaryKeyID() = %s, want %s", p.PrimaryKeyID(), tt.wantPrimary)
+			}
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	oldKey, newKey := newKey(t), newKey(t)
+
+	oldProvider, err := NewLocalKeyProvider("key-1:" + oldKey)
+	if err != nil {
+		t.Fatalf("unexpected error creating provider: %v", err)
+	}
+	rotatedProvider, err := NewLocalKeyProvider("key-2:" + newKey + ",key-1:" + oldKey)
+	if err != nil {
+		t.Fatalf("unexpected error creating provider: %v", err)
+	}
+
+	t.Run("seal and open", func(t *testing.T) {
+		e := NewEnvelope(oldProvider)
+		sealed, err := e.Seal("access-token", "refresh-token")
+		if err != nil {
+			t.Fatalf("unexpected error sealing: %v", err)
+		}
+		if sealed.KeyID != "key-1" {
+			t.Errorf("got key id %s, want key-1", sealed.KeyID)
+		}
+		if sealed.Values[0] == "access-token" || sealed.Values[1] == "refresh-token" {
+			t.Errorf("values were not encrypted")
+		}
+
+		values, err := e.Open(sealed)
+		if err != nil {
+			t.Fatalf("unexpected error opening: %v", err)
+		}
+		if values[0] != "access-token" || values[1] != "refresh-token" {
+			t.Errorf("got %v, want [access-token refresh-token]", values)
+		}
+	})
+
+	t.Run("every seal uses a new data key", func(t *testing.T) {
+		e := NewEnvelope(oldProvider)
+		first, _ := e.Seal("token")
+		second, _ := e.Seal("token")
+		if first.DataKey == second.DataKey || first.Values[0] == second.Values[0] {
+			t.Errorf("expected different data keys and ciphertexts")
+		}
+	})
+
+	t.Run("open values sealed before a rotation", func(t *testing.T) {
+		sealed, err := NewEnvelope(oldProvider).Seal("token")
+		if err != nil {
+			t.Fatalf("unexpected error sealing: %v", err)
+		}
+
+		e := NewEnvelope(rotatedProvider)
+		values, err := e.Open(sealed)
+		if err != nil {
+			t.Fatalf("unexpected error opening: %v", err)
+		}
+		if values[0] != "token" {
+			t.Errorf("got %s, want token", values[0])
+		}
+
+		resealed, err := e.Seal(values...)
+		if err != nil {
+			t.Fatalf("unexpected error sealing: %v", err)
+		}
+		if resealed.KeyID != e.PrimaryKeyID() {
+			t.Errorf("got key id %s, want %s", resealed.KeyID, e.PrimaryKeyID())
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		sealed, _ := NewEnvelope(rotatedProvider).Seal("token")
+		if _, err := NewEnvelope(oldProvider).Open(sealed); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("got %v, want %v", err, ErrUnknownKey)
+		}
+	})
+
+	t.Run("tampered value", func(t *testing.T) {
+		e := NewEnvelope(oldProvider)
+		sealed, _ := e.Seal("token")
+		raw, _ := base64.StdEncoding.DecodeString(sealed.Values[0])
+		raw[len(raw)-1] ^= 0xff
+		sealed.Values[0] = base64.StdEncoding.EncodeToString(raw)
+
+		if _, err := e.Open(sealed); !errors.Is(err, ErrMalformedData) {
+			t.Errorf("got %v, want %v", err, ErrMalformedData)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	ErrUnknownKey    = errors.New("unknown key id")
+	ErrInvalidKey    = errors.New("invalid key")
+	ErrNoKeys        = errors.New("no keys provided")
+	ErrMalformedData = errors.New("malformed encrypted data")
+)
+
+// KeyProvider wraps and unwraps data keys using a key-encryption key (KEK).
+//
+// The local provider keeps the KEKs in memory, but the interface is meant to be
+// implemented by a KMS as well, where the KEKs never leave the service.
+type KeyProvider interface {
+	// PrimaryKeyID returns the ID of the key used to wrap new data keys
+	PrimaryKeyID() string
+	WrapKey(dataKey []byte) (wrapped []byte, keyID string, err error)
+	UnwrapKey(wrapped []byte, keyID string) ([]byte, error)
+}
+
+// LocalKeyProvider is a KeyProvider backed by AES-256 keys loaded from a file or
+// from an environment variable.
+type LocalKeyProvider struct {
+	primary string
+	keys    map[string][]byte
+}
+
+// NewLocalKeyProvider parses a list of keys in the format
+//
+//	key-id:base64-key,another-key-id:base64-key
+//
+// Keys can also be separated by new lines. The first key is the primary one,
+// used to wrap new data keys, the others are only used to unwrap data keys that
+// were wrapped before a rotation.
+func NewLocalKeyProvider(keys string) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{keys: make(map[string][]byte)}
+
+	entries := strings.FieldsFunc(keys, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(entry, ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("%w: entry must follow the format key-id:base64-key", ErrInvalidKey)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s isn't valid base64", ErrInvalidKey, id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w: key %s must have 32 bytes", ErrInvalidKey, id)
+		}
+		if _, exists := p.keys[id]; exists {
+			return nil, fmt.Errorf("%w: key %s is duplicated", ErrInvalidKey, id)
+		}
+
+		if p.primary == "" {
+			p.primary = id
+		}
+		p.keys[id] = key
+	}
+
+	if p.primary == "" {
+		return nil, ErrNoKeys
+	}
+
+	return p, nil
+}
+
+// NewLocalKeyProviderFromFile reads the keys from a file, one key per line.
+func NewLocalKeyProviderFromFile(path string) (*LocalKeyProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalKeyProvider(string(content))
+}
+
+// PrimaryKeyID implements KeyProvider
+func (p *LocalKeyProvider) PrimaryKeyID() string {
+	return p.primary
+}
+
+// WrapKey implements KeyProvider
+func (p *LocalKeyProvider) WrapKey(dataKey []byte) ([]byte, string, error) {
+	wrapped, err := seal(p.keys[p.primary], dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return wrapped, p.primary, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *LocalKeyProvider) UnwrapKey(wrapped []byte, keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return open(key, wrapped)
+}
+
+// seal encrypts the plaintext with AES-GCM and prepends the nonce to the result
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data produced by seal
+func open(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrMalformedData
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrMalformedData
+	}
+	return plaintext, nil
+}
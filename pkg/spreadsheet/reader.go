@@ -0,0 +1,97 @@
+// Package spreadsheet reads the records of CSV and XLSX files
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format, use CSV or XLSX")
+	ErrEmptyFile         = errors.New("empty file")
+)
+
+// Read reads the records of a file, the format is given by the extension of its name.
+// CSV files may be separated by commas, semicolons or tabs; only the first sheet of XLSX files is read.
+func Read(name string, r io.Reader) ([][]string, error) {
+	var records [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		records, err = readCSV(r)
+	case ".xlsx":
+		records, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Blank lines aren't records
+	nonEmpty := records[:0]
+	for _, record := range records {
+		for _, field := range record {
+			if strings.TrimSpace(field) != "" {
+				nonEmpty = append(nonEmpty, record)
+				break
+			}
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return nonEmpty, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// Spreadsheet editors add a BOM to the UTF-8 files
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+
+	firstLine := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = delimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+// delimiter guesses the delimiter of a CSV file from its first line
+func delimiter(line []byte) rune {
+	best, count := ',', bytes.Count(line, []byte{','})
+	for _, d := range []rune{';', '\t'} {
+		if c := bytes.Count(line, []byte(string(d))); c > count {
+			best, count = d, c
+		}
+	}
+	return best
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return f.GetRows(sheets[0])
+}
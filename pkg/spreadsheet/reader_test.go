@@ -0,0 +1,75 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [][]string
+	}{
+		{
+			name:    "comma separated",
+			content: "sku,quantity\nA-1,10\n\nB-2,3\n",
+			want:    [][]string{{"sku", "quantity"}, {"A-1", "10"}, {"B-2", "3"}},
+		},
+		{
+			name:    "semicolon separated with BOM",
+			content: "\xEF\xBB\xBFsku;quantidade\r\nA-1;10\r\n",
+			want:    [][]string{{"sku", "quantidade"}, {"A-1", "10"}},
+		},
+		{
+			name:    "tab separated",
+			content: "A-1\t10\nB-2\t3",
+			want:    [][]string{{"A-1", "10"}, {"B-2", "3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read("stock.csv", strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"sku", "quantity"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"A-1", 10})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read("Stock.XLSX", &buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := [][]string{{"sku", "quantity"}, {"A-1", "10"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, want %v", got, want)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	if _, err := Read("stock.pdf", strings.NewReader("sku,quantity")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Read() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+	if _, err := Read("stock.csv", strings.NewReader("\n ,\n")); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("Read() error = %v, want %v", err, ErrEmptyFile)
+	}
+}
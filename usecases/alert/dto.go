@@ -0,0 +1,16 @@
+package alert
+
+import "github.com/Vractos/kloni/entity"
+
+type UpdateSettingsDtoInput struct {
+	Store            entity.ID `json:"-"`
+	WebhookURL       string    `json:"webhook_url" validate:"omitempty,url"`
+	Email            string    `json:"email" validate:"omitempty,email"`
+	DefaultThreshold *int      `json:"default_threshold" validate:"omitempty,min=0"`
+}
+
+type SetThresholdDtoInput struct {
+	Store     entity.ID `json:"-"`
+	Sku       string    `json:"-"`
+	Threshold int       `json:"threshold" validate:"min=0"`
+}
@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"github.com/Vractos/kloni/entity"
+)
+
+type UseCase interface {
+	// GetSettings retrieves where the alerts of a store are sent and its default threshold.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - *Settings: The settings, empty when the alerts were never configured
+	//   - error: Error if the settings can't be retrieved
+	GetSettings(storeId entity.ID) (*Settings, error)
+	// UpdateSettings changes where the alerts of a store are sent and its default threshold.
+	//
+	// Parameters:
+	//   - input: UpdateSettingsDtoInput containing the webhook URL, the email and the default threshold
+	//
+	// Returns:
+	//   - error: ErrInvalidWebhookURL, ErrInvalidEmail or ErrInvalidThreshold if the input is invalid
+	UpdateSettings(input UpdateSettingsDtoInput) error
+	// ListThresholds lists the thresholds of the SKUs of a store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []Threshold: The thresholds, ordered by the SKU
+	//   - error: Error if the thresholds can't be retrieved
+	ListThresholds(storeId entity.ID) ([]Threshold, error)
+	// SetThreshold sets the threshold of a SKU, it overrides the default threshold of the store.
+	//
+	// Parameters:
+	//   - input: SetThresholdDtoInput containing the SKU and its threshold
+	//
+	// Returns:
+	//   - error: ErrInvalidThreshold if the SKU is empty or the threshold is negative
+	SetThreshold(input SetThresholdDtoInput) error
+	// RemoveThreshold removes the threshold of a SKU, the default threshold is used again.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - sku: SKU of the threshold
+	//
+	// Returns:
+	//   - error: ErrThresholdNotFound if the SKU doesn't have a threshold
+	RemoveThreshold(storeId entity.ID, sku string) error
+	// ListOpenAlerts lists the alerts of a store that weren't resolved by a restock.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []entity.StockAlert: The open alerts, the most recent first
+	//   - error: Error if the alerts can't be retrieved
+	ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error)
+	// EvaluateStock compares the quantity of a SKU with its threshold.
+	// An alert is raised and notified when the threshold is reached, it isn't raised again
+	// until the SKU is restocked, unless it runs out of stock.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - sku: SKU that had its quantity changed
+	//   - quantity: Quantity available of the SKU
+	//
+	// Returns:
+	//   - error: Error if the alert can't be stored, notification errors are only logged
+	EvaluateStock(storeId entity.ID, sku string, quantity int) error
+	// ScanStock evaluates the stock of the SKUs with a threshold, an open alert or recent sales,
+	// of every store with alerts configured.
+	// It catches the changes that weren't made by the order synchronization.
+	//
+	// Returns:
+	//   - error: Error if the SKUs to be evaluated can't be retrieved
+	ScanStock() error
+}
+
+// Settings define where the alerts of a store are sent
+type Settings struct {
+	Store entity.ID
+	// Empty when the alerts aren't sent to a webhook
+	WebhookURL string
+	// Empty when the alerts aren't sent by email
+	Email string
+	// Threshold of the SKUs without their own, nil to only alert when a SKU is out of stock
+	DefaultThreshold *int
+}
+
+type Threshold struct {
+	Sku       string
+	Threshold int
+}
+
+// ScanTarget is a store and the SKUs to be evaluated by the periodic scan
+type ScanTarget struct {
+	Store entity.ID
+	Skus  []string
+}
+
+/*
+#########################################
+#########################################
+----------------NOTIFIER-----------------
+#########################################
+#########################################
+*/
+
+// Notifier sends the stock alerts of a store, the target is the address
+// of the channel, e.g. the URL of a webhook or an email
+type Notifier interface {
+	Notify(target string, alert entity.StockAlert) error
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	SaveSettings(settings *Settings) error
+	SaveThreshold(storeId entity.ID, threshold Threshold) error
+	// Returns ErrThresholdNotFound if the SKU doesn't have a threshold
+	DeleteThreshold(storeId entity.ID, sku string) error
+	// Returns ErrAlertAlreadyOpen if the SKU already has an open alert
+	RegisterAlert(alert *entity.StockAlert) error
+	// Updates the level and the quantity of an open alert
+	UpdateAlert(alert *entity.StockAlert) error
+	ResolveAlert(alert *entity.StockAlert) error
+}
+
+type RepoReader interface {
+	// Nil when the alerts of the store were never configured
+	GetSettings(storeId entity.ID) (*Settings, error)
+	ListThresholds(storeId entity.ID) ([]Threshold, error)
+	// Nil when the SKU doesn't have its own threshold
+	GetThreshold(storeId entity.ID, sku string) (*int, error)
+	// Nil when the SKU doesn't have an open alert
+	GetOpenAlert(storeId entity.ID, sku string) (*entity.StockAlert, error)
+	ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error)
+	ScanTargets() ([]ScanTarget, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
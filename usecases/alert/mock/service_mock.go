@@ -0,0 +1,610 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/alert/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/alert/interface.go -destination=usecases/alert/mock/service_mock.go
+//
+
+// Package mock_alert is a generated GoMock package.
+package mock_alert
+
+import (
+	reflect "reflect"
+
+	entity "github.com/Vractos/kloni/entity"
+	alert "github.com/Vractos/kloni/usecases/alert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// EvaluateStock mocks base method.
+func (m *MockUseCase) EvaluateStock(storeId entity.ID, sku string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateStock", storeId, sku, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvaluateStock indicates an expected call of EvaluateStock.
+func (mr *MockUseCaseMockRecorder) EvaluateStock(storeId, sku, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateStock", reflect.TypeOf((*MockUseCase)(nil).EvaluateStock), storeId, sku, quantity)
+}
+
+// GetSettings mocks base method.
+func (m *MockUseCase) GetSettings(storeId entity.ID) (*alert.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", storeId)
+	ret0, _ := ret[0].(*alert.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockUseCaseMockRecorder) GetSettings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockUseCase)(nil).GetSettings), storeId)
+}
+
+// ListOpenAlerts mocks base method.
+func (m *MockUseCase) ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenAlerts", storeId)
+	ret0, _ := ret[0].([]entity.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenAlerts indicates an expected call of ListOpenAlerts.
+func (mr *MockUseCaseMockRecorder) ListOpenAlerts(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenAlerts", reflect.TypeOf((*MockUseCase)(nil).ListOpenAlerts), storeId)
+}
+
+// ListThresholds mocks base method.
+func (m *MockUseCase) ListThresholds(storeId entity.ID) ([]alert.Threshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThresholds", storeId)
+	ret0, _ := ret[0].([]alert.Threshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThresholds indicates an expected call of ListThresholds.
+func (mr *MockUseCaseMockRecorder) ListThresholds(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThresholds", reflect.TypeOf((*MockUseCase)(nil).ListThresholds), storeId)
+}
+
+// RemoveThreshold mocks base method.
+func (m *MockUseCase) RemoveThreshold(storeId entity.ID, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveThreshold", storeId, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveThreshold indicates an expected call of RemoveThreshold.
+func (mr *MockUseCaseMockRecorder) RemoveThreshold(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveThreshold", reflect.TypeOf((*MockUseCase)(nil).RemoveThreshold), storeId, sku)
+}
+
+// ScanStock mocks base method.
+func (m *MockUseCase) ScanStock() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanStock")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScanStock indicates an expected call of ScanStock.
+func (mr *MockUseCaseMockRecorder) ScanStock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanStock", reflect.TypeOf((*MockUseCase)(nil).ScanStock))
+}
+
+// SetThreshold mocks base method.
+func (m *MockUseCase) SetThreshold(input alert.SetThresholdDtoInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetThreshold", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetThreshold indicates an expected call of SetThreshold.
+func (mr *MockUseCaseMockRecorder) SetThreshold(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetThreshold", reflect.TypeOf((*MockUseCase)(nil).SetThreshold), input)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockUseCase) UpdateSettings(input alert.UpdateSettingsDtoInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockUseCaseMockRecorder) UpdateSettings(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockUseCase)(nil).UpdateSettings), input)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(target string, alert entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", target, alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(target, alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), target, alert)
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// DeleteThreshold mocks base method.
+func (m *MockRepoWriter) DeleteThreshold(storeId entity.ID, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteThreshold", storeId, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteThreshold indicates an expected call of DeleteThreshold.
+func (mr *MockRepoWriterMockRecorder) DeleteThreshold(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteThreshold", reflect.TypeOf((*MockRepoWriter)(nil).DeleteThreshold), storeId, sku)
+}
+
+// RegisterAlert mocks base method.
+func (m *MockRepoWriter) RegisterAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterAlert indicates an expected call of RegisterAlert.
+func (mr *MockRepoWriterMockRecorder) RegisterAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAlert", reflect.TypeOf((*MockRepoWriter)(nil).RegisterAlert), alert)
+}
+
+// ResolveAlert mocks base method.
+func (m *MockRepoWriter) ResolveAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveAlert indicates an expected call of ResolveAlert.
+func (mr *MockRepoWriterMockRecorder) ResolveAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAlert", reflect.TypeOf((*MockRepoWriter)(nil).ResolveAlert), alert)
+}
+
+// SaveSettings mocks base method.
+func (m *MockRepoWriter) SaveSettings(settings *alert.Settings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockRepoWriterMockRecorder) SaveSettings(settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockRepoWriter)(nil).SaveSettings), settings)
+}
+
+// SaveThreshold mocks base method.
+func (m *MockRepoWriter) SaveThreshold(storeId entity.ID, threshold alert.Threshold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveThreshold", storeId, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveThreshold indicates an expected call of SaveThreshold.
+func (mr *MockRepoWriterMockRecorder) SaveThreshold(storeId, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveThreshold", reflect.TypeOf((*MockRepoWriter)(nil).SaveThreshold), storeId, threshold)
+}
+
+// UpdateAlert mocks base method.
+func (m *MockRepoWriter) UpdateAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlert indicates an expected call of UpdateAlert.
+func (mr *MockRepoWriterMockRecorder) UpdateAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlert", reflect.TypeOf((*MockRepoWriter)(nil).UpdateAlert), alert)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// GetOpenAlert mocks base method.
+func (m *MockRepoReader) GetOpenAlert(storeId entity.ID, sku string) (*entity.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenAlert", storeId, sku)
+	ret0, _ := ret[0].(*entity.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenAlert indicates an expected call of GetOpenAlert.
+func (mr *MockRepoReaderMockRecorder) GetOpenAlert(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenAlert", reflect.TypeOf((*MockRepoReader)(nil).GetOpenAlert), storeId, sku)
+}
+
+// GetSettings mocks base method.
+func (m *MockRepoReader) GetSettings(storeId entity.ID) (*alert.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", storeId)
+	ret0, _ := ret[0].(*alert.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockRepoReaderMockRecorder) GetSettings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockRepoReader)(nil).GetSettings), storeId)
+}
+
+// GetThreshold mocks base method.
+func (m *MockRepoReader) GetThreshold(storeId entity.ID, sku string) (*int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThreshold", storeId, sku)
+	ret0, _ := ret[0].(*int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThreshold indicates an expected call of GetThreshold.
+func (mr *MockRepoReaderMockRecorder) GetThreshold(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThreshold", reflect.TypeOf((*MockRepoReader)(nil).GetThreshold), storeId, sku)
+}
+
+// ListOpenAlerts mocks base method.
+func (m *MockRepoReader) ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenAlerts", storeId)
+	ret0, _ := ret[0].([]entity.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenAlerts indicates an expected call of ListOpenAlerts.
+func (mr *MockRepoReaderMockRecorder) ListOpenAlerts(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenAlerts", reflect.TypeOf((*MockRepoReader)(nil).ListOpenAlerts), storeId)
+}
+
+// ListThresholds mocks base method.
+func (m *MockRepoReader) ListThresholds(storeId entity.ID) ([]alert.Threshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThresholds", storeId)
+	ret0, _ := ret[0].([]alert.Threshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThresholds indicates an expected call of ListThresholds.
+func (mr *MockRepoReaderMockRecorder) ListThresholds(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThresholds", reflect.TypeOf((*MockRepoReader)(nil).ListThresholds), storeId)
+}
+
+// ScanTargets mocks base method.
+func (m *MockRepoReader) ScanTargets() ([]alert.ScanTarget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanTargets")
+	ret0, _ := ret[0].([]alert.ScanTarget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanTargets indicates an expected call of ScanTargets.
+func (mr *MockRepoReaderMockRecorder) ScanTargets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanTargets", reflect.TypeOf((*MockRepoReader)(nil).ScanTargets))
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteThreshold mocks base method.
+func (m *MockRepository) DeleteThreshold(storeId entity.ID, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteThreshold", storeId, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteThreshold indicates an expected call of DeleteThreshold.
+func (mr *MockRepositoryMockRecorder) DeleteThreshold(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteThreshold", reflect.TypeOf((*MockRepository)(nil).DeleteThreshold), storeId, sku)
+}
+
+// GetOpenAlert mocks base method.
+func (m *MockRepository) GetOpenAlert(storeId entity.ID, sku string) (*entity.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenAlert", storeId, sku)
+	ret0, _ := ret[0].(*entity.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenAlert indicates an expected call of GetOpenAlert.
+func (mr *MockRepositoryMockRecorder) GetOpenAlert(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenAlert", reflect.TypeOf((*MockRepository)(nil).GetOpenAlert), storeId, sku)
+}
+
+// GetSettings mocks base method.
+func (m *MockRepository) GetSettings(storeId entity.ID) (*alert.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", storeId)
+	ret0, _ := ret[0].(*alert.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockRepositoryMockRecorder) GetSettings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockRepository)(nil).GetSettings), storeId)
+}
+
+// GetThreshold mocks base method.
+func (m *MockRepository) GetThreshold(storeId entity.ID, sku string) (*int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThreshold", storeId, sku)
+	ret0, _ := ret[0].(*int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThreshold indicates an expected call of GetThreshold.
+func (mr *MockRepositoryMockRecorder) GetThreshold(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThreshold", reflect.TypeOf((*MockRepository)(nil).GetThreshold), storeId, sku)
+}
+
+// ListOpenAlerts mocks base method.
+func (m *MockRepository) ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenAlerts", storeId)
+	ret0, _ := ret[0].([]entity.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenAlerts indicates an expected call of ListOpenAlerts.
+func (mr *MockRepositoryMockRecorder) ListOpenAlerts(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenAlerts", reflect.TypeOf((*MockRepository)(nil).ListOpenAlerts), storeId)
+}
+
+// ListThresholds mocks base method.
+func (m *MockRepository) ListThresholds(storeId entity.ID) ([]alert.Threshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThresholds", storeId)
+	ret0, _ := ret[0].([]alert.Threshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThresholds indicates an expected call of ListThresholds.
+func (mr *MockRepositoryMockRecorder) ListThresholds(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThresholds", reflect.TypeOf((*MockRepository)(nil).ListThresholds), storeId)
+}
+
+// RegisterAlert mocks base method.
+func (m *MockRepository) RegisterAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterAlert indicates an expected call of RegisterAlert.
+func (mr *MockRepositoryMockRecorder) RegisterAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAlert", reflect.TypeOf((*MockRepository)(nil).RegisterAlert), alert)
+}
+
+// ResolveAlert mocks base method.
+func (m *MockRepository) ResolveAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveAlert indicates an expected call of ResolveAlert.
+func (mr *MockRepositoryMockRecorder) ResolveAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAlert", reflect.TypeOf((*MockRepository)(nil).ResolveAlert), alert)
+}
+
+// SaveSettings mocks base method.
+func (m *MockRepository) SaveSettings(settings *alert.Settings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockRepositoryMockRecorder) SaveSettings(settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockRepository)(nil).SaveSettings), settings)
+}
+
+// SaveThreshold mocks base method.
+func (m *MockRepository) SaveThreshold(storeId entity.ID, threshold alert.Threshold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveThreshold", storeId, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveThreshold indicates an expected call of SaveThreshold.
+func (mr *MockRepositoryMockRecorder) SaveThreshold(storeId, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveThreshold", reflect.TypeOf((*MockRepository)(nil).SaveThreshold), storeId, threshold)
+}
+
+// ScanTargets mocks base method.
+func (m *MockRepository) ScanTargets() ([]alert.ScanTarget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanTargets")
+	ret0, _ := ret[0].([]alert.ScanTarget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanTargets indicates an expected call of ScanTargets.
+func (mr *MockRepositoryMockRecorder) ScanTargets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanTargets", reflect.TypeOf((*MockRepository)(nil).ScanTargets))
+}
+
+// UpdateAlert mocks base method.
+func (m *MockRepository) UpdateAlert(alert *entity.StockAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlert indicates an expected call of UpdateAlert.
+func (mr *MockRepositoryMockRecorder) UpdateAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlert", reflect.TypeOf((*MockRepository)(nil).UpdateAlert), alert)
+}
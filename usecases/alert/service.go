@@ -0,0 +1,383 @@
+package alert
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/store"
+	"go.uber.org/zap"
+)
+
+// Error definitions for alert operations
+var (
+	// ErrInvalidWebhookURL is returned when the webhook URL isn't an absolute https URL resolving to public addresses
+	ErrInvalidWebhookURL = errors.New("invalid webhook url")
+	// ErrInvalidEmail is returned when the email of the alerts is malformed
+	ErrInvalidEmail = errors.New("invalid email")
+	// ErrInvalidThreshold is returned when a threshold is negative or its SKU is empty
+	ErrInvalidThreshold = errors.New("invalid threshold")
+	// ErrThresholdNotFound is returned when the SKU doesn't have a threshold
+	ErrThresholdNotFound = errors.New("threshold not found")
+	// ErrAlertAlreadyOpen is returned when the SKU already has an open alert
+	ErrAlertAlreadyOpen = errors.New("alert already open")
+)
+
+type AlertService struct {
+	repo     Repository
+	store    store.UseCase
+	announce announcement.UseCase
+	webhook  Notifier
+	email    Notifier
+	guard    common.URLGuard
+	logger   common.Logger
+}
+
+// NewAlertService creates a new instance of AlertService.
+//
+// Parameters:
+//   - repository: Repository of the settings, thresholds and alerts
+//   - storeUseCase: Store use case, used to retrieve the credentials of the scanned stores
+//   - announceUseCase: Announcement use case, used to retrieve the quantities of the scanned SKUs
+//   - webhook: Notifier that sends the alerts to webhooks
+//   - email: Notifier that sends the alerts by email, nil when SMTP isn't configured
+//   - guard: Guard of the webhook URLs
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *AlertService: A new instance of AlertService
+func NewAlertService(
+	repository Repository,
+	storeUseCase store.UseCase,
+	announceUseCase announcement.UseCase,
+	webhook Notifier,
+	email Notifier,
+	guard common.URLGuard,
+	logger common.Logger,
+) *AlertService {
+	return &AlertService{
+		repo:     repository,
+		store:    storeUseCase,
+		announce: announceUseCase,
+		webhook:  webhook,
+		email:    email,
+		guard:    guard,
+		logger:   logger,
+	}
+}
+
+// GetSettings retrieves where the alerts of a store are sent and its default threshold.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - *Settings: The settings, empty when the alerts were never configured
+//   - error: Error if the settings can't be retrieved
+func (a *AlertService) GetSettings(storeId entity.ID) (*Settings, error) {
+	settings, err := a.repo.GetSettings(storeId)
+	if err != nil {
+		a.logger.Error("Fail to retrieve the alert settings", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	if settings == nil {
+		return &Settings{Store: storeId}, nil
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes where the alerts of a store are sent and its default threshold.
+//
+// Parameters:
+//   - input: UpdateSettingsDtoInput containing the webhook URL, the email and the default threshold
+//
+// Returns:
+//   - error: ErrInvalidWebhookURL, ErrInvalidEmail or ErrInvalidThreshold if the input is invalid
+func (a *AlertService) UpdateSettings(input UpdateSettingsDtoInput) error {
+	settings := &Settings{
+		Store:            input.Store,
+		WebhookURL:       strings.TrimSpace(input.WebhookURL),
+		Email:            strings.TrimSpace(input.Email),
+		DefaultThreshold: input.DefaultThreshold,
+	}
+
+	if settings.WebhookURL != "" {
+		if err := a.guard.Check(settings.WebhookURL); err != nil {
+			a.logger.Warn("The webhook URL of the alerts was refused", zap.String("store_id", input.Store.String()), zap.Error(err))
+			return ErrInvalidWebhookURL
+		}
+	}
+	if settings.Email != "" {
+		if _, err := mail.ParseAddress(settings.Email); err != nil {
+			return ErrInvalidEmail
+		}
+	}
+	if settings.DefaultThreshold != nil && *settings.DefaultThreshold < 0 {
+		return ErrInvalidThreshold
+	}
+
+	if err := a.repo.SaveSettings(settings); err != nil {
+		a.logger.Error("Fail to save the alert settings", err, zap.String("store_id", input.Store.String()))
+		return err
+	}
+	return nil
+}
+
+// ListThresholds lists the thresholds of the SKUs of a store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []Threshold: The thresholds, ordered by the SKU
+//   - error: Error if the thresholds can't be retrieved
+func (a *AlertService) ListThresholds(storeId entity.ID) ([]Threshold, error) {
+	thresholds, err := a.repo.ListThresholds(storeId)
+	if err != nil {
+		a.logger.Error("Fail to list the thresholds", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+// SetThreshold sets the threshold of a SKU, it overrides the default threshold of the store.
+//
+// Parameters:
+//   - input: SetThresholdDtoInput containing the SKU and its threshold
+//
+// Returns:
+//   - error: ErrInvalidThreshold if the SKU is empty or the threshold is negative
+func (a *AlertService) SetThreshold(input SetThresholdDtoInput) error {
+	sku := strings.TrimSpace(input.Sku)
+	if sku == "" || input.Threshold < 0 {
+		return ErrInvalidThreshold
+	}
+
+	if err := a.repo.SaveThreshold(input.Store, Threshold{Sku: sku, Threshold: input.Threshold}); err != nil {
+		a.logger.Error("Fail to save the threshold", err, zap.String("store_id", input.Store.String()), zap.String("sku", sku))
+		return err
+	}
+	return nil
+}
+
+// RemoveThreshold removes the threshold of a SKU, the default threshold is used again.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - sku: SKU of the threshold
+//
+// Returns:
+//   - error: ErrThresholdNotFound if the SKU doesn't have a threshold
+func (a *AlertService) RemoveThreshold(storeId entity.ID, sku string) error {
+	if err := a.repo.DeleteThreshold(storeId, sku); err != nil {
+		if !errors.Is(err, ErrThresholdNotFound) {
+			a.logger.Error("Fail to remove the threshold", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+		}
+		return err
+	}
+	return nil
+}
+
+// ListOpenAlerts lists the alerts of a store that weren't resolved by a restock.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []entity.StockAlert: The open alerts, the most recent first
+//   - error: Error if the alerts can't be retrieved
+func (a *AlertService) ListOpenAlerts(storeId entity.ID) ([]entity.StockAlert, error) {
+	alerts, err := a.repo.ListOpenAlerts(storeId)
+	if err != nil {
+		a.logger.Error("Fail to list the alerts", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// EvaluateStock compares the quantity of a SKU with its threshold.
+// An alert is raised and notified when the threshold is reached, it isn't raised again
+// until the SKU is restocked, unless it runs out of stock.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - sku: SKU that had its quantity changed
+//   - quantity: Quantity available of the SKU
+//
+// Returns:
+//   - error: Error if the alert can't be stored, notification errors are only logged
+func (a *AlertService) EvaluateStock(storeId entity.ID, sku string, quantity int) error {
+	settings, err := a.repo.GetSettings(storeId)
+	if err != nil {
+		a.logger.Error("Fail to retrieve the alert settings", err, zap.String("store_id", storeId.String()))
+		return err
+	}
+	// The alerts of the store aren't configured
+	if settings == nil {
+		return nil
+	}
+
+	threshold, err := a.threshold(settings, sku)
+	if err != nil {
+		return err
+	}
+
+	open, err := a.repo.GetOpenAlert(storeId, sku)
+	if err != nil {
+		a.logger.Error("Fail to retrieve the open alert", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+		return err
+	}
+
+	level := entity.EvaluateStock(quantity, threshold)
+	switch {
+	case level == entity.InStock:
+		if open == nil {
+			return nil
+		}
+		now := time.Now().UTC()
+		open.ResolvedAt = &now
+		if err := a.repo.ResolveAlert(open); err != nil {
+			a.logger.Error("Fail to resolve the alert", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+			return err
+		}
+		return nil
+	case open == nil:
+		alert := entity.NewStockAlert(storeId, sku, level, quantity, threshold)
+		if err := a.repo.RegisterAlert(alert); err != nil {
+			// Raised by a concurrent evaluation, which also notified it
+			if errors.Is(err, ErrAlertAlreadyOpen) {
+				return nil
+			}
+			a.logger.Error("Fail to register the alert", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+			return err
+		}
+		a.notify(settings, alert)
+	case open.Escalates(level):
+		open.Level = level
+		open.Quantity = quantity
+		if err := a.repo.UpdateAlert(open); err != nil {
+			a.logger.Error("Fail to update the alert", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+			return err
+		}
+		a.notify(settings, open)
+	}
+	return nil
+}
+
+// ScanStock evaluates the stock of the SKUs with a threshold, an open alert or recent sales,
+// of every store with alerts configured.
+//
+// Returns:
+//   - error: Error if the SKUs to be evaluated can't be retrieved
+func (a *AlertService) ScanStock() error {
+	targets, err := a.repo.ScanTargets()
+	if err != nil {
+		a.logger.Error("Fail to retrieve the SKUs to be scanned", err)
+		return err
+	}
+
+	for _, target := range targets {
+		credentials, err := a.store.RetrieveMeliCredentialsFromStoreID(target.Store)
+		if err != nil {
+			a.logger.Error("Fail to retrieve the credentials of the scanned store", err, zap.String("store_id", target.Store.String()))
+			continue
+		}
+
+		for _, sku := range target.Skus {
+			announcements, err := a.announce.RetrieveAnnouncementsFromAllAccounts(sku, credentials)
+			if err != nil {
+				a.logger.Warn("Fail to retrieve the announcements of the scanned SKU",
+					zap.String("store_id", target.Store.String()),
+					zap.String("sku", sku),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			// Errors were already logged, the next SKUs are still evaluated
+			a.EvaluateStock(target.Store, sku, AvailableQuantity(announcements, sku))
+		}
+	}
+	return nil
+}
+
+// AvailableQuantity is the quantity of a SKU in the account with the most units.
+// The clones of a SKU share its stock, so the account with the most units
+// is the one that wasn't synchronized after the last sale.
+//
+// Parameters:
+//   - announcements: The announcements of the SKU on each account
+//   - sku: The SKU, only its variations are summed
+//
+// Returns:
+//   - int: The quantity available, the sum of the variations of the SKU for announcements with variations
+func AvailableQuantity(announcements *[]announcement.Announcements, sku string) int {
+	quantity := 0
+	for _, account := range *announcements {
+		if account.Announcements == nil {
+			continue
+		}
+		for _, ann := range *account.Announcements {
+			annQuantity := ann.Quantity
+			if ann.Variations != nil {
+				annQuantity = 0
+				for _, variation := range ann.Variations {
+					if account.HasSku(variation, sku) {
+						annQuantity += variation.AvailableQuantity
+					}
+				}
+			}
+			if annQuantity > quantity {
+				quantity = annQuantity
+			}
+		}
+	}
+	return quantity
+}
+
+// threshold resolves the threshold of a SKU, its own or the default of the store.
+//
+// Parameters:
+//   - settings: Alert settings of the store
+//   - sku: SKU of the threshold
+//
+// Returns:
+//   - int: The threshold, zero to only alert when the SKU is out of stock
+//   - error: Error if the threshold can't be retrieved
+func (a *AlertService) threshold(settings *Settings, sku string) (int, error) {
+	threshold, err := a.repo.GetThreshold(settings.Store, sku)
+	if err != nil {
+		a.logger.Error("Fail to retrieve the threshold", err, zap.String("store_id", settings.Store.String()), zap.String("sku", sku))
+		return 0, err
+	}
+	if threshold != nil {
+		return *threshold, nil
+	}
+	if settings.DefaultThreshold != nil {
+		return *settings.DefaultThreshold, nil
+	}
+	return 0, nil
+}
+
+// notify sends an alert to the channels configured by the store.
+// A failure on a channel doesn't stop the others.
+//
+// Parameters:
+//   - settings: Alert settings of the store
+//   - alert: The alert to be sent
+func (a *AlertService) notify(settings *Settings, alert *entity.StockAlert) {
+	if settings.WebhookURL != "" && a.webhook != nil {
+		if err := a.webhook.Notify(settings.WebhookURL, *alert); err != nil {
+			a.logger.Error("Fail to send the alert to the webhook", err, zap.String("store_id", alert.StoreID.String()), zap.String("sku", alert.Sku))
+		}
+	}
+	if settings.Email != "" && a.email != nil {
+		if err := a.email.Notify(settings.Email, *alert); err != nil {
+			a.logger.Error("Fail to send the alert by email", err, zap.String("store_id", alert.StoreID.String()), zap.String("sku", alert.Sku))
+		}
+	}
+}
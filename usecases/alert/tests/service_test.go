@@ -0,0 +1,383 @@
+package alert
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/alert"
+	mock_alert "github.com/Vractos/kloni/usecases/alert/mock"
+	"github.com/Vractos/kloni/usecases/announcement"
+	mock_announcement "github.com/Vractos/kloni/usecases/announcement/mock"
+	common "github.com/Vractos/kloni/usecases/common"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/store"
+	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type Mocks struct {
+	repo     *mock_alert.MockRepository
+	store    *mock_store.MockUseCase
+	announce *mock_announcement.MockUseCase
+	webhook  *mock_alert.MockNotifier
+	email    *mock_alert.MockNotifier
+	guard    *common_mock.MockURLGuard
+	logger   *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:     mock_alert.NewMockRepository(ctrl),
+		store:    mock_store.NewMockUseCase(ctrl),
+		announce: mock_announcement.NewMockUseCase(ctrl),
+		webhook:  mock_alert.NewMockNotifier(ctrl),
+		email:    mock_alert.NewMockNotifier(ctrl),
+		guard:    common_mock.NewMockURLGuard(ctrl),
+		logger:   common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newAlertService() *alert.AlertService {
+	return alert.NewAlertService(m.repo, m.store, m.announce, m.webhook, m.email, m.guard, m.logger)
+}
+
+// alertMatcher matches the stock alerts by their SKU, level and quantity
+type alertMatcher struct {
+	sku      string
+	level    entity.StockLevel
+	quantity int
+}
+
+func (a alertMatcher) Matches(x interface{}) bool {
+	var alert entity.StockAlert
+	switch v := x.(type) {
+	case entity.StockAlert:
+		alert = v
+	case *entity.StockAlert:
+		alert = *v
+	default:
+		return false
+	}
+	return alert.Sku == a.sku && alert.Level == a.level && alert.Quantity == a.quantity
+}
+
+func (a alertMatcher) String() string {
+	return "alert of " + a.sku + " " + string(a.level)
+}
+
+func TestEvaluateStock(t *testing.T) {
+	storeId := entity.NewID()
+	defaultThreshold := 5
+	settings := &alert.Settings{
+		Store:            storeId,
+		WebhookURL:       "https://example.com/hook",
+		Email:            "owner@example.com",
+		DefaultThreshold: &defaultThreshold,
+	}
+
+	tests := []struct {
+		name     string
+		quantity int
+		mocks    func(m *Mocks)
+	}{
+		{
+			name:     "alerts aren't configured",
+			quantity: 0,
+			mocks: func(m *Mocks) {
+				m.repo.EXPECT().GetSettings(storeId).Return(nil, nil)
+			},
+		},
+		{
+			name:     "low stock with the default threshold",
+			quantity: 3,
+			mocks: func(m *Mocks) {
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().RegisterAlert(alertMatcher{"SKU-1", entity.LowStock, 3}).Return(nil)
+				m.webhook.EXPECT().Notify(settings.WebhookURL, alertMatcher{"SKU-1", entity.LowStock, 3}).Return(nil)
+				m.email.EXPECT().Notify(settings.Email, alertMatcher{"SKU-1", entity.LowStock, 3}).Return(nil)
+			},
+		},
+		{
+			name:     "SKU threshold overrides the default",
+			quantity: 3,
+			mocks: func(m *Mocks) {
+				threshold := 2
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(&threshold, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(nil, nil)
+			},
+		},
+		{
+			name:     "open alert isn't raised again",
+			quantity: 2,
+			mocks: func(m *Mocks) {
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(entity.NewStockAlert(storeId, "SKU-1", entity.LowStock, 3, 5), nil)
+			},
+		},
+		{
+			name:     "low stock escalates to out of stock",
+			quantity: 0,
+			mocks: func(m *Mocks) {
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(entity.NewStockAlert(storeId, "SKU-1", entity.LowStock, 3, 5), nil)
+				m.repo.EXPECT().UpdateAlert(alertMatcher{"SKU-1", entity.OutOfStock, 0}).Return(nil)
+				m.webhook.EXPECT().Notify(settings.WebhookURL, alertMatcher{"SKU-1", entity.OutOfStock, 0}).Return(nil)
+				m.email.EXPECT().Notify(settings.Email, alertMatcher{"SKU-1", entity.OutOfStock, 0}).Return(nil)
+			},
+		},
+		{
+			name:     "restock resolves the alert",
+			quantity: 10,
+			mocks: func(m *Mocks) {
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(entity.NewStockAlert(storeId, "SKU-1", entity.OutOfStock, 0, 5), nil)
+				m.repo.EXPECT().ResolveAlert(gomock.Any()).DoAndReturn(func(a *entity.StockAlert) error {
+					if a.ResolvedAt == nil {
+						t.Errorf("the resolved alert doesn't have ResolvedAt")
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:     "a failing channel doesn't stop the others",
+			quantity: 0,
+			mocks: func(m *Mocks) {
+				notifyErr := errors.New("webhook down")
+				m.repo.EXPECT().GetSettings(storeId).Return(settings, nil)
+				m.repo.EXPECT().GetThreshold(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().GetOpenAlert(storeId, "SKU-1").Return(nil, nil)
+				m.repo.EXPECT().RegisterAlert(gomock.Any()).Return(nil)
+				m.webhook.EXPECT().Notify(settings.WebhookURL, gomock.Any()).Return(notifyErr)
+				m.logger.EXPECT().Error("Fail to send the alert to the webhook", notifyErr, zap.String("store_id", storeId.String()), zap.String("sku", "SKU-1"))
+				m.email.EXPECT().Notify(settings.Email, gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mocks := newMocks(ctrl)
+			service := mocks.newAlertService()
+
+			tt.mocks(mocks)
+
+			if err := service.EvaluateStock(storeId, "SKU-1", tt.quantity); err != nil {
+				t.Errorf("EvaluateStock() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	storeId := entity.NewID()
+	negative := -1
+	refused := errors.New("the url must resolve to a public address")
+
+	tests := []struct {
+		name     string
+		input    alert.UpdateSettingsDtoInput
+		guardErr error
+		wantErr  error
+	}{
+		{
+			name:  "valid settings",
+			input: alert.UpdateSettingsDtoInput{Store: storeId, WebhookURL: "https://example.com/hook", Email: "owner@example.com"},
+		},
+		{
+			name:     "relative webhook URL",
+			input:    alert.UpdateSettingsDtoInput{Store: storeId, WebhookURL: "/hook"},
+			guardErr: refused,
+			wantErr:  alert.ErrInvalidWebhookURL,
+		},
+		{
+			name:     "webhook URL with another scheme",
+			input:    alert.UpdateSettingsDtoInput{Store: storeId, WebhookURL: "http://example.com/hook"},
+			guardErr: refused,
+			wantErr:  alert.ErrInvalidWebhookURL,
+		},
+		{
+			name:     "webhook URL of the internal network",
+			input:    alert.UpdateSettingsDtoInput{Store: storeId, WebhookURL: "https://10.0.0.5/hook"},
+			guardErr: refused,
+			wantErr:  alert.ErrInvalidWebhookURL,
+		},
+		{
+			name:    "malformed email",
+			input:   alert.UpdateSettingsDtoInput{Store: storeId, Email: "owner"},
+			wantErr: alert.ErrInvalidEmail,
+		},
+		{
+			name:    "negative default threshold",
+			input:   alert.UpdateSettingsDtoInput{Store: storeId, DefaultThreshold: &negative},
+			wantErr: alert.ErrInvalidThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mocks := newMocks(ctrl)
+			service := mocks.newAlertService()
+
+			if tt.input.WebhookURL != "" {
+				mocks.guard.EXPECT().Check(tt.input.WebhookURL).Return(tt.guardErr)
+			}
+			if tt.guardErr != nil {
+				mocks.logger.EXPECT().Warn("The webhook URL of the alerts was refused", zap.String("store_id", storeId.String()), zap.Error(tt.guardErr))
+			}
+			if tt.wantErr == nil {
+				mocks.repo.EXPECT().SaveSettings(&alert.Settings{
+					Store:      storeId,
+					WebhookURL: tt.input.WebhookURL,
+					Email:      tt.input.Email,
+				}).Return(nil)
+			}
+
+			if err := service.UpdateSettings(tt.input); !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateSettings() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	storeId := entity.NewID()
+
+	t.Run("set threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		service := mocks.newAlertService()
+
+		mocks.repo.EXPECT().SaveThreshold(storeId, alert.Threshold{Sku: "SKU-1", Threshold: 3}).Return(nil)
+
+		if err := service.SetThreshold(alert.SetThresholdDtoInput{Store: storeId, Sku: " SKU-1 ", Threshold: 3}); err != nil {
+			t.Errorf("SetThreshold() error = %v", err)
+		}
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := newMocks(ctrl).newAlertService()
+
+		for _, input := range []alert.SetThresholdDtoInput{
+			{Store: storeId, Sku: "", Threshold: 3},
+			{Store: storeId, Sku: "SKU-1", Threshold: -1},
+		} {
+			if err := service.SetThreshold(input); !errors.Is(err, alert.ErrInvalidThreshold) {
+				t.Errorf("SetThreshold(%+v) error = %v, want %v", input, err, alert.ErrInvalidThreshold)
+			}
+		}
+	})
+
+	t.Run("remove missing threshold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		service := mocks.newAlertService()
+
+		mocks.repo.EXPECT().DeleteThreshold(storeId, "SKU-1").Return(alert.ErrThresholdNotFound)
+
+		if err := service.RemoveThreshold(storeId, "SKU-1"); !errors.Is(err, alert.ErrThresholdNotFound) {
+			t.Errorf("RemoveThreshold() error = %v, want %v", err, alert.ErrThresholdNotFound)
+		}
+	})
+}
+
+func TestScanStock(t *testing.T) {
+	storeId := entity.NewID()
+	credentials := &[]store.Credentials{{ID: entity.NewID(), OwnerID: storeId, MeliCredential: &common.MeliCredential{}}}
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	service := mocks.newAlertService()
+
+	mocks.repo.EXPECT().ScanTargets().Return([]alert.ScanTarget{{Store: storeId, Skus: []string{"SKU-1", "SKU-2"}}}, nil)
+	mocks.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+
+	mocks.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("SKU-1", credentials).Return(&[]announcement.Announcements{
+		{Announcements: &[]common.MeliAnnouncement{{ID: "1", Sku: "SKU-1", Quantity: 20}}},
+	}, nil)
+	// The alerts of the store aren't configured anymore, so nothing else is done
+	mocks.repo.EXPECT().GetSettings(storeId).Return(nil, nil)
+
+	lookupErr := errors.New("meli down")
+	mocks.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("SKU-2", credentials).Return(nil, lookupErr)
+	mocks.logger.EXPECT().Warn("Fail to retrieve the announcements of the scanned SKU",
+		zap.String("store_id", storeId.String()),
+		zap.String("sku", "SKU-2"),
+		zap.Error(lookupErr),
+	)
+
+	if err := service.ScanStock(); err != nil {
+		t.Errorf("ScanStock() error = %v", err)
+	}
+}
+
+func TestAvailableQuantity(t *testing.T) {
+	tests := []struct {
+		name          string
+		announcements *[]announcement.Announcements
+		want          int
+	}{
+		{
+			name: "listing with the most units",
+			announcements: &[]announcement.Announcements{
+				{
+					Announcements: &[]common.MeliAnnouncement{
+						{ID: "1", Quantity: 4},
+						{ID: "2", Variations: []common.MeliVariation{{ID: 1, AvailableQuantity: 3, Sku: "SKU-1"}, {ID: 2, AvailableQuantity: 3, Sku: "SKU-1"}}},
+					},
+				},
+				{Announcements: nil},
+			},
+			want: 6,
+		},
+		{
+			name: "listing with variations of other SKUs",
+			announcements: &[]announcement.Announcements{
+				{
+					Announcements: &[]common.MeliAnnouncement{
+						{ID: "1", Variations: []common.MeliVariation{
+							{ID: 1, AvailableQuantity: 2, Sku: "SKU-1"},
+							{ID: 2, AvailableQuantity: 8, Sku: "SKU-2"},
+							{ID: 3, AvailableQuantity: 5},
+						}},
+					},
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "alias of the SKU in the account",
+			announcements: &[]announcement.Announcements{
+				{
+					Sku: "ALIAS-1",
+					Announcements: &[]common.MeliAnnouncement{
+						{ID: "1", Variations: []common.MeliVariation{
+							{ID: 1, AvailableQuantity: 3, Sku: "ALIAS-1"},
+							{ID: 2, AvailableQuantity: 7, Sku: "SKU-1"},
+						}},
+					},
+				},
+			},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alert.AvailableQuantity(tt.announcements, "SKU-1"); got != tt.want {
+				t.Errorf("AvailableQuantity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
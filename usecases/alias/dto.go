@@ -0,0 +1,22 @@
+package alias
+
+import "github.com/Vractos/kloni/entity"
+
+type SkuAliasDtoInput struct {
+	AccountID  entity.ID `json:"account_id" validate:"required"`
+	Sku        string    `json:"sku"`
+	ListingIDs []string  `json:"listing_ids"`
+}
+
+type CreateMappingDtoInput struct {
+	Store   entity.ID          `json:"-"`
+	Sku     string             `json:"sku" validate:"required"`
+	Aliases []SkuAliasDtoInput `json:"aliases" validate:"required,min=1,dive"`
+}
+
+type UpdateMappingDtoInput struct {
+	Store   entity.ID          `json:"-"`
+	ID      entity.ID          `json:"-"`
+	Sku     string             `json:"sku" validate:"required"`
+	Aliases []SkuAliasDtoInput `json:"aliases" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,133 @@
+package alias
+
+import (
+	"github.com/Vractos/kloni/entity"
+)
+
+// Resolver finds the mapping of a SKU, so the announcements of the accounts
+// that use other SKUs for the same product are found
+type Resolver interface {
+	// Resolve retrieves the mapping that has the SKU as its canonical SKU or as an alias.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - sku: Canonical SKU or SKU of an alias
+	//
+	// Returns:
+	//   - *entity.SkuMapping: The mapping, nil when the SKU isn't mapped
+	//   - error: Error if the mapping can't be retrieved
+	Resolve(storeId entity.ID, sku string) (*entity.SkuMapping, error)
+}
+
+type UseCase interface {
+	Resolver
+	// CreateMapping relates a canonical SKU to the SKUs and the listings the accounts use for it.
+	//
+	// Parameters:
+	//   - input: CreateMappingDtoInput containing the canonical SKU and the aliases
+	//
+	// Returns:
+	//   - *entity.SkuMapping: The created mapping
+	//   - error: entity.ErrInvalidSkuMapping, entity.ErrInvalidSkuAlias, ErrUnknownAccount or ErrSkuAlreadyMapped
+	CreateMapping(input CreateMappingDtoInput) (*entity.SkuMapping, error)
+	// UpdateMapping replaces the canonical SKU and the aliases of a mapping.
+	//
+	// Parameters:
+	//   - input: UpdateMappingDtoInput containing the ID of the mapping and its new definition
+	//
+	// Returns:
+	//   - *entity.SkuMapping: The updated mapping
+	//   - error: ErrMappingNotFound, entity.ErrInvalidSkuMapping, entity.ErrInvalidSkuAlias, ErrUnknownAccount or ErrSkuAlreadyMapped
+	UpdateMapping(input UpdateMappingDtoInput) (*entity.SkuMapping, error)
+	// GetMapping retrieves a mapping of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - mappingId: ID of the mapping
+	//
+	// Returns:
+	//   - *entity.SkuMapping: The mapping
+	//   - error: ErrMappingNotFound if the mapping doesn't exist or belongs to another store
+	GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error)
+	// ListMappings lists the mappings of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []entity.SkuMapping: The mappings, ordered by the canonical SKU
+	//   - error: Error if the mappings can't be retrieved
+	ListMappings(storeId entity.ID) ([]entity.SkuMapping, error)
+	// DeleteMapping removes a mapping, the accounts are searched only by the canonical SKU again.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - mappingId: ID of the mapping
+	//
+	// Returns:
+	//   - error: ErrMappingNotFound if the mapping doesn't exist or belongs to another store
+	DeleteMapping(storeId, mappingId entity.ID) error
+	// SuggestAliases finds the listings of the other accounts that are likely the same product
+	// as the listings of a SKU, by their GTIN or their title.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - sku: Canonical SKU of the product
+	//
+	// Returns:
+	//   - []Suggestion: The likely matches, the most similar first
+	//   - error: ErrInvalidSku if the SKU is empty or ErrSkuNotFound if no listing has the SKU
+	SuggestAliases(storeId entity.ID, sku string) ([]Suggestion, error)
+}
+
+type MatchReason string
+
+const (
+	// The listing has the same GTIN as the listings of the SKU
+	MatchGTIN MatchReason = "gtin"
+	// The listing has a title similar to the listings of the SKU
+	MatchTitle MatchReason = "title"
+)
+
+// Suggestion is a listing of an account that is likely the same product as a SKU
+type Suggestion struct {
+	AccountID      entity.ID
+	AccountName    string
+	AnnouncementID string
+	Title          string
+	// SKU of the listing on its account, empty when it doesn't have one
+	Sku    string
+	Reason MatchReason
+	// Between 0 and 1, 1 for the listings with the same GTIN
+	Score float64
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	// Returns ErrSkuAlreadyMapped if the store already has a mapping with the canonical SKU
+	RegisterMapping(mapping *entity.SkuMapping) error
+	// Returns ErrMappingNotFound if the mapping doesn't exist or ErrSkuAlreadyMapped if the canonical SKU is taken
+	UpdateMapping(mapping *entity.SkuMapping) error
+	// Returns ErrMappingNotFound if the mapping doesn't exist
+	DeleteMapping(storeId, mappingId entity.ID) error
+}
+
+type RepoReader interface {
+	// Nil when the mapping doesn't exist or belongs to another store
+	GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error)
+	// Mappings that have one of the SKUs as their canonical SKU or as an alias
+	FindMappings(storeId entity.ID, skus []string) ([]entity.SkuMapping, error)
+	ListMappings(storeId entity.ID) ([]entity.SkuMapping, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
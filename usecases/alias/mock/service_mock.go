@@ -0,0 +1,426 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/alias/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/alias/interface.go -destination=usecases/alias/mock/service_mock.go
+//
+
+// Package mock_alias is a generated GoMock package.
+package mock_alias
+
+import (
+	reflect "reflect"
+
+	entity "github.com/Vractos/kloni/entity"
+	alias "github.com/Vractos/kloni/usecases/alias"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockResolver is a mock of Resolver interface.
+type MockResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverMockRecorder
+}
+
+// MockResolverMockRecorder is the mock recorder for MockResolver.
+type MockResolverMockRecorder struct {
+	mock *MockResolver
+}
+
+// NewMockResolver creates a new mock instance.
+func NewMockResolver(ctrl *gomock.Controller) *MockResolver {
+	mock := &MockResolver{ctrl: ctrl}
+	mock.recorder = &MockResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockResolver) Resolve(storeId entity.ID, sku string) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", storeId, sku)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockResolverMockRecorder) Resolve(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockResolver)(nil).Resolve), storeId, sku)
+}
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateMapping mocks base method.
+func (m *MockUseCase) CreateMapping(input alias.CreateMappingDtoInput) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMapping", input)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMapping indicates an expected call of CreateMapping.
+func (mr *MockUseCaseMockRecorder) CreateMapping(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMapping", reflect.TypeOf((*MockUseCase)(nil).CreateMapping), input)
+}
+
+// DeleteMapping mocks base method.
+func (m *MockUseCase) DeleteMapping(storeId, mappingId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMapping", storeId, mappingId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMapping indicates an expected call of DeleteMapping.
+func (mr *MockUseCaseMockRecorder) DeleteMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMapping", reflect.TypeOf((*MockUseCase)(nil).DeleteMapping), storeId, mappingId)
+}
+
+// GetMapping mocks base method.
+func (m *MockUseCase) GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapping", storeId, mappingId)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMapping indicates an expected call of GetMapping.
+func (mr *MockUseCaseMockRecorder) GetMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapping", reflect.TypeOf((*MockUseCase)(nil).GetMapping), storeId, mappingId)
+}
+
+// ListMappings mocks base method.
+func (m *MockUseCase) ListMappings(storeId entity.ID) ([]entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMappings", storeId)
+	ret0, _ := ret[0].([]entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMappings indicates an expected call of ListMappings.
+func (mr *MockUseCaseMockRecorder) ListMappings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMappings", reflect.TypeOf((*MockUseCase)(nil).ListMappings), storeId)
+}
+
+// Resolve mocks base method.
+func (m *MockUseCase) Resolve(storeId entity.ID, sku string) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", storeId, sku)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockUseCaseMockRecorder) Resolve(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockUseCase)(nil).Resolve), storeId, sku)
+}
+
+// SuggestAliases mocks base method.
+func (m *MockUseCase) SuggestAliases(storeId entity.ID, sku string) ([]alias.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestAliases", storeId, sku)
+	ret0, _ := ret[0].([]alias.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestAliases indicates an expected call of SuggestAliases.
+func (mr *MockUseCaseMockRecorder) SuggestAliases(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestAliases", reflect.TypeOf((*MockUseCase)(nil).SuggestAliases), storeId, sku)
+}
+
+// UpdateMapping mocks base method.
+func (m *MockUseCase) UpdateMapping(input alias.UpdateMappingDtoInput) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMapping", input)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMapping indicates an expected call of UpdateMapping.
+func (mr *MockUseCaseMockRecorder) UpdateMapping(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMapping", reflect.TypeOf((*MockUseCase)(nil).UpdateMapping), input)
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// DeleteMapping mocks base method.
+func (m *MockRepoWriter) DeleteMapping(storeId, mappingId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMapping", storeId, mappingId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMapping indicates an expected call of DeleteMapping.
+func (mr *MockRepoWriterMockRecorder) DeleteMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMapping", reflect.TypeOf((*MockRepoWriter)(nil).DeleteMapping), storeId, mappingId)
+}
+
+// RegisterMapping mocks base method.
+func (m *MockRepoWriter) RegisterMapping(mapping *entity.SkuMapping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterMapping", mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterMapping indicates an expected call of RegisterMapping.
+func (mr *MockRepoWriterMockRecorder) RegisterMapping(mapping any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMapping", reflect.TypeOf((*MockRepoWriter)(nil).RegisterMapping), mapping)
+}
+
+// UpdateMapping mocks base method.
+func (m *MockRepoWriter) UpdateMapping(mapping *entity.SkuMapping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMapping", mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMapping indicates an expected call of UpdateMapping.
+func (mr *MockRepoWriterMockRecorder) UpdateMapping(mapping any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMapping", reflect.TypeOf((*MockRepoWriter)(nil).UpdateMapping), mapping)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// FindMappings mocks base method.
+func (m *MockRepoReader) FindMappings(storeId entity.ID, skus []string) ([]entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMappings", storeId, skus)
+	ret0, _ := ret[0].([]entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMappings indicates an expected call of FindMappings.
+func (mr *MockRepoReaderMockRecorder) FindMappings(storeId, skus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMappings", reflect.TypeOf((*MockRepoReader)(nil).FindMappings), storeId, skus)
+}
+
+// GetMapping mocks base method.
+func (m *MockRepoReader) GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapping", storeId, mappingId)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMapping indicates an expected call of GetMapping.
+func (mr *MockRepoReaderMockRecorder) GetMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapping", reflect.TypeOf((*MockRepoReader)(nil).GetMapping), storeId, mappingId)
+}
+
+// ListMappings mocks base method.
+func (m *MockRepoReader) ListMappings(storeId entity.ID) ([]entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMappings", storeId)
+	ret0, _ := ret[0].([]entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMappings indicates an expected call of ListMappings.
+func (mr *MockRepoReaderMockRecorder) ListMappings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMappings", reflect.TypeOf((*MockRepoReader)(nil).ListMappings), storeId)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteMapping mocks base method.
+func (m *MockRepository) DeleteMapping(storeId, mappingId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMapping", storeId, mappingId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMapping indicates an expected call of DeleteMapping.
+func (mr *MockRepositoryMockRecorder) DeleteMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMapping", reflect.TypeOf((*MockRepository)(nil).DeleteMapping), storeId, mappingId)
+}
+
+// FindMappings mocks base method.
+func (m *MockRepository) FindMappings(storeId entity.ID, skus []string) ([]entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMappings", storeId, skus)
+	ret0, _ := ret[0].([]entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMappings indicates an expected call of FindMappings.
+func (mr *MockRepositoryMockRecorder) FindMappings(storeId, skus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMappings", reflect.TypeOf((*MockRepository)(nil).FindMappings), storeId, skus)
+}
+
+// GetMapping mocks base method.
+func (m *MockRepository) GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapping", storeId, mappingId)
+	ret0, _ := ret[0].(*entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMapping indicates an expected call of GetMapping.
+func (mr *MockRepositoryMockRecorder) GetMapping(storeId, mappingId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapping", reflect.TypeOf((*MockRepository)(nil).GetMapping), storeId, mappingId)
+}
+
+// ListMappings mocks base method.
+func (m *MockRepository) ListMappings(storeId entity.ID) ([]entity.SkuMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMappings", storeId)
+	ret0, _ := ret[0].([]entity.SkuMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMappings indicates an expected call of ListMappings.
+func (mr *MockRepositoryMockRecorder) ListMappings(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMappings", reflect.TypeOf((*MockRepository)(nil).ListMappings), storeId)
+}
+
+// RegisterMapping mocks base method.
+func (m *MockRepository) RegisterMapping(mapping *entity.SkuMapping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterMapping", mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterMapping indicates an expected call of RegisterMapping.
+func (mr *MockRepositoryMockRecorder) RegisterMapping(mapping any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMapping", reflect.TypeOf((*MockRepository)(nil).RegisterMapping), mapping)
+}
+
+// UpdateMapping mocks base method.
+func (m *MockRepository) UpdateMapping(mapping *entity.SkuMapping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMapping", mapping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMapping indicates an expected call of UpdateMapping.
+func (mr *MockRepositoryMockRecorder) UpdateMapping(mapping any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMapping", reflect.TypeOf((*MockRepository)(nil).UpdateMapping), mapping)
+}
@@ -0,0 +1,441 @@
+// Package alias implements the mappings between the canonical SKU of a product
+// and the SKUs and listings the accounts use for it
+package alias
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/store"
+	"github.com/Vractos/kloni/utils"
+	"go.uber.org/zap"
+)
+
+// Error definitions for alias operations
+var (
+	// ErrMappingNotFound is returned when the mapping doesn't exist or belongs to another store
+	ErrMappingNotFound = errors.New("sku mapping not found")
+	// ErrSkuAlreadyMapped is returned when the canonical SKU or an alias belongs to another mapping
+	ErrSkuAlreadyMapped = errors.New("sku already mapped")
+	// ErrUnknownAccount is returned when an alias has an account of another store
+	ErrUnknownAccount = errors.New("unknown account")
+	// ErrInvalidSku is returned when the SKU of the suggestions is empty
+	ErrInvalidSku = errors.New("invalid sku")
+	// ErrSkuNotFound is returned when no listing of the store has the SKU of the suggestions
+	ErrSkuNotFound = errors.New("sku not found")
+)
+
+const (
+	// maxSuggestions is the maximum number of suggestions returned
+	maxSuggestions = 20
+	// maxCandidates is the maximum number of listings of an account compared with the SKU,
+	// the most relevant results of the searches come first
+	maxCandidates = 10
+	// minTitleScore is the minimum similarity of the titles for a listing to be suggested
+	minTitleScore = 0.5
+	// gtinAttribute is the attribute of the listings with their GTIN, e.g. the EAN
+	gtinAttribute = "GTIN"
+)
+
+type AliasService struct {
+	repo   Repository
+	meli   common.MercadoLivre
+	store  store.UseCase
+	logger common.Logger
+}
+
+// NewAliasService creates a new instance of AliasService.
+//
+// Parameters:
+//   - repository: Repository of the SKU mappings
+//   - mercadolivre: Mercado Livre API client, used to search the suggestions
+//   - storeUseCase: Store use case, used to retrieve the accounts of the store
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *AliasService: A new instance of AliasService
+func NewAliasService(
+	repository Repository,
+	mercadolivre common.MercadoLivre,
+	storeUseCase store.UseCase,
+	logger common.Logger,
+) *AliasService {
+	return &AliasService{
+		repo:   repository,
+		meli:   mercadolivre,
+		store:  storeUseCase,
+		logger: logger,
+	}
+}
+
+// Resolve retrieves the mapping that has the SKU as its canonical SKU or as an alias.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - sku: Canonical SKU or SKU of an alias
+//
+// Returns:
+//   - *entity.SkuMapping: The mapping, nil when the SKU isn't mapped
+//   - error: Error if the mapping can't be retrieved
+func (s *AliasService) Resolve(storeId entity.ID, sku string) (*entity.SkuMapping, error) {
+	mappings, err := s.repo.FindMappings(storeId, []string{sku})
+	if err != nil {
+		s.logger.Error("Fail to resolve the sku", err, zap.String("store_id", storeId.String()), zap.String("sku", sku))
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	return &mappings[0], nil
+}
+
+// CreateMapping relates a canonical SKU to the SKUs and the listings the accounts use for it.
+//
+// Parameters:
+//   - input: CreateMappingDtoInput containing the canonical SKU and the aliases
+//
+// Returns:
+//   - *entity.SkuMapping: The created mapping
+//   - error: entity.ErrInvalidSkuMapping, entity.ErrInvalidSkuAlias, ErrUnknownAccount or ErrSkuAlreadyMapped
+func (s *AliasService) CreateMapping(input CreateMappingDtoInput) (*entity.SkuMapping, error) {
+	mapping, err := entity.NewSkuMapping(input.Store, input.Sku, toAliases(input.Aliases))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateMapping(mapping); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RegisterMapping(mapping); err != nil {
+		if !errors.Is(err, ErrSkuAlreadyMapped) {
+			s.logger.Error("Fail to register the sku mapping", err, zap.String("store_id", input.Store.String()), zap.String("sku", mapping.Sku))
+		}
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// UpdateMapping replaces the canonical SKU and the aliases of a mapping.
+//
+// Parameters:
+//   - input: UpdateMappingDtoInput containing the ID of the mapping and its new definition
+//
+// Returns:
+//   - *entity.SkuMapping: The updated mapping
+//   - error: ErrMappingNotFound, entity.ErrInvalidSkuMapping, entity.ErrInvalidSkuAlias, ErrUnknownAccount or ErrSkuAlreadyMapped
+func (s *AliasService) UpdateMapping(input UpdateMappingDtoInput) (*entity.SkuMapping, error) {
+	mapping, err := s.GetMapping(input.Store, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := mapping.Define(input.Sku, toAliases(input.Aliases)); err != nil {
+		return nil, err
+	}
+	if err := s.validateMapping(mapping); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMapping(mapping); err != nil {
+		if !errors.Is(err, ErrSkuAlreadyMapped) && !errors.Is(err, ErrMappingNotFound) {
+			s.logger.Error("Fail to update the sku mapping", err, zap.String("mapping_id", mapping.ID.String()))
+		}
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// GetMapping retrieves a mapping of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - mappingId: ID of the mapping
+//
+// Returns:
+//   - *entity.SkuMapping: The mapping
+//   - error: ErrMappingNotFound if the mapping doesn't exist or belongs to another store
+func (s *AliasService) GetMapping(storeId, mappingId entity.ID) (*entity.SkuMapping, error) {
+	mapping, err := s.repo.GetMapping(storeId, mappingId)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the sku mapping", err, zap.String("mapping_id", mappingId.String()))
+		return nil, err
+	}
+	if mapping == nil {
+		return nil, ErrMappingNotFound
+	}
+	return mapping, nil
+}
+
+// ListMappings lists the mappings of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []entity.SkuMapping: The mappings, ordered by the canonical SKU
+//   - error: Error if the mappings can't be retrieved
+func (s *AliasService) ListMappings(storeId entity.ID) ([]entity.SkuMapping, error) {
+	mappings, err := s.repo.ListMappings(storeId)
+	if err != nil {
+		s.logger.Error("Fail to list the sku mappings", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// DeleteMapping removes a mapping, the accounts are searched only by the canonical SKU again.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - mappingId: ID of the mapping
+//
+// Returns:
+//   - error: ErrMappingNotFound if the mapping doesn't exist or belongs to another store
+func (s *AliasService) DeleteMapping(storeId, mappingId entity.ID) error {
+	if err := s.repo.DeleteMapping(storeId, mappingId); err != nil {
+		if !errors.Is(err, ErrMappingNotFound) {
+			s.logger.Error("Fail to delete the sku mapping", err, zap.String("mapping_id", mappingId.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// SuggestAliases finds the listings of the other accounts that are likely the same product
+// as the listings of a SKU, by their GTIN or their title.
+// Only the accounts without listings with the SKU are searched.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - sku: Canonical SKU of the product
+//
+// Returns:
+//   - []Suggestion: The likely matches, the most similar first
+//   - error: ErrInvalidSku if the SKU is empty or ErrSkuNotFound if no listing has the SKU
+func (s *AliasService) SuggestAliases(storeId entity.ID, sku string) ([]Suggestion, error) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, ErrInvalidSku
+	}
+
+	credentials, err := s.store.RetrieveMeliCredentialsFromStoreID(storeId)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+
+	// Listings with the SKU, the references of the comparisons
+	references := []common.MeliAnnouncement{}
+	withSku := make(map[entity.ID]bool)
+	for _, c := range *credentials {
+		ids, err := s.meli.GetAnnouncementsIDsViaSKU(sku, c.UserID, c.AccessToken)
+		if err != nil {
+			s.logger.Error("Fail to retrieve the listings of the sku", err, zap.String("sku", sku), zap.String("account_id", c.ID.String()))
+			return nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		withSku[c.ID] = true
+		if len(ids) > maxCandidates {
+			ids = ids[:maxCandidates]
+		}
+		anns, err := s.meli.GetAnnouncements(ids, c.AccessToken)
+		if err != nil {
+			s.logger.Error("Fail to retrieve the listings of the sku", err, zap.String("sku", sku), zap.String("account_id", c.ID.String()))
+			return nil, err
+		}
+		references = append(references, *anns...)
+	}
+	if len(references) == 0 {
+		return nil, ErrSkuNotFound
+	}
+
+	gtins := make(map[string]bool)
+	for _, ref := range references {
+		if gtin := gtinOf(ref); gtin != "" {
+			gtins[gtin] = true
+		}
+	}
+	title := references[0].Title
+
+	suggestions := []Suggestion{}
+	for _, c := range *credentials {
+		if withSku[c.ID] {
+			continue
+		}
+
+		queries := []string{title}
+		for gtin := range gtins {
+			queries = append(queries, gtin)
+		}
+		candidates, err := s.searchCandidates(queries, c)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ann := range candidates {
+			suggestion := Suggestion{
+				AccountID:      c.ID,
+				AccountName:    utils.GetOrDefault(c.AccountName, ""),
+				AnnouncementID: ann.ID,
+				Title:          ann.Title,
+				Sku:            ann.Sku,
+			}
+			if gtin := gtinOf(ann); gtin != "" && gtins[gtin] {
+				suggestion.Reason = MatchGTIN
+				suggestion.Score = 1
+			} else if score := titleSimilarity(title, ann.Title); score >= minTitleScore {
+				suggestion.Reason = MatchTitle
+				suggestion.Score = score
+			} else {
+				continue
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions, nil
+}
+
+// searchCandidates searches the listings of an account that may be the same product.
+//
+// Parameters:
+//   - queries: The title and the GTINs of the product
+//   - credentials: Credentials of the account
+//
+// Returns:
+//   - []common.MeliAnnouncement: The listings found, at most maxCandidates
+//   - error: Error if the listings can't be searched
+func (s *AliasService) searchCandidates(queries []string, credentials store.Credentials) ([]common.MeliAnnouncement, error) {
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, query := range queries {
+		found, err := s.meli.SearchAnnouncementsIDs(query, credentials.UserID, credentials.AccessToken)
+		if err != nil {
+			s.logger.Error("Fail to search the listings of the account", err, zap.String("account_id", credentials.ID.String()))
+			return nil, err
+		}
+		for _, id := range found {
+			if !seen[id] && len(ids) < maxCandidates {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	anns, err := s.meli.GetAnnouncements(ids, credentials.AccessToken)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the listings of the account", err, zap.String("account_id", credentials.ID.String()))
+		return nil, err
+	}
+	return *anns, nil
+}
+
+// validateMapping verifies that the accounts of the aliases belong to the store
+// and that the SKUs of the mapping don't belong to another mapping.
+//
+// Parameters:
+//   - mapping: The mapping to be saved
+//
+// Returns:
+//   - error: ErrUnknownAccount or ErrSkuAlreadyMapped
+func (s *AliasService) validateMapping(mapping *entity.SkuMapping) error {
+	credentials, err := s.store.RetrieveMeliCredentialsFromStoreID(mapping.StoreID)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", mapping.StoreID.String()))
+		return err
+	}
+	accounts := make(map[entity.ID]bool, len(*credentials))
+	for _, c := range *credentials {
+		accounts[c.ID] = true
+	}
+	for _, a := range mapping.Aliases {
+		if !accounts[a.AccountID] {
+			return ErrUnknownAccount
+		}
+	}
+
+	others, err := s.repo.FindMappings(mapping.StoreID, mapping.Skus())
+	if err != nil {
+		s.logger.Error("Fail to retrieve the mappings of the skus", err, zap.String("sku", mapping.Sku))
+		return err
+	}
+	for _, other := range others {
+		if other.ID != mapping.ID {
+			return ErrSkuAlreadyMapped
+		}
+	}
+	return nil
+}
+
+// gtinOf is the GTIN of a listing, empty when it doesn't have one
+func gtinOf(ann common.MeliAnnouncement) string {
+	for _, attr := range ann.Attributes {
+		if attr.ID == gtinAttribute {
+			return strings.TrimSpace(attr.ValueName)
+		}
+	}
+	return ""
+}
+
+// titleSimilarity is the Jaccard index of the words of two titles, between 0 and 1.
+// The words are compared in lowercase, ignoring the punctuation and the single characters.
+//
+// Parameters:
+//   - a: A title
+//   - b: Another title
+//
+// Returns:
+//   - float64: The similarity, 1 when the titles have the same words
+func titleSimilarity(a, b string) float64 {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) > 1 {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func toAliases(input []SkuAliasDtoInput) []entity.SkuAlias {
+	aliases := make([]entity.SkuAlias, len(input))
+	for i, a := range input {
+		aliases[i] = entity.SkuAlias{AccountID: a.AccountID, Sku: a.Sku, ListingIDs: a.ListingIDs}
+	}
+	return aliases
+}
+
+// TitleSimilarityTest is a test helper function that exposes titleSimilarity for testing
+func TitleSimilarityTest(a, b string) float64 {
+	return titleSimilarity(a, b)
+}
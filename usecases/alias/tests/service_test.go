@@ -0,0 +1,218 @@
+package alias
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/alias"
+	mock_alias "github.com/Vractos/kloni/usecases/alias/mock"
+	common "github.com/Vractos/kloni/usecases/common"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/store"
+	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+)
+
+type Mocks struct {
+	repo   *mock_alias.MockRepository
+	meli   *common_mock.MockMercadoLivre
+	store  *mock_store.MockUseCase
+	logger *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:   mock_alias.NewMockRepository(ctrl),
+		meli:   common_mock.NewMockMercadoLivre(ctrl),
+		store:  mock_store.NewMockUseCase(ctrl),
+		logger: common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newAliasService() *alias.AliasService {
+	return alias.NewAliasService(m.repo, m.meli, m.store, m.logger)
+}
+
+// withGTIN sets the GTIN attribute of an announcement
+func withGTIN(t *testing.T, ann common.MeliAnnouncement, gtin string) common.MeliAnnouncement {
+	if err := json.Unmarshal([]byte(`[{"ID":"GTIN","ValueName":"`+gtin+`"}]`), &ann.Attributes); err != nil {
+		t.Fatalf("fail to set the GTIN: %v", err)
+	}
+	return ann
+}
+
+func TestResolve(t *testing.T) {
+	storeId := entity.NewID()
+	mapping, _ := entity.NewSkuMapping(storeId, "SKU-1", []entity.SkuAlias{{AccountID: entity.NewID(), Sku: "ALT-1"}})
+
+	ctrl := gomock.NewController(t)
+	m := newMocks(ctrl)
+
+	m.repo.EXPECT().FindMappings(storeId, []string{"ALT-1"}).Return([]entity.SkuMapping{*mapping}, nil)
+	m.repo.EXPECT().FindMappings(storeId, []string{"OTHER"}).Return([]entity.SkuMapping{}, nil)
+
+	got, err := m.newAliasService().Resolve(storeId, "ALT-1")
+	if err != nil || got == nil || got.ID != mapping.ID {
+		t.Errorf("Resolve(ALT-1) = %+v, %v", got, err)
+	}
+	got, err = m.newAliasService().Resolve(storeId, "OTHER")
+	if err != nil || got != nil {
+		t.Errorf("Resolve(OTHER) = %+v, %v", got, err)
+	}
+}
+
+func TestCreateMapping(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	credentials := &[]store.Credentials{{ID: accountId, OwnerID: storeId, MeliCredential: &common.MeliCredential{}}}
+	input := alias.CreateMappingDtoInput{
+		Store: storeId,
+		Sku:   "SKU-1",
+		Aliases: []alias.SkuAliasDtoInput{
+			{AccountID: accountId, Sku: "ALT-1", ListingIDs: []string{"MLB1"}},
+		},
+	}
+
+	t.Run("mapping created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.repo.EXPECT().FindMappings(storeId, []string{"SKU-1", "ALT-1"}).Return(nil, nil)
+		m.repo.EXPECT().RegisterMapping(gomock.Any()).Return(nil)
+
+		mapping, err := m.newAliasService().CreateMapping(input)
+		if err != nil {
+			t.Fatalf("CreateMapping() error = %v", err)
+		}
+		want := []entity.SkuAlias{{AccountID: accountId, Sku: "ALT-1", ListingIDs: []string{"MLB1"}}}
+		if diff := cmp.Diff(want, mapping.Aliases); diff != "" {
+			t.Errorf("CreateMapping() aliases mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("account of another store", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		other := input
+		other.Aliases = []alias.SkuAliasDtoInput{{AccountID: entity.NewID(), Sku: "ALT-1"}}
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+
+		if _, err := m.newAliasService().CreateMapping(other); !errors.Is(err, alias.ErrUnknownAccount) {
+			t.Errorf("CreateMapping() error = %v, want %v", err, alias.ErrUnknownAccount)
+		}
+	})
+
+	t.Run("alias of another mapping", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		existing, _ := entity.NewSkuMapping(storeId, "SKU-2", []entity.SkuAlias{{AccountID: accountId, Sku: "ALT-1"}})
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.repo.EXPECT().FindMappings(storeId, []string{"SKU-1", "ALT-1"}).Return([]entity.SkuMapping{*existing}, nil)
+
+		if _, err := m.newAliasService().CreateMapping(input); !errors.Is(err, alias.ErrSkuAlreadyMapped) {
+			t.Errorf("CreateMapping() error = %v, want %v", err, alias.ErrSkuAlreadyMapped)
+		}
+	})
+
+	t.Run("invalid alias", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		invalid := input
+		invalid.Aliases = []alias.SkuAliasDtoInput{{AccountID: accountId}}
+		if _, err := m.newAliasService().CreateMapping(invalid); !errors.Is(err, entity.ErrInvalidSkuAlias) {
+			t.Errorf("CreateMapping() error = %v, want %v", err, entity.ErrInvalidSkuAlias)
+		}
+	})
+}
+
+// TestSuggestAliases tests the suggestions of the listings that are likely the same product as a SKU.
+// It verifies:
+// 1. The listings with the same GTIN come first, followed by the ones with a similar title
+// 2. The accounts that already have the SKU aren't searched
+// 3. A SKU without listings isn't suggested
+func TestSuggestAliases(t *testing.T) {
+	storeId := entity.NewID()
+	mainAccount, otherAccount := entity.NewID(), entity.NewID()
+	otherName := "Other"
+	credentials := &[]store.Credentials{
+		{ID: mainAccount, OwnerID: storeId, MeliCredential: &common.MeliCredential{UserID: "1", AccessToken: "main-token"}},
+		{ID: otherAccount, OwnerID: storeId, AccountName: &otherName, MeliCredential: &common.MeliCredential{UserID: "2", AccessToken: "other-token"}},
+	}
+	reference := withGTIN(t, common.MeliAnnouncement{ID: "MLB1", Title: "Oil Filter Bosch 0986", Sku: "SKU-1"}, "7891234567890")
+
+	t.Run("suggestions found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.meli.EXPECT().GetAnnouncementsIDsViaSKU("SKU-1", "1", "main-token").Return([]string{"MLB1"}, nil)
+		m.meli.EXPECT().GetAnnouncements([]string{"MLB1"}, "main-token").Return(&[]common.MeliAnnouncement{reference}, nil)
+		m.meli.EXPECT().GetAnnouncementsIDsViaSKU("SKU-1", "2", "other-token").Return(nil, nil)
+		m.meli.EXPECT().SearchAnnouncementsIDs("Oil Filter Bosch 0986", "2", "other-token").Return([]string{"MLB3", "MLB4"}, nil)
+		m.meli.EXPECT().SearchAnnouncementsIDs("7891234567890", "2", "other-token").Return([]string{"MLB2", "MLB3"}, nil)
+		m.meli.EXPECT().GetAnnouncements([]string{"MLB3", "MLB4", "MLB2"}, "other-token").Return(&[]common.MeliAnnouncement{
+			{ID: "MLB3", Title: "Bosch Oil Filter 0986 Original", Sku: "B-22"},
+			{ID: "MLB4", Title: "Air Filter Bosch", Sku: "B-23"},
+			withGTIN(t, common.MeliAnnouncement{ID: "MLB2", Title: "Filtro de Óleo", Sku: "B-21"}, "7891234567890"),
+		}, nil)
+
+		suggestions, err := m.newAliasService().SuggestAliases(storeId, " SKU-1 ")
+		if err != nil {
+			t.Fatalf("SuggestAliases() error = %v", err)
+		}
+
+		want := []alias.Suggestion{
+			{AccountID: otherAccount, AccountName: "Other", AnnouncementID: "MLB2", Title: "Filtro de Óleo", Sku: "B-21", Reason: alias.MatchGTIN, Score: 1},
+			{AccountID: otherAccount, AccountName: "Other", AnnouncementID: "MLB3", Title: "Bosch Oil Filter 0986 Original", Sku: "B-22", Reason: alias.MatchTitle, Score: 0.8},
+		}
+		if diff := cmp.Diff(want, suggestions); diff != "" {
+			t.Errorf("SuggestAliases() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("sku without listings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.meli.EXPECT().GetAnnouncementsIDsViaSKU("SKU-1", gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+		if _, err := m.newAliasService().SuggestAliases(storeId, "SKU-1"); !errors.Is(err, alias.ErrSkuNotFound) {
+			t.Errorf("SuggestAliases() error = %v, want %v", err, alias.ErrSkuNotFound)
+		}
+	})
+
+	t.Run("empty sku", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		if _, err := m.newAliasService().SuggestAliases(storeId, " "); !errors.Is(err, alias.ErrInvalidSku) {
+			t.Errorf("SuggestAliases() error = %v, want %v", err, alias.ErrInvalidSku)
+		}
+	})
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "Oil Filter Bosch", b: "bosch oil-filter", want: 1},
+		{a: "Oil Filter Bosch", b: "Oil Filter Mann", want: 0.5},
+		{a: "Oil Filter", b: "Air Pump", want: 0},
+		{a: "", b: "Air Pump", want: 0},
+	}
+	for _, tt := range tests {
+		if got := alias.TitleSimilarityTest(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package allocation
+
+import "github.com/Vractos/kloni/entity"
+
+type AccountAllocationDtoInput struct {
+	AccountID   entity.ID `json:"account_id" validate:"required"`
+	Buffer      int       `json:"buffer" validate:"min=0"`
+	Percentage  int       `json:"percentage" validate:"min=1,max=100"`
+	MaxQuantity *int      `json:"max_quantity" validate:"omitempty,min=0"`
+}
+
+type CreatePolicyDtoInput struct {
+	Store entity.ID `json:"-"`
+	// Empty for the default policy of the store
+	Sku             string                      `json:"sku"`
+	PriorityAccount *entity.ID                  `json:"priority_account_id"`
+	Accounts        []AccountAllocationDtoInput `json:"accounts" validate:"required,min=1,dive"`
+}
+
+type UpdatePolicyDtoInput struct {
+	Store           entity.ID                   `json:"-"`
+	ID              entity.ID                   `json:"-"`
+	Sku             string                      `json:"sku"`
+	PriorityAccount *entity.ID                  `json:"priority_account_id"`
+	Accounts        []AccountAllocationDtoInput `json:"accounts" validate:"required,min=1,dive"`
+}
+
+type AllocateDtoInput struct {
+	Store entity.ID
+	Sku   string
+	// Quantity published on the listings, the initial true stock when it isn't tracked yet
+	Published int
+	// Either the absolute true stock or the delta is informed, or neither to only read it
+	Quantity *int
+	Delta    *int
+}
@@ -0,0 +1,116 @@
+package allocation
+
+import (
+	"github.com/Vractos/kloni/entity"
+)
+
+// Allocation is the true stock of a SKU with the policy that distributes it across the accounts
+type Allocation struct {
+	Policy *entity.AllocationPolicy
+	Stock  int
+}
+
+// Quantity is the quantity published on the listings of an account
+func (a *Allocation) Quantity(account entity.ID) int {
+	return a.Policy.Quantity(account, a.Stock)
+}
+
+// Allocator keeps the true stock of the SKUs with a policy, so the sync engine computes
+// the quantity of each listing from it instead of pushing the same quantity everywhere
+type Allocator interface {
+	// Allocate applies a change to the true stock of a SKU and returns its allocation.
+	// Without a quantity and a delta, the current allocation is returned.
+	//
+	// Parameters:
+	//   - input: AllocateDtoInput containing the SKU and the change of its stock
+	//
+	// Returns:
+	//   - *Allocation: The allocation, nil when the SKU doesn't have a policy
+	//   - error: Error if the policy or the stock can't be retrieved
+	Allocate(input AllocateDtoInput) (*Allocation, error)
+}
+
+type UseCase interface {
+	Allocator
+	// CreatePolicy defines how the stock of a SKU, or of every SKU, is distributed across the accounts.
+	//
+	// Parameters:
+	//   - input: CreatePolicyDtoInput containing the SKU and the rules of the accounts
+	//
+	// Returns:
+	//   - *entity.AllocationPolicy: The created policy
+	//   - error: entity.ErrInvalidAllocationPolicy, entity.ErrInvalidAccountAllocation, ErrUnknownAccount or ErrPolicyAlreadyExists
+	CreatePolicy(input CreatePolicyDtoInput) (*entity.AllocationPolicy, error)
+	// UpdatePolicy replaces the SKU, the priority account and the rules of a policy.
+	//
+	// Parameters:
+	//   - input: UpdatePolicyDtoInput containing the ID of the policy and its new definition
+	//
+	// Returns:
+	//   - *entity.AllocationPolicy: The updated policy
+	//   - error: ErrPolicyNotFound, entity.ErrInvalidAllocationPolicy, entity.ErrInvalidAccountAllocation, ErrUnknownAccount or ErrPolicyAlreadyExists
+	UpdatePolicy(input UpdatePolicyDtoInput) (*entity.AllocationPolicy, error)
+	// GetPolicy retrieves a policy of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - policyId: ID of the policy
+	//
+	// Returns:
+	//   - *entity.AllocationPolicy: The policy
+	//   - error: ErrPolicyNotFound if the policy doesn't exist or belongs to another store
+	GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error)
+	// ListPolicies lists the policies of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []entity.AllocationPolicy: The policies, ordered by the SKU
+	//   - error: Error if the policies can't be retrieved
+	ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error)
+	// DeletePolicy removes a policy, the quantities of the listings aren't changed.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - policyId: ID of the policy
+	//
+	// Returns:
+	//   - error: ErrPolicyNotFound if the policy doesn't exist or belongs to another store
+	DeletePolicy(storeId, policyId entity.ID) error
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	// Returns ErrPolicyAlreadyExists if the store already has a policy for the SKU
+	RegisterPolicy(policy *entity.AllocationPolicy) error
+	// Returns ErrPolicyNotFound if the policy doesn't exist or ErrPolicyAlreadyExists if the SKU is taken
+	UpdatePolicy(policy *entity.AllocationPolicy) error
+	// Returns ErrPolicyNotFound if the policy doesn't exist
+	DeletePolicy(storeId, policyId entity.ID) error
+	// Sets the true stock of a SKU
+	SetStock(storeId entity.ID, sku string, quantity int) error
+	// Adds the delta to the true stock of a SKU, which never goes below zero, and returns the new stock.
+	// The stock is initialized with the initial quantity when it isn't tracked yet
+	AddStock(storeId entity.ID, sku string, delta, initial int) (int, error)
+}
+
+type RepoReader interface {
+	// Nil when the policy doesn't exist or belongs to another store
+	GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error)
+	// Policy of the SKU, or the default policy of the store. Nil when there's none
+	FindPolicy(storeId entity.ID, sku string) (*entity.AllocationPolicy, error)
+	ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
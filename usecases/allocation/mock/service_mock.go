@@ -0,0 +1,469 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/allocation/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/allocation/interface.go -destination=usecases/allocation/mock/service_mock.go
+//
+
+// Package mock_allocation is a generated GoMock package.
+package mock_allocation
+
+import (
+	reflect "reflect"
+
+	entity "github.com/Vractos/kloni/entity"
+	allocation "github.com/Vractos/kloni/usecases/allocation"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAllocator is a mock of Allocator interface.
+type MockAllocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAllocatorMockRecorder
+}
+
+// MockAllocatorMockRecorder is the mock recorder for MockAllocator.
+type MockAllocatorMockRecorder struct {
+	mock *MockAllocator
+}
+
+// NewMockAllocator creates a new mock instance.
+func NewMockAllocator(ctrl *gomock.Controller) *MockAllocator {
+	mock := &MockAllocator{ctrl: ctrl}
+	mock.recorder = &MockAllocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAllocator) EXPECT() *MockAllocatorMockRecorder {
+	return m.recorder
+}
+
+// Allocate mocks base method.
+func (m *MockAllocator) Allocate(input allocation.AllocateDtoInput) (*allocation.Allocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allocate", input)
+	ret0, _ := ret[0].(*allocation.Allocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allocate indicates an expected call of Allocate.
+func (mr *MockAllocatorMockRecorder) Allocate(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allocate", reflect.TypeOf((*MockAllocator)(nil).Allocate), input)
+}
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Allocate mocks base method.
+func (m *MockUseCase) Allocate(input allocation.AllocateDtoInput) (*allocation.Allocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allocate", input)
+	ret0, _ := ret[0].(*allocation.Allocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allocate indicates an expected call of Allocate.
+func (mr *MockUseCaseMockRecorder) Allocate(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allocate", reflect.TypeOf((*MockUseCase)(nil).Allocate), input)
+}
+
+// CreatePolicy mocks base method.
+func (m *MockUseCase) CreatePolicy(input allocation.CreatePolicyDtoInput) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePolicy", input)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePolicy indicates an expected call of CreatePolicy.
+func (mr *MockUseCaseMockRecorder) CreatePolicy(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePolicy", reflect.TypeOf((*MockUseCase)(nil).CreatePolicy), input)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockUseCase) DeletePolicy(storeId, policyId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", storeId, policyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockUseCaseMockRecorder) DeletePolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockUseCase)(nil).DeletePolicy), storeId, policyId)
+}
+
+// GetPolicy mocks base method.
+func (m *MockUseCase) GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", storeId, policyId)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockUseCaseMockRecorder) GetPolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockUseCase)(nil).GetPolicy), storeId, policyId)
+}
+
+// ListPolicies mocks base method.
+func (m *MockUseCase) ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicies", storeId)
+	ret0, _ := ret[0].([]entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPolicies indicates an expected call of ListPolicies.
+func (mr *MockUseCaseMockRecorder) ListPolicies(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockUseCase)(nil).ListPolicies), storeId)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockUseCase) UpdatePolicy(input allocation.UpdatePolicyDtoInput) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", input)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockUseCaseMockRecorder) UpdatePolicy(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockUseCase)(nil).UpdatePolicy), input)
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// AddStock mocks base method.
+func (m *MockRepoWriter) AddStock(storeId entity.ID, sku string, delta, initial int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStock", storeId, sku, delta, initial)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStock indicates an expected call of AddStock.
+func (mr *MockRepoWriterMockRecorder) AddStock(storeId, sku, delta, initial any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStock", reflect.TypeOf((*MockRepoWriter)(nil).AddStock), storeId, sku, delta, initial)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockRepoWriter) DeletePolicy(storeId, policyId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", storeId, policyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockRepoWriterMockRecorder) DeletePolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockRepoWriter)(nil).DeletePolicy), storeId, policyId)
+}
+
+// RegisterPolicy mocks base method.
+func (m *MockRepoWriter) RegisterPolicy(policy *entity.AllocationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterPolicy indicates an expected call of RegisterPolicy.
+func (mr *MockRepoWriterMockRecorder) RegisterPolicy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPolicy", reflect.TypeOf((*MockRepoWriter)(nil).RegisterPolicy), policy)
+}
+
+// SetStock mocks base method.
+func (m *MockRepoWriter) SetStock(storeId entity.ID, sku string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", storeId, sku, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockRepoWriterMockRecorder) SetStock(storeId, sku, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockRepoWriter)(nil).SetStock), storeId, sku, quantity)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockRepoWriter) UpdatePolicy(policy *entity.AllocationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockRepoWriterMockRecorder) UpdatePolicy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockRepoWriter)(nil).UpdatePolicy), policy)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// FindPolicy mocks base method.
+func (m *MockRepoReader) FindPolicy(storeId entity.ID, sku string) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPolicy", storeId, sku)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPolicy indicates an expected call of FindPolicy.
+func (mr *MockRepoReaderMockRecorder) FindPolicy(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPolicy", reflect.TypeOf((*MockRepoReader)(nil).FindPolicy), storeId, sku)
+}
+
+// GetPolicy mocks base method.
+func (m *MockRepoReader) GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", storeId, policyId)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockRepoReaderMockRecorder) GetPolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockRepoReader)(nil).GetPolicy), storeId, policyId)
+}
+
+// ListPolicies mocks base method.
+func (m *MockRepoReader) ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicies", storeId)
+	ret0, _ := ret[0].([]entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPolicies indicates an expected call of ListPolicies.
+func (mr *MockRepoReaderMockRecorder) ListPolicies(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockRepoReader)(nil).ListPolicies), storeId)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddStock mocks base method.
+func (m *MockRepository) AddStock(storeId entity.ID, sku string, delta, initial int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStock", storeId, sku, delta, initial)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStock indicates an expected call of AddStock.
+func (mr *MockRepositoryMockRecorder) AddStock(storeId, sku, delta, initial any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStock", reflect.TypeOf((*MockRepository)(nil).AddStock), storeId, sku, delta, initial)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockRepository) DeletePolicy(storeId, policyId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", storeId, policyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockRepositoryMockRecorder) DeletePolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockRepository)(nil).DeletePolicy), storeId, policyId)
+}
+
+// FindPolicy mocks base method.
+func (m *MockRepository) FindPolicy(storeId entity.ID, sku string) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPolicy", storeId, sku)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPolicy indicates an expected call of FindPolicy.
+func (mr *MockRepositoryMockRecorder) FindPolicy(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPolicy", reflect.TypeOf((*MockRepository)(nil).FindPolicy), storeId, sku)
+}
+
+// GetPolicy mocks base method.
+func (m *MockRepository) GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", storeId, policyId)
+	ret0, _ := ret[0].(*entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockRepositoryMockRecorder) GetPolicy(storeId, policyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockRepository)(nil).GetPolicy), storeId, policyId)
+}
+
+// ListPolicies mocks base method.
+func (m *MockRepository) ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicies", storeId)
+	ret0, _ := ret[0].([]entity.AllocationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPolicies indicates an expected call of ListPolicies.
+func (mr *MockRepositoryMockRecorder) ListPolicies(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockRepository)(nil).ListPolicies), storeId)
+}
+
+// RegisterPolicy mocks base method.
+func (m *MockRepository) RegisterPolicy(policy *entity.AllocationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterPolicy indicates an expected call of RegisterPolicy.
+func (mr *MockRepositoryMockRecorder) RegisterPolicy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPolicy", reflect.TypeOf((*MockRepository)(nil).RegisterPolicy), policy)
+}
+
+// SetStock mocks base method.
+func (m *MockRepository) SetStock(storeId entity.ID, sku string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", storeId, sku, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockRepositoryMockRecorder) SetStock(storeId, sku, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockRepository)(nil).SetStock), storeId, sku, quantity)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockRepository) UpdatePolicy(policy *entity.AllocationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockRepositoryMockRecorder) UpdatePolicy(policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockRepository)(nil).UpdatePolicy), policy)
+}
@@ -0,0 +1,245 @@
+// Package allocation implements the allocation policies, which distribute the true stock of a SKU
+// across the accounts of a store with buffers, percentages, caps and a priority account
+package allocation
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/store"
+	"go.uber.org/zap"
+)
+
+// Error definitions for allocation operations
+var (
+	// ErrPolicyNotFound is returned when the policy doesn't exist or belongs to another store
+	ErrPolicyNotFound = errors.New("allocation policy not found")
+	// ErrPolicyAlreadyExists is returned when the store already has a policy for the SKU
+	ErrPolicyAlreadyExists = errors.New("allocation policy already exists")
+	// ErrUnknownAccount is returned when a rule or the priority account is an account of another store
+	ErrUnknownAccount = errors.New("unknown account")
+)
+
+type AllocationService struct {
+	repo   Repository
+	store  store.UseCase
+	logger common.Logger
+}
+
+// NewAllocationService creates a new instance of AllocationService.
+//
+// Parameters:
+//   - repository: Repository of the policies and the true stock of the SKUs
+//   - storeUseCase: Store use case, used to validate the accounts of the policies
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *AllocationService: A new instance of AllocationService
+func NewAllocationService(repository Repository, storeUseCase store.UseCase, logger common.Logger) *AllocationService {
+	return &AllocationService{
+		repo:   repository,
+		store:  storeUseCase,
+		logger: logger,
+	}
+}
+
+// Allocate applies a change to the true stock of a SKU and returns its allocation.
+// The SKU uses its own policy or the default policy of the store. When its true stock
+// isn't tracked yet, it starts from the quantity published on the listings.
+//
+// Parameters:
+//   - input: AllocateDtoInput containing the SKU and the change of its stock
+//
+// Returns:
+//   - *Allocation: The allocation, nil when the SKU doesn't have a policy
+//   - error: Error if the policy or the stock can't be retrieved
+func (s *AllocationService) Allocate(input AllocateDtoInput) (*Allocation, error) {
+	sku := strings.TrimSpace(input.Sku)
+	policy, err := s.repo.FindPolicy(input.Store, sku)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the allocation policy", err, zap.String("store_id", input.Store.String()), zap.String("sku", sku))
+		return nil, err
+	}
+	if policy == nil {
+		return nil, nil
+	}
+
+	if input.Quantity != nil {
+		stock := *input.Quantity
+		if stock < 0 {
+			stock = 0
+		}
+		if err := s.repo.SetStock(input.Store, sku, stock); err != nil {
+			s.logger.Error("Fail to set the stock of the sku", err, zap.String("sku", sku), zap.Int("quantity", stock))
+			return nil, err
+		}
+		return &Allocation{Policy: policy, Stock: stock}, nil
+	}
+
+	delta := 0
+	if input.Delta != nil {
+		delta = *input.Delta
+	}
+	stock, err := s.repo.AddStock(input.Store, sku, delta, input.Published)
+	if err != nil {
+		s.logger.Error("Fail to update the stock of the sku", err, zap.String("sku", sku), zap.Int("delta", delta))
+		return nil, err
+	}
+	return &Allocation{Policy: policy, Stock: stock}, nil
+}
+
+// CreatePolicy defines how the stock of a SKU, or of every SKU, is distributed across the accounts.
+//
+// Parameters:
+//   - input: CreatePolicyDtoInput containing the SKU and the rules of the accounts
+//
+// Returns:
+//   - *entity.AllocationPolicy: The created policy
+//   - error: entity.ErrInvalidAllocationPolicy, entity.ErrInvalidAccountAllocation, ErrUnknownAccount or ErrPolicyAlreadyExists
+func (s *AllocationService) CreatePolicy(input CreatePolicyDtoInput) (*entity.AllocationPolicy, error) {
+	policy, err := entity.NewAllocationPolicy(input.Store, input.Sku, input.PriorityAccount, toAccountAllocations(input.Accounts))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateAccounts(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RegisterPolicy(policy); err != nil {
+		if !errors.Is(err, ErrPolicyAlreadyExists) {
+			s.logger.Error("Fail to register the allocation policy", err, zap.String("store_id", input.Store.String()), zap.String("sku", policy.Sku))
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdatePolicy replaces the SKU, the priority account and the rules of a policy.
+// The listings get the new quantities on the next sync of the SKU.
+//
+// Parameters:
+//   - input: UpdatePolicyDtoInput containing the ID of the policy and its new definition
+//
+// Returns:
+//   - *entity.AllocationPolicy: The updated policy
+//   - error: ErrPolicyNotFound, entity.ErrInvalidAllocationPolicy, entity.ErrInvalidAccountAllocation, ErrUnknownAccount or ErrPolicyAlreadyExists
+func (s *AllocationService) UpdatePolicy(input UpdatePolicyDtoInput) (*entity.AllocationPolicy, error) {
+	policy, err := s.GetPolicy(input.Store, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Define(input.Sku, input.PriorityAccount, toAccountAllocations(input.Accounts)); err != nil {
+		return nil, err
+	}
+	if err := s.validateAccounts(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePolicy(policy); err != nil {
+		if !errors.Is(err, ErrPolicyAlreadyExists) && !errors.Is(err, ErrPolicyNotFound) {
+			s.logger.Error("Fail to update the allocation policy", err, zap.String("policy_id", policy.ID.String()))
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetPolicy retrieves a policy of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - policyId: ID of the policy
+//
+// Returns:
+//   - *entity.AllocationPolicy: The policy
+//   - error: ErrPolicyNotFound if the policy doesn't exist or belongs to another store
+func (s *AllocationService) GetPolicy(storeId, policyId entity.ID) (*entity.AllocationPolicy, error) {
+	policy, err := s.repo.GetPolicy(storeId, policyId)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the allocation policy", err, zap.String("policy_id", policyId.String()))
+		return nil, err
+	}
+	if policy == nil {
+		return nil, ErrPolicyNotFound
+	}
+	return policy, nil
+}
+
+// ListPolicies lists the policies of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []entity.AllocationPolicy: The policies, ordered by the SKU
+//   - error: Error if the policies can't be retrieved
+func (s *AllocationService) ListPolicies(storeId entity.ID) ([]entity.AllocationPolicy, error) {
+	policies, err := s.repo.ListPolicies(storeId)
+	if err != nil {
+		s.logger.Error("Fail to list the allocation policies", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return policies, nil
+}
+
+// DeletePolicy removes a policy, the quantities of the listings aren't changed.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - policyId: ID of the policy
+//
+// Returns:
+//   - error: ErrPolicyNotFound if the policy doesn't exist or belongs to another store
+func (s *AllocationService) DeletePolicy(storeId, policyId entity.ID) error {
+	if err := s.repo.DeletePolicy(storeId, policyId); err != nil {
+		if !errors.Is(err, ErrPolicyNotFound) {
+			s.logger.Error("Fail to delete the allocation policy", err, zap.String("policy_id", policyId.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// validateAccounts verifies that the rules and the priority account are accounts of the store.
+//
+// Parameters:
+//   - policy: The policy to be saved
+//
+// Returns:
+//   - error: ErrUnknownAccount if an account belongs to another store
+func (s *AllocationService) validateAccounts(policy *entity.AllocationPolicy) error {
+	credentials, err := s.store.RetrieveMeliCredentialsFromStoreID(policy.StoreID)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", policy.StoreID.String()))
+		return err
+	}
+	accounts := make(map[entity.ID]bool, len(*credentials))
+	for _, c := range *credentials {
+		accounts[c.ID] = true
+	}
+
+	if policy.PriorityAccount != nil && !accounts[*policy.PriorityAccount] {
+		return ErrUnknownAccount
+	}
+	for _, a := range policy.Accounts {
+		if !accounts[a.AccountID] {
+			return ErrUnknownAccount
+		}
+	}
+	return nil
+}
+
+func toAccountAllocations(input []AccountAllocationDtoInput) []entity.AccountAllocation {
+	accounts := make([]entity.AccountAllocation, len(input))
+	for i, a := range input {
+		accounts[i] = entity.AccountAllocation{
+			AccountID:   a.AccountID,
+			Buffer:      a.Buffer,
+			Percentage:  a.Percentage,
+			MaxQuantity: a.MaxQuantity,
+		}
+	}
+	return accounts
+}
@@ -0,0 +1,170 @@
+package allocation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/allocation"
+	mock_allocation "github.com/Vractos/kloni/usecases/allocation/mock"
+	common "github.com/Vractos/kloni/usecases/common"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/store"
+	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type Mocks struct {
+	repo   *mock_allocation.MockRepository
+	store  *mock_store.MockUseCase
+	logger *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:   mock_allocation.NewMockRepository(ctrl),
+		store:  mock_store.NewMockUseCase(ctrl),
+		logger: common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newAllocationService() *allocation.AllocationService {
+	return allocation.NewAllocationService(m.repo, m.store, m.logger)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// TestAllocate tests the changes of the true stock of the SKUs.
+// It verifies:
+// 1. The SKUs without a policy aren't tracked
+// 2. A delta is added to the stock, which starts from the published quantity
+// 3. An absolute quantity replaces the stock
+// 4. A failure of the repository is returned
+func TestAllocate(t *testing.T) {
+	storeId, accountId := entity.NewID(), entity.NewID()
+	policy, _ := entity.NewAllocationPolicy(storeId, "", nil, []entity.AccountAllocation{{AccountID: accountId, Buffer: 2}})
+
+	t.Run("sku without policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().FindPolicy(storeId, "SKU-1").Return(nil, nil)
+
+		alloc, err := m.newAllocationService().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: " SKU-1 ", Delta: intPtr(-1)})
+		if err != nil || alloc != nil {
+			t.Errorf("Allocate() = %+v, %v, want nil", alloc, err)
+		}
+	})
+
+	t.Run("delta", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().FindPolicy(storeId, "SKU-1").Return(policy, nil)
+		m.repo.EXPECT().AddStock(storeId, "SKU-1", -1, 8).Return(7, nil)
+
+		alloc, err := m.newAllocationService().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "SKU-1", Published: 8, Delta: intPtr(-1)})
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if alloc.Stock != 7 || alloc.Quantity(accountId) != 5 {
+			t.Errorf("Allocate() stock = %d, quantity = %d, want 7 and 5", alloc.Stock, alloc.Quantity(accountId))
+		}
+	})
+
+	t.Run("absolute quantity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().FindPolicy(storeId, "SKU-1").Return(policy, nil)
+		m.repo.EXPECT().SetStock(storeId, "SKU-1", 10).Return(nil)
+
+		alloc, err := m.newAllocationService().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "SKU-1", Published: 8, Quantity: intPtr(10)})
+		if err != nil || alloc.Stock != 10 {
+			t.Errorf("Allocate() = %+v, %v, want a stock of 10", alloc, err)
+		}
+	})
+
+	t.Run("stock can't be updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		repoErr := errors.New("db error")
+		m.repo.EXPECT().FindPolicy(storeId, "SKU-1").Return(policy, nil)
+		m.repo.EXPECT().AddStock(storeId, "SKU-1", 0, 8).Return(0, repoErr)
+		m.logger.EXPECT().Error("Fail to update the stock of the sku", repoErr, zap.String("sku", "SKU-1"), zap.Int("delta", 0))
+
+		if _, err := m.newAllocationService().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "SKU-1", Published: 8}); !errors.Is(err, repoErr) {
+			t.Errorf("Allocate() error = %v, want %v", err, repoErr)
+		}
+	})
+}
+
+func TestCreatePolicy(t *testing.T) {
+	storeId, accountId := entity.NewID(), entity.NewID()
+	credentials := &[]store.Credentials{{ID: accountId, OwnerID: storeId, MeliCredential: &common.MeliCredential{}}}
+	input := allocation.CreatePolicyDtoInput{
+		Store:           storeId,
+		Sku:             "SKU-1",
+		PriorityAccount: &accountId,
+		Accounts:        []allocation.AccountAllocationDtoInput{{AccountID: accountId, Buffer: 2}},
+	}
+
+	t.Run("policy created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.repo.EXPECT().RegisterPolicy(gomock.Any()).Return(nil)
+
+		policy, err := m.newAllocationService().CreatePolicy(input)
+		if err != nil {
+			t.Fatalf("CreatePolicy() error = %v", err)
+		}
+		want := []entity.AccountAllocation{{AccountID: accountId, Buffer: 2, Percentage: 100}}
+		if diff := cmp.Diff(want, policy.Accounts); diff != "" {
+			t.Errorf("CreatePolicy() accounts mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("priority account of another store", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		other := input
+		otherAccount := entity.NewID()
+		other.PriorityAccount = &otherAccount
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+
+		if _, err := m.newAllocationService().CreatePolicy(other); !errors.Is(err, allocation.ErrUnknownAccount) {
+			t.Errorf("CreatePolicy() error = %v, want %v", err, allocation.ErrUnknownAccount)
+		}
+	})
+
+	t.Run("policy of the sku already exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.repo.EXPECT().RegisterPolicy(gomock.Any()).Return(allocation.ErrPolicyAlreadyExists)
+
+		if _, err := m.newAllocationService().CreatePolicy(input); !errors.Is(err, allocation.ErrPolicyAlreadyExists) {
+			t.Errorf("CreatePolicy() error = %v, want %v", err, allocation.ErrPolicyAlreadyExists)
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		invalid := input
+		invalid.Accounts = []allocation.AccountAllocationDtoInput{{AccountID: accountId, Percentage: 150}}
+		if _, err := m.newAllocationService().CreatePolicy(invalid); !errors.Is(err, entity.ErrInvalidAccountAllocation) {
+			t.Errorf("CreatePolicy() error = %v, want %v", err, entity.ErrInvalidAccountAllocation)
+		}
+	})
+}
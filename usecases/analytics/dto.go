@@ -0,0 +1,19 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type SalesReportDtoInput struct {
+	Store     entity.ID
+	Dimension Dimension
+	// Daily when empty
+	Interval Interval
+	// The last 30 days when empty
+	From    *time.Time
+	To      *time.Time
+	Account *entity.ID
+	Sku     string
+}
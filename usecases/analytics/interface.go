@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+// Dimension is what the sales are grouped by
+type Dimension string
+
+const (
+	BySku         Dimension = "sku"
+	ByAccount     Dimension = "account"
+	ByListingType Dimension = "listing_type"
+)
+
+// Interval is the size of the time buckets of a report
+type Interval string
+
+const (
+	Daily   Interval = "day"
+	Weekly  Interval = "week"
+	Monthly Interval = "month"
+)
+
+type UseCase interface {
+	// SalesReport aggregates the units sold and the revenue of a store,
+	// grouped by a dimension over time buckets.
+	// Cancelled and invalid orders aren't counted.
+	//
+	// Parameters:
+	//   - input: SalesReportDtoInput containing the dimension, the interval and the filters
+	//
+	// Returns:
+	//   - *SalesReport: The buckets of the report, ordered by the period and the key
+	//   - error: ErrInvalidDimension, ErrInvalidInterval or ErrInvalidPeriod if the input is invalid, nil otherwise
+	SalesReport(input SalesReportDtoInput) (*SalesReport, error)
+}
+
+// SalesBucket holds the sales of a key (SKU, account or listing type) in a period
+type SalesBucket struct {
+	// Start of the period
+	Period time.Time
+	Key    string
+	Units  int
+	// Sum of the unit price times the quantity of the items
+	Revenue float64
+	// The revenue isn't converted, so each currency has its own bucket
+	CurrencyID string
+}
+
+type SalesReport struct {
+	Dimension Dimension
+	Interval  Interval
+	From      time.Time
+	To        time.Time
+	Buckets   []SalesBucket
+}
+
+// SalesFilter filters the sales of a store, the empty fields are ignored
+type SalesFilter struct {
+	Store     entity.ID
+	Dimension Dimension
+	Interval  Interval
+	From      time.Time
+	To        time.Time
+	Account   *entity.ID
+	Sku       string
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoReader interface {
+	// Aggregates the sales of the orders created in [From, To)
+	Sales(filter SalesFilter) ([]SalesBucket, error)
+}
+
+type Repository interface {
+	RepoReader
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/analytics/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/analytics/interface.go -destination=usecases/analytics/mock/service_mock.go
+//
+
+// Package mock_analytics is a generated GoMock package.
+package mock_analytics
+
+import (
+	reflect "reflect"
+
+	analytics "github.com/Vractos/kloni/usecases/analytics"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// SalesReport mocks base method.
+func (m *MockUseCase) SalesReport(input analytics.SalesReportDtoInput) (*analytics.SalesReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SalesReport", input)
+	ret0, _ := ret[0].(*analytics.SalesReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SalesReport indicates an expected call of SalesReport.
+func (mr *MockUseCaseMockRecorder) SalesReport(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SalesReport", reflect.TypeOf((*MockUseCase)(nil).SalesReport), input)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// Sales mocks base method.
+func (m *MockRepoReader) Sales(filter analytics.SalesFilter) ([]analytics.SalesBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sales", filter)
+	ret0, _ := ret[0].([]analytics.SalesBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sales indicates an expected call of Sales.
+func (mr *MockRepoReaderMockRecorder) Sales(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sales", reflect.TypeOf((*MockRepoReader)(nil).Sales), filter)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Sales mocks base method.
+func (m *MockRepository) Sales(filter analytics.SalesFilter) ([]analytics.SalesBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sales", filter)
+	ret0, _ := ret[0].([]analytics.SalesBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sales indicates an expected call of Sales.
+func (mr *MockRepositoryMockRecorder) Sales(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sales", reflect.TypeOf((*MockRepository)(nil).Sales), filter)
+}
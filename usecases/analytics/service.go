@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vractos/kloni/usecases/common"
+	"go.uber.org/zap"
+)
+
+// Error definitions for analytics operations
+var (
+	// ErrInvalidDimension is returned when the sales can't be grouped by the dimension
+	ErrInvalidDimension = errors.New("invalid dimension")
+	// ErrInvalidInterval is returned when the interval of the buckets isn't supported
+	ErrInvalidInterval = errors.New("invalid interval")
+	// ErrInvalidPeriod is returned when the period of the report is empty or too long
+	ErrInvalidPeriod = errors.New("invalid period")
+)
+
+const (
+	// defaultPeriod is the period of the report when it isn't informed
+	defaultPeriod = 30 * 24 * time.Hour
+	// maxPeriod is the longest period of a report
+	maxPeriod = 366 * 24 * time.Hour
+)
+
+type AnalyticsService struct {
+	repo   Repository
+	logger common.Logger
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsService.
+//
+// Parameters:
+//   - repository: Repository used to aggregate the sales
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *AnalyticsService: A new instance of AnalyticsService
+func NewAnalyticsService(repository Repository, logger common.Logger) *AnalyticsService {
+	return &AnalyticsService{
+		repo:   repository,
+		logger: logger,
+	}
+}
+
+// SalesReport aggregates the units sold and the revenue of a store,
+// grouped by a dimension over time buckets.
+//
+// Parameters:
+//   - input: SalesReportDtoInput containing the dimension, the interval and the filters
+//
+// Returns:
+//   - *SalesReport: The buckets of the report, ordered by the period and the key
+//   - error: ErrInvalidDimension, ErrInvalidInterval or ErrInvalidPeriod if the input is invalid, nil otherwise
+func (a *AnalyticsService) SalesReport(input SalesReportDtoInput) (*SalesReport, error) {
+	switch input.Dimension {
+	case BySku, ByAccount, ByListingType:
+	default:
+		return nil, ErrInvalidDimension
+	}
+
+	interval := input.Interval
+	switch interval {
+	case "":
+		interval = Daily
+	case Daily, Weekly, Monthly:
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	to := time.Now().UTC()
+	if input.To != nil {
+		to = input.To.UTC()
+	}
+	from := to.Add(-defaultPeriod)
+	if input.From != nil {
+		from = input.From.UTC()
+	}
+	if !from.Before(to) || to.Sub(from) > maxPeriod {
+		return nil, ErrInvalidPeriod
+	}
+
+	buckets, err := a.repo.Sales(SalesFilter{
+		Store:     input.Store,
+		Dimension: input.Dimension,
+		Interval:  interval,
+		From:      from,
+		To:        to,
+		Account:   input.Account,
+		Sku:       input.Sku,
+	})
+	if err != nil {
+		a.logger.Error("Fail to aggregate the sales", err,
+			zap.String("store_id", input.Store.String()),
+			zap.String("dimension", string(input.Dimension)),
+		)
+		return nil, err
+	}
+
+	return &SalesReport{
+		Dimension: input.Dimension,
+		Interval:  interval,
+		From:      from,
+		To:        to,
+		Buckets:   buckets,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/analytics"
+	mock_analytics "github.com/Vractos/kloni/usecases/analytics/mock"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestSalesReport(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	buckets := []analytics.SalesBucket{
+		{Period: from, Key: "SKU-1", Units: 3, Revenue: 149.7, CurrencyID: "BRL"},
+		{Period: from.AddDate(0, 0, 7), Key: "SKU-1", Units: 1, Revenue: 49.9, CurrencyID: "BRL"},
+	}
+
+	t.Run("report with filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock_analytics.NewMockRepository(ctrl)
+		service := analytics.NewAnalyticsService(mockRepo, common_mock.NewMockLogger(ctrl))
+
+		mockRepo.EXPECT().Sales(analytics.SalesFilter{
+			Store:     storeId,
+			Dimension: analytics.BySku,
+			Interval:  analytics.Weekly,
+			From:      from,
+			To:        to,
+			Account:   &accountId,
+			Sku:       "SKU-1",
+		}).Return(buckets, nil)
+
+		report, err := service.SalesReport(analytics.SalesReportDtoInput{
+			Store:     storeId,
+			Dimension: analytics.BySku,
+			Interval:  analytics.Weekly,
+			From:      &from,
+			To:        &to,
+			Account:   &accountId,
+			Sku:       "SKU-1",
+		})
+		if err != nil {
+			t.Fatalf("SalesReport() error = %v", err)
+		}
+		if diff := cmp.Diff(buckets, report.Buckets); diff != "" {
+			t.Errorf("SalesReport() buckets mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("default interval and period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock_analytics.NewMockRepository(ctrl)
+		service := analytics.NewAnalyticsService(mockRepo, common_mock.NewMockLogger(ctrl))
+
+		mockRepo.EXPECT().Sales(gomock.Any()).DoAndReturn(func(filter analytics.SalesFilter) ([]analytics.SalesBucket, error) {
+			if filter.Interval != analytics.Daily {
+				t.Errorf("Interval = %v, want %v", filter.Interval, analytics.Daily)
+			}
+			if period := filter.To.Sub(filter.From); period != 30*24*time.Hour {
+				t.Errorf("period = %v, want 30 days", period)
+			}
+			return nil, nil
+		})
+
+		report, err := service.SalesReport(analytics.SalesReportDtoInput{Store: storeId, Dimension: analytics.ByListingType})
+		if err != nil {
+			t.Fatalf("SalesReport() error = %v", err)
+		}
+		if report.Interval != analytics.Daily {
+			t.Errorf("report Interval = %v, want %v", report.Interval, analytics.Daily)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := analytics.NewAnalyticsService(mock_analytics.NewMockRepository(ctrl), common_mock.NewMockLogger(ctrl))
+		longAgo := to.AddDate(-2, 0, 0)
+
+		tests := []struct {
+			name    string
+			input   analytics.SalesReportDtoInput
+			wantErr error
+		}{
+			{
+				name:    "unknown dimension",
+				input:   analytics.SalesReportDtoInput{Dimension: "buyer"},
+				wantErr: analytics.ErrInvalidDimension,
+			},
+			{
+				name:    "unknown interval",
+				input:   analytics.SalesReportDtoInput{Dimension: analytics.BySku, Interval: "hour"},
+				wantErr: analytics.ErrInvalidInterval,
+			},
+			{
+				name:    "from after to",
+				input:   analytics.SalesReportDtoInput{Dimension: analytics.BySku, From: &to, To: &from},
+				wantErr: analytics.ErrInvalidPeriod,
+			},
+			{
+				name:    "period too long",
+				input:   analytics.SalesReportDtoInput{Dimension: analytics.ByAccount, From: &longAgo, To: &to},
+				wantErr: analytics.ErrInvalidPeriod,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := service.SalesReport(tt.input); !errors.Is(err, tt.wantErr) {
+					t.Errorf("SalesReport() error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock_analytics.NewMockRepository(ctrl)
+		mockLogger := common_mock.NewMockLogger(ctrl)
+		service := analytics.NewAnalyticsService(mockRepo, mockLogger)
+
+		repoErr := errors.New("db error")
+		mockRepo.EXPECT().Sales(gomock.Any()).Return(nil, repoErr)
+		mockLogger.EXPECT().Error("Fail to aggregate the sales", repoErr,
+			zap.String("store_id", storeId.String()),
+			zap.String("dimension", string(analytics.ByAccount)),
+		)
+
+		if _, err := service.SalesReport(analytics.SalesReportDtoInput{Store: storeId, Dimension: analytics.ByAccount}); !errors.Is(err, repoErr) {
+			t.Errorf("SalesReport() error = %v, want %v", err, repoErr)
+		}
+	})
+}
@@ -5,9 +5,9 @@ import (
 )
 
 type CloneAnnouncementDtoInput struct {
-	RootID          string      `json:"root_id"`
-	Titles          []string    `json:"titles"`
-	RootAccountID   entity.ID   `json:"account_id"`
+	RootID          string      `json:"root_id" validate:"required"`
+	Titles          []string    `json:"titles" validate:"dive,required"`
+	RootAccountID   entity.ID   `json:"account_id" validate:"required"`
 	DestinyAccounts []entity.ID `json:"destiny_accounts"`
 }
 
@@ -16,7 +16,7 @@ type GetAnnouncementsDtoInput struct {
 }
 
 type ImportAnnouncementDtoInput struct {
-	AnnouncementID string    `json:"announcement_id"`
-	AccountOrigin  entity.ID `json:"account_id_origin"`
-	AccountDestiny entity.ID `json:"account_id_destiny"`
+	AnnouncementID string    `json:"announcement_id" validate:"required"`
+	AccountOrigin  entity.ID `json:"account_id_origin" validate:"required"`
+	AccountDestiny entity.ID `json:"account_id_destiny" validate:"required"`
 }
@@ -7,6 +7,8 @@ type AnnouncementError struct {
 	AnnouncementID string
 	IsAbleToRetry  bool
 	Sku            string
+	// Cause of the error, nil when it isn't known
+	Err error
 }
 
 func (a *AnnouncementError) Error() string {
@@ -18,3 +20,7 @@ func (a *AnnouncementError) Error() string {
 
 	return fmt.Sprintf("Message: %s", a.Message)
 }
+
+func (a *AnnouncementError) Unwrap() error {
+	return a.Err
+}
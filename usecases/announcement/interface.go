@@ -1,23 +1,43 @@
 package announcement
 
 import (
+	"context"
+
 	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/usecases/common"
 	"github.com/Vractos/kloni/usecases/store"
 )
 
 type Announcements struct {
-	AccountID     entity.ID
-	AccountName   string
+	AccountID   entity.ID
+	AccountName string
+	// SKU of the product in the account, the alias of the account when the searched SKU is mapped
+	Sku           string
 	Announcements *[]common.MeliAnnouncement
 }
 
+// HasSku reports whether a variation has the SKU of the product in the account,
+// which is an alias when the searched SKU is mapped
+//
+// Parameters:
+//   - variation: The variation of a listing of the account
+//   - sku: The searched SKU
+//
+// Returns:
+//   - bool: True if the variation is the product of the SKU
+func (a Announcements) HasSku(variation common.MeliVariation, sku string) bool {
+	if a.Sku != "" {
+		sku = a.Sku
+	}
+	return variation.Sku != "" && variation.Sku == sku
+}
+
 type UseCase interface {
 	// Retrieve announcements from a specific account
 	RetrieveAnnouncements(sku string, credentials store.Credentials) (*[]common.MeliAnnouncement, error)
 	// Retrieve announcements from all accounts that have the same SKU
 	RetrieveAnnouncementsFromAllAccounts(sku string, credentials *[]store.Credentials) (*[]Announcements, error)
-	UpdateQuantity(id string, quantity int, credentials store.Credentials, variationIDs ...int) error
+	UpdateQuantity(ctx context.Context, id string, quantity int, credentials store.Credentials, variationIDs ...int) error
 	CloneAnnouncement(input CloneAnnouncementDtoInput, credentials *[]store.Credentials) error
 	ImportAnnouncement(input ImportAnnouncementDtoInput, credentials *[]store.Credentials) error
 }
@@ -10,6 +10,7 @@
 package mock_announcement
 
 import (
+	context "context"
 	reflect "reflect"
 
 	announcement "github.com/Vractos/kloni/usecases/announcement"
@@ -100,9 +101,9 @@ func (mr *MockUseCaseMockRecorder) RetrieveAnnouncementsFromAllAccounts(sku, cre
 }
 
 // UpdateQuantity mocks base method.
-func (m *MockUseCase) UpdateQuantity(id string, quantity int, credentials store.Credentials, variationIDs ...int) error {
+func (m *MockUseCase) UpdateQuantity(ctx context.Context, id string, quantity int, credentials store.Credentials, variationIDs ...int) error {
 	m.ctrl.T.Helper()
-	varargs := []any{id, quantity, credentials}
+	varargs := []any{ctx, id, quantity, credentials}
 	for _, a := range variationIDs {
 		varargs = append(varargs, a)
 	}
@@ -112,8 +113,8 @@ func (m *MockUseCase) UpdateQuantity(id string, quantity int, credentials store.
 }
 
 // UpdateQuantity indicates an expected call of UpdateQuantity.
-func (mr *MockUseCaseMockRecorder) UpdateQuantity(id, quantity, credentials any, variationIDs ...any) *gomock.Call {
+func (mr *MockUseCaseMockRecorder) UpdateQuantity(ctx, id, quantity, credentials any, variationIDs ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{id, quantity, credentials}, variationIDs...)
+	varargs := append([]any{ctx, id, quantity, credentials}, variationIDs...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuantity", reflect.TypeOf((*MockUseCase)(nil).UpdateQuantity), varargs...)
 }
@@ -1,28 +1,39 @@
 package announcement
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alias"
 	"github.com/Vractos/kloni/usecases/common"
 	"github.com/Vractos/kloni/usecases/store"
+	"github.com/Vractos/kloni/usecases/webhook"
 	"github.com/Vractos/kloni/utils"
 	"go.uber.org/zap"
 )
 
 type AnnouncementService struct {
-	meli   common.MercadoLivre
-	store  store.UseCase
-	logger metrics.Logger
+	meli     common.MercadoLivre
+	store    store.UseCase
+	price    common.PriceConverter
+	webhook  webhook.Publisher
+	resolver alias.Resolver
+	logger   metrics.Logger
 }
 
-func NewAnnouncementService(mercadolivre common.MercadoLivre, storeUseCase store.UseCase, logger metrics.Logger) *AnnouncementService {
+func NewAnnouncementService(mercadolivre common.MercadoLivre, storeUseCase store.UseCase, priceConverter common.PriceConverter, publisher webhook.Publisher, resolver alias.Resolver, logger metrics.Logger) *AnnouncementService {
 	return &AnnouncementService{
-		meli:   mercadolivre,
-		store:  storeUseCase,
-		logger: logger,
+		meli:     mercadolivre,
+		store:    storeUseCase,
+		price:    priceConverter,
+		webhook:  publisher,
+		resolver: resolver,
+		logger:   logger,
 	}
 }
 
@@ -76,10 +87,32 @@ func (a *AnnouncementService) RetrieveAnnouncements(sku string, credentials stor
 	return anns, err
 }
 
+// RetrieveAnnouncementsFromAllAccounts implements UseCase.
+// The SKU is resolved through the SKU mappings of the store, so each account is searched by its own SKU
+// for the product, and its listings pinned to the product are included.
 func (a *AnnouncementService) RetrieveAnnouncementsFromAllAccounts(sku string, credentials *[]store.Credentials) (*[]Announcements, error) {
+	mapping, err := a.resolveSku(sku, credentials)
+	if err != nil {
+		return nil, &AnnouncementError{
+			Message:       "Error to resolve the sku",
+			Sku:           sku,
+			IsAbleToRetry: true,
+		}
+	}
+
 	announcements := make([]Announcements, len(*credentials))
 	for i, cred := range *credentials {
-		anns, err := a.RetrieveAnnouncements(sku, cred)
+		accountSku := sku
+		var pinned []string
+		if mapping != nil {
+			accountSku = mapping.SkuFor(cred.ID)
+			pinned = mapping.ListingsFor(cred.ID)
+		}
+
+		anns, err := a.RetrieveAnnouncements(accountSku, cred)
+		if err == nil && len(pinned) > 0 {
+			anns, err = a.appendPinned(anns, pinned, cred)
+		}
 		if err != nil {
 			cErr := &AnnouncementError{
 				Message: "Error to retrieve announcements",
@@ -92,6 +125,7 @@ func (a *AnnouncementService) RetrieveAnnouncementsFromAllAccounts(sku string, c
 		announcements[i] = Announcements{
 			AccountID:     cred.ID,
 			AccountName:   utils.GetOrDefault(cred.AccountName, ""),
+			Sku:           accountSku,
 			Announcements: anns,
 		}
 	}
@@ -99,13 +133,75 @@ func (a *AnnouncementService) RetrieveAnnouncementsFromAllAccounts(sku string, c
 	return &announcements, nil
 }
 
-func (a *AnnouncementService) UpdateQuantity(id string, newQuantity int, credentials store.Credentials, variationIDs ...int) error {
-	err := a.meli.UpdateQuantity(newQuantity, id, credentials.AccessToken, variationIDs...)
+// resolveSku retrieves the mapping of a SKU in the store of the credentials.
+//
+// Parameters:
+//   - sku: Canonical SKU or SKU of an alias
+//   - credentials: Credentials of the accounts of the store
+//
+// Returns:
+//   - *entity.SkuMapping: The mapping, nil when the SKU isn't mapped
+//   - error: Error if the mapping can't be retrieved
+func (a *AnnouncementService) resolveSku(sku string, credentials *[]store.Credentials) (*entity.SkuMapping, error) {
+	if len(*credentials) == 0 {
+		return nil, nil
+	}
+	// The error is logged by the alias use case
+	return a.resolver.Resolve((*credentials)[0].OwnerID, sku)
+}
+
+// appendPinned adds the listings pinned to a product to the listings found by its SKU.
+//
+// Parameters:
+//   - anns: Listings found by the SKU, nil when there's none
+//   - pinned: IDs of the pinned listings
+//   - credentials: Credentials of the account of the listings
+//
+// Returns:
+//   - *[]common.MeliAnnouncement: The listings, without repetitions
+//   - error: Error if the pinned listings can't be retrieved
+func (a *AnnouncementService) appendPinned(anns *[]common.MeliAnnouncement, pinned []string, credentials store.Credentials) (*[]common.MeliAnnouncement, error) {
+	found := []common.MeliAnnouncement{}
+	if anns != nil {
+		found = *anns
+	}
+	missing := []string{}
+	for _, id := range pinned {
+		included := false
+		for _, ann := range found {
+			if ann.ID == id {
+				included = true
+				break
+			}
+		}
+		if !included {
+			missing = append(missing, id)
+		}
+	}
+
+	for _, ids := range utils.Chunk(missing, 10) {
+		pinnedAnns, err := a.meli.GetAnnouncements(ids, credentials.AccessToken)
+		if err != nil {
+			cErr := &AnnouncementError{
+				Message:       "Error to retrieve the pinned announcements",
+				IsAbleToRetry: true,
+			}
+			a.logger.Error(cErr.Message, err, zap.Strings("announcements_ids", ids))
+			return nil, cErr
+		}
+		found = append(found, *pinnedAnns...)
+	}
+	return &found, nil
+}
+
+func (a *AnnouncementService) UpdateQuantity(ctx context.Context, id string, newQuantity int, credentials store.Credentials, variationIDs ...int) error {
+	err := a.meli.UpdateQuantity(ctx, newQuantity, id, credentials.AccessToken, variationIDs...)
 	if err != nil {
 		cErr := &AnnouncementError{
 			Message:        "Error to update quantity",
 			AnnouncementID: id,
 			IsAbleToRetry:  true,
+			Err:            err,
 		}
 		a.logger.Error(cErr.Message, err, zap.String("announcement_id", id))
 		return cErr
@@ -135,7 +231,11 @@ func (a *AnnouncementService) getAnnouncement(id string, credentials store.Crede
 }
 
 // CloneAnnouncement implements UseCase
-func (a *AnnouncementService) CloneAnnouncement(input CloneAnnouncementDtoInput, credentials *[]store.Credentials) error {
+func (a *AnnouncementService) CloneAnnouncement(input CloneAnnouncementDtoInput, credentials *[]store.Credentials) (err error) {
+	defer func(start time.Time) {
+		metrics.ObserveCloneJob(err, time.Since(start))
+	}(time.Now())
+
 	credMap, err := utils.HashMap(credentials, "ID")
 	if err != nil {
 		a.logger.Error("Error to create credentials map", err)
@@ -175,7 +275,13 @@ func (a *AnnouncementService) CloneAnnouncement(input CloneAnnouncementDtoInput,
 	for _, id := range input.DestinyAccounts {
 		credential := findCredentialsByID(id, credMap)
 		for _, ans := range newAnns {
-			jsonAnn, err := json.Marshal(ans)
+			siteAnn, err := a.adaptToSite(ans, ann.SiteID, credential.SiteID)
+			if err != nil {
+				a.logger.Error("Error to adapt the clone to the site of the account", err, zap.String("announcement_id", input.RootID), zap.String("account_id", id.String()))
+				return errors.New("error to adapt the clone to the site of the account")
+			}
+
+			jsonAnn, err := json.Marshal(siteAnn)
 			if err != nil {
 				a.logger.Error("Error to marshal announcement json", err, zap.String("announcement_id", input.RootID))
 				return errors.New("error to marshal announcement json")
@@ -191,6 +297,7 @@ func (a *AnnouncementService) CloneAnnouncement(input CloneAnnouncementDtoInput,
 			}
 
 			a.logger.Info("New clone", zap.String("new_announcement_id", *rAnn))
+			a.publishCloneCreated(credential, input.RootID, *rAnn, siteAnn.Title, ann.Sku)
 
 			err = a.meli.AddDescription(ann.Description, *rAnn, credential.AccessToken)
 			if err != nil {
@@ -229,6 +336,12 @@ func (a *AnnouncementService) ImportAnnouncement(input ImportAnnouncementDtoInpu
 		return err
 	}
 
+	newAnn, err = a.adaptToSite(*newAnn, ann.SiteID, credential.SiteID)
+	if err != nil {
+		a.logger.Error("Error to adapt the announcement to the site of the account", err, zap.String("announcement_id", input.AnnouncementID))
+		return errors.New("error to adapt the announcement to the site of the account")
+	}
+
 	jsonAnn, err := json.Marshal(newAnn)
 
 	rAnn, err := a.meli.PublishAnnouncement(jsonAnn, credential.AccessToken)
@@ -288,6 +401,52 @@ func (a *AnnouncementService) cloneCompatibilityProductsFromDiffAccounts(rootAnn
 	return nil
 }
 
+// adaptToSite adapts an announcement to be published in the site of another account.
+// The price is converted to the currency of the site and, since the category IDs
+// differ between sites, the category is predicted from the title.
+func (a *AnnouncementService) adaptToSite(ann entity.Announcement, originSite, destinySite string) (*entity.Announcement, error) {
+	origin, destiny := entity.SiteOrDefault(originSite), entity.SiteOrDefault(destinySite)
+	if origin == destiny {
+		return &ann, nil
+	}
+	if !destiny.IsValid() {
+		return nil, fmt.Errorf("site %s isn't supported", destiny)
+	}
+
+	price, err := a.price.Convert(ann.Price, origin.Currency(), destiny.Currency())
+	if err != nil {
+		a.logger.Error("Error to convert the price", err, zap.String("from", origin.Currency()), zap.String("to", destiny.Currency()))
+		return nil, err
+	}
+
+	category, err := a.meli.PredictCategory(string(destiny), ann.Title)
+	if err != nil {
+		a.logger.Error("Error to find the category in the site", err, zap.String("site_id", string(destiny)), zap.String("category_id", ann.CategoryID))
+		return nil, err
+	}
+
+	ann.ChangeSite(destiny, price, category)
+	return &ann, nil
+}
+
+// Publishes a new clone to the webhooks of the store, a failure doesn't stop the cloning
+func (a *AnnouncementService) publishCloneCreated(credentials *store.Credentials, rootId, announcementId, title, sku string) {
+	event := entity.NewEvent(credentials.OwnerID, entity.CloneCreated, entity.CloneCreatedData{
+		AccountID:      credentials.ID,
+		RootID:         rootId,
+		AnnouncementID: announcementId,
+		Title:          title,
+		Sku:            sku,
+	})
+	if err := a.webhook.Publish(event); err != nil {
+		a.logger.Warn("Fail to publish the event",
+			zap.String("event_type", string(event.Type)),
+			zap.String("announcement_id", announcementId),
+			zap.Error(err),
+		)
+	}
+}
+
 func findCredentialsByID(id entity.ID, hashMap map[interface{}]store.Credentials) *store.Credentials {
 	if val, ok := hashMap[id]; ok {
 		return &val
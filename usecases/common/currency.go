@@ -0,0 +1,7 @@
+package common
+
+// PriceConverter converts prices between currencies when announcements are
+// cloned between sites
+type PriceConverter interface {
+	Convert(price float64, from, to string) (float64, error)
+}
@@ -1,10 +1,16 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"image"
 	"time"
 )
 
+// ErrMeliTimeout is returned when Mercado Livre didn't answer a request in time,
+// the change it requested may have been applied anyway
+var ErrMeliTimeout = errors.New("mercado livre didn't answer in time")
+
 /*
 ###################################
 ###################################
@@ -19,6 +25,15 @@ type MeliCredential struct {
 	UserID       string
 	RefreshToken string
 	UpdatedAt    time.Time
+	// Site of the account, e.g. MLB
+	SiteID string
+}
+
+type MeliUser struct {
+	ID        string
+	Nickname  string
+	SiteID    string
+	CountryID string
 }
 
 type OrderStatus string
@@ -42,18 +57,56 @@ type OrderItem struct {
 	Sku         string
 	Quantity    int
 	VariationID int
+	UnitPrice   float64
+	CurrencyID  string
+	// Listing type of the announcement when it was sold, e.g. gold_special
+	ListingTypeID string
+	// Attribute combination of the sold variation, empty when the announcement doesn't have variations
+	VariationAttributes []MeliVariationAttribute
 }
 
 type MeliOrder struct {
 	ID string
 	// Can be converted to time
 	DateCreated string
-	Status      OrderStatus
-	Items       []OrderItem
+	// Can be converted to time, empty if the order isn't closed
+	DateClosed string
+	// Can be converted to time
+	LastUpdated   string
+	Status        OrderStatus
+	Items         []OrderItem
+	TotalAmount   float64
+	PaidAmount    float64
+	CurrencyID    string
+	PackID        string
+	ShippingID    string
+	BuyerNickname string
+}
+
+// MeliPack groups the orders of a cart, bought together by a buyer
+type MeliPack struct {
+	ID       string
+	OrderIDs []string
+}
+
+// MeliVariation is a variation of an announcement, e.g. a color and size of a t-shirt
+type MeliVariation struct {
+	ID                int
+	AvailableQuantity int
+	// SELLER_SKU attribute of the variation, empty when it isn't set
+	Sku string
+	// Attribute combination of the variation, e.g. COLOR Azul and SIZE M
+	Attributes []MeliVariationAttribute
+}
+
+type MeliVariationAttribute struct {
+	ID        string
+	ValueName string
 }
 
 type MeliAnnouncement struct {
 	ID            string
+	SiteID        string
 	Title         string
 	Quantity      int
 	Price         float64
@@ -67,11 +120,8 @@ type MeliAnnouncement struct {
 	Pictures      []string
 	Description   string
 	Channels      []string
-	Variations    []struct {
-		ID                int
-		AvailableQuantity int
-	}
-	SaleTerms []struct {
+	Variations    []MeliVariation
+	SaleTerms     []struct {
 		ID          string
 		Name        string
 		ValueID     interface{}
@@ -126,31 +176,42 @@ type AnnouncementCompatibilityProduct struct {
 
 // Mercado Livre reader interface
 type meliReaderStore interface {
-	// Implement
+	// Returns the URL where the seller authorizes the application, in the domain of the site.
+	// The code challenge is derived from the code verifier (PKCE S256)
+	AuthorizationURL(siteId, state, codeVerifier string) string
+	// Returns the user that owns the access token
+	GetUser(accessToken string) (*MeliUser, error)
 }
 
 // Mercado Livre writer interface
 type meliWriterStore interface {
-	RegisterCredential(code string) (*MeliCredential, error)
+	// The code verifier is sent only when it isn't empty
+	RegisterCredential(code, codeVerifier string) (*MeliCredential, error)
 	RefreshCredentials(refreshToken string) (*MeliCredential, error)
 }
 
 type meliReaderOrder interface {
-	FetchOrder(orderId string, accessToken string) (*MeliOrder, error)
+	FetchOrder(ctx context.Context, orderId string, accessToken string) (*MeliOrder, error)
+	FetchPack(ctx context.Context, packId string, accessToken string) (*MeliPack, error)
 }
 
 type meliReaderAnnouncement interface {
 	GetAnnouncementsIDsViaSKU(sku string, userId string, accessToken string) ([]string, error)
+	// Searches the announcements of the user by a free text, e.g. a title or a GTIN
+	SearchAnnouncementsIDs(query string, userId string, accessToken string) ([]string, error)
 	// Max 10 IDs
 	GetAnnouncements(ids []string, accessToken string) (*[]MeliAnnouncement, error)
 	GetAnnouncement(id string, accessToken string) (*MeliAnnouncement, error)
 	GetDescription(id string) (*string, error)
 	GetProductsPictures(picturesURL []string) (pics []image.Image, err error)
 	GetAnnouncementCompatibilities(id string, accessToken string) ([]AnnouncementCompatibilityProduct, error)
+	// Predicts the category of a product in a site from its title,
+	// used when the category IDs differ between sites
+	PredictCategory(siteId, title string) (string, error)
 }
 
 type meliWriterAnnouncement interface {
-	UpdateQuantity(quantity int, announcementId, accessToken string, variationIDs ...int) error
+	UpdateQuantity(ctx context.Context, quantity int, announcementId, accessToken string, variationIDs ...int) error
 	PublishAnnouncement(announcementJson []byte, accessToken string) (ID *string, err error)
 	AddDescription(description, announcementId, accessToken string) error
 	ValidateAndExchangeImages(images []*image.Image, accessToken string) (urlF []string, err error)
@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/common/currency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/common/currency.go -destination=usecases/common/mock/currency_mock.go
+//
+
+// Package mock_common is a generated GoMock package.
+package mock_common
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPriceConverter is a mock of PriceConverter interface.
+type MockPriceConverter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceConverterMockRecorder
+}
+
+// MockPriceConverterMockRecorder is the mock recorder for MockPriceConverter.
+type MockPriceConverterMockRecorder struct {
+	mock *MockPriceConverter
+}
+
+// NewMockPriceConverter creates a new mock instance.
+func NewMockPriceConverter(ctrl *gomock.Controller) *MockPriceConverter {
+	mock := &MockPriceConverter{ctrl: ctrl}
+	mock.recorder = &MockPriceConverterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceConverter) EXPECT() *MockPriceConverterMockRecorder {
+	return m.recorder
+}
+
+// Convert mocks base method.
+func (m *MockPriceConverter) Convert(price float64, from, to string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Convert", price, from, to)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Convert indicates an expected call of Convert.
+func (mr *MockPriceConverterMockRecorder) Convert(price, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockPriceConverter)(nil).Convert), price, from, to)
+}
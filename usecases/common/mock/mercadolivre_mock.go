@@ -10,6 +10,7 @@
 package mock_common
 
 import (
+	context "context"
 	image "image"
 	reflect "reflect"
 
@@ -40,6 +41,35 @@ func (m *MockmeliReaderStore) EXPECT() *MockmeliReaderStoreMockRecorder {
 	return m.recorder
 }
 
+// AuthorizationURL mocks base method.
+func (m *MockmeliReaderStore) AuthorizationURL(siteId, state, codeVerifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationURL", siteId, state, codeVerifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthorizationURL indicates an expected call of AuthorizationURL.
+func (mr *MockmeliReaderStoreMockRecorder) AuthorizationURL(siteId, state, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationURL", reflect.TypeOf((*MockmeliReaderStore)(nil).AuthorizationURL), siteId, state, codeVerifier)
+}
+
+// GetUser mocks base method.
+func (m *MockmeliReaderStore) GetUser(accessToken string) (*common.MeliUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", accessToken)
+	ret0, _ := ret[0].(*common.MeliUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockmeliReaderStoreMockRecorder) GetUser(accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockmeliReaderStore)(nil).GetUser), accessToken)
+}
+
 // MockmeliWriterStore is a mock of meliWriterStore interface.
 type MockmeliWriterStore struct {
 	ctrl     *gomock.Controller
@@ -79,18 +109,18 @@ func (mr *MockmeliWriterStoreMockRecorder) RefreshCredentials(refreshToken any)
 }
 
 // RegisterCredential mocks base method.
-func (m *MockmeliWriterStore) RegisterCredential(code string) (*common.MeliCredential, error) {
+func (m *MockmeliWriterStore) RegisterCredential(code, codeVerifier string) (*common.MeliCredential, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterCredential", code)
+	ret := m.ctrl.Call(m, "RegisterCredential", code, codeVerifier)
 	ret0, _ := ret[0].(*common.MeliCredential)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterCredential indicates an expected call of RegisterCredential.
-func (mr *MockmeliWriterStoreMockRecorder) RegisterCredential(code any) *gomock.Call {
+func (mr *MockmeliWriterStoreMockRecorder) RegisterCredential(code, codeVerifier any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCredential", reflect.TypeOf((*MockmeliWriterStore)(nil).RegisterCredential), code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCredential", reflect.TypeOf((*MockmeliWriterStore)(nil).RegisterCredential), code, codeVerifier)
 }
 
 // MockmeliReaderOrder is a mock of meliReaderOrder interface.
@@ -117,18 +147,33 @@ func (m *MockmeliReaderOrder) EXPECT() *MockmeliReaderOrderMockRecorder {
 }
 
 // FetchOrder mocks base method.
-func (m *MockmeliReaderOrder) FetchOrder(orderId, accessToken string) (*common.MeliOrder, error) {
+func (m *MockmeliReaderOrder) FetchOrder(ctx context.Context, orderId, accessToken string) (*common.MeliOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchOrder", orderId, accessToken)
+	ret := m.ctrl.Call(m, "FetchOrder", ctx, orderId, accessToken)
 	ret0, _ := ret[0].(*common.MeliOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchOrder indicates an expected call of FetchOrder.
-func (mr *MockmeliReaderOrderMockRecorder) FetchOrder(orderId, accessToken any) *gomock.Call {
+func (mr *MockmeliReaderOrderMockRecorder) FetchOrder(ctx, orderId, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchOrder", reflect.TypeOf((*MockmeliReaderOrder)(nil).FetchOrder), ctx, orderId, accessToken)
+}
+
+// FetchPack mocks base method.
+func (m *MockmeliReaderOrder) FetchPack(ctx context.Context, packId, accessToken string) (*common.MeliPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPack", ctx, packId, accessToken)
+	ret0, _ := ret[0].(*common.MeliPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPack indicates an expected call of FetchPack.
+func (mr *MockmeliReaderOrderMockRecorder) FetchPack(ctx, packId, accessToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchOrder", reflect.TypeOf((*MockmeliReaderOrder)(nil).FetchOrder), orderId, accessToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPack", reflect.TypeOf((*MockmeliReaderOrder)(nil).FetchPack), ctx, packId, accessToken)
 }
 
 // MockmeliReaderAnnouncement is a mock of meliReaderAnnouncement interface.
@@ -244,6 +289,36 @@ func (mr *MockmeliReaderAnnouncementMockRecorder) GetProductsPictures(picturesUR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsPictures", reflect.TypeOf((*MockmeliReaderAnnouncement)(nil).GetProductsPictures), picturesURL)
 }
 
+// PredictCategory mocks base method.
+func (m *MockmeliReaderAnnouncement) PredictCategory(siteId, title string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PredictCategory", siteId, title)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PredictCategory indicates an expected call of PredictCategory.
+func (mr *MockmeliReaderAnnouncementMockRecorder) PredictCategory(siteId, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PredictCategory", reflect.TypeOf((*MockmeliReaderAnnouncement)(nil).PredictCategory), siteId, title)
+}
+
+// SearchAnnouncementsIDs mocks base method.
+func (m *MockmeliReaderAnnouncement) SearchAnnouncementsIDs(query, userId, accessToken string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAnnouncementsIDs", query, userId, accessToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAnnouncementsIDs indicates an expected call of SearchAnnouncementsIDs.
+func (mr *MockmeliReaderAnnouncementMockRecorder) SearchAnnouncementsIDs(query, userId, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAnnouncementsIDs", reflect.TypeOf((*MockmeliReaderAnnouncement)(nil).SearchAnnouncementsIDs), query, userId, accessToken)
+}
+
 // MockmeliWriterAnnouncement is a mock of meliWriterAnnouncement interface.
 type MockmeliWriterAnnouncement struct {
 	ctrl     *gomock.Controller
@@ -325,9 +400,9 @@ func (mr *MockmeliWriterAnnouncementMockRecorder) PublishAnnouncement(announceme
 }
 
 // UpdateQuantity mocks base method.
-func (m *MockmeliWriterAnnouncement) UpdateQuantity(quantity int, announcementId, accessToken string, variationIDs ...int) error {
+func (m *MockmeliWriterAnnouncement) UpdateQuantity(ctx context.Context, quantity int, announcementId, accessToken string, variationIDs ...int) error {
 	m.ctrl.T.Helper()
-	varargs := []any{quantity, announcementId, accessToken}
+	varargs := []any{ctx, quantity, announcementId, accessToken}
 	for _, a := range variationIDs {
 		varargs = append(varargs, a)
 	}
@@ -337,9 +412,9 @@ func (m *MockmeliWriterAnnouncement) UpdateQuantity(quantity int, announcementId
 }
 
 // UpdateQuantity indicates an expected call of UpdateQuantity.
-func (mr *MockmeliWriterAnnouncementMockRecorder) UpdateQuantity(quantity, announcementId, accessToken any, variationIDs ...any) *gomock.Call {
+func (mr *MockmeliWriterAnnouncementMockRecorder) UpdateQuantity(ctx, quantity, announcementId, accessToken any, variationIDs ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{quantity, announcementId, accessToken}, variationIDs...)
+	varargs := append([]any{ctx, quantity, announcementId, accessToken}, variationIDs...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuantity", reflect.TypeOf((*MockmeliWriterAnnouncement)(nil).UpdateQuantity), varargs...)
 }
 
@@ -423,19 +498,48 @@ func (mr *MockMercadoLivreMockRecorder) AddDescription(description, announcement
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDescription", reflect.TypeOf((*MockMercadoLivre)(nil).AddDescription), description, announcementId, accessToken)
 }
 
+// AuthorizationURL mocks base method.
+func (m *MockMercadoLivre) AuthorizationURL(siteId, state, codeVerifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationURL", siteId, state, codeVerifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthorizationURL indicates an expected call of AuthorizationURL.
+func (mr *MockMercadoLivreMockRecorder) AuthorizationURL(siteId, state, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationURL", reflect.TypeOf((*MockMercadoLivre)(nil).AuthorizationURL), siteId, state, codeVerifier)
+}
+
 // FetchOrder mocks base method.
-func (m *MockMercadoLivre) FetchOrder(orderId, accessToken string) (*common.MeliOrder, error) {
+func (m *MockMercadoLivre) FetchOrder(ctx context.Context, orderId, accessToken string) (*common.MeliOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchOrder", orderId, accessToken)
+	ret := m.ctrl.Call(m, "FetchOrder", ctx, orderId, accessToken)
 	ret0, _ := ret[0].(*common.MeliOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchOrder indicates an expected call of FetchOrder.
-func (mr *MockMercadoLivreMockRecorder) FetchOrder(orderId, accessToken any) *gomock.Call {
+func (mr *MockMercadoLivreMockRecorder) FetchOrder(ctx, orderId, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchOrder", reflect.TypeOf((*MockMercadoLivre)(nil).FetchOrder), ctx, orderId, accessToken)
+}
+
+// FetchPack mocks base method.
+func (m *MockMercadoLivre) FetchPack(ctx context.Context, packId, accessToken string) (*common.MeliPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPack", ctx, packId, accessToken)
+	ret0, _ := ret[0].(*common.MeliPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPack indicates an expected call of FetchPack.
+func (mr *MockMercadoLivreMockRecorder) FetchPack(ctx, packId, accessToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchOrder", reflect.TypeOf((*MockMercadoLivre)(nil).FetchOrder), orderId, accessToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPack", reflect.TypeOf((*MockMercadoLivre)(nil).FetchPack), ctx, packId, accessToken)
 }
 
 // GetAnnouncement mocks base method.
@@ -528,6 +632,36 @@ func (mr *MockMercadoLivreMockRecorder) GetProductsPictures(picturesURL any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsPictures", reflect.TypeOf((*MockMercadoLivre)(nil).GetProductsPictures), picturesURL)
 }
 
+// GetUser mocks base method.
+func (m *MockMercadoLivre) GetUser(accessToken string) (*common.MeliUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", accessToken)
+	ret0, _ := ret[0].(*common.MeliUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockMercadoLivreMockRecorder) GetUser(accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockMercadoLivre)(nil).GetUser), accessToken)
+}
+
+// PredictCategory mocks base method.
+func (m *MockMercadoLivre) PredictCategory(siteId, title string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PredictCategory", siteId, title)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PredictCategory indicates an expected call of PredictCategory.
+func (mr *MockMercadoLivreMockRecorder) PredictCategory(siteId, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PredictCategory", reflect.TypeOf((*MockMercadoLivre)(nil).PredictCategory), siteId, title)
+}
+
 // PublishAnnouncement mocks base method.
 func (m *MockMercadoLivre) PublishAnnouncement(announcementJson []byte, accessToken string) (*string, error) {
 	m.ctrl.T.Helper()
@@ -559,24 +693,39 @@ func (mr *MockMercadoLivreMockRecorder) RefreshCredentials(refreshToken any) *go
 }
 
 // RegisterCredential mocks base method.
-func (m *MockMercadoLivre) RegisterCredential(code string) (*common.MeliCredential, error) {
+func (m *MockMercadoLivre) RegisterCredential(code, codeVerifier string) (*common.MeliCredential, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterCredential", code)
+	ret := m.ctrl.Call(m, "RegisterCredential", code, codeVerifier)
 	ret0, _ := ret[0].(*common.MeliCredential)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterCredential indicates an expected call of RegisterCredential.
-func (mr *MockMercadoLivreMockRecorder) RegisterCredential(code any) *gomock.Call {
+func (mr *MockMercadoLivreMockRecorder) RegisterCredential(code, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCredential", reflect.TypeOf((*MockMercadoLivre)(nil).RegisterCredential), code, codeVerifier)
+}
+
+// SearchAnnouncementsIDs mocks base method.
+func (m *MockMercadoLivre) SearchAnnouncementsIDs(query, userId, accessToken string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAnnouncementsIDs", query, userId, accessToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAnnouncementsIDs indicates an expected call of SearchAnnouncementsIDs.
+func (mr *MockMercadoLivreMockRecorder) SearchAnnouncementsIDs(query, userId, accessToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCredential", reflect.TypeOf((*MockMercadoLivre)(nil).RegisterCredential), code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAnnouncementsIDs", reflect.TypeOf((*MockMercadoLivre)(nil).SearchAnnouncementsIDs), query, userId, accessToken)
 }
 
 // UpdateQuantity mocks base method.
-func (m *MockMercadoLivre) UpdateQuantity(quantity int, announcementId, accessToken string, variationIDs ...int) error {
+func (m *MockMercadoLivre) UpdateQuantity(ctx context.Context, quantity int, announcementId, accessToken string, variationIDs ...int) error {
 	m.ctrl.T.Helper()
-	varargs := []any{quantity, announcementId, accessToken}
+	varargs := []any{ctx, quantity, announcementId, accessToken}
 	for _, a := range variationIDs {
 		varargs = append(varargs, a)
 	}
@@ -586,9 +735,9 @@ func (m *MockMercadoLivre) UpdateQuantity(quantity int, announcementId, accessTo
 }
 
 // UpdateQuantity indicates an expected call of UpdateQuantity.
-func (mr *MockMercadoLivreMockRecorder) UpdateQuantity(quantity, announcementId, accessToken any, variationIDs ...any) *gomock.Call {
+func (mr *MockMercadoLivreMockRecorder) UpdateQuantity(ctx, quantity, announcementId, accessToken any, variationIDs ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{quantity, announcementId, accessToken}, variationIDs...)
+	varargs := append([]any{ctx, quantity, announcementId, accessToken}, variationIDs...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuantity", reflect.TypeOf((*MockMercadoLivre)(nil).UpdateQuantity), varargs...)
 }
 
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/common/oauth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/common/oauth.go -destination=usecases/common/mock/oauth_mock.go
+//
+
+// Package mock_common is a generated GoMock package.
+package mock_common
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStateSigner is a mock of StateSigner interface.
+type MockStateSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockStateSignerMockRecorder
+}
+
+// MockStateSignerMockRecorder is the mock recorder for MockStateSigner.
+type MockStateSignerMockRecorder struct {
+	mock *MockStateSigner
+}
+
+// NewMockStateSigner creates a new mock instance.
+func NewMockStateSigner(ctrl *gomock.Controller) *MockStateSigner {
+	mock := &MockStateSigner{ctrl: ctrl}
+	mock.recorder = &MockStateSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStateSigner) EXPECT() *MockStateSignerMockRecorder {
+	return m.recorder
+}
+
+// CodeVerifier mocks base method.
+func (m *MockStateSigner) CodeVerifier(state string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CodeVerifier", state)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CodeVerifier indicates an expected call of CodeVerifier.
+func (mr *MockStateSignerMockRecorder) CodeVerifier(state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CodeVerifier", reflect.TypeOf((*MockStateSigner)(nil).CodeVerifier), state)
+}
+
+// Sign mocks base method.
+func (m *MockStateSigner) Sign(payload []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", payload)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockStateSignerMockRecorder) Sign(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockStateSigner)(nil).Sign), payload)
+}
+
+// Verify mocks base method.
+func (m *MockStateSigner) Verify(state string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", state)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockStateSignerMockRecorder) Verify(state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockStateSigner)(nil).Verify), state)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/common/url.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/common/url.go -destination=usecases/common/mock/url_mock.go
+//
+
+// Package mock_common is a generated GoMock package.
+package mock_common
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockURLGuard is a mock of URLGuard interface.
+type MockURLGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockURLGuardMockRecorder
+}
+
+// MockURLGuardMockRecorder is the mock recorder for MockURLGuard.
+type MockURLGuardMockRecorder struct {
+	mock *MockURLGuard
+}
+
+// NewMockURLGuard creates a new mock instance.
+func NewMockURLGuard(ctrl *gomock.Controller) *MockURLGuard {
+	mock := &MockURLGuard{ctrl: ctrl}
+	mock.recorder = &MockURLGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockURLGuard) EXPECT() *MockURLGuardMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockURLGuard) Check(rawUrl string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", rawUrl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockURLGuardMockRecorder) Check(rawUrl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockURLGuard)(nil).Check), rawUrl)
+}
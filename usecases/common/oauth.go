@@ -0,0 +1,14 @@
+package common
+
+// StateSigner issues and verifies the state parameter of OAuth authorizations.
+//
+// The state carries an opaque payload, is signed and expires, so the callback
+// can trust it without storing anything in the backend.
+type StateSigner interface {
+	// Sign returns a state carrying the payload
+	Sign(payload []byte) (state string, err error)
+	// Verify checks the signature and the expiration of a state, returning its payload
+	Verify(state string) ([]byte, error)
+	// CodeVerifier derives the PKCE code verifier bound to a state
+	CodeVerifier(state string) string
+}
@@ -0,0 +1,9 @@
+package common
+
+// URLGuard checks the URLs given by the stores before anything is sent to them,
+// so a store can't make the application reach its internal network
+type URLGuard interface {
+	// Check returns an error unless the URL is https and its host resolves only to public addresses,
+	// not to loopback, private, link-local or metadata ones
+	Check(rawUrl string) error
+}
@@ -0,0 +1,23 @@
+package kit
+
+import "github.com/Vractos/kloni/entity"
+
+type KitComponentDtoInput struct {
+	Sku      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type CreateKitDtoInput struct {
+	Store      entity.ID              `json:"-"`
+	Sku        string                 `json:"sku" validate:"required"`
+	Title      string                 `json:"title"`
+	Components []KitComponentDtoInput `json:"components" validate:"required,min=1,dive"`
+}
+
+type UpdateKitDtoInput struct {
+	Store      entity.ID              `json:"-"`
+	ID         entity.ID              `json:"-"`
+	Sku        string                 `json:"sku" validate:"required"`
+	Title      string                 `json:"title"`
+	Components []KitComponentDtoInput `json:"components" validate:"required,min=1,dive"`
+}
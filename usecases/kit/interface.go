@@ -0,0 +1,108 @@
+package kit
+
+import (
+	"github.com/Vractos/kloni/entity"
+)
+
+type UseCase interface {
+	// CreateKit defines a kit of the store and the SKUs that compose it.
+	//
+	// Parameters:
+	//   - input: CreateKitDtoInput containing the SKU of the kit and its components
+	//
+	// Returns:
+	//   - *entity.Kit: The created kit
+	//   - error: entity.ErrInvalidKit, entity.ErrInvalidComponent, ErrNestedKit or ErrKitAlreadyExists
+	CreateKit(input CreateKitDtoInput) (*entity.Kit, error)
+	// UpdateKit replaces the SKU, the title and the components of a kit.
+	//
+	// Parameters:
+	//   - input: UpdateKitDtoInput containing the ID of the kit and its new definition
+	//
+	// Returns:
+	//   - *entity.Kit: The updated kit
+	//   - error: ErrKitNotFound, entity.ErrInvalidKit, entity.ErrInvalidComponent, ErrNestedKit or ErrKitAlreadyExists
+	UpdateKit(input UpdateKitDtoInput) (*entity.Kit, error)
+	// GetKit retrieves a kit of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - kitId: ID of the kit
+	//
+	// Returns:
+	//   - *entity.Kit: The kit
+	//   - error: ErrKitNotFound if the kit doesn't exist or belongs to another store
+	GetKit(storeId, kitId entity.ID) (*entity.Kit, error)
+	// ListKits lists the kits of the store.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []entity.Kit: The kits, ordered by the SKU
+	//   - error: Error if the kits can't be retrieved
+	ListKits(storeId entity.ID) ([]entity.Kit, error)
+	// DeleteKit removes a kit, the stock of its listings isn't changed.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - kitId: ID of the kit
+	//
+	// Returns:
+	//   - error: ErrKitNotFound if the kit doesn't exist or belongs to another store
+	DeleteKit(storeId, kitId entity.ID) error
+	// ApplySale propagates a sale to the kits of the store.
+	// Selling a kit decrements its components, and selling a component, directly or inside a kit,
+	// recomputes the availability of every kit that contains it.
+	// A sale can be applied again after a failure, the components it already claimed are skipped.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - saleId: ID of the sale, the kit sale action of the order
+	//   - sku: SKU that was sold
+	//   - quantity: Units sold
+	//
+	// Returns:
+	//   - []entity.SyncAction: Changes made to the listings of the components and the kits
+	//   - error: Error if the kits can't be retrieved, the failures of the listings are in their actions
+	ApplySale(storeId, saleId entity.ID, sku string, quantity int) ([]entity.SyncAction, error)
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	// Returns ErrKitAlreadyExists if the store already has a kit with the SKU
+	RegisterKit(kit *entity.Kit) error
+	// Returns ErrKitNotFound if the kit doesn't exist or ErrKitAlreadyExists if the SKU is taken
+	UpdateKit(kit *entity.Kit) error
+	// Returns ErrKitNotFound if the kit doesn't exist
+	DeleteKit(storeId, kitId entity.ID) error
+	// Records a component of the sale of a kit as pending before it's decremented,
+	// false when the component was already claimed
+	ClaimSaleComponent(saleId entity.ID, sku string) (bool, error)
+	// Records a component decremented on every listing by the sale of a kit
+	CompleteSaleComponent(saleId entity.ID, sku string) error
+}
+
+type RepoReader interface {
+	// Nil when the kit doesn't exist or belongs to another store
+	GetKit(storeId, kitId entity.ID) (*entity.Kit, error)
+	// Nil when the SKU isn't a kit
+	GetKitBySku(storeId entity.ID, sku string) (*entity.Kit, error)
+	ListKits(storeId entity.ID) ([]entity.Kit, error)
+	// Kits that have at least one of the SKUs as a component
+	ListKitsContaining(storeId entity.ID, skus []string) ([]entity.Kit, error)
+	// Components claimed by the sale of a kit by their SKU, true when they were decremented on every listing
+	ListSaleComponents(saleId entity.ID) (map[string]bool, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
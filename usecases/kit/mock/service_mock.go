@@ -0,0 +1,491 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/kit/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/kit/interface.go -destination=usecases/kit/mock/service_mock.go
+//
+
+// Package mock_kit is a generated GoMock package.
+package mock_kit
+
+import (
+	reflect "reflect"
+
+	entity "github.com/Vractos/kloni/entity"
+	kit "github.com/Vractos/kloni/usecases/kit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ApplySale mocks base method.
+func (m *MockUseCase) ApplySale(storeId, saleId entity.ID, sku string, quantity int) ([]entity.SyncAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplySale", storeId, saleId, sku, quantity)
+	ret0, _ := ret[0].([]entity.SyncAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplySale indicates an expected call of ApplySale.
+func (mr *MockUseCaseMockRecorder) ApplySale(storeId, saleId, sku, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplySale", reflect.TypeOf((*MockUseCase)(nil).ApplySale), storeId, saleId, sku, quantity)
+}
+
+// CreateKit mocks base method.
+func (m *MockUseCase) CreateKit(input kit.CreateKitDtoInput) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKit", input)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKit indicates an expected call of CreateKit.
+func (mr *MockUseCaseMockRecorder) CreateKit(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKit", reflect.TypeOf((*MockUseCase)(nil).CreateKit), input)
+}
+
+// DeleteKit mocks base method.
+func (m *MockUseCase) DeleteKit(storeId, kitId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKit", storeId, kitId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKit indicates an expected call of DeleteKit.
+func (mr *MockUseCaseMockRecorder) DeleteKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKit", reflect.TypeOf((*MockUseCase)(nil).DeleteKit), storeId, kitId)
+}
+
+// GetKit mocks base method.
+func (m *MockUseCase) GetKit(storeId, kitId entity.ID) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKit", storeId, kitId)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKit indicates an expected call of GetKit.
+func (mr *MockUseCaseMockRecorder) GetKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKit", reflect.TypeOf((*MockUseCase)(nil).GetKit), storeId, kitId)
+}
+
+// ListKits mocks base method.
+func (m *MockUseCase) ListKits(storeId entity.ID) ([]entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKits", storeId)
+	ret0, _ := ret[0].([]entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKits indicates an expected call of ListKits.
+func (mr *MockUseCaseMockRecorder) ListKits(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKits", reflect.TypeOf((*MockUseCase)(nil).ListKits), storeId)
+}
+
+// UpdateKit mocks base method.
+func (m *MockUseCase) UpdateKit(input kit.UpdateKitDtoInput) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKit", input)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateKit indicates an expected call of UpdateKit.
+func (mr *MockUseCaseMockRecorder) UpdateKit(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKit", reflect.TypeOf((*MockUseCase)(nil).UpdateKit), input)
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// ClaimSaleComponent mocks base method.
+func (m *MockRepoWriter) ClaimSaleComponent(saleId entity.ID, sku string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimSaleComponent", saleId, sku)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimSaleComponent indicates an expected call of ClaimSaleComponent.
+func (mr *MockRepoWriterMockRecorder) ClaimSaleComponent(saleId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimSaleComponent", reflect.TypeOf((*MockRepoWriter)(nil).ClaimSaleComponent), saleId, sku)
+}
+
+// CompleteSaleComponent mocks base method.
+func (m *MockRepoWriter) CompleteSaleComponent(saleId entity.ID, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSaleComponent", saleId, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteSaleComponent indicates an expected call of CompleteSaleComponent.
+func (mr *MockRepoWriterMockRecorder) CompleteSaleComponent(saleId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSaleComponent", reflect.TypeOf((*MockRepoWriter)(nil).CompleteSaleComponent), saleId, sku)
+}
+
+// DeleteKit mocks base method.
+func (m *MockRepoWriter) DeleteKit(storeId, kitId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKit", storeId, kitId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKit indicates an expected call of DeleteKit.
+func (mr *MockRepoWriterMockRecorder) DeleteKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKit", reflect.TypeOf((*MockRepoWriter)(nil).DeleteKit), storeId, kitId)
+}
+
+// RegisterKit mocks base method.
+func (m *MockRepoWriter) RegisterKit(kit *entity.Kit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterKit", kit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterKit indicates an expected call of RegisterKit.
+func (mr *MockRepoWriterMockRecorder) RegisterKit(kit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterKit", reflect.TypeOf((*MockRepoWriter)(nil).RegisterKit), kit)
+}
+
+// UpdateKit mocks base method.
+func (m *MockRepoWriter) UpdateKit(kit *entity.Kit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKit", kit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKit indicates an expected call of UpdateKit.
+func (mr *MockRepoWriterMockRecorder) UpdateKit(kit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKit", reflect.TypeOf((*MockRepoWriter)(nil).UpdateKit), kit)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// GetKit mocks base method.
+func (m *MockRepoReader) GetKit(storeId, kitId entity.ID) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKit", storeId, kitId)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKit indicates an expected call of GetKit.
+func (mr *MockRepoReaderMockRecorder) GetKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKit", reflect.TypeOf((*MockRepoReader)(nil).GetKit), storeId, kitId)
+}
+
+// GetKitBySku mocks base method.
+func (m *MockRepoReader) GetKitBySku(storeId entity.ID, sku string) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKitBySku", storeId, sku)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKitBySku indicates an expected call of GetKitBySku.
+func (mr *MockRepoReaderMockRecorder) GetKitBySku(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKitBySku", reflect.TypeOf((*MockRepoReader)(nil).GetKitBySku), storeId, sku)
+}
+
+// ListKits mocks base method.
+func (m *MockRepoReader) ListKits(storeId entity.ID) ([]entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKits", storeId)
+	ret0, _ := ret[0].([]entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKits indicates an expected call of ListKits.
+func (mr *MockRepoReaderMockRecorder) ListKits(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKits", reflect.TypeOf((*MockRepoReader)(nil).ListKits), storeId)
+}
+
+// ListKitsContaining mocks base method.
+func (m *MockRepoReader) ListKitsContaining(storeId entity.ID, skus []string) ([]entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKitsContaining", storeId, skus)
+	ret0, _ := ret[0].([]entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKitsContaining indicates an expected call of ListKitsContaining.
+func (mr *MockRepoReaderMockRecorder) ListKitsContaining(storeId, skus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKitsContaining", reflect.TypeOf((*MockRepoReader)(nil).ListKitsContaining), storeId, skus)
+}
+
+// ListSaleComponents mocks base method.
+func (m *MockRepoReader) ListSaleComponents(saleId entity.ID) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSaleComponents", saleId)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSaleComponents indicates an expected call of ListSaleComponents.
+func (mr *MockRepoReaderMockRecorder) ListSaleComponents(saleId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSaleComponents", reflect.TypeOf((*MockRepoReader)(nil).ListSaleComponents), saleId)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimSaleComponent mocks base method.
+func (m *MockRepository) ClaimSaleComponent(saleId entity.ID, sku string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimSaleComponent", saleId, sku)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimSaleComponent indicates an expected call of ClaimSaleComponent.
+func (mr *MockRepositoryMockRecorder) ClaimSaleComponent(saleId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimSaleComponent", reflect.TypeOf((*MockRepository)(nil).ClaimSaleComponent), saleId, sku)
+}
+
+// CompleteSaleComponent mocks base method.
+func (m *MockRepository) CompleteSaleComponent(saleId entity.ID, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSaleComponent", saleId, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteSaleComponent indicates an expected call of CompleteSaleComponent.
+func (mr *MockRepositoryMockRecorder) CompleteSaleComponent(saleId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSaleComponent", reflect.TypeOf((*MockRepository)(nil).CompleteSaleComponent), saleId, sku)
+}
+
+// DeleteKit mocks base method.
+func (m *MockRepository) DeleteKit(storeId, kitId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKit", storeId, kitId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKit indicates an expected call of DeleteKit.
+func (mr *MockRepositoryMockRecorder) DeleteKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKit", reflect.TypeOf((*MockRepository)(nil).DeleteKit), storeId, kitId)
+}
+
+// GetKit mocks base method.
+func (m *MockRepository) GetKit(storeId, kitId entity.ID) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKit", storeId, kitId)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKit indicates an expected call of GetKit.
+func (mr *MockRepositoryMockRecorder) GetKit(storeId, kitId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKit", reflect.TypeOf((*MockRepository)(nil).GetKit), storeId, kitId)
+}
+
+// GetKitBySku mocks base method.
+func (m *MockRepository) GetKitBySku(storeId entity.ID, sku string) (*entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKitBySku", storeId, sku)
+	ret0, _ := ret[0].(*entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKitBySku indicates an expected call of GetKitBySku.
+func (mr *MockRepositoryMockRecorder) GetKitBySku(storeId, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKitBySku", reflect.TypeOf((*MockRepository)(nil).GetKitBySku), storeId, sku)
+}
+
+// ListKits mocks base method.
+func (m *MockRepository) ListKits(storeId entity.ID) ([]entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKits", storeId)
+	ret0, _ := ret[0].([]entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKits indicates an expected call of ListKits.
+func (mr *MockRepositoryMockRecorder) ListKits(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKits", reflect.TypeOf((*MockRepository)(nil).ListKits), storeId)
+}
+
+// ListKitsContaining mocks base method.
+func (m *MockRepository) ListKitsContaining(storeId entity.ID, skus []string) ([]entity.Kit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKitsContaining", storeId, skus)
+	ret0, _ := ret[0].([]entity.Kit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKitsContaining indicates an expected call of ListKitsContaining.
+func (mr *MockRepositoryMockRecorder) ListKitsContaining(storeId, skus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKitsContaining", reflect.TypeOf((*MockRepository)(nil).ListKitsContaining), storeId, skus)
+}
+
+// ListSaleComponents mocks base method.
+func (m *MockRepository) ListSaleComponents(saleId entity.ID) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSaleComponents", saleId)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSaleComponents indicates an expected call of ListSaleComponents.
+func (mr *MockRepositoryMockRecorder) ListSaleComponents(saleId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSaleComponents", reflect.TypeOf((*MockRepository)(nil).ListSaleComponents), saleId)
+}
+
+// RegisterKit mocks base method.
+func (m *MockRepository) RegisterKit(kit *entity.Kit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterKit", kit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterKit indicates an expected call of RegisterKit.
+func (mr *MockRepositoryMockRecorder) RegisterKit(kit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterKit", reflect.TypeOf((*MockRepository)(nil).RegisterKit), kit)
+}
+
+// UpdateKit mocks base method.
+func (m *MockRepository) UpdateKit(kit *entity.Kit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKit", kit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKit indicates an expected call of UpdateKit.
+func (mr *MockRepositoryMockRecorder) UpdateKit(kit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKit", reflect.TypeOf((*MockRepository)(nil).UpdateKit), kit)
+}
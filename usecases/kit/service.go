@@ -0,0 +1,443 @@
+// Package kit implements the kits, SKUs composed of other SKUs, and the propagation of their sales
+package kit
+
+import (
+	"errors"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/stock"
+	"github.com/Vractos/kloni/usecases/store"
+	"go.uber.org/zap"
+)
+
+// Error definitions for kit operations
+var (
+	// ErrKitNotFound is returned when the kit doesn't exist or belongs to another store
+	ErrKitNotFound = errors.New("kit not found")
+	// ErrKitAlreadyExists is returned when the store already has a kit with the SKU
+	ErrKitAlreadyExists = errors.New("kit already exists")
+	// ErrNestedKit is returned when a component is a kit, or the kit is a component of another kit
+	ErrNestedKit = errors.New("kits can't be nested")
+)
+
+type KitService struct {
+	repo     Repository
+	store    store.UseCase
+	announce announcement.UseCase
+	stock    stock.UseCase
+	logger   common.Logger
+}
+
+// NewKitService creates a new instance of KitService.
+//
+// Parameters:
+//   - repository: Repository of the kits
+//   - storeUseCase: Store use case, used to retrieve the credentials of the accounts
+//   - announceUseCase: Announcement use case, used to retrieve the stock of the components
+//   - stockUseCase: Stock use case, used to change the quantities of the components and the kits
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *KitService: A new instance of KitService
+func NewKitService(
+	repository Repository,
+	storeUseCase store.UseCase,
+	announceUseCase announcement.UseCase,
+	stockUseCase stock.UseCase,
+	logger common.Logger,
+) *KitService {
+	return &KitService{
+		repo:     repository,
+		store:    storeUseCase,
+		announce: announceUseCase,
+		stock:    stockUseCase,
+		logger:   logger,
+	}
+}
+
+// CreateKit defines a kit of the store and the SKUs that compose it.
+//
+// Parameters:
+//   - input: CreateKitDtoInput containing the SKU of the kit and its components
+//
+// Returns:
+//   - *entity.Kit: The created kit
+//   - error: entity.ErrInvalidKit, entity.ErrInvalidComponent, ErrNestedKit or ErrKitAlreadyExists
+func (k *KitService) CreateKit(input CreateKitDtoInput) (*entity.Kit, error) {
+	kit, err := entity.NewKit(input.Store, input.Sku, input.Title, toComponents(input.Components))
+	if err != nil {
+		return nil, err
+	}
+	if err := k.validateComposition(kit); err != nil {
+		return nil, err
+	}
+
+	if err := k.repo.RegisterKit(kit); err != nil {
+		if !errors.Is(err, ErrKitAlreadyExists) {
+			k.logger.Error("Fail to register the kit", err, zap.String("store_id", input.Store.String()), zap.String("sku", kit.Sku))
+		}
+		return nil, err
+	}
+	return kit, nil
+}
+
+// UpdateKit replaces the SKU, the title and the components of a kit.
+//
+// Parameters:
+//   - input: UpdateKitDtoInput containing the ID of the kit and its new definition
+//
+// Returns:
+//   - *entity.Kit: The updated kit
+//   - error: ErrKitNotFound, entity.ErrInvalidKit, entity.ErrInvalidComponent, ErrNestedKit or ErrKitAlreadyExists
+func (k *KitService) UpdateKit(input UpdateKitDtoInput) (*entity.Kit, error) {
+	kit, err := k.GetKit(input.Store, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := kit.Define(input.Sku, input.Title, toComponents(input.Components)); err != nil {
+		return nil, err
+	}
+	if err := k.validateComposition(kit); err != nil {
+		return nil, err
+	}
+
+	if err := k.repo.UpdateKit(kit); err != nil {
+		if !errors.Is(err, ErrKitAlreadyExists) && !errors.Is(err, ErrKitNotFound) {
+			k.logger.Error("Fail to update the kit", err, zap.String("kit_id", kit.ID.String()))
+		}
+		return nil, err
+	}
+	return kit, nil
+}
+
+// GetKit retrieves a kit of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - kitId: ID of the kit
+//
+// Returns:
+//   - *entity.Kit: The kit
+//   - error: ErrKitNotFound if the kit doesn't exist or belongs to another store
+func (k *KitService) GetKit(storeId, kitId entity.ID) (*entity.Kit, error) {
+	kit, err := k.repo.GetKit(storeId, kitId)
+	if err != nil {
+		k.logger.Error("Fail to retrieve the kit", err, zap.String("kit_id", kitId.String()))
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrKitNotFound
+	}
+	return kit, nil
+}
+
+// ListKits lists the kits of the store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []entity.Kit: The kits, ordered by the SKU
+//   - error: Error if the kits can't be retrieved
+func (k *KitService) ListKits(storeId entity.ID) ([]entity.Kit, error) {
+	kits, err := k.repo.ListKits(storeId)
+	if err != nil {
+		k.logger.Error("Fail to list the kits", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return kits, nil
+}
+
+// DeleteKit removes a kit, the stock of its listings isn't changed.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - kitId: ID of the kit
+//
+// Returns:
+//   - error: ErrKitNotFound if the kit doesn't exist or belongs to another store
+func (k *KitService) DeleteKit(storeId, kitId entity.ID) error {
+	if err := k.repo.DeleteKit(storeId, kitId); err != nil {
+		if !errors.Is(err, ErrKitNotFound) {
+			k.logger.Error("Fail to delete the kit", err, zap.String("kit_id", kitId.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// ApplySale propagates a sale to the kits of the store.
+// Selling a kit decrements its components, and selling a component, directly or inside a kit,
+// recomputes the availability of every kit that contains it.
+// The kits with a component without listings aren't recomputed, its stock is unknown.
+// Each component is claimed with the sale before its listings are updated, so a sale applied again
+// after a failure only decrements the remaining ones, and it's applied once every listing was updated.
+// The failures of the listings are in their actions. The availability of the kits is recomputed every time.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - saleId: ID of the sale, the kit sale action of the order
+//   - sku: SKU that was sold
+//   - quantity: Units sold
+//
+// Returns:
+//   - []entity.SyncAction: Changes made to the listings of the components and the kits
+//   - error: Error if the kits can't be retrieved, the failures of the listings are in their actions
+func (k *KitService) ApplySale(storeId, saleId entity.ID, sku string, quantity int) ([]entity.SyncAction, error) {
+	sold, err := k.repo.GetKitBySku(storeId, sku)
+	if err != nil {
+		k.logger.Error("Fail to retrieve the kit of the sold SKU", err, zap.String("sku", sku))
+		return nil, err
+	}
+
+	actions := []entity.SyncAction{}
+	// Stock of the components after the sale, nil when they don't have listings
+	stockOf := make(map[string]*int)
+	changed := []string{sku}
+
+	if sold != nil {
+		applied, err := k.repo.ListSaleComponents(saleId)
+		if err != nil {
+			k.logger.Error("Fail to retrieve the components decremented by the sale", err, zap.String("sale_id", saleId.String()))
+			return nil, err
+		}
+
+		changed = changed[:0]
+		for _, c := range sold.Components {
+			// Its stock is retrieved from its listings when the kits are recomputed
+			if done, claimed := applied[c.Sku]; claimed {
+				if !done {
+					k.logger.Warn("The component of the sold kit was claimed by an attempt that didn't decrement every listing",
+						zap.String("sale_id", saleId.String()),
+						zap.String("sku", c.Sku),
+					)
+				}
+				changed = append(changed, c.Sku)
+				continue
+			}
+
+			claimed, err := k.repo.ClaimSaleComponent(saleId, c.Sku)
+			if err != nil {
+				k.logger.Error("Fail to claim the component of the sale", err,
+					zap.String("sale_id", saleId.String()),
+					zap.String("sku", c.Sku),
+				)
+				return actions, err
+			}
+			if !claimed {
+				changed = append(changed, c.Sku)
+				continue
+			}
+
+			delta := -c.Quantity * quantity
+			adjustment, err := k.stock.AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: c.Sku, Delta: &delta})
+			if err != nil {
+				if !errors.Is(err, stock.ErrSkuNotFound) {
+					return actions, err
+				}
+				k.logger.Warn("The component of the sold kit doesn't have listings",
+					zap.String("kit_sku", sold.Sku),
+					zap.String("sku", c.Sku),
+				)
+				stockOf[c.Sku] = nil
+				continue
+			}
+			actions = append(actions, toSyncActions(adjustment)...)
+			stockOf[c.Sku] = adjustedQuantity(adjustment)
+			changed = append(changed, c.Sku)
+			if failed(adjustment) {
+				continue
+			}
+			if err := k.repo.CompleteSaleComponent(saleId, c.Sku); err != nil {
+				k.logger.Error("Fail to record the component decremented by the sale", err,
+					zap.String("sale_id", saleId.String()),
+					zap.String("sku", c.Sku),
+				)
+				return actions, err
+			}
+		}
+		if len(changed) == 0 {
+			return actions, nil
+		}
+	}
+
+	kits, err := k.repo.ListKitsContaining(storeId, changed)
+	if err != nil {
+		k.logger.Error("Fail to retrieve the kits of the sold SKU", err, zap.String("sku", sku))
+		return actions, err
+	}
+
+	var credentials *[]store.Credentials
+	for _, kit := range kits {
+		stocks := make(map[string]int, len(kit.Components))
+		known := true
+		for _, c := range kit.Components {
+			units, fetched := stockOf[c.Sku]
+			if !fetched {
+				if credentials == nil {
+					credentials, err = k.store.RetrieveMeliCredentialsFromStoreID(storeId)
+					if err != nil {
+						k.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", storeId.String()))
+						return actions, err
+					}
+				}
+				units, err = k.componentStock(c.Sku, credentials)
+				if err != nil {
+					return actions, err
+				}
+				stockOf[c.Sku] = units
+			}
+			if units == nil {
+				known = false
+				break
+			}
+			stocks[c.Sku] = *units
+		}
+		if !known {
+			k.logger.Warn("The kit has a component without listings", zap.String("kit_sku", kit.Sku))
+			continue
+		}
+
+		availability := kit.Availability(stocks)
+		adjustment, err := k.stock.AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: kit.Sku, Quantity: &availability})
+		if err != nil {
+			if errors.Is(err, stock.ErrSkuNotFound) {
+				continue
+			}
+			return actions, err
+		}
+		actions = append(actions, toSyncActions(adjustment)...)
+	}
+	return actions, nil
+}
+
+// validateComposition verifies that the kit isn't nested, i.e. none of its components is a kit
+// and it isn't a component of another kit.
+//
+// Parameters:
+//   - kit: The kit to be saved
+//
+// Returns:
+//   - error: ErrNestedKit if the kit is nested
+func (k *KitService) validateComposition(kit *entity.Kit) error {
+	for _, c := range kit.Components {
+		component, err := k.repo.GetKitBySku(kit.StoreID, c.Sku)
+		if err != nil {
+			k.logger.Error("Fail to retrieve the kit of the component", err, zap.String("sku", c.Sku))
+			return err
+		}
+		if component != nil && component.ID != kit.ID {
+			return ErrNestedKit
+		}
+	}
+
+	parents, err := k.repo.ListKitsContaining(kit.StoreID, []string{kit.Sku})
+	if err != nil {
+		k.logger.Error("Fail to retrieve the kits containing the kit", err, zap.String("sku", kit.Sku))
+		return err
+	}
+	for _, parent := range parents {
+		if parent.ID != kit.ID {
+			return ErrNestedKit
+		}
+	}
+	return nil
+}
+
+// componentStock retrieves the stock of a component from its listings.
+//
+// Parameters:
+//   - sku: SKU of the component
+//   - credentials: Credentials of the accounts of the store
+//
+// Returns:
+//   - *int: The quantity available, nil when the component doesn't have listings
+//   - error: Error if the listings can't be retrieved
+func (k *KitService) componentStock(sku string, credentials *[]store.Credentials) (*int, error) {
+	announcements, err := k.announce.RetrieveAnnouncementsFromAllAccounts(sku, credentials)
+	if err != nil {
+		k.logger.Error("Fail to retrieve the announcements of the component", err, zap.String("sku", sku))
+		return nil, err
+	}
+	for _, account := range *announcements {
+		if account.Announcements != nil && len(*account.Announcements) > 0 {
+			quantity := alert.AvailableQuantity(announcements, sku)
+			return &quantity, nil
+		}
+	}
+	return nil, nil
+}
+
+// adjustedQuantity is the stock of a SKU after an adjustment, the listing with the most units.
+// The variations of a listing are summed, like in alert.AvailableQuantity.
+//
+// Parameters:
+//   - adjustment: The adjustment of the SKU
+//
+// Returns:
+//   - *int: The quantity available
+func adjustedQuantity(adjustment *stock.Adjustment) *int {
+	listings := make(map[string]int)
+	quantity := 0
+	for _, listing := range adjustment.Listings {
+		units := listing.Quantity
+		if listing.Status == stock.ListingFailed {
+			units = listing.PreviousQuantity
+		}
+		key := listing.AccountID.String() + "/" + listing.AnnouncementID
+		listings[key] += units
+		if listings[key] > quantity {
+			quantity = listings[key]
+		}
+	}
+	return &quantity
+}
+
+// failed reports whether the update of a listing of an adjustment failed.
+//
+// Parameters:
+//   - adjustment: The adjustment of a SKU
+//
+// Returns:
+//   - bool: True if a listing wasn't updated
+func failed(adjustment *stock.Adjustment) bool {
+	for _, listing := range adjustment.Listings {
+		if listing.Status == stock.ListingFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// toSyncActions converts the listings changed by an adjustment to sync actions.
+//
+// Parameters:
+//   - adjustment: The adjustment of a SKU
+//
+// Returns:
+//   - []entity.SyncAction: An action for each updated or failed listing
+func toSyncActions(adjustment *stock.Adjustment) []entity.SyncAction {
+	actions := []entity.SyncAction{}
+	for _, listing := range adjustment.Listings {
+		var err error
+		switch listing.Status {
+		case stock.ListingUnchanged:
+			continue
+		case stock.ListingFailed:
+			err = errors.New(listing.Error)
+		}
+		actions = append(actions, *entity.NewSyncAction(listing.AccountID, listing.AnnouncementID, listing.VariationID, listing.Quantity, err))
+	}
+	return actions
+}
+
+func toComponents(input []KitComponentDtoInput) []entity.KitComponent {
+	components := make([]entity.KitComponent, len(input))
+	for i, c := range input {
+		components[i] = entity.KitComponent{Sku: c.Sku, Quantity: c.Quantity}
+	}
+	return components
+}
@@ -0,0 +1,473 @@
+package kit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/announcement"
+	mock_announcement "github.com/Vractos/kloni/usecases/announcement/mock"
+	common "github.com/Vractos/kloni/usecases/common"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/kit"
+	mock_kit "github.com/Vractos/kloni/usecases/kit/mock"
+	"github.com/Vractos/kloni/usecases/stock"
+	mock_stock "github.com/Vractos/kloni/usecases/stock/mock"
+	"github.com/Vractos/kloni/usecases/store"
+	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type Mocks struct {
+	repo     *mock_kit.MockRepository
+	store    *mock_store.MockUseCase
+	announce *mock_announcement.MockUseCase
+	stock    *mock_stock.MockUseCase
+	logger   *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:     mock_kit.NewMockRepository(ctrl),
+		store:    mock_store.NewMockUseCase(ctrl),
+		announce: mock_announcement.NewMockUseCase(ctrl),
+		stock:    mock_stock.NewMockUseCase(ctrl),
+		logger:   common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newKitService() *kit.KitService {
+	return kit.NewKitService(m.repo, m.store, m.announce, m.stock, m.logger)
+}
+
+// adjustmentMatcher matches the adjustments by their SKU and their quantity or delta
+type adjustmentMatcher struct {
+	sku      string
+	quantity *int
+	delta    *int
+}
+
+func (a adjustmentMatcher) Matches(x interface{}) bool {
+	input, ok := x.(stock.AdjustStockDtoInput)
+	if !ok || input.Sku != a.sku {
+		return false
+	}
+	if a.quantity != nil {
+		return input.Quantity != nil && *input.Quantity == *a.quantity && input.Delta == nil
+	}
+	return input.Delta != nil && *input.Delta == *a.delta && input.Quantity == nil
+}
+
+func (a adjustmentMatcher) String() string {
+	return "adjustment of " + a.sku
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newKit(t *testing.T, store entity.ID, sku string, components ...entity.KitComponent) *entity.Kit {
+	k, err := entity.NewKit(store, sku, sku, components)
+	if err != nil {
+		t.Fatalf("NewKit() error = %v", err)
+	}
+	return k
+}
+
+func TestCreateKit(t *testing.T) {
+	storeId := entity.NewID()
+	input := kit.CreateKitDtoInput{
+		Store: storeId,
+		Sku:   "KIT-1",
+		Title: "Filter and oil",
+		Components: []kit.KitComponentDtoInput{
+			{Sku: "FILTER", Quantity: 2},
+			{Sku: "OIL", Quantity: 1},
+		},
+	}
+
+	t.Run("kit created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "FILTER").Return(nil, nil)
+		m.repo.EXPECT().GetKitBySku(storeId, "OIL").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"KIT-1"}).Return(nil, nil)
+		m.repo.EXPECT().RegisterKit(gomock.Any()).Return(nil)
+
+		created, err := m.newKitService().CreateKit(input)
+		if err != nil {
+			t.Fatalf("CreateKit() error = %v", err)
+		}
+		if created.Sku != "KIT-1" || len(created.Components) != 2 || created.StoreID != storeId {
+			t.Errorf("CreateKit() = %+v", created)
+		}
+	})
+
+	t.Run("invalid component", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		invalid := input
+		invalid.Components = []kit.KitComponentDtoInput{{Sku: "OIL", Quantity: 0}}
+		if _, err := m.newKitService().CreateKit(invalid); !errors.Is(err, entity.ErrInvalidComponent) {
+			t.Errorf("CreateKit() error = %v, want %v", err, entity.ErrInvalidComponent)
+		}
+	})
+
+	t.Run("component is a kit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "FILTER").Return(newKit(t, storeId, "FILTER", entity.KitComponent{Sku: "A", Quantity: 1}), nil)
+		if _, err := m.newKitService().CreateKit(input); !errors.Is(err, kit.ErrNestedKit) {
+			t.Errorf("CreateKit() error = %v, want %v", err, kit.ErrNestedKit)
+		}
+	})
+
+	t.Run("kit is a component", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, gomock.Any()).Return(nil, nil).Times(2)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"KIT-1"}).
+			Return([]entity.Kit{*newKit(t, storeId, "KIT-2", entity.KitComponent{Sku: "KIT-1", Quantity: 1})}, nil)
+		if _, err := m.newKitService().CreateKit(input); !errors.Is(err, kit.ErrNestedKit) {
+			t.Errorf("CreateKit() error = %v, want %v", err, kit.ErrNestedKit)
+		}
+	})
+
+	t.Run("sku already taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, gomock.Any()).Return(nil, nil).Times(2)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"KIT-1"}).Return(nil, nil)
+		m.repo.EXPECT().RegisterKit(gomock.Any()).Return(kit.ErrKitAlreadyExists)
+		if _, err := m.newKitService().CreateKit(input); !errors.Is(err, kit.ErrKitAlreadyExists) {
+			t.Errorf("CreateKit() error = %v, want %v", err, kit.ErrKitAlreadyExists)
+		}
+	})
+}
+
+func TestUpdateKit(t *testing.T) {
+	storeId := entity.NewID()
+
+	t.Run("kit updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		existing := newKit(t, storeId, "KIT-1", entity.KitComponent{Sku: "OIL", Quantity: 1})
+		m.repo.EXPECT().GetKit(storeId, existing.ID).Return(existing, nil)
+		m.repo.EXPECT().GetKitBySku(storeId, "OIL").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"KIT-1"}).Return(nil, nil)
+		m.repo.EXPECT().UpdateKit(existing).Return(nil)
+
+		updated, err := m.newKitService().UpdateKit(kit.UpdateKitDtoInput{
+			Store:      storeId,
+			ID:         existing.ID,
+			Sku:        "KIT-1",
+			Title:      "Two oils",
+			Components: []kit.KitComponentDtoInput{{Sku: "OIL", Quantity: 2}},
+		})
+		if err != nil {
+			t.Fatalf("UpdateKit() error = %v", err)
+		}
+		if updated.Title != "Two oils" || updated.Components[0].Quantity != 2 {
+			t.Errorf("UpdateKit() = %+v", updated)
+		}
+	})
+
+	t.Run("kit not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		kitId := entity.NewID()
+		m.repo.EXPECT().GetKit(storeId, kitId).Return(nil, nil)
+		_, err := m.newKitService().UpdateKit(kit.UpdateKitDtoInput{Store: storeId, ID: kitId, Sku: "KIT-1"})
+		if !errors.Is(err, kit.ErrKitNotFound) {
+			t.Errorf("UpdateKit() error = %v, want %v", err, kit.ErrKitNotFound)
+		}
+	})
+}
+
+// TestApplySale tests the propagation of the sales to the kits.
+// It verifies:
+// 1. Selling a kit decrements its components and recomputes the kits that share them
+// 2. A retried kit sale doesn't decrement the components it already claimed
+// 3. A component with a failed listing stays claimed, it isn't applied
+// 4. Selling a component recomputes its kits with the stock of the other components
+// 5. Only the variations of a component are its stock
+// 6. The kits with a component without listings aren't recomputed
+func TestApplySale(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	credentials := &[]store.Credentials{
+		{ID: accountId, OwnerID: storeId, MeliCredential: &common.MeliCredential{AccessToken: "token"}},
+	}
+	filterAndOil := newKit(t, storeId, "KIT-1",
+		entity.KitComponent{Sku: "FILTER", Quantity: 2},
+		entity.KitComponent{Sku: "OIL", Quantity: 1},
+	)
+	oilPack := newKit(t, storeId, "KIT-2", entity.KitComponent{Sku: "OIL", Quantity: 3})
+	saleId := entity.NewID()
+
+	t.Run("kit sold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "KIT-1").Return(filterAndOil, nil)
+		m.repo.EXPECT().ListSaleComponents(saleId).Return(map[string]bool{}, nil)
+		gomock.InOrder(
+			m.repo.EXPECT().ClaimSaleComponent(saleId, "FILTER").Return(true, nil),
+			m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "FILTER", delta: intPtr(-4)}).Return(&stock.Adjustment{
+				Sku: "FILTER",
+				Listings: []stock.ListingAdjustment{
+					{AccountID: accountId, AnnouncementID: "MLB1", PreviousQuantity: 11, Quantity: 7, Status: stock.ListingUpdated},
+				},
+			}, nil),
+			m.repo.EXPECT().CompleteSaleComponent(saleId, "FILTER").Return(nil),
+		)
+		gomock.InOrder(
+			m.repo.EXPECT().ClaimSaleComponent(saleId, "OIL").Return(true, nil),
+			m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "OIL", delta: intPtr(-2)}).Return(&stock.Adjustment{
+				Sku: "OIL",
+				Listings: []stock.ListingAdjustment{
+					{AccountID: accountId, AnnouncementID: "MLB2", PreviousQuantity: 12, Quantity: 10, Status: stock.ListingUpdated},
+				},
+			}, nil),
+			m.repo.EXPECT().CompleteSaleComponent(saleId, "OIL").Return(nil),
+		)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"FILTER", "OIL"}).Return([]entity.Kit{*filterAndOil, *oilPack}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-1", quantity: intPtr(3)}).Return(&stock.Adjustment{
+			Sku: "KIT-1",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB3", PreviousQuantity: 3, Quantity: 3, Status: stock.ListingUnchanged},
+			},
+		}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-2", quantity: intPtr(3)}).Return(&stock.Adjustment{
+			Sku: "KIT-2",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB4", PreviousQuantity: 4, Quantity: 3, Status: stock.ListingFailed, Error: "meli error"},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "KIT-1", 2)
+		if err != nil {
+			t.Fatalf("ApplySale() error = %v", err)
+		}
+		if len(actions) != 3 {
+			t.Fatalf("ApplySale() actions = %d, want 3", len(actions))
+		}
+		if actions[2].AnnouncementID != "MLB4" || actions[2].Status != entity.SyncActionFailed || actions[2].Error != "meli error" {
+			t.Errorf("failed action = %+v", actions[2])
+		}
+	})
+
+	t.Run("retried kit sale", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		// The filter was decremented by the failed attempt, its stock is read from its listings
+		m.repo.EXPECT().GetKitBySku(storeId, "KIT-1").Return(filterAndOil, nil)
+		m.repo.EXPECT().ListSaleComponents(saleId).Return(map[string]bool{"FILTER": true}, nil)
+		m.repo.EXPECT().ClaimSaleComponent(saleId, "OIL").Return(true, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "OIL", delta: intPtr(-2)}).Return(&stock.Adjustment{
+			Sku: "OIL",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB2", PreviousQuantity: 12, Quantity: 10, Status: stock.ListingUpdated},
+			},
+		}, nil)
+		m.repo.EXPECT().CompleteSaleComponent(saleId, "OIL").Return(nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"FILTER", "OIL"}).Return([]entity.Kit{*filterAndOil}, nil)
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("FILTER", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB1", Quantity: 7}}},
+		}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-1", quantity: intPtr(3)}).Return(&stock.Adjustment{
+			Sku: "KIT-1",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB3", PreviousQuantity: 3, Quantity: 3, Status: stock.ListingUnchanged},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "KIT-1", 2)
+		if err != nil {
+			t.Fatalf("ApplySale() error = %v", err)
+		}
+		if len(actions) != 1 || actions[0].AnnouncementID != "MLB2" {
+			t.Errorf("ApplySale() actions = %+v, want only the oil decremented", actions)
+		}
+	})
+
+	t.Run("component with a failed listing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		// The oil stays claimed, applying the sale again would decrement its updated listing twice
+		m.repo.EXPECT().GetKitBySku(storeId, "KIT-2").Return(oilPack, nil)
+		m.repo.EXPECT().ListSaleComponents(saleId).Return(map[string]bool{}, nil)
+		m.repo.EXPECT().ClaimSaleComponent(saleId, "OIL").Return(true, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "OIL", delta: intPtr(-3)}).Return(&stock.Adjustment{
+			Sku: "OIL",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB2", PreviousQuantity: 12, Quantity: 9, Status: stock.ListingUpdated},
+				{AccountID: accountId, AnnouncementID: "MLB5", PreviousQuantity: 12, Quantity: 9, Status: stock.ListingFailed, Error: "meli error"},
+			},
+		}, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return([]entity.Kit{*oilPack}, nil)
+		// The failed listing still has its previous quantity
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-2", quantity: intPtr(4)}).Return(&stock.Adjustment{
+			Sku: "KIT-2",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB4", PreviousQuantity: 3, Quantity: 4, Status: stock.ListingUpdated},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "KIT-2", 1)
+		if err != nil {
+			t.Fatalf("ApplySale() error = %v", err)
+		}
+		if len(actions) != 3 || actions[1].AnnouncementID != "MLB5" || actions[1].Status != entity.SyncActionFailed {
+			t.Errorf("ApplySale() actions = %+v", actions)
+		}
+	})
+
+	t.Run("component claimed by an unfinished attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "KIT-2").Return(oilPack, nil)
+		m.repo.EXPECT().ListSaleComponents(saleId).Return(map[string]bool{"OIL": false}, nil)
+		m.logger.EXPECT().Warn("The component of the sold kit was claimed by an attempt that didn't decrement every listing",
+			zap.String("sale_id", saleId.String()),
+			zap.String("sku", "OIL"),
+		)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return([]entity.Kit{*oilPack}, nil)
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("OIL", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB2", Quantity: 9}}},
+		}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-2", quantity: intPtr(3)}).Return(&stock.Adjustment{
+			Sku: "KIT-2",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB4", PreviousQuantity: 3, Quantity: 3, Status: stock.ListingUnchanged},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "KIT-2", 1)
+		if err != nil || len(actions) != 0 {
+			t.Errorf("ApplySale() = %+v, %v", actions, err)
+		}
+	})
+
+	t.Run("component claimed by a concurrent attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "KIT-2").Return(oilPack, nil)
+		m.repo.EXPECT().ListSaleComponents(saleId).Return(map[string]bool{}, nil)
+		m.repo.EXPECT().ClaimSaleComponent(saleId, "OIL").Return(false, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return(nil, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "KIT-2", 1)
+		if err != nil || len(actions) != 0 {
+			t.Errorf("ApplySale() = %+v, %v", actions, err)
+		}
+	})
+
+	t.Run("component sold", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "OIL").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return([]entity.Kit{*filterAndOil}, nil)
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("FILTER", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB1", Quantity: 9}}},
+		}, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("OIL", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB2", Quantity: 2}, {ID: "MLB5", Quantity: 1}}},
+		}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-1", quantity: intPtr(2)}).Return(&stock.Adjustment{
+			Sku: "KIT-1",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB3", PreviousQuantity: 3, Quantity: 2, Status: stock.ListingUpdated},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "OIL", 1)
+		if err != nil {
+			t.Fatalf("ApplySale() error = %v", err)
+		}
+		if len(actions) != 1 || actions[0].AnnouncementID != "MLB3" || actions[0].Quantity != 2 {
+			t.Errorf("ApplySale() actions = %+v", actions)
+		}
+	})
+
+	t.Run("component sharing a listing with other SKUs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		// Only the variations of the filter are its stock
+		m.repo.EXPECT().GetKitBySku(storeId, "OIL").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return([]entity.Kit{*filterAndOil}, nil)
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("FILTER", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB1", Variations: []common.MeliVariation{
+				{ID: 10, AvailableQuantity: 2, Sku: "FILTER"},
+				{ID: 11, AvailableQuantity: 2, Sku: "FILTER"},
+				{ID: 12, AvailableQuantity: 30, Sku: "AIR-FILTER"},
+			}}}},
+		}, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("OIL", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{{ID: "MLB2", Quantity: 5}}},
+		}, nil)
+		m.stock.EXPECT().AdjustStock(adjustmentMatcher{sku: "KIT-1", quantity: intPtr(2)}).Return(&stock.Adjustment{
+			Sku: "KIT-1",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: accountId, AnnouncementID: "MLB3", PreviousQuantity: 3, Quantity: 2, Status: stock.ListingUpdated},
+			},
+		}, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "OIL", 1)
+		if err != nil {
+			t.Fatalf("ApplySale() error = %v", err)
+		}
+		if len(actions) != 1 || actions[0].Quantity != 2 {
+			t.Errorf("ApplySale() actions = %+v", actions)
+		}
+	})
+
+	t.Run("component without listings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "OIL").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OIL"}).Return([]entity.Kit{*filterAndOil}, nil)
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("FILTER", credentials).Return(&[]announcement.Announcements{
+			{AccountID: accountId, Announcements: &[]common.MeliAnnouncement{}},
+		}, nil)
+		m.logger.EXPECT().Warn("The kit has a component without listings", zap.String("kit_sku", "KIT-1"))
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "OIL", 1)
+		if err != nil || len(actions) != 0 {
+			t.Errorf("ApplySale() = %+v, %v", actions, err)
+		}
+	})
+
+	t.Run("not a kit nor a component", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetKitBySku(storeId, "OTHER").Return(nil, nil)
+		m.repo.EXPECT().ListKitsContaining(storeId, []string{"OTHER"}).Return(nil, nil)
+
+		actions, err := m.newKitService().ApplySale(storeId, saleId, "OTHER", 1)
+		if err != nil || len(actions) != 0 {
+			t.Errorf("ApplySale() = %+v, %v", actions, err)
+		}
+	})
+}
@@ -1,10 +1,16 @@
 package order
 
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
 type OrderWebhookDtoInput struct {
 	ID            string `json:"_id"`
-	Resource      string `json:"resource"`
-	UserID        int    `json:"user_id"`
-	Topic         string `json:"topic"`
+	Resource      string `json:"resource" validate:"required"`
+	UserID        int    `json:"user_id" validate:"required"`
+	Topic         string `json:"topic" validate:"required"`
 	ApplicationID int64  `json:"application_id"`
 	Attempts      int    `json:"attempts"`
 	// Can be converted to time
@@ -12,3 +18,16 @@ type OrderWebhookDtoInput struct {
 	// Can be converted to time
 	Received string `json:"received"`
 }
+
+type ListOrdersDtoInput struct {
+	Store   entity.ID
+	Account *entity.ID
+	Status  string
+	Sku     string
+	Pack    string
+	From    *time.Time
+	To      *time.Time
+	// Returned by the previous page, empty for the first one
+	Cursor string
+	Limit  int
+}
@@ -1,21 +1,28 @@
 package order
 
-import "github.com/Vractos/kloni/entity"
+import (
+	"context"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
 
 type UseCase interface {
 	// ProcessWebhook handles incoming order webhooks from Mercado Livre.
-	// It posts the order notification to a queue for asynchronous processing.
+	// It posts the order notification to a queue for asynchronous processing,
+	// with the trace context of the request.
 	//
 	// Parameters:
+	//   - ctx: Context of the request
 	//   - input: OrderWebhookDtoInput containing the webhook data
 	//
 	// Returns:
 	//   - error: ErrPostingOrderNotification if there's an error posting to the queue, nil otherwise
-	ProcessWebhook(input OrderWebhookDtoInput) error
+	ProcessWebhook(ctx context.Context, input OrderWebhookDtoInput) error
 	// ProcessOrder handles the complete order processing workflow.
 	// It validates the order, retrieves necessary credentials, fetches order data,
-	// and synchronizes quantities across cloned items.
-	// It also registers the order in the database and caches it.
+	// and plans the quantities of the cloned items. The order is registered with
+	// its sync actions in one transaction, which are dispatched afterwards.
 	//
 	// Parameters:
 	//   - order: OrderMessage containing the order details to process
@@ -23,6 +30,49 @@ type UseCase interface {
 	// Returns:
 	//   - error: Various error types depending on the failure point, nil on success
 	ProcessOrder(order OrderMessage) error
+	// DispatchSyncActions executes the sync actions that are due, the ones whose order
+	// wasn't dispatched to the end and the failed attempts to be retried.
+	//
+	// Returns:
+	//   - error: Error if the sync actions can't be retrieved
+	DispatchSyncActions() error
+	// ListOrders lists the orders of a store, the most recent first.
+	//
+	// Parameters:
+	//   - input: ListOrdersDtoInput containing the filters and the cursor of the page
+	//
+	// Returns:
+	//   - *OrderPage: The orders and the cursor of the next page
+	//   - error: ErrInvalidCursor if the cursor is malformed, nil otherwise
+	ListOrders(input ListOrdersDtoInput) (*OrderPage, error)
+	// GetOrderDetail retrieves an order of a store with its items and sync actions.
+	//
+	// Parameters:
+	//   - storeId: ID of the store that owns the order
+	//   - orderId: ID of the order
+	//
+	// Returns:
+	//   - *entity.Order: The order
+	//   - error: ErrOrderNotFound if the order doesn't exist or belongs to another store
+	GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error)
+	// ResumeSync retries the listings of an order whose quantity failed to be set.
+	// The listings that were already updated aren't touched again.
+	//
+	// Parameters:
+	//   - storeId: ID of the store that owns the order
+	//   - orderId: ID of the order
+	//
+	// Returns:
+	//   - *entity.Order: The order with its sync actions
+	//   - error: ErrOrderNotFound if the order doesn't exist or belongs to another store
+	ResumeSync(storeId, orderId entity.ID) (*entity.Order, error)
+}
+
+// OrderPage is a page of orders
+type OrderPage struct {
+	Orders []entity.Order
+	// Empty on the last page
+	NextCursor string
 }
 
 /*
@@ -38,11 +88,14 @@ type OrderMessage struct {
 	OrderId       string
 	Attempts      int
 	ReceiptHandle string
+	// Trace context of the webhook that posted the notification, e.g. traceparent
+	TraceContext map[string]string
 }
 
 // Queue producer interface
 type QueueProducer interface {
-	PostOrderNotification(input OrderWebhookDtoInput) error
+	// Posts the notification with the trace context of ctx
+	PostOrderNotification(ctx context.Context, input OrderWebhookDtoInput) error
 }
 
 type QueueConsumer interface {
@@ -63,12 +116,59 @@ type Queue interface {
 #########################################
 */
 
+// DueSyncAction is a sync action that is due with the order it belongs to
+type DueSyncAction struct {
+	entity.SyncAction
+	OrderID       entity.ID
+	MarketplaceID string
+	StoreID       entity.ID
+}
+
 type RepoWriter interface {
-	RegisterOrder(o *entity.Order) error
+	// Registers the order with its pending sync actions and applies its stock changes, in one transaction
+	RegisterOrder(ctx context.Context, o *entity.Order) error
+	// Registers the orders of a pack in one transaction, as RegisterOrder does for each of them
+	RegisterPack(ctx context.Context, orders []*entity.Order) error
+	// Claims a pending sync action that is due before executing it, it isn't claimed again before the lease expires.
+	// A quantity isn't claimed while another claim sets the quantity of the same listing. The attempts of the action
+	// and the quantity set by its last attempt are refreshed. Returns false when the action isn't pending or due, or is held back
+	ClaimSyncAction(action *entity.SyncAction, claim entity.ID, lease time.Duration) (bool, error)
+	// Records the result of an attempt of a sync action, with the changes made by a kit sale, in one transaction.
+	// Returns false, without recording anything, when the action is no longer held by the claim
+	AcknowledgeSyncAction(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error)
+	// Makes the failed quantities of the order pending again. The sales are applied to the quantity the listings
+	// have when they're executed, the absolute quantities aren't resumed when a newer order planned the quantity
+	// of the same listing. Returns the number of resumed actions.
+	ResumeSyncActions(orderId entity.ID) (int, error)
+}
+
+// OrderCursor is the position of the last order of a page
+type OrderCursor struct {
+	DateCreated time.Time
+	ID          entity.ID
+}
+
+// OrderFilter filters the orders of a store, the empty fields are ignored
+type OrderFilter struct {
+	Store   entity.ID
+	Account *entity.ID
+	Status  string
+	Sku     string
+	Pack    string
+	From    *time.Time
+	To      *time.Time
+	After   *OrderCursor
+	Limit   int
 }
 
 type RepoReader interface {
-	GetOrder(orderMarketplaceId string) (*entity.Order, error)
+	GetOrder(ctx context.Context, orderMarketplaceId string) (*entity.Order, error)
+	// Lists the orders with their items, ordered by the creation date and the ID, descending
+	ListOrders(filter OrderFilter) ([]entity.Order, error)
+	// Retrieves an order with its items and sync actions, nil if it doesn't belong to the store
+	GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error)
+	// Claims the pending sync actions that are due, they aren't claimed again before the lease expires
+	ClaimDueSyncActions(claim entity.ID, limit int, lease time.Duration) ([]DueSyncAction, error)
 }
 
 type Repository interface {
@@ -85,11 +185,11 @@ type Repository interface {
 */
 
 type CacheWriter interface {
-	SetOrder(o *entity.Order) error
+	SetOrder(ctx context.Context, o *entity.Order) error
 }
 
 type CacheReader interface {
-	GetOrder(orderId string) (*entity.OrderStatus, error)
+	GetOrder(ctx context.Context, orderId string) (*entity.OrderStatus, error)
 }
 
 type Cache interface {
@@ -10,7 +10,9 @@
 package mock_order
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/Vractos/kloni/entity"
 	order "github.com/Vractos/kloni/usecases/order"
@@ -40,6 +42,50 @@ func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
 	return m.recorder
 }
 
+// DispatchSyncActions mocks base method.
+func (m *MockUseCase) DispatchSyncActions() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchSyncActions")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchSyncActions indicates an expected call of DispatchSyncActions.
+func (mr *MockUseCaseMockRecorder) DispatchSyncActions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchSyncActions", reflect.TypeOf((*MockUseCase)(nil).DispatchSyncActions))
+}
+
+// GetOrderDetail mocks base method.
+func (m *MockUseCase) GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderDetail", storeId, orderId)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderDetail indicates an expected call of GetOrderDetail.
+func (mr *MockUseCaseMockRecorder) GetOrderDetail(storeId, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderDetail", reflect.TypeOf((*MockUseCase)(nil).GetOrderDetail), storeId, orderId)
+}
+
+// ListOrders mocks base method.
+func (m *MockUseCase) ListOrders(input order.ListOrdersDtoInput) (*order.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", input)
+	ret0, _ := ret[0].(*order.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockUseCaseMockRecorder) ListOrders(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockUseCase)(nil).ListOrders), input)
+}
+
 // ProcessOrder mocks base method.
 func (m *MockUseCase) ProcessOrder(order order.OrderMessage) error {
 	m.ctrl.T.Helper()
@@ -55,17 +101,32 @@ func (mr *MockUseCaseMockRecorder) ProcessOrder(order any) *gomock.Call {
 }
 
 // ProcessWebhook mocks base method.
-func (m *MockUseCase) ProcessWebhook(input order.OrderWebhookDtoInput) error {
+func (m *MockUseCase) ProcessWebhook(ctx context.Context, input order.OrderWebhookDtoInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessWebhook", input)
+	ret := m.ctrl.Call(m, "ProcessWebhook", ctx, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ProcessWebhook indicates an expected call of ProcessWebhook.
-func (mr *MockUseCaseMockRecorder) ProcessWebhook(input any) *gomock.Call {
+func (mr *MockUseCaseMockRecorder) ProcessWebhook(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessWebhook", reflect.TypeOf((*MockUseCase)(nil).ProcessWebhook), ctx, input)
+}
+
+// ResumeSync mocks base method.
+func (m *MockUseCase) ResumeSync(storeId, orderId entity.ID) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSync", storeId, orderId)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeSync indicates an expected call of ResumeSync.
+func (mr *MockUseCaseMockRecorder) ResumeSync(storeId, orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessWebhook", reflect.TypeOf((*MockUseCase)(nil).ProcessWebhook), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSync", reflect.TypeOf((*MockUseCase)(nil).ResumeSync), storeId, orderId)
 }
 
 // MockQueueProducer is a mock of QueueProducer interface.
@@ -92,17 +153,17 @@ func (m *MockQueueProducer) EXPECT() *MockQueueProducerMockRecorder {
 }
 
 // PostOrderNotification mocks base method.
-func (m *MockQueueProducer) PostOrderNotification(input order.OrderWebhookDtoInput) error {
+func (m *MockQueueProducer) PostOrderNotification(ctx context.Context, input order.OrderWebhookDtoInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostOrderNotification", input)
+	ret := m.ctrl.Call(m, "PostOrderNotification", ctx, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PostOrderNotification indicates an expected call of PostOrderNotification.
-func (mr *MockQueueProducerMockRecorder) PostOrderNotification(input any) *gomock.Call {
+func (mr *MockQueueProducerMockRecorder) PostOrderNotification(ctx, input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOrderNotification", reflect.TypeOf((*MockQueueProducer)(nil).PostOrderNotification), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOrderNotification", reflect.TypeOf((*MockQueueProducer)(nil).PostOrderNotification), ctx, input)
 }
 
 // MockQueueConsumer is a mock of QueueConsumer interface.
@@ -208,17 +269,17 @@ func (mr *MockQueueMockRecorder) DeleteOrderNotification(receiptHandle any) *gom
 }
 
 // PostOrderNotification mocks base method.
-func (m *MockQueue) PostOrderNotification(input order.OrderWebhookDtoInput) error {
+func (m *MockQueue) PostOrderNotification(ctx context.Context, input order.OrderWebhookDtoInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostOrderNotification", input)
+	ret := m.ctrl.Call(m, "PostOrderNotification", ctx, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PostOrderNotification indicates an expected call of PostOrderNotification.
-func (mr *MockQueueMockRecorder) PostOrderNotification(input any) *gomock.Call {
+func (mr *MockQueueMockRecorder) PostOrderNotification(ctx, input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOrderNotification", reflect.TypeOf((*MockQueue)(nil).PostOrderNotification), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostOrderNotification", reflect.TypeOf((*MockQueue)(nil).PostOrderNotification), ctx, input)
 }
 
 // MockRepoWriter is a mock of RepoWriter interface.
@@ -244,18 +305,77 @@ func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeSyncAction mocks base method.
+func (m *MockRepoWriter) AcknowledgeSyncAction(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeSyncAction", orderId, claim, action, followUps)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcknowledgeSyncAction indicates an expected call of AcknowledgeSyncAction.
+func (mr *MockRepoWriterMockRecorder) AcknowledgeSyncAction(orderId, claim, action, followUps any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeSyncAction", reflect.TypeOf((*MockRepoWriter)(nil).AcknowledgeSyncAction), orderId, claim, action, followUps)
+}
+
+// ClaimSyncAction mocks base method.
+func (m *MockRepoWriter) ClaimSyncAction(action *entity.SyncAction, claim entity.ID, lease time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimSyncAction", action, claim, lease)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimSyncAction indicates an expected call of ClaimSyncAction.
+func (mr *MockRepoWriterMockRecorder) ClaimSyncAction(action, claim, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimSyncAction", reflect.TypeOf((*MockRepoWriter)(nil).ClaimSyncAction), action, claim, lease)
+}
+
 // RegisterOrder mocks base method.
-func (m *MockRepoWriter) RegisterOrder(o *entity.Order) error {
+func (m *MockRepoWriter) RegisterOrder(ctx context.Context, o *entity.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterOrder", o)
+	ret := m.ctrl.Call(m, "RegisterOrder", ctx, o)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RegisterOrder indicates an expected call of RegisterOrder.
-func (mr *MockRepoWriterMockRecorder) RegisterOrder(o any) *gomock.Call {
+func (mr *MockRepoWriterMockRecorder) RegisterOrder(ctx, o any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockRepoWriter)(nil).RegisterOrder), o)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockRepoWriter)(nil).RegisterOrder), ctx, o)
+}
+
+// RegisterPack mocks base method.
+func (m *MockRepoWriter) RegisterPack(ctx context.Context, orders []*entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPack", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterPack indicates an expected call of RegisterPack.
+func (mr *MockRepoWriterMockRecorder) RegisterPack(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPack", reflect.TypeOf((*MockRepoWriter)(nil).RegisterPack), ctx, orders)
+}
+
+// ResumeSyncActions mocks base method.
+func (m *MockRepoWriter) ResumeSyncActions(orderId entity.ID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSyncActions", orderId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeSyncActions indicates an expected call of ResumeSyncActions.
+func (mr *MockRepoWriterMockRecorder) ResumeSyncActions(orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSyncActions", reflect.TypeOf((*MockRepoWriter)(nil).ResumeSyncActions), orderId)
 }
 
 // MockRepoReader is a mock of RepoReader interface.
@@ -281,19 +401,64 @@ func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
 	return m.recorder
 }
 
+// ClaimDueSyncActions mocks base method.
+func (m *MockRepoReader) ClaimDueSyncActions(claim entity.ID, limit int, lease time.Duration) ([]order.DueSyncAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSyncActions", claim, limit, lease)
+	ret0, _ := ret[0].([]order.DueSyncAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSyncActions indicates an expected call of ClaimDueSyncActions.
+func (mr *MockRepoReaderMockRecorder) ClaimDueSyncActions(claim, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSyncActions", reflect.TypeOf((*MockRepoReader)(nil).ClaimDueSyncActions), claim, limit, lease)
+}
+
 // GetOrder mocks base method.
-func (m *MockRepoReader) GetOrder(orderMarketplaceId string) (*entity.Order, error) {
+func (m *MockRepoReader) GetOrder(ctx context.Context, orderMarketplaceId string) (*entity.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", orderMarketplaceId)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderMarketplaceId)
 	ret0, _ := ret[0].(*entity.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockRepoReaderMockRecorder) GetOrder(orderMarketplaceId any) *gomock.Call {
+func (mr *MockRepoReaderMockRecorder) GetOrder(ctx, orderMarketplaceId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepoReader)(nil).GetOrder), orderMarketplaceId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepoReader)(nil).GetOrder), ctx, orderMarketplaceId)
+}
+
+// GetOrderDetail mocks base method.
+func (m *MockRepoReader) GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderDetail", storeId, orderId)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderDetail indicates an expected call of GetOrderDetail.
+func (mr *MockRepoReaderMockRecorder) GetOrderDetail(storeId, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderDetail", reflect.TypeOf((*MockRepoReader)(nil).GetOrderDetail), storeId, orderId)
+}
+
+// ListOrders mocks base method.
+func (m *MockRepoReader) ListOrders(filter order.OrderFilter) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", filter)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockRepoReaderMockRecorder) ListOrders(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockRepoReader)(nil).ListOrders), filter)
 }
 
 // MockRepository is a mock of Repository interface.
@@ -319,33 +484,137 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeSyncAction mocks base method.
+func (m *MockRepository) AcknowledgeSyncAction(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeSyncAction", orderId, claim, action, followUps)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcknowledgeSyncAction indicates an expected call of AcknowledgeSyncAction.
+func (mr *MockRepositoryMockRecorder) AcknowledgeSyncAction(orderId, claim, action, followUps any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeSyncAction", reflect.TypeOf((*MockRepository)(nil).AcknowledgeSyncAction), orderId, claim, action, followUps)
+}
+
+// ClaimDueSyncActions mocks base method.
+func (m *MockRepository) ClaimDueSyncActions(claim entity.ID, limit int, lease time.Duration) ([]order.DueSyncAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSyncActions", claim, limit, lease)
+	ret0, _ := ret[0].([]order.DueSyncAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSyncActions indicates an expected call of ClaimDueSyncActions.
+func (mr *MockRepositoryMockRecorder) ClaimDueSyncActions(claim, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSyncActions", reflect.TypeOf((*MockRepository)(nil).ClaimDueSyncActions), claim, limit, lease)
+}
+
+// ClaimSyncAction mocks base method.
+func (m *MockRepository) ClaimSyncAction(action *entity.SyncAction, claim entity.ID, lease time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimSyncAction", action, claim, lease)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimSyncAction indicates an expected call of ClaimSyncAction.
+func (mr *MockRepositoryMockRecorder) ClaimSyncAction(action, claim, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimSyncAction", reflect.TypeOf((*MockRepository)(nil).ClaimSyncAction), action, claim, lease)
+}
+
 // GetOrder mocks base method.
-func (m *MockRepository) GetOrder(orderMarketplaceId string) (*entity.Order, error) {
+func (m *MockRepository) GetOrder(ctx context.Context, orderMarketplaceId string) (*entity.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", orderMarketplaceId)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderMarketplaceId)
 	ret0, _ := ret[0].(*entity.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockRepositoryMockRecorder) GetOrder(orderMarketplaceId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetOrder(ctx, orderMarketplaceId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepository)(nil).GetOrder), orderMarketplaceId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepository)(nil).GetOrder), ctx, orderMarketplaceId)
+}
+
+// GetOrderDetail mocks base method.
+func (m *MockRepository) GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderDetail", storeId, orderId)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderDetail indicates an expected call of GetOrderDetail.
+func (mr *MockRepositoryMockRecorder) GetOrderDetail(storeId, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderDetail", reflect.TypeOf((*MockRepository)(nil).GetOrderDetail), storeId, orderId)
+}
+
+// ListOrders mocks base method.
+func (m *MockRepository) ListOrders(filter order.OrderFilter) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", filter)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockRepositoryMockRecorder) ListOrders(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockRepository)(nil).ListOrders), filter)
 }
 
 // RegisterOrder mocks base method.
-func (m *MockRepository) RegisterOrder(o *entity.Order) error {
+func (m *MockRepository) RegisterOrder(ctx context.Context, o *entity.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterOrder", o)
+	ret := m.ctrl.Call(m, "RegisterOrder", ctx, o)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RegisterOrder indicates an expected call of RegisterOrder.
-func (mr *MockRepositoryMockRecorder) RegisterOrder(o any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) RegisterOrder(ctx, o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockRepository)(nil).RegisterOrder), ctx, o)
+}
+
+// RegisterPack mocks base method.
+func (m *MockRepository) RegisterPack(ctx context.Context, orders []*entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPack", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterPack indicates an expected call of RegisterPack.
+func (mr *MockRepositoryMockRecorder) RegisterPack(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPack", reflect.TypeOf((*MockRepository)(nil).RegisterPack), ctx, orders)
+}
+
+// ResumeSyncActions mocks base method.
+func (m *MockRepository) ResumeSyncActions(orderId entity.ID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSyncActions", orderId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeSyncActions indicates an expected call of ResumeSyncActions.
+func (mr *MockRepositoryMockRecorder) ResumeSyncActions(orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockRepository)(nil).RegisterOrder), o)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSyncActions", reflect.TypeOf((*MockRepository)(nil).ResumeSyncActions), orderId)
 }
 
 // MockCacheWriter is a mock of CacheWriter interface.
@@ -372,17 +641,17 @@ func (m *MockCacheWriter) EXPECT() *MockCacheWriterMockRecorder {
 }
 
 // SetOrder mocks base method.
-func (m *MockCacheWriter) SetOrder(o *entity.Order) error {
+func (m *MockCacheWriter) SetOrder(ctx context.Context, o *entity.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetOrder", o)
+	ret := m.ctrl.Call(m, "SetOrder", ctx, o)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetOrder indicates an expected call of SetOrder.
-func (mr *MockCacheWriterMockRecorder) SetOrder(o any) *gomock.Call {
+func (mr *MockCacheWriterMockRecorder) SetOrder(ctx, o any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockCacheWriter)(nil).SetOrder), o)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockCacheWriter)(nil).SetOrder), ctx, o)
 }
 
 // MockCacheReader is a mock of CacheReader interface.
@@ -409,18 +678,18 @@ func (m *MockCacheReader) EXPECT() *MockCacheReaderMockRecorder {
 }
 
 // GetOrder mocks base method.
-func (m *MockCacheReader) GetOrder(orderId string) (*entity.OrderStatus, error) {
+func (m *MockCacheReader) GetOrder(ctx context.Context, orderId string) (*entity.OrderStatus, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", orderId)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderId)
 	ret0, _ := ret[0].(*entity.OrderStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockCacheReaderMockRecorder) GetOrder(orderId any) *gomock.Call {
+func (mr *MockCacheReaderMockRecorder) GetOrder(ctx, orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockCacheReader)(nil).GetOrder), orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockCacheReader)(nil).GetOrder), ctx, orderId)
 }
 
 // MockCache is a mock of Cache interface.
@@ -447,30 +716,30 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 }
 
 // GetOrder mocks base method.
-func (m *MockCache) GetOrder(orderId string) (*entity.OrderStatus, error) {
+func (m *MockCache) GetOrder(ctx context.Context, orderId string) (*entity.OrderStatus, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", orderId)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderId)
 	ret0, _ := ret[0].(*entity.OrderStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockCacheMockRecorder) GetOrder(orderId any) *gomock.Call {
+func (mr *MockCacheMockRecorder) GetOrder(ctx, orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockCache)(nil).GetOrder), orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockCache)(nil).GetOrder), ctx, orderId)
 }
 
 // SetOrder mocks base method.
-func (m *MockCache) SetOrder(o *entity.Order) error {
+func (m *MockCache) SetOrder(ctx context.Context, o *entity.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetOrder", o)
+	ret := m.ctrl.Call(m, "SetOrder", ctx, o)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetOrder indicates an expected call of SetOrder.
-func (mr *MockCacheMockRecorder) SetOrder(o any) *gomock.Call {
+func (mr *MockCacheMockRecorder) SetOrder(ctx, o any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockCache)(nil).SetOrder), o)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockCache)(nil).SetOrder), ctx, o)
 }
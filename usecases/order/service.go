@@ -2,13 +2,26 @@
 package order
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
+	"github.com/Vractos/kloni/usecases/alert"
+	"github.com/Vractos/kloni/usecases/allocation"
 	"github.com/Vractos/kloni/usecases/announcement"
 	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/kit"
 	"github.com/Vractos/kloni/usecases/store"
+	"github.com/Vractos/kloni/usecases/webhook"
 	"github.com/Vractos/kloni/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +41,23 @@ var (
 	ErrSyncingQuantities = errors.New("error syncing quantities")
 	// ErrCredentialsNotFound is returned when store credentials cannot be found
 	ErrCredentialsNotFound = errors.New("credentials not found")
+	// ErrInvalidCursor is returned when the cursor of a page of orders is malformed
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrVariationNotFound is returned when the variation of a sync action was removed from its listing
+	ErrVariationNotFound = errors.New("variation not found")
+)
+
+const (
+	// defaultPageSize is the number of orders of a page when the limit isn't informed
+	defaultPageSize = 20
+	// maxPageSize is the maximum number of orders of a page
+	maxPageSize = 100
+	// syncActionMaxAttempts is the number of attempts before a sync action fails
+	syncActionMaxAttempts = 5
+	// syncActionsBatch is the number of sync actions executed on each run of DispatchSyncActions
+	syncActionsBatch = 50
+	// syncActionLease is how long a claimed sync action isn't claimed again, it's longer than its execution
+	syncActionLease = 2 * time.Minute
 )
 
 type SyncContext struct {
@@ -37,6 +67,12 @@ type SyncContext struct {
 	CredentialsHashMap  map[interface{}]store.Credentials
 	ProcessedItems      map[string]bool
 	ProcessedVariations map[string][]int
+	// Changes planned to the clones while the item is synchronized
+	Actions []entity.SyncAction
+	// Largest quantity of the clones after the sale, from the quantities they had when it was planned
+	Quantity int
+	// True stock of the item after the sale, nil when its SKU doesn't have an allocation policy
+	Allocation *allocation.Allocation
 }
 
 // OrderService handles all order-related operations including processing orders,
@@ -46,6 +82,10 @@ type OrderService struct {
 	meli     common.MercadoLivre  // Mercado Livre API client
 	store    store.UseCase        // Store management use case
 	announce announcement.UseCase // Announcement management use case
+	alert    alert.UseCase        // Stock alerts use case
+	kit      kit.UseCase          // Kits use case, propagates the sales to the kits
+	allocate allocation.Allocator // Allocator of the true stock of the SKUs with a policy
+	webhook  webhook.Publisher    // Publisher of the events to the webhooks
 	repo     Repository           // Order repository for data persistence
 	cache    Cache                // Cache service for temporary data storage
 	logger   common.Logger        // Logger for error and info logging
@@ -59,6 +99,10 @@ type OrderService struct {
 //   - mercadolivre: Mercado Livre API client
 //   - storeUseCase: Store management use case
 //   - announceUseCase: Announcement management use case
+//   - alertUseCase: Stock alerts use case
+//   - kitUseCase: Kits use case, propagates the sales to the kits
+//   - allocator: Allocator of the true stock of the SKUs with an allocation policy
+//   - publisher: Publisher of the events to the webhooks
 //   - repository: Order repository for data persistence
 //   - cache: Cache service for temporary data storage
 //   - logger: Logger for error and info logging
@@ -70,6 +114,10 @@ func NewOrderService(
 	mercadolivre common.MercadoLivre,
 	storeUseCase store.UseCase,
 	announceUseCase announcement.UseCase,
+	alertUseCase alert.UseCase,
+	kitUseCase kit.UseCase,
+	allocator allocation.Allocator,
+	publisher webhook.Publisher,
 	repository Repository,
 	cache Cache,
 	logger common.Logger,
@@ -79,6 +127,10 @@ func NewOrderService(
 		meli:     mercadolivre,
 		store:    storeUseCase,
 		announce: announceUseCase,
+		alert:    alertUseCase,
+		kit:      kitUseCase,
+		allocate: allocator,
+		webhook:  publisher,
 		repo:     repository,
 		cache:    cache,
 		logger:   logger,
@@ -88,13 +140,16 @@ func NewOrderService(
 // ProcessWebhook handles incoming order webhooks from Mercado Livre.
 // It posts the order notification to a queue for asynchronous processing.
 //
+// The trace context of the webhook request is carried by the notification.
+//
 // Parameters:
+//   - ctx: Context of the webhook request
 //   - input: OrderWebhookDtoInput containing the webhook data
 //
 // Returns:
 //   - error: ErrPostingOrderNotification if there's an error posting to the queue, nil otherwise
-func (o *OrderService) ProcessWebhook(input OrderWebhookDtoInput) error {
-	if err := o.queue.PostOrderNotification(input); err != nil {
+func (o *OrderService) ProcessWebhook(ctx context.Context, input OrderWebhookDtoInput) error {
+	if err := o.queue.PostOrderNotification(ctx, input); err != nil {
 		o.logger.Error(
 			"Error to post order notification",
 			err,
@@ -110,16 +165,27 @@ func (o *OrderService) ProcessWebhook(input OrderWebhookDtoInput) error {
 
 // ProcessOrder handles the complete order processing workflow.
 // It validates the order, retrieves necessary credentials, fetches order data,
-// and synchronizes quantities across cloned items.
-// It also registers the order in the database and caches it.
+// and plans the quantities of the cloned items as pending sync actions.
+// The order, its sync actions and the changes to the true stock are registered in one
+// transaction, so Mercado Livre is only updated after the order is stored. The sync actions
+// are then dispatched, and the ones that aren't acknowledged are left to DispatchSyncActions.
+// The processing continues the trace of the webhook that posted the notification.
 //
 // Parameters:
 //   - order: OrderMessage containing the order details to process
 //
 // Returns:
 //   - error: Various error types depending on the failure point, nil on success
-func (o *OrderService) ProcessOrder(order OrderMessage) error {
-	if precessed, err := o.orderExists(order); err != nil {
+func (o *OrderService) ProcessOrder(order OrderMessage) (err error) {
+	ctx, span := metrics.StartSpan(
+		metrics.ExtractTraceContext(context.Background(), order.TraceContext),
+		"order.ProcessOrder",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("order_id", order.OrderId), attribute.String("store_id", order.Store)),
+	)
+	defer func() { metrics.EndSpan(span, err) }()
+
+	if precessed, err := o.orderExists(ctx, order); err != nil {
 		return err
 	} else if precessed {
 		return nil
@@ -131,7 +197,13 @@ func (o *OrderService) ProcessOrder(order OrderMessage) error {
 		return err
 	}
 
-	orderData, err := o.fetchOrderData(order.OrderId, credentials.AccessToken)
+	orderData, err := o.fetchOrderData(ctx, order.OrderId, credentials.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	// The orders of a cart are processed as one unit, so a SKU bought in several of them is synchronized once
+	packOrders, err := o.fetchPackOrders(ctx, orderData, credentials.AccessToken)
 	if err != nil {
 		return err
 	}
@@ -139,19 +211,29 @@ func (o *OrderService) ProcessOrder(order OrderMessage) error {
 	// Track processed items and variations to avoid duplicate processing
 	processedItems := make(map[string]bool)
 	processedVariations := make(map[string][]int)
-	for _, item := range orderData.Items {
-		if item.VariationID == 0 {
-			processedItems[item.ID] = true
-		} else {
-			sku := item.Sku
-			if _, exists := processedVariations[sku]; !exists {
-				processedVariations[sku] = make([]int, 0)
+	items := make([]common.OrderItem, 0, len(orderData.Items))
+	for _, packOrder := range packOrders {
+		for _, item := range packOrder.Items {
+			if item.VariationID == 0 {
+				processedItems[item.ID] = true
+			} else {
+				sku := item.Sku
+				if _, exists := processedVariations[sku]; !exists {
+					processedVariations[sku] = make([]int, 0)
+				}
+				processedVariations[sku] = append(processedVariations[sku], item.VariationID)
 			}
-			processedVariations[sku] = append(processedVariations[sku], item.VariationID)
 		}
+		items = append(items, packOrder.Items...)
 	}
+	removeDuplicateItems(&items)
 
-	for _, item := range orderData.Items {
+	var (
+		syncActions  []entity.SyncAction
+		stockChanges []entity.StockChange
+		synced       []*SyncContext
+	)
+	for _, item := range items {
 		if item.Sku == "" {
 			o.logger.Warn("The product doesn't have sku",
 				zap.String("order_id", orderData.ID),
@@ -160,7 +242,7 @@ func (o *OrderService) ProcessOrder(order OrderMessage) error {
 			continue
 		}
 
-		ctx := &SyncContext{
+		syncCtx := &SyncContext{
 			Item:                item,
 			Credentials:         credentials,
 			AllCredentials:      allCredentials,
@@ -169,57 +251,357 @@ func (o *OrderService) ProcessOrder(order OrderMessage) error {
 			ProcessedVariations: processedVariations,
 		}
 
-		if err := o.syncItemQuantities(ctx); err != nil {
+		_, itemSpan := metrics.StartSpan(ctx, "order.SyncItem", trace.WithAttributes(
+			attribute.String("sku", item.Sku),
+			attribute.String("announcement_id", item.ID),
+		))
+		err := o.syncItemQuantities(syncCtx)
+		metrics.EndSpan(itemSpan, err)
+		if err != nil {
+			o.publish(entity.NewEvent(credentials.OwnerID, entity.SyncFailed, entity.SyncFailedData{
+				MarketplaceID: orderData.ID,
+				Sku:           item.Sku,
+				Error:         err.Error(),
+			}))
 			return err
 		}
+		syncActions = append(syncActions, syncCtx.Actions...)
+		// The sale is propagated to the kits after the clones of the item are updated
+		syncActions = append(syncActions, *newSyncAction(entity.SyncKitSale, credentials.ID, item.ID, item.VariationID, item.Sku, item.Quantity))
+		if syncCtx.Allocation != nil {
+			stockChanges = append(stockChanges, entity.StockChange{StoreID: credentials.OwnerID, Sku: item.Sku, Delta: -item.Quantity})
+		}
+		synced = append(synced, syncCtx)
 	}
 
 	// ------------------------------------
 	// --------- STORE ORDER IN DB --------
 	// ------------------------------------
-	orderItems := make([]entity.OrderItem, len(orderData.Items))
-	for i, item := range orderData.Items {
-		orderItems[i] = entity.OrderItem{
-			Title:       item.Title,
-			Quantity:    item.Quantity,
-			Sku:         item.Sku,
-			VariationID: item.VariationID,
+	odrs := make([]*entity.Order, len(packOrders))
+	for i, packOrder := range packOrders {
+		odr, err := o.newOrder(credentials.ID, packOrder)
+		if err != nil {
+			o.logger.Error("Fail to generate the order entity", err, zap.String("order_id", packOrder.ID))
+			return err
 		}
+		odrs[i] = odr
 	}
+	// The sync actions of the pack belong to the notified order
+	odr := odrs[0]
+	odr.SyncActions = syncActions
+	odr.StockChanges = stockChanges
 
-	odr, err := entity.NewOrder(credentials.ID, order.OrderId, orderItems, entity.OrderStatus(orderData.Status))
-	if err != nil {
-		o.logger.Error("Fail to generate the order entity", err, zap.String("order_id", orderData.ID))
-		return err
+	if len(odrs) == 1 {
+		err = o.repo.RegisterOrder(ctx, odr)
+	} else {
+		err = o.repo.RegisterPack(ctx, odrs)
 	}
-
-	if err := o.repo.RegisterOrder(odr); err != nil {
+	if err != nil {
 		o.logger.Error("Fail to store the order", err, zap.String("order_id", orderData.ID))
 		return errors.New("couldn't store order")
 	}
 
-	// ------------------------------
-	// --------- CACHE ORDER --------
-	// ------------------------------
-	if err := o.cache.SetOrder(odr); err != nil {
-		o.logger.Warn("Fail to cache the order", zap.String("order_id", orderData.ID))
+	o.dispatch(ctx, odr, credentials.OwnerID, credMap)
+	for _, syncCtx := range synced {
+		o.evaluateStock(syncCtx)
+		o.publishQuantitySynced(credentials.OwnerID, orderData.ID, syncCtx.Item.Sku, odr.SyncActions)
+	}
+
+	for _, odr := range odrs {
+		eventItems := make([]entity.EventOrderItem, len(odr.Items))
+		for i, item := range odr.Items {
+			eventItems[i] = entity.EventOrderItem{
+				Sku:         item.Sku,
+				Title:       item.Title,
+				Quantity:    item.Quantity,
+				VariationID: item.VariationID,
+			}
+		}
+		o.publish(entity.NewEvent(credentials.OwnerID, entity.OrderProcessed, entity.OrderProcessedData{
+			OrderID:       odr.ID,
+			MarketplaceID: odr.MarketplaceID,
+			AccountID:     odr.AccountID,
+			Status:        odr.Status,
+			TotalAmount:   odr.TotalAmount,
+			CurrencyID:    odr.CurrencyID,
+			Items:         eventItems,
+		}))
+
+		// ------------------------------
+		// --------- CACHE ORDER --------
+		// ------------------------------
+		if err := o.cache.SetOrder(ctx, odr); err != nil {
+			o.logger.Warn("Fail to cache the order", zap.String("order_id", odr.MarketplaceID))
+		}
 	}
 
 	o.queue.DeleteOrderNotification(order.ReceiptHandle)
 	return nil
 }
 
+// ListOrders lists the orders of a store, the most recent first.
+// The cursor is opaque to the clients, it encodes the position of the last order of the previous page.
+//
+// Parameters:
+//   - input: ListOrdersDtoInput containing the filters and the cursor of the page
+//
+// Returns:
+//   - *OrderPage: The orders and the cursor of the next page
+//   - error: ErrInvalidCursor if the cursor is malformed, nil otherwise
+func (o *OrderService) ListOrders(input ListOrdersDtoInput) (*OrderPage, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	} else if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	filter := OrderFilter{
+		Store:   input.Store,
+		Account: input.Account,
+		Status:  input.Status,
+		Sku:     input.Sku,
+		Pack:    input.Pack,
+		From:    input.From,
+		To:      input.To,
+		// One more order is fetched to know if there's a next page
+		Limit: limit + 1,
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	orders, err := o.repo.ListOrders(filter)
+	if err != nil {
+		o.logger.Error("Fail to list the orders", err, zap.String("store_id", input.Store.String()))
+		return nil, err
+	}
+
+	page := &OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		last := page.Orders[limit-1]
+		page.NextCursor = encodeCursor(&OrderCursor{DateCreated: last.DateCreated, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// GetOrderDetail retrieves an order of a store with its items and sync actions.
+//
+// Parameters:
+//   - storeId: ID of the store that owns the order
+//   - orderId: ID of the order
+//
+// Returns:
+//   - *entity.Order: The order
+//   - error: ErrOrderNotFound if the order doesn't exist or belongs to another store
+func (o *OrderService) GetOrderDetail(storeId, orderId entity.ID) (*entity.Order, error) {
+	odr, err := o.repo.GetOrderDetail(storeId, orderId)
+	if err != nil {
+		o.logger.Error("Fail to retrieve the order", err, zap.String("store_id", storeId.String()), zap.String("order_id", orderId.String()))
+		return nil, err
+	}
+	if odr == nil {
+		return nil, ErrOrderNotFound
+	}
+	return odr, nil
+}
+
+// ResumeSync retries the listings of an order whose quantity failed to be set.
+// The sync actions keep the state of each listing, so the listings that were already updated
+// aren't touched again. A sale is removed again from the quantity the listing has when it's resumed,
+// the quantities set by the older orders aren't resumed once the listing was planned again.
+// The failed kit sales aren't resumed.
+//
+// Parameters:
+//   - storeId: ID of the store that owns the order
+//   - orderId: ID of the order
+//
+// Returns:
+//   - *entity.Order: The order with its sync actions
+//   - error: ErrOrderNotFound if the order doesn't exist or belongs to another store
+func (o *OrderService) ResumeSync(storeId, orderId entity.ID) (*entity.Order, error) {
+	odr, err := o.GetOrderDetail(storeId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if entity.NewSyncProgress(odr.SyncActions).Failed == 0 {
+		return odr, nil
+	}
+
+	resumed, err := o.repo.ResumeSyncActions(odr.ID)
+	if err != nil {
+		o.logger.Error("Fail to resume the sync of the order", err, zap.String("order_id", orderId.String()))
+		return nil, err
+	}
+	if resumed == 0 {
+		return odr, nil
+	}
+	return o.GetOrderDetail(storeId, orderId)
+}
+
+// evaluateStock evaluates the stock of a synchronized item, so the store is alerted
+// when it reaches its threshold. The alerts don't stop the processing of the order.
+//
+// Parameters:
+//   - ctx: SyncContext of the synchronized item
+func (o *OrderService) evaluateStock(ctx *SyncContext) {
+	var quantity int
+	if ctx.Allocation != nil {
+		// The listings publish only a share of the true stock
+		quantity = ctx.Allocation.Stock
+	} else {
+		if len(ctx.Actions) == 0 {
+			return
+		}
+
+		// The clones get the same quantity, except when they were out of sync
+		quantity = ctx.Quantity
+	}
+
+	if err := o.alert.EvaluateStock(ctx.Credentials.OwnerID, ctx.Item.Sku, quantity); err != nil {
+		o.logger.Warn("Fail to evaluate the stock",
+			zap.String("sku", ctx.Item.Sku),
+			zap.Int("quantity", quantity),
+			zap.Error(err),
+		)
+	}
+}
+
+// publishQuantitySynced publishes the quantities set on the clones of a synchronized item.
+// The actions that are still pending are published by the dispatcher when they fail for good.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - marketplaceId: ID of the order on Mercado Livre
+//   - sku: SKU of the synchronized item
+//   - actions: Sync actions of the order, after they were dispatched
+func (o *OrderService) publishQuantitySynced(storeId entity.ID, marketplaceId, sku string, actions []entity.SyncAction) {
+	var clones []entity.EventSyncedClone
+	for _, action := range actions {
+		if action.Kind != entity.SyncQuantity || action.Sku != sku || action.Status != entity.SyncActionDone {
+			continue
+		}
+		clones = append(clones, entity.EventSyncedClone{
+			AccountID:      action.AccountID,
+			AnnouncementID: action.AnnouncementID,
+			VariationID:    action.VariationID,
+			Quantity:       action.Quantity,
+		})
+	}
+	if len(clones) == 0 {
+		return
+	}
+	o.publish(entity.NewEvent(storeId, entity.QuantitySynced, entity.QuantitySyncedData{
+		MarketplaceID: marketplaceId,
+		Sku:           sku,
+		Clones:        clones,
+	}))
+}
+
+// publish publishes an event to the webhooks of the store.
+// The webhooks don't stop the processing of the order.
+//
+// Parameters:
+//   - event: The event to be published
+func (o *OrderService) publish(event *entity.Event) {
+	if err := o.webhook.Publish(event); err != nil {
+		o.logger.Warn("Fail to publish the event",
+			zap.String("event_type", string(event.Type)),
+			zap.String("store_id", event.StoreID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// parseMeliTime parses a date of a Mercado Livre order.
+// A malformed date isn't a reason to lose the order, so it's logged and ignored.
+//
+// Parameters:
+//   - orderId: ID of the order on Mercado Livre
+//   - field: Name of the field of the date
+//   - value: The date, in RFC 3339
+//
+// Returns:
+//   - time.Time: The date in UTC, zero when it's empty or malformed
+func (o *OrderService) parseMeliTime(orderId, field, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		o.logger.Warn("Invalid date on the order",
+			zap.String("order_id", orderId),
+			zap.String("field", field),
+			zap.String("value", value),
+		)
+		return time.Time{}
+	}
+	return date.UTC()
+}
+
+// encodeCursor encodes the position of an order as an opaque cursor.
+//
+// Parameters:
+//   - cursor: Position of the order
+//
+// Returns:
+//   - string: The encoded cursor
+func encodeCursor(cursor *OrderCursor) string {
+	raw := fmt.Sprintf("%d:%s", cursor.DateCreated.UnixNano(), cursor.ID.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor decodes a cursor created by encodeCursor.
+//
+// Parameters:
+//   - encoded: The encoded cursor
+//
+// Returns:
+//   - *OrderCursor: Position of the order
+//   - error: Error if the cursor is malformed
+func decodeCursor(encoded string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	orderId, err := entity.StringToID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderCursor{DateCreated: time.Unix(0, unixNano).UTC(), ID: orderId}, nil
+}
+
 // orderExists verifies if an order has already been processed by checking both cache and repository storage.
 // Returns true and deletes the notification if the order exists, false if it's a new order.
 // This prevents duplicate order processing and ensures data consistency.
 //
 // Parameters:
+//   - ctx: Context of the processing
 //   - order: OrderMessage to validate
 //
 // Returns:
 //   - error: Error if validation fails, nil if order is valid
-func (o *OrderService) orderExists(order OrderMessage) (bool, error) {
-	status, err := o.cache.GetOrder(order.OrderId)
+func (o *OrderService) orderExists(ctx context.Context, order OrderMessage) (bool, error) {
+	status, err := o.cache.GetOrder(ctx, order.OrderId)
 	if err != nil {
 		o.logger.Warn("Fail to retrieve order from cache", zap.String("order_id", order.OrderId), zap.Error(err))
 	}
@@ -229,7 +611,7 @@ func (o *OrderService) orderExists(order OrderMessage) (bool, error) {
 		return true, nil
 	}
 
-	odrSaved, err := o.repo.GetOrder(order.OrderId)
+	odrSaved, err := o.repo.GetOrder(ctx, order.OrderId)
 	if err != nil {
 		o.logger.Error("Fail to retrieve order from the DB", err, zap.String("order_id", order.OrderId))
 		return false, err
@@ -275,26 +657,115 @@ func (o *OrderService) getStoreCredentials(storeID string) (*store.Credentials,
 }
 
 // fetchOrderData retrieves the order data from Mercado Livre API.
-// It also removes duplicate items from the order.
+// The items are kept as sold, with their own variation and price, they're only merged to plan the sync.
 //
 // Parameters:
+//   - ctx: Context of the processing
 //   - orderID: ID of the order to fetch
 //   - accessToken: Access token for Mercado Livre API
 //
 // Returns:
 //   - *common.MeliOrder: Order data
 //   - error: ErrProcessingOrder or other errors
-func (o *OrderService) fetchOrderData(orderID string, accessToken string) (*common.MeliOrder, error) {
-	orderData, err := o.meli.FetchOrder(orderID, accessToken)
+func (o *OrderService) fetchOrderData(ctx context.Context, orderID string, accessToken string) (*common.MeliOrder, error) {
+	orderData, err := o.meli.FetchOrder(ctx, orderID, accessToken)
 	if err != nil {
 		o.logger.Error("Error to fetch the order", err, zap.String("order_id", orderID))
 		return nil, ErrProcessingOrder
 	}
 
-	removeDuplicateItems(&orderData.Items)
 	return orderData, nil
 }
 
+// fetchPackOrders retrieves the orders of the pack the order belongs to, the order itself first.
+// The orders of the pack that were already registered are left out, their items were synchronized.
+//
+// Parameters:
+//   - ctx: Context of the processing
+//   - orderData: Order data of the notified order
+//   - accessToken: Access token for Mercado Livre API
+//
+// Returns:
+//   - []*common.MeliOrder: Orders of the pack that weren't registered yet
+//   - error: ErrProcessingOrder or other errors
+func (o *OrderService) fetchPackOrders(ctx context.Context, orderData *common.MeliOrder, accessToken string) ([]*common.MeliOrder, error) {
+	orders := []*common.MeliOrder{orderData}
+	if orderData.PackID == "" {
+		return orders, nil
+	}
+
+	pack, err := o.meli.FetchPack(ctx, orderData.PackID, accessToken)
+	if err != nil {
+		o.logger.Error("Error to fetch the pack", err, zap.String("order_id", orderData.ID), zap.String("pack_id", orderData.PackID))
+		return nil, ErrProcessingOrder
+	}
+
+	for _, orderId := range pack.OrderIDs {
+		if orderId == orderData.ID {
+			continue
+		}
+
+		odrSaved, err := o.repo.GetOrder(ctx, orderId)
+		if err != nil {
+			o.logger.Error("Fail to retrieve order from the DB", err, zap.String("order_id", orderId))
+			return nil, err
+		}
+		if odrSaved != nil {
+			continue
+		}
+
+		packOrder, err := o.fetchOrderData(ctx, orderId, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, packOrder)
+	}
+	return orders, nil
+}
+
+// newOrder generates the order entity from the order data of Mercado Livre.
+//
+// Parameters:
+//   - accountId: ID of the account that sold the order
+//   - orderData: Order data
+//
+// Returns:
+//   - *entity.Order: The order, without sync actions
+//   - error: Error if the order is invalid
+func (o *OrderService) newOrder(accountId entity.ID, orderData *common.MeliOrder) (*entity.Order, error) {
+	orderItems := make([]entity.OrderItem, len(orderData.Items))
+	for i, item := range orderData.Items {
+		orderItems[i] = entity.OrderItem{
+			Title:         item.Title,
+			Quantity:      item.Quantity,
+			Sku:           item.Sku,
+			VariationID:   item.VariationID,
+			UnitPrice:     item.UnitPrice,
+			CurrencyID:    item.CurrencyID,
+			ListingTypeID: item.ListingTypeID,
+		}
+	}
+
+	dateCreated := o.parseMeliTime(orderData.ID, "date_created", orderData.DateCreated)
+	odr, err := entity.NewOrder(accountId, orderData.ID, orderItems, entity.OrderStatus(orderData.Status), dateCreated)
+	if err != nil {
+		return nil, err
+	}
+	odr.TotalAmount = orderData.TotalAmount
+	odr.PaidAmount = orderData.PaidAmount
+	odr.CurrencyID = orderData.CurrencyID
+	odr.PackID = orderData.PackID
+	odr.ShippingID = orderData.ShippingID
+	odr.BuyerNickname = orderData.BuyerNickname
+	if dateClosed := o.parseMeliTime(orderData.ID, "date_closed", orderData.DateClosed); !dateClosed.IsZero() {
+		odr.DateClosed = &dateClosed
+	}
+	if lastUpdated := o.parseMeliTime(orderData.ID, "last_updated", orderData.LastUpdated); !lastUpdated.IsZero() {
+		odr.LastUpdated = &lastUpdated
+	}
+	return odr, nil
+}
+
 // syncItemQuantities synchronizes quantities for a specific item across all cloned announcements.
 //
 // Parameters:
@@ -314,19 +785,39 @@ func (o *OrderService) syncItemQuantities(
 	return o.updateCloneQuantities(clones, ctx)
 }
 
-// updateCloneQuantities updates the quantities of cloned items.
+// updateCloneQuantities plans the sale on the cloned items as pending sync actions.
 // It handles both simple items and items with variations.
+// The items with an allocation policy get their quantities from their true stock instead.
 //
 // Parameters:
 //   - clones: List of cloned announcements
 //   - ctx: SyncContext containing the item and processing context
 //
 // Returns:
-//   - error: ErrSyncingQuantities or other errors
+//   - error: ErrSyncingQuantities if the true stock can't be retrieved
 func (o *OrderService) updateCloneQuantities(
 	clones *[]announcement.Announcements,
 	ctx *SyncContext,
 ) error {
+	// The sale is removed from the true stock when the order is registered
+	alloc, err := o.allocate.Allocate(allocation.AllocateDtoInput{
+		Store:     ctx.Credentials.OwnerID,
+		Sku:       ctx.Item.Sku,
+		Published: publishedQuantity(clones, ctx.Item),
+	})
+	if err != nil {
+		return ErrSyncingQuantities
+	}
+	if alloc != nil {
+		alloc.Stock -= ctx.Item.Quantity
+		if alloc.Stock < 0 {
+			alloc.Stock = 0
+		}
+		ctx.Allocation = alloc
+		o.allocateCloneQuantities(clones, ctx)
+		return nil
+	}
+
 	for _, cln := range *clones {
 		announcements := []common.MeliAnnouncement{}
 
@@ -334,7 +825,7 @@ func (o *OrderService) updateCloneQuantities(
 
 		for _, cl := range *cln.Announcements {
 			if cl.Variations != nil {
-				ann := o.handleVariationUpdate(cl, ctx.Item, ctx.ProcessedVariations)
+				ann := o.handleVariationUpdate(cl, ctx.Item, cln.Sku, ctx.ProcessedVariations)
 				if ann != nil {
 					announcements = append(announcements, *ann)
 				}
@@ -349,70 +840,309 @@ func (o *OrderService) updateCloneQuantities(
 		for _, ann := range announcements {
 			if ann.Variations != nil {
 				for _, variation := range ann.Variations {
-					if err := o.announce.UpdateQuantity(ann.ID, variation.AvailableQuantity, *credentials, variation.ID); err != nil {
-						odrErr := &OrderError{
-							Message: "Error updating announcements",
-							AnnouncementsError: []announcement.Announcements{
-								{
-									AccountID: ctx.Credentials.ID,
-									Announcements: &[]common.MeliAnnouncement{
-										{
-											ID:       ann.ID,
-											Title:    ann.Title,
-											Sku:      ann.Sku,
-											Quantity: ann.Quantity,
-											Variations: []struct {
-												ID                int
-												AvailableQuantity int
-											}{
-												{
-													ID:                variation.ID,
-													AvailableQuantity: variation.AvailableQuantity,
-												},
-											},
-										},
-									},
-								},
-							},
-						}
-						o.logger.Error("Error updating announcements", odrErr)
-						return ErrSyncingQuantities
+					ctx.Actions = append(ctx.Actions, *newSaleSyncAction(credentials.ID, ann.ID, variation.ID, ctx.Item.Sku, ctx.Item.Quantity))
+					if variation.AvailableQuantity > ctx.Quantity {
+						ctx.Quantity = variation.AvailableQuantity
 					}
 				}
 			} else {
-				if err := o.announce.UpdateQuantity(ann.ID, ann.Quantity, *credentials); err != nil {
-					odrErr := &OrderError{
-						Message: "Error updating announcements",
-						AnnouncementsError: []announcement.Announcements{
-							{
-								AccountID: ctx.Credentials.ID,
-								Announcements: &[]common.MeliAnnouncement{
-									{
-										ID:       ann.ID,
-										Title:    ann.Title,
-										Sku:      ann.Sku,
-										Quantity: ann.Quantity,
-									},
-								},
-							},
-						},
-					}
+				ctx.Actions = append(ctx.Actions, *newSaleSyncAction(credentials.ID, ann.ID, 0, ctx.Item.Sku, ctx.Item.Quantity))
+				if ann.Quantity > ctx.Quantity {
+					ctx.Quantity = ann.Quantity
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// allocateCloneQuantities plans the quantities of the clones of an item with an allocation policy.
+// Every listing, the sold one included, publishes the quantity allocated to its account,
+// and only the listings with a different quantity are updated. The allocated quantity is
+// computed again from the true stock when the action is executed.
+//
+// Parameters:
+//   - clones: List of cloned announcements
+//   - ctx: SyncContext containing the item, its allocation and the processing context
+func (o *OrderService) allocateCloneQuantities(
+	clones *[]announcement.Announcements,
+	ctx *SyncContext,
+) {
+	for _, cln := range *clones {
+		if cln.Announcements == nil {
+			continue
+		}
+		quantity := ctx.Allocation.Quantity(cln.AccountID)
 
-					o.logger.Error("Error updating announcements", odrErr)
-					return ErrSyncingQuantities
+		for _, cl := range *cln.Announcements {
+			if cl.Variations == nil {
+				if cl.Quantity != quantity {
+					ctx.Actions = append(ctx.Actions, *newSaleSyncAction(cln.AccountID, cl.ID, 0, ctx.Item.Sku, ctx.Item.Quantity))
 				}
+				continue
 			}
+
+			matched := false
+			for _, variation := range cl.Variations {
+				if !matchesVariation(variation, ctx.Item, cln.Sku) {
+					continue
+				}
+				matched = true
+				if variation.AvailableQuantity != quantity {
+					ctx.Actions = append(ctx.Actions, *newSaleSyncAction(cln.AccountID, cl.ID, variation.ID, ctx.Item.Sku, ctx.Item.Quantity))
+				}
+			}
+			if !matched {
+				o.warnUnmatchedVariations(cl.ID, ctx.Item, cln.Sku)
+			}
+		}
+	}
+}
+
+// dispatch executes the sync actions of an order that was just registered.
+// Each action is claimed right before it's executed, the ones claimed by the dispatcher meanwhile are left to it.
+//
+// Parameters:
+//   - ctx: Context of the processing
+//   - odr: The registered order, its actions receive the results
+//   - storeId: ID of the store
+//   - credMap: Credentials of the accounts of the store
+func (o *OrderService) dispatch(ctx context.Context, odr *entity.Order, storeId entity.ID, credMap map[interface{}]store.Credentials) {
+	ctx, span := metrics.StartSpan(ctx, "order.Dispatch", trace.WithAttributes(attribute.Int("sync_actions", len(odr.SyncActions))))
+	defer span.End()
+
+	claim := entity.NewID()
+	for i, count := 0, len(odr.SyncActions); i < count; i++ {
+		action := &odr.SyncActions[i]
+		if action.Status != entity.SyncActionPending {
+			continue
+		}
+		claimed, err := o.repo.ClaimSyncAction(action, claim, syncActionLease)
+		if err != nil {
+			o.logger.Error("Fail to claim the sync action", err, zap.String("sync_action_id", action.ID.String()))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		followUps := o.attempt(ctx, action, storeId, findCredentialsByAccountID(action.AccountID, credMap))
+		if o.acknowledge(odr.ID, odr.MarketplaceID, storeId, claim, action, followUps) {
+			odr.SyncActions = append(odr.SyncActions, followUps...)
+		}
+	}
+}
+
+// DispatchSyncActions executes the sync actions that are due, the ones whose order
+// wasn't dispatched to the end and the failed attempts to be retried.
+// A claimed action is acknowledged with its result, failures are retried with an exponential backoff.
+//
+// Returns:
+//   - error: Error if the sync actions can't be retrieved
+func (o *OrderService) DispatchSyncActions() error {
+	claim := entity.NewID()
+	due, err := o.repo.ClaimDueSyncActions(claim, syncActionsBatch, syncActionLease)
+	if err != nil {
+		o.logger.Error("Fail to claim the due sync actions", err)
+		return err
+	}
+
+	storeCredentials := make(map[entity.ID]*[]store.Credentials)
+	for i := range due {
+		action := due[i].SyncAction
+		var credentials *store.Credentials
+		if action.Kind == entity.SyncQuantity {
+			credentials = o.accountCredentials(due[i].StoreID, action.AccountID, storeCredentials)
+		}
+		followUps := o.attempt(context.Background(), &action, due[i].StoreID, credentials)
+		o.acknowledge(due[i].OrderID, due[i].MarketplaceID, due[i].StoreID, claim, &action, followUps)
+	}
+	return nil
+}
+
+// attempt executes a sync action once and records the result on it.
+// A failed attempt is retried later, a kit sale only decrements the components it didn't decrement yet.
+//
+// Parameters:
+//   - ctx: Context of the dispatch, the update of the listing is a span of its trace
+//   - action: The sync action that receives the result
+//   - storeId: ID of the store
+//   - credentials: Credentials of the account of the action, nil if it wasn't found
+//
+// Returns:
+//   - []entity.SyncAction: Changes made to the listings of the components and the kits by a kit sale
+func (o *OrderService) attempt(ctx context.Context, action *entity.SyncAction, storeId entity.ID, credentials *store.Credentials) []entity.SyncAction {
+	attempts := action.Attempts + 1
+	retryAt := time.Now().UTC().Add(webhook.RetryDelay(attempts))
+
+	if action.Kind == entity.SyncKitSale {
+		followUps, err := o.kit.ApplySale(storeId, action.ID, action.Sku, action.Quantity)
+		if err != nil {
+			o.logger.Error("Fail to apply the sale to the kits", err,
+				zap.String("sku", action.Sku),
+				zap.Int("quantity", action.Quantity),
+				zap.Int("attempts", attempts),
+			)
+		}
+		metrics.CountSyncOperation(string(action.Kind), err)
+		action.Complete(err, retryAt, attempts >= syncActionMaxAttempts)
+		return followUps
+	}
+
+	var err error
+	if credentials == nil {
+		err = ErrCredentialsNotFound
+	} else {
+		err = o.setQuantity(ctx, action, storeId, *credentials)
+	}
+	if err != nil {
+		o.logger.Error("Error updating announcements", err,
+			zap.String("announcement_id", action.AnnouncementID),
+			zap.Int("variation_id", action.VariationID),
+			zap.Int("attempts", attempts),
+		)
+	}
+	metrics.CountSyncOperation(string(action.Kind), err)
+	action.Complete(err, retryAt, attempts >= syncActionMaxAttempts)
+	return nil
+}
+
+// setQuantity sets the quantity of the listing of a sync action.
+// The quantity of a sale is computed when the action is executed, so the sales of the listing
+// planned meanwhile aren't lost: the listing gets the quantity allocated to its account when
+// the SKU has an allocation policy, otherwise the units sold are removed from its current quantity.
+// The computed quantity is recorded on the action. When Mercado Livre didn't answer the update in time and
+// the retried or resumed action finds the quantity on the listing, the update was applied and it isn't decremented again.
+//
+// Parameters:
+//   - ctx: Context of the dispatch
+//   - action: The quantity sync action
+//   - storeId: ID of the store
+//   - credentials: Credentials of the account of the action
+//
+// Returns:
+//   - error: ErrVariationNotFound or the error to read or update the listing
+func (o *OrderService) setQuantity(ctx context.Context, action *entity.SyncAction, storeId entity.ID, credentials store.Credentials) error {
+	variations := []int{}
+	if action.VariationID != 0 {
+		variations = append(variations, action.VariationID)
+	}
+	if action.Delta == 0 {
+		return o.announce.UpdateQuantity(ctx, action.AnnouncementID, action.Quantity, credentials, variations...)
+	}
+
+	listing, err := o.meli.GetAnnouncement(action.AnnouncementID, credentials.AccessToken)
+	if err != nil {
+		return err
+	}
+	current, found := listingQuantity(listing, action.VariationID)
+	if !found {
+		return ErrVariationNotFound
+	}
+	unconfirmed := action.Unconfirmed
+	action.Unconfirmed = false
+	if unconfirmed && current == action.Quantity {
+		return nil
+	}
+
+	alloc, err := o.allocate.Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: action.Sku, Published: current})
+	if err != nil {
+		return err
+	}
+	quantity := current + action.Delta
+	if alloc != nil {
+		quantity = alloc.Quantity(action.AccountID)
+	} else if quantity < 0 {
+		quantity = 0
+	}
+	action.Quantity = quantity
+	if quantity == current {
+		return nil
+	}
+	err = o.announce.UpdateQuantity(ctx, action.AnnouncementID, quantity, credentials, variations...)
+	action.Unconfirmed = errors.Is(err, common.ErrMeliTimeout)
+	return err
+}
+
+// acknowledge stores the result of an attempt of a sync action.
+// An action that failed for good is published to the webhooks of the store.
+// When the result can't be stored, the action is attempted again once its lease expires.
+//
+// Parameters:
+//   - orderId: ID of the order of the action
+//   - marketplaceId: ID of the order on Mercado Livre
+//   - storeId: ID of the store
+//   - claim: Claim that holds the action
+//   - action: The attempted sync action
+//   - followUps: Changes made by a kit sale
+//
+// Returns:
+//   - bool: True if the result was stored, false when the claim no longer holds the action
+func (o *OrderService) acknowledge(orderId entity.ID, marketplaceId string, storeId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) bool {
+	acknowledged, err := o.repo.AcknowledgeSyncAction(orderId, claim, action, followUps)
+	if err != nil {
+		o.logger.Error("Fail to acknowledge the sync action", err, zap.String("sync_action_id", action.ID.String()))
+		return false
+	}
+	if !acknowledged {
+		o.logger.Warn("The claim of the sync action expired, its result was discarded", zap.String("sync_action_id", action.ID.String()))
+		return false
+	}
+
+	if action.Status == entity.SyncActionFailed {
+		o.logger.Warn("Sync action failed",
+			zap.String("sync_action_id", action.ID.String()),
+			zap.String("order_id", marketplaceId),
+			zap.Int("attempts", action.Attempts),
+		)
+		o.publish(entity.NewEvent(storeId, entity.SyncFailed, entity.SyncFailedData{
+			MarketplaceID: marketplaceId,
+			Sku:           action.Sku,
+			Error:         action.Error,
+		}))
+	}
+	return true
+}
+
+// accountCredentials finds the credentials of an account of a store.
+// The credentials of the stores are retrieved once per run of the dispatcher.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - accountId: ID of the account
+//   - storeCredentials: Credentials already retrieved, by the store ID
+//
+// Returns:
+//   - *store.Credentials: Credentials of the account, nil if they can't be found
+func (o *OrderService) accountCredentials(storeId, accountId entity.ID, storeCredentials map[entity.ID]*[]store.Credentials) *store.Credentials {
+	credentials, retrieved := storeCredentials[storeId]
+	if !retrieved {
+		var err error
+		credentials, err = o.store.RetrieveMeliCredentialsFromStoreID(storeId)
+		if err != nil {
+			o.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", storeId.String()))
+			credentials = nil
+		}
+		storeCredentials[storeId] = credentials
+	}
+	if credentials == nil {
+		return nil
+	}
+	for i := range *credentials {
+		if (*credentials)[i].ID == accountId {
+			return &(*credentials)[i]
 		}
 	}
 	return nil
 }
 
 // handleVariationUpdate processes quantity updates for items with variations.
-// It checks if variations have already been processed and updates quantities accordingly.
+// Only the counterparts of the sold variation are updated, the ones that weren't
+// already processed in the order.
 //
 // Parameters:
 //   - cl: The announcement to update
 //   - item: The order item being processed
+//   - sku: SKU of the product in the account of the announcement
 //   - processedVariations: Map of already processed variations
 //
 // Returns:
@@ -420,6 +1150,7 @@ func (o *OrderService) updateCloneQuantities(
 func (o *OrderService) handleVariationUpdate(
 	cl common.MeliAnnouncement,
 	item common.OrderItem,
+	sku string,
 	processedVariations map[string][]int,
 ) *common.MeliAnnouncement {
 	ann := common.MeliAnnouncement{
@@ -428,8 +1159,12 @@ func (o *OrderService) handleVariationUpdate(
 		Sku:   cl.Sku,
 	}
 
-	hasUpdates := false
+	hasUpdates, matched := false, false
 	for _, variation := range cl.Variations {
+		if !matchesVariation(variation, item, sku) {
+			continue
+		}
+		matched = true
 		// Skip if this variation was already processed in the order
 		if variations, exists := processedVariations[item.Sku]; exists && utils.Contains(&variations, variation.ID) {
 			continue
@@ -437,10 +1172,7 @@ func (o *OrderService) handleVariationUpdate(
 
 		if variation.AvailableQuantity > 0 {
 			newQuantity := variation.AvailableQuantity - item.Quantity
-			ann.Variations = append(ann.Variations, struct {
-				ID                int
-				AvailableQuantity int
-			}{
+			ann.Variations = append(ann.Variations, common.MeliVariation{
 				ID:                variation.ID,
 				AvailableQuantity: newQuantity,
 			})
@@ -448,12 +1180,25 @@ func (o *OrderService) handleVariationUpdate(
 		}
 	}
 
+	if !matched {
+		o.warnUnmatchedVariations(cl.ID, item, sku)
+	}
 	if hasUpdates {
 		return &ann
 	}
 	return nil
 }
 
+// warnUnmatchedVariations logs a listing with variations whose quantity isn't synchronized,
+// since none of its variations is a counterpart of the sold one
+func (o *OrderService) warnUnmatchedVariations(announcementId string, item common.OrderItem, sku string) {
+	o.logger.Warn("No variation of the listing matches the sold one",
+		zap.String("announcement_id", announcementId),
+		zap.String("sku", item.Sku),
+		zap.String("account_sku", sku),
+	)
+}
+
 // handleSimpleUpdate processes quantity updates for simple items without variations.
 //
 // Parameters:
@@ -528,19 +1273,158 @@ func (o *OrderService) retryAnnouncementRetrieval(
 	return o.updateCloneQuantities(clones, ctx)
 }
 
+// newSyncAction plans a sync action of an order. It's due as soon as the order is registered,
+// then either the order or the dispatcher claims it.
+//
+// Parameters:
+//   - kind: Kind of the action
+//   - account: ID of the account of the announcement
+//   - announcementId: ID of the announcement, the sold one for a kit sale
+//   - variationId: ID of the variation, zero when the announcement doesn't have variations
+//   - sku: SKU of the sold item
+//   - quantity: Quantity to be set, or the units sold for a kit sale
+//
+// Returns:
+//   - *entity.SyncAction: The pending action
+func newSyncAction(kind entity.SyncActionKind, account entity.ID, announcementId string, variationId int, sku string, quantity int) *entity.SyncAction {
+	return entity.NewPendingSyncAction(kind, account, announcementId, variationId, sku, quantity, time.Now().UTC())
+}
+
+// newSaleSyncAction plans the sale of an item on a listing, its quantity is decremented
+// by the units sold when the action is executed.
+//
+// Parameters:
+//   - account: ID of the account of the announcement
+//   - announcementId: ID of the announcement
+//   - variationId: ID of the variation, zero when the announcement doesn't have variations
+//   - sku: SKU of the sold item
+//   - units: Units sold
+//
+// Returns:
+//   - *entity.SyncAction: The pending action
+func newSaleSyncAction(account entity.ID, announcementId string, variationId int, sku string, units int) *entity.SyncAction {
+	action := newSyncAction(entity.SyncQuantity, account, announcementId, variationId, sku, 0)
+	action.Delta = -units
+	return action
+}
+
+// listingQuantity is the quantity of a listing, or of one of its variations.
+//
+// Parameters:
+//   - listing: The listing
+//   - variationId: ID of the variation, zero for the quantity of the listing
+//
+// Returns:
+//   - int: The available quantity
+//   - bool: False when the listing doesn't have the variation
+func listingQuantity(listing *common.MeliAnnouncement, variationId int) (int, bool) {
+	if variationId == 0 {
+		return listing.Quantity, true
+	}
+	for _, variation := range listing.Variations {
+		if variation.ID == variationId {
+			return variation.AvailableQuantity, true
+		}
+	}
+	return 0, false
+}
+
+// publishedQuantity is the quantity of an item published before the sale, the largest quantity
+// of its clones. The sold listing was already decremented by Mercado Livre, so the sale is added back to it.
+//
+// Parameters:
+//   - clones: List of cloned announcements
+//   - item: The order item being processed
+//
+// Returns:
+//   - int: The quantity published before the sale
+func publishedQuantity(clones *[]announcement.Announcements, item common.OrderItem) int {
+	published := 0
+	for _, cln := range *clones {
+		if cln.Announcements == nil {
+			continue
+		}
+		for _, cl := range *cln.Announcements {
+			quantities := []int{cl.Quantity}
+			if cl.Variations != nil {
+				quantities = quantities[:0]
+				for _, variation := range cl.Variations {
+					if !matchesVariation(variation, item, cln.Sku) {
+						continue
+					}
+					quantity := variation.AvailableQuantity
+					if cl.ID == item.ID && variation.ID == item.VariationID {
+						quantity += item.Quantity
+					}
+					quantities = append(quantities, quantity)
+				}
+			} else if cl.ID == item.ID {
+				quantities[0] += item.Quantity
+			}
+			for _, quantity := range quantities {
+				if quantity > published {
+					published = quantity
+				}
+			}
+		}
+	}
+	return published
+}
+
+// matchesVariation reports whether a variation of a clone is a counterpart of the sold variation.
+// The variations are matched by the SKU of the product in the account of the clone, which is
+// an alias when the SKU is mapped, or by their attribute combination, e.g. the same color and size.
+//
+// Parameters:
+//   - variation: The variation of the clone
+//   - item: The order item being processed
+//   - sku: SKU of the product in the account of the clone, the SKU of the item when it's empty
+//
+// Returns:
+//   - bool: True if the variation is a counterpart of the sold one
+func matchesVariation(variation common.MeliVariation, item common.OrderItem, sku string) bool {
+	if sku == "" {
+		sku = item.Sku
+	}
+	if variation.Sku != "" && variation.Sku == sku {
+		return true
+	}
+	if len(item.VariationAttributes) == 0 || len(variation.Attributes) != len(item.VariationAttributes) {
+		return false
+	}
+
+	for _, sold := range item.VariationAttributes {
+		found := false
+		for _, attr := range variation.Attributes {
+			if attr.ID == sold.ID && strings.EqualFold(attr.ValueName, sold.ValueName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // removeDuplicateItems removes duplicate items from a slice of OrderItems.
-// Items are considered duplicates if they have the same SKU.
+// Items are considered duplicates if they have the same SKU and the same variation,
+// the variations of a product may share its SKU.
 // Quantities of duplicate items are summed together.
 //
 // Parameters:
 //   - items: Pointer to the slice of OrderItems to process
 func removeDuplicateItems(items *[]common.OrderItem) {
 	var unique []common.OrderItem
-	type key struct{ Sku string }
+	type key struct {
+		Sku         string
+		VariationID int
+	}
 	m := make(map[key]int)
 
 	for _, item := range *items {
-		k := key{item.Sku}
+		k := key{item.Sku, item.VariationID}
 		if i, ok := m[k]; ok && item.Sku != "" {
 			unique[i].Quantity = unique[i].Quantity + item.Quantity
 		} else {
@@ -1,19 +1,26 @@
 package order
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Vractos/kloni/entity"
+	mock_alert "github.com/Vractos/kloni/usecases/alert/mock"
+	"github.com/Vractos/kloni/usecases/allocation"
+	mock_allocation "github.com/Vractos/kloni/usecases/allocation/mock"
 	"github.com/Vractos/kloni/usecases/announcement"
 	mock_announcement "github.com/Vractos/kloni/usecases/announcement/mock"
 	common "github.com/Vractos/kloni/usecases/common"
 	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	mock_kit "github.com/Vractos/kloni/usecases/kit/mock"
 	"github.com/Vractos/kloni/usecases/order"
 	mock_order "github.com/Vractos/kloni/usecases/order/mock"
 	"github.com/Vractos/kloni/usecases/store"
 	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	mock_webhook "github.com/Vractos/kloni/usecases/webhook/mock"
 	"github.com/Vractos/kloni/utils"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -47,7 +54,7 @@ func TestProcessWebhook(t *testing.T) {
 				Sent:          "2022-10-30T16:19:20.106Z",
 			},
 			setupMocks: func(m *Mocks) {
-				m.mockOrderQueue.EXPECT().PostOrderNotification(gomock.Any()).Return(nil)
+				m.mockOrderQueue.EXPECT().PostOrderNotification(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -64,7 +71,7 @@ func TestProcessWebhook(t *testing.T) {
 				Sent:          "2022-10-30T16:19:20.106Z",
 			},
 			setupMocks: func(m *Mocks) {
-				m.mockOrderQueue.EXPECT().PostOrderNotification(gomock.Any()).Return(errors.New("queue error"))
+				m.mockOrderQueue.EXPECT().PostOrderNotification(gomock.Any(), gomock.Any()).Return(errors.New("queue error"))
 				m.mockLogger.EXPECT().Error(
 					"Error to post order notification",
 					gomock.Any(),
@@ -89,7 +96,7 @@ func TestProcessWebhook(t *testing.T) {
 
 			tt.setupMocks(mocks)
 
-			err := orderService.ProcessWebhook(tt.input)
+			err := orderService.ProcessWebhook(context.Background(), tt.input)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ProcessWebhook() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -115,6 +122,7 @@ func TestProcessOrder(t *testing.T) {
 	// -------------------------------------------------------
 	accountId := entity.ID(uuid.New())
 	secondAccountId := entity.ID(uuid.New())
+	dateClosed := time.Date(2022, 10, 30, 20, 19, 25, 0, time.UTC)
 	defaultOrderMessage := order.OrderMessage{
 		Store:         "1",
 		OrderId:       "20210101000000",
@@ -205,16 +213,25 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			meliOrder: &common.MeliOrder{
 				ID:          "20210101000000",
-				DateCreated: "2022-10-30T16:19:20.129Z",
+				DateCreated: "2022-10-30T16:19:20.129-04:00",
+				DateClosed:  "2022-10-30T16:19:25.000-04:00",
 				Status:      common.Paid,
 				Items: []common.OrderItem{
 					{
-						ID:       "1",
-						Title:    "test-title",
-						Sku:      "test-sku",
-						Quantity: 1,
+						ID:         "1",
+						Title:      "test-title",
+						Sku:        "test-sku",
+						Quantity:   1,
+						UnitPrice:  49.9,
+						CurrencyID: "BRL",
 					},
 				},
+				TotalAmount:   49.9,
+				PaidAmount:    62.4,
+				CurrencyID:    "BRL",
+				PackID:        "2000000000000001",
+				ShippingID:    "40000000001",
+				BuyerNickname: "TEST_BUYER",
 			},
 			meliCredentials: defaultMeliCredentials,
 			rootCredentials: &(*defaultMeliCredentials)[0],
@@ -245,13 +262,22 @@ func TestProcessOrder(t *testing.T) {
 				AccountID:     accountId,
 				MarketplaceID: "20210101000000",
 				Status:        "paid",
+				DateClosed:    &dateClosed,
 				Items: []entity.OrderItem{
 					{
-						Title:    "test-title",
-						Quantity: 1,
-						Sku:      "test-sku",
+						Title:      "test-title",
+						Quantity:   1,
+						Sku:        "test-sku",
+						UnitPrice:  49.9,
+						CurrencyID: "BRL",
 					},
 				},
+				TotalAmount:   49.9,
+				PaidAmount:    62.4,
+				CurrencyID:    "BRL",
+				PackID:        "2000000000000001",
+				ShippingID:    "40000000001",
+				BuyerNickname: "TEST_BUYER",
 			},
 			OrderMatcher: &OrderMatcher{},
 		},
@@ -292,13 +318,11 @@ func TestProcessOrder(t *testing.T) {
 								Quantity: 1,
 								Price:    1.0,
 								Sku:      "test-sku",
-								Variations: []struct {
-									ID                int
-									AvailableQuantity int
-								}{
+								Variations: []common.MeliVariation{
 									{
 										ID:                222,
 										AvailableQuantity: 1,
+										Sku:               "test-sku",
 									},
 								},
 							},
@@ -351,13 +375,11 @@ func TestProcessOrder(t *testing.T) {
 								Quantity: 1,
 								Price:    1.0,
 								Sku:      "test-sku",
-								Variations: []struct {
-									ID                int
-									AvailableQuantity int
-								}{
+								Variations: []common.MeliVariation{
 									{
 										ID:                111,
 										AvailableQuantity: 1,
+										Sku:               "test-sku",
 									},
 								},
 							},
@@ -554,14 +576,25 @@ func TestProcessOrder(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
-
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+			mocks.ignoreEvents()
+
+			mocks.serveListings(tt.orderAnnouncementsClones...)
 			tt.OrderMatcher.expected = tt.odr
-			mocks.mockOrderCache.EXPECT().GetOrder(tt.orderMessage.OrderId).Return(nil, nil)
-			mocks.mockOrderRepo.EXPECT().GetOrder(tt.orderMessage.OrderId).Return(nil, nil)
+			mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), tt.orderMessage.OrderId).Return(nil, nil)
+			mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), tt.orderMessage.OrderId).Return(nil, nil)
 			mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(tt.orderMessage.Store).Return(tt.meliCredentials, nil)
-			mocks.mockMercadoLivre.EXPECT().FetchOrder(tt.orderMessage.OrderId, tt.rootCredentials.AccessToken).Return(tt.meliOrder, nil)
-			order.RemoveDuplicateItemsTest(&tt.meliOrder.Items)
-
+			mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), tt.orderMessage.OrderId, tt.rootCredentials.AccessToken).Return(tt.meliOrder, nil)
+			if tt.meliOrder.PackID != "" {
+				mocks.mockMercadoLivre.EXPECT().FetchPack(gomock.Any(), tt.meliOrder.PackID, tt.rootCredentials.AccessToken).Return(&common.MeliPack{
+					ID:       tt.meliOrder.PackID,
+					OrderIDs: []string{tt.meliOrder.ID},
+				}, nil)
+			}
 			for i, item := range tt.meliOrder.Items {
 				if item.Sku == "" {
 					mocks.mockLogger.EXPECT().Warn("The product doesn't have sku", zap.String("order_id", tt.orderMessage.OrderId), zap.String("announcement_id", item.ID))
@@ -577,6 +610,15 @@ func TestProcessOrder(t *testing.T) {
 				orderItemsIds[i] = item.ID
 			}
 
+			// Every updated clone and the kit sale of every item with a SKU are sync actions of the order
+			syncActions := 0
+			for _, item := range tt.meliOrder.Items {
+				if item.Sku != "" {
+					syncActions++
+				}
+			}
+			tt.OrderMatcher.syncActions = &syncActions
+
 			for _, itmClns := range tt.orderAnnouncementsClones {
 				for _, acc := range itmClns {
 					var currentCredentials *store.Credentials
@@ -600,19 +642,21 @@ func TestProcessOrder(t *testing.T) {
 
 						if ann.Variations != nil {
 							for _, variation := range ann.Variations {
-								mocks.mockAnnUseCase.EXPECT().UpdateQuantity(
+								mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(),
 									ann.ID, variation.AvailableQuantity-soldQuantity, *currentCredentials, variation.ID).Return(nil)
+								syncActions++
 							}
 							continue
 						}
-						mocks.mockAnnUseCase.EXPECT().UpdateQuantity(
+						mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(),
 							ann.ID, ann.Quantity-soldQuantity, *currentCredentials).Return(nil)
+						syncActions++
 					}
 				}
 			}
 
-			mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.All(tt.OrderMatcher)).Return(nil)
-			mocks.mockOrderCache.EXPECT().SetOrder(gomock.All(tt.OrderMatcher)).Return(nil)
+			mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.All(tt.OrderMatcher)).Return(nil)
+			mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.All(tt.OrderMatcher)).Return(nil)
 			mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(tt.orderMessage.ReceiptHandle).Return(nil)
 
 			err := orderService.ProcessOrder(tt.orderMessage)
@@ -636,7 +680,7 @@ func TestProcessOrder(t *testing.T) {
 			mockCall: func(m *Mocks) {
 				orderStatus := entity.Paid
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(&orderStatus, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(&orderStatus, nil),
 					m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil),
 				)
 			},
@@ -646,8 +690,8 @@ func TestProcessOrder(t *testing.T) {
 			name: "order already exists on db",
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(&entity.Order{}, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(&entity.Order{}, nil),
 					m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil),
 				)
 			},
@@ -657,19 +701,19 @@ func TestProcessOrder(t *testing.T) {
 			name: "error getting order from cache",
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, errors.New("error getting order from cache")),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, errors.New("error getting order from cache")),
 					m.mockLogger.EXPECT().Warn(
 						"Fail to retrieve order from cache",
 						zap.String("order_id", defaultOrderMessage.OrderId),
 						zap.Error(errors.New("error getting order from cache")),
 					),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(gomock.Any()).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), gomock.Any()).Return(&common.MeliOrder{}, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(&common.MeliOrder{}, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(gomock.Any(), gomock.Any()).Return(&[]announcement.Announcements{}, nil).AnyTimes(),
-					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
-					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any()).Return(nil),
-					m.mockOrderCache.EXPECT().SetOrder(gomock.Any()).Return(nil),
+					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
+					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil),
+					m.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil),
 					m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil),
 				)
 			},
@@ -679,8 +723,8 @@ func TestProcessOrder(t *testing.T) {
 			name: "error getting order from db",
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, errors.New("error getting order from db")),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, errors.New("error getting order from db")),
 					m.mockLogger.EXPECT().Error(
 						"Fail to retrieve order from the DB",
 						errors.New("error getting order from db"),
@@ -702,6 +746,11 @@ func TestProcessOrder(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+			mocks.ignoreEvents()
 
 			if tt.mockCall != nil {
 				tt.mockCall(mocks)
@@ -731,8 +780,8 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(gomock.Any()).Return(nil, errors.New("error retrieving meli credentials")),
 					m.mockLogger.EXPECT().Error(
 						"Error in retrieving Meli credentials during order processing",
@@ -753,8 +802,8 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(gomock.Any()).Return(nilCredentials, nil),
 					m.mockLogger.EXPECT().Error(
 						"Error in converting credentials to map",
@@ -775,10 +824,10 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(gomock.Any()).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting order from meli")),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting order from meli")),
 					m.mockLogger.EXPECT().Error(
 						"Error to fetch the order",
 						errors.New("error getting order from meli"),
@@ -794,10 +843,10 @@ func TestProcessOrder(t *testing.T) {
 			mockCall: func(m *Mocks) {
 				annErr := errors.New("error retrieving announcements")
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(nil, annErr),
 					m.mockLogger.EXPECT().Error(
 						"Error in retrieving the order product clones",
@@ -818,10 +867,10 @@ func TestProcessOrder(t *testing.T) {
 					Sku:           defaultMeliOrder.Items[0].Sku,
 				}
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(nil, annErr),
 					m.mockLogger.EXPECT().Warn(
 						"Fail in retrieving the order product clones",
@@ -847,11 +896,12 @@ func TestProcessOrder(t *testing.T) {
 					Sku:           defaultMeliOrder.Items[0].Sku,
 				}
 
+				m.serveListings(defaultMeliAnnouncementsClones)
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(nil, annErr),
 					m.mockLogger.EXPECT().Warn(
 						"Fail in retrieving the order product clones",
@@ -863,14 +913,13 @@ func TestProcessOrder(t *testing.T) {
 						zap.String("sku", defaultMeliOrder.Items[0].Sku),
 					),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(&defaultMeliAnnouncementsClones, nil),
-
-					m.mockAnnUseCase.EXPECT().UpdateQuantity(
+					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil),
+					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(),
 						(*defaultMeliAnnouncementsClones[0].Announcements)[1].ID,
 						(*defaultMeliAnnouncementsClones[0].Announcements)[1].Quantity-defaultMeliOrder.Items[0].Quantity,
 						(*defaultMeliCredentials)[0],
 					).Return(nil),
-					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any()).Return(nil),
-					m.mockOrderCache.EXPECT().SetOrder(gomock.Any()).Return(nil),
+					m.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil),
 					m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil),
 				)
 			},
@@ -886,10 +935,10 @@ func TestProcessOrder(t *testing.T) {
 				}
 
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(nil, annErr),
 					m.mockLogger.EXPECT().Warn(
 						"Fail in retrieving the order product clones",
@@ -921,6 +970,12 @@ func TestProcessOrder(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+			mocks.ignoreEvents()
 
 			if tt.mockCall != nil {
 				tt.mockCall(mocks)
@@ -939,7 +994,6 @@ func TestProcessOrder(t *testing.T) {
 	// -------------------------------------------------------
 	// --------- Scenarios -> Error updating data ------------
 	// -------------------------------------------------------
-	// TODO: Fix this test
 	errUpdatingAnnouncements := []struct {
 		name         string
 		orderMessage order.OrderMessage
@@ -947,7 +1001,7 @@ func TestProcessOrder(t *testing.T) {
 		errMessage   string
 	}{
 		{
-			name:         "error updating announcements",
+			name:         "error updating announcements is retried",
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				anns := []announcement.Announcements{
@@ -971,41 +1025,34 @@ func TestProcessOrder(t *testing.T) {
 						},
 					},
 				}
-				odrErro := &order.OrderError{
-					Message: "Error updating announcements",
-					AnnouncementsError: []announcement.Announcements{
-						{
-							AccountID: accountId,
-							Announcements: &[]common.MeliAnnouncement{
-								{
-									ID:       (*anns[0].Announcements)[1].ID,
-									Title:    (*anns[0].Announcements)[1].Title,
-									Sku:      (*anns[0].Announcements)[1].Sku,
-									Quantity: (*anns[0].Announcements)[1].Quantity - defaultMeliOrder.Items[0].Quantity,
-								},
-							},
-						},
-					},
-				}
+				updateErr := errors.New("error updating announcements")
 
+				m.serveListings(anns)
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(&anns, nil),
-					m.mockAnnUseCase.EXPECT().UpdateQuantity(
+					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil),
+					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(),
 						(*anns[0].Announcements)[1].ID,
 						(*anns[0].Announcements)[1].Quantity-defaultMeliOrder.Items[0].Quantity,
 						(*defaultMeliCredentials)[0],
-					).Return(errors.New("error updating announcements")),
+					).Return(updateErr),
 					m.mockLogger.EXPECT().Error(
-						odrErro.Message,
-						odrErro,
+						"Error updating announcements",
+						updateErr,
+						zap.String("announcement_id", (*anns[0].Announcements)[1].ID),
+						zap.Int("variation_id", 0),
+						zap.Int("attempts", 1),
 					),
+					// The update is stored to be retried by the dispatcher
+					m.mockOrderRepo.EXPECT().AcknowledgeSyncAction(gomock.Any(), gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionPending, 1}, gomock.Nil()).Return(true, nil),
+					m.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil),
+					m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil),
 				)
 			},
-			errMessage: "error syncing quantities",
 		},
 	}
 
@@ -1018,10 +1065,16 @@ func TestProcessOrder(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreEvents()
 
 			if tt.mockCall != nil {
 				tt.mockCall(mocks)
 			}
+			mocks.ignoreAcknowledgements()
 
 			err := orderService.ProcessOrder(tt.orderMessage)
 			if (err != nil) != (tt.errMessage != "") {
@@ -1047,13 +1100,13 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(&[]announcement.Announcements{}, nil),
-					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
-					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any()).Return(errors.New("error registering order")),
+					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
+					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(errors.New("error registering order")),
 					m.mockLogger.EXPECT().Error(
 						"Fail to store the order",
 						errors.New("error registering order"),
@@ -1068,14 +1121,14 @@ func TestProcessOrder(t *testing.T) {
 			orderMessage: defaultOrderMessage,
 			mockCall: func(m *Mocks) {
 				gomock.InOrder(
-					m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
-					m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
+					m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil),
 					m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil),
-					m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
+					m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(defaultMeliOrder, nil),
 					m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts(defaultMeliOrder.Items[0].Sku, defaultMeliCredentials).Return(&[]announcement.Announcements{}, nil),
-					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
-					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any()).Return(nil),
-					m.mockOrderCache.EXPECT().SetOrder(gomock.Any()).Return(errors.New("error setting order on cache")),
+					m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes(),
+					m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil),
+					m.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(errors.New("error setting order on cache")),
 					m.mockLogger.EXPECT().Warn(
 						"Fail to cache the order",
 						zap.String("order_id", defaultOrderMessage.OrderId),
@@ -1095,6 +1148,12 @@ func TestProcessOrder(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+			mocks.ignoreEvents()
 
 			if tt.mockCall != nil {
 				tt.mockCall(mocks)
@@ -1174,6 +1233,18 @@ func TestHelperFunctions(t *testing.T) {
 					},
 				},
 			},
+			{
+				name: "variations with the same SKU",
+				input: []common.OrderItem{
+					{Title: "test-title", Quantity: 1, Sku: "test-sku", VariationID: 1},
+					{Title: "test-title", Quantity: 2, Sku: "test-sku", VariationID: 2},
+					{Title: "test-title", Quantity: 3, Sku: "test-sku", VariationID: 1},
+				},
+				expected: []common.OrderItem{
+					{Title: "test-title", Quantity: 4, Sku: "test-sku", VariationID: 1},
+					{Title: "test-title", Quantity: 2, Sku: "test-sku", VariationID: 2},
+				},
+			},
 			{
 				name: "items with empty SKUs",
 				input: []common.OrderItem{
@@ -1251,8 +1322,10 @@ func TestHelperFunctions(t *testing.T) {
 
 // OrderMatcher is a custom gomock matcher for Order entities.
 // It compares orders while ignoring specific fields (ID, AccountID, MarketplaceID, DateCreated).
+// When syncActions is set, the number of recorded sync actions is also verified.
 type OrderMatcher struct {
-	expected *entity.Order
+	expected    *entity.Order
+	syncActions *int
 }
 
 func (o *OrderMatcher) Matches(x interface{}) bool {
@@ -1261,13 +1334,33 @@ func (o *OrderMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
-	return cmp.Equal(order, o.expected, cmpopts.IgnoreFields(entity.Order{}, "ID", "AccountID", "MarketplaceID", "DateCreated"), cmpopts.IgnoreFields(entity.OrderItem{}, "ID"))
+	if o.syncActions != nil && len(order.SyncActions) != *o.syncActions {
+		return false
+	}
+
+	return cmp.Equal(order, o.expected, cmpopts.IgnoreFields(entity.Order{}, "ID", "AccountID", "MarketplaceID", "DateCreated", "SyncActions"), cmpopts.IgnoreFields(entity.OrderItem{}, "ID"))
 }
 
 func (o *OrderMatcher) String() string {
 	return fmt.Sprintf("matches order %v", o.expected)
 }
 
+// syncActionMatcher matches the sync actions by their kind, status and attempts
+type syncActionMatcher struct {
+	kind     entity.SyncActionKind
+	status   entity.SyncActionStatus
+	attempts int
+}
+
+func (s syncActionMatcher) Matches(x interface{}) bool {
+	action, ok := x.(*entity.SyncAction)
+	return ok && action.Kind == s.kind && action.Status == s.status && action.Attempts == s.attempts
+}
+
+func (s syncActionMatcher) String() string {
+	return fmt.Sprintf("%s sync action %s after %d attempts", s.kind, s.status, s.attempts)
+}
+
 // ================================== Mocks =================================
 
 // Mocks holds all mock instances used in testing.
@@ -1277,6 +1370,10 @@ type Mocks struct {
 	mockMercadoLivre *common_mock.MockMercadoLivre
 	mockStoreUseCase *mock_store.MockUseCase
 	mockAnnUseCase   *mock_announcement.MockUseCase
+	mockAlertUseCase *mock_alert.MockUseCase
+	mockKitUseCase   *mock_kit.MockUseCase
+	mockAllocator    *mock_allocation.MockAllocator
+	mockPublisher    *mock_webhook.MockPublisher
 	mockOrderRepo    *mock_order.MockRepository
 	mockOrderCache   *mock_order.MockCache
 	mockLogger       *common_mock.MockLogger
@@ -1288,6 +1385,10 @@ func newMocks(ctrl *gomock.Controller) *Mocks {
 		mockMercadoLivre: common_mock.NewMockMercadoLivre(ctrl),
 		mockStoreUseCase: mock_store.NewMockUseCase(ctrl),
 		mockAnnUseCase:   mock_announcement.NewMockUseCase(ctrl),
+		mockAlertUseCase: mock_alert.NewMockUseCase(ctrl),
+		mockKitUseCase:   mock_kit.NewMockUseCase(ctrl),
+		mockAllocator:    mock_allocation.NewMockAllocator(ctrl),
+		mockPublisher:    mock_webhook.NewMockPublisher(ctrl),
 		mockOrderRepo:    mock_order.NewMockRepository(ctrl),
 		mockOrderCache:   mock_order.NewMockCache(ctrl),
 		mockLogger:       common_mock.NewMockLogger(ctrl),
@@ -1300,18 +1401,73 @@ func (m *Mocks) newOrderService() *order.OrderService {
 		m.mockMercadoLivre,
 		m.mockStoreUseCase,
 		m.mockAnnUseCase,
+		m.mockAlertUseCase,
+		m.mockKitUseCase,
+		m.mockAllocator,
+		m.mockPublisher,
 		m.mockOrderRepo,
 		m.mockOrderCache,
 		m.mockLogger,
 	)
 }
 
+// ignoreStockEvaluation allows the stock of the synchronized items to be evaluated,
+// for the tests that aren't about the stock alerts.
+func (m *Mocks) ignoreStockEvaluation() {
+	m.mockAlertUseCase.EXPECT().EvaluateStock(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+// ignoreKits allows the sales to be propagated to the kits,
+// for the tests that aren't about the kits.
+func (m *Mocks) ignoreKits() {
+	m.mockKitUseCase.EXPECT().ApplySale(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+// ignoreAllocations makes the SKUs have no allocation policy,
+// for the tests that aren't about the allocations.
+func (m *Mocks) ignoreAllocations() {
+	m.mockAllocator.EXPECT().Allocate(gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+// ignoreAcknowledgements allows the results of the sync actions to be stored,
+// for the tests that aren't about the dispatch of the sync actions.
+func (m *Mocks) ignoreAcknowledgements() {
+	m.mockOrderRepo.EXPECT().AcknowledgeSyncAction(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+}
+
+// serveListings makes the listings read when the sync actions are executed have the quantities of the clones.
+func (m *Mocks) serveListings(clones ...[]announcement.Announcements) {
+	for _, accounts := range clones {
+		for _, account := range accounts {
+			if account.Announcements == nil {
+				continue
+			}
+			for _, ann := range *account.Announcements {
+				listing := ann
+				m.mockMercadoLivre.EXPECT().GetAnnouncement(listing.ID, gomock.Any()).Return(&listing, nil).AnyTimes()
+			}
+		}
+	}
+}
+
+// ignoreClaims lets the order claim the sync actions it registered,
+// for the tests that aren't about the claims.
+func (m *Mocks) ignoreClaims() {
+	m.mockOrderRepo.EXPECT().ClaimSyncAction(gomock.Any(), gomock.Any(), 2*time.Minute).Return(true, nil).AnyTimes()
+}
+
+// ignoreEvents allows the events of the order to be published,
+// for the tests that aren't about the webhooks.
+func (m *Mocks) ignoreEvents() {
+	m.mockPublisher.EXPECT().Publish(gomock.Any()).Return(nil).AnyTimes()
+}
+
 // TestProcessOrderErrors specifically tests error scenarios in order processing.
 // Covers errors in:
 // 1. Credential retrieval
 // 2. Mercado Livre API calls
 // 3. Announcement retrieval
-// 4. Quantity updates
+// 4. Quantity updates, which are retried instead of failing the order
 // 5. Order registration
 func TestProcessOrderErrors(t *testing.T) {
 	accountId := entity.ID(uuid.New())
@@ -1341,8 +1497,8 @@ func TestProcessOrderErrors(t *testing.T) {
 		{
 			name: "error getting credentials",
 			setupMocks: func(m *Mocks) {
-				m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
-				m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
 				m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(nil, errors.New("credentials error"))
 				m.mockLogger.EXPECT().Error("Error in retrieving Meli credentials during order processing",
 					gomock.Any(),
@@ -1359,10 +1515,10 @@ func TestProcessOrderErrors(t *testing.T) {
 		{
 			name: "error fetching order from Mercado Livre",
 			setupMocks: func(m *Mocks) {
-				m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
-				m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
 				m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil)
-				m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(nil, errors.New("meli error"))
+				m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(nil, errors.New("meli error"))
 				m.mockLogger.EXPECT().Error("Error to fetch the order",
 					gomock.Any(),
 					zap.String("order_id", defaultOrderMessage.OrderId))
@@ -1387,10 +1543,10 @@ func TestProcessOrderErrors(t *testing.T) {
 					},
 				}
 
-				m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
-				m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
 				m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil)
-				m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
+				m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
 				m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", defaultMeliCredentials).Return(nil, errors.New("announcement error"))
 				m.mockLogger.EXPECT().Error("Error in retrieving the order product clones",
 					gomock.Any(),
@@ -1430,18 +1586,25 @@ func TestProcessOrderErrors(t *testing.T) {
 					},
 				}
 
-				m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
-				m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
 				m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil)
-				m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
+				m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
+				m.serveListings(*announcements)
 				m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", defaultMeliCredentials).Return(announcements, nil)
-				m.mockAnnUseCase.EXPECT().UpdateQuantity("2", 1, (*defaultMeliCredentials)[0]).Return(errors.New("update error"))
+				m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+				m.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 1, (*defaultMeliCredentials)[0]).Return(errors.New("update error"))
 				m.mockLogger.EXPECT().Error("Error updating announcements",
 					gomock.Any(),
+					zap.String("announcement_id", "2"),
+					zap.Int("variation_id", 0),
+					zap.Int("attempts", 1),
 				)
+				m.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+				m.mockOrderQueue.EXPECT().DeleteOrderNotification(defaultOrderMessage.ReceiptHandle).Return(nil)
 			},
-			wantErr:    true,
-			errMessage: "error syncing quantities",
+			// The order is registered and the update is retried by the dispatcher
+			wantErr: false,
 		},
 		{
 			name: "error registering order",
@@ -1474,13 +1637,14 @@ func TestProcessOrderErrors(t *testing.T) {
 					},
 				}
 
-				m.mockOrderCache.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
-				m.mockOrderRepo.EXPECT().GetOrder(defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
+				m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), defaultOrderMessage.OrderId).Return(nil, nil)
 				m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(defaultOrderMessage.Store).Return(defaultMeliCredentials, nil)
-				m.mockMercadoLivre.EXPECT().FetchOrder(defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
+				m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), defaultOrderMessage.OrderId, (*defaultMeliCredentials)[0].AccessToken).Return(meliOrder, nil)
+				m.serveListings(*announcements)
 				m.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", defaultMeliCredentials).Return(announcements, nil)
-				m.mockAnnUseCase.EXPECT().UpdateQuantity("2", 1, (*defaultMeliCredentials)[0]).Return(nil)
-				m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any()).Return(errors.New("register error"))
+				// Mercado Livre isn't updated before the order is registered
+				m.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(errors.New("register error"))
 				m.mockLogger.EXPECT().Error("Fail to store the order",
 					gomock.Any(),
 					zap.String("order_id", defaultOrderMessage.OrderId))
@@ -1497,6 +1661,12 @@ func TestProcessOrderErrors(t *testing.T) {
 
 			mocks := newMocks(ctrl)
 			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+			mocks.ignoreEvents()
 
 			tt.setupMocks(mocks)
 
@@ -1511,3 +1681,1395 @@ func TestProcessOrderErrors(t *testing.T) {
 		})
 	}
 }
+
+// TestListOrders tests the ListOrders method of OrderService.
+// It verifies:
+// 1. The page size defaults and limits
+// 2. The cursor of the next page points to the last order of the page
+// 3. Malformed cursors are rejected
+func TestListOrders(t *testing.T) {
+	storeId := entity.NewID()
+	newOrders := func(n int) []entity.Order {
+		orders := make([]entity.Order, n)
+		for i := range orders {
+			orders[i] = entity.Order{
+				ID:          entity.NewID(),
+				DateCreated: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Minute),
+			}
+		}
+		return orders
+	}
+
+	t.Run("default limit without next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+
+		orders := newOrders(3)
+		mocks.mockOrderRepo.EXPECT().ListOrders(gomock.Any()).DoAndReturn(func(filter order.OrderFilter) ([]entity.Order, error) {
+			if filter.Limit != 21 || filter.After != nil || filter.Store != storeId {
+				t.Errorf("unexpected filter %+v", filter)
+			}
+			return orders, nil
+		})
+
+		page, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId})
+		if err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+		if len(page.Orders) != 3 || page.NextCursor != "" {
+			t.Errorf("ListOrders() = %d orders, cursor %q", len(page.Orders), page.NextCursor)
+		}
+	})
+
+	t.Run("next page cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+
+		orders := newOrders(3)
+		gomock.InOrder(
+			mocks.mockOrderRepo.EXPECT().ListOrders(gomock.Any()).DoAndReturn(func(filter order.OrderFilter) ([]entity.Order, error) {
+				if filter.Limit != 3 {
+					t.Errorf("Limit = %d, want 3", filter.Limit)
+				}
+				return orders, nil
+			}),
+			mocks.mockOrderRepo.EXPECT().ListOrders(gomock.Any()).DoAndReturn(func(filter order.OrderFilter) ([]entity.Order, error) {
+				if filter.After == nil || filter.After.ID != orders[1].ID || !filter.After.DateCreated.Equal(orders[1].DateCreated) {
+					t.Errorf("After = %+v, want the position of %v", filter.After, orders[1].ID)
+				}
+				return orders[2:], nil
+			}),
+		)
+
+		page, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId, Limit: 2})
+		if err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+		if len(page.Orders) != 2 || page.NextCursor == "" {
+			t.Fatalf("ListOrders() = %d orders, cursor %q", len(page.Orders), page.NextCursor)
+		}
+
+		next, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId, Limit: 2, Cursor: page.NextCursor})
+		if err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+		if len(next.Orders) != 1 || next.NextCursor != "" {
+			t.Errorf("ListOrders() = %d orders, cursor %q", len(next.Orders), next.NextCursor)
+		}
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+
+		mocks.mockOrderRepo.EXPECT().ListOrders(gomock.Any()).DoAndReturn(func(filter order.OrderFilter) ([]entity.Order, error) {
+			if filter.Limit != 101 {
+				t.Errorf("Limit = %d, want 101", filter.Limit)
+			}
+			return nil, nil
+		})
+
+		if _, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId, Limit: 1000}); err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+
+		for _, cursor := range []string{"%%%", "bm8tc2VwYXJhdG9y", "MTI6bm90LWEtdXVpZA"} {
+			_, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId, Cursor: cursor})
+			if !errors.Is(err, order.ErrInvalidCursor) {
+				t.Errorf("ListOrders(%q) error = %v, want %v", cursor, err, order.ErrInvalidCursor)
+			}
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+
+		repoErr := errors.New("db error")
+		mocks.mockOrderRepo.EXPECT().ListOrders(gomock.Any()).Return(nil, repoErr)
+		mocks.mockLogger.EXPECT().Error("Fail to list the orders", repoErr, zap.String("store_id", storeId.String()))
+
+		if _, err := orderService.ListOrders(order.ListOrdersDtoInput{Store: storeId}); !errors.Is(err, repoErr) {
+			t.Errorf("ListOrders() error = %v, want %v", err, repoErr)
+		}
+	})
+}
+
+// TestGetOrderDetail tests the GetOrderDetail method of OrderService.
+func TestGetOrderDetail(t *testing.T) {
+	storeId := entity.NewID()
+	orderId := entity.NewID()
+
+	tests := []struct {
+		name    string
+		odr     *entity.Order
+		repoErr error
+		wantErr error
+	}{
+		{
+			name: "order found",
+			odr: &entity.Order{
+				ID:          orderId,
+				SyncActions: []entity.SyncAction{*entity.NewSyncAction(entity.NewID(), "MLB1", 0, 1, nil)},
+			},
+		},
+		{
+			name:    "order of another store",
+			wantErr: order.ErrOrderNotFound,
+		},
+		{
+			name:    "repository error",
+			repoErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mocks := newMocks(ctrl)
+			orderService := mocks.newOrderService()
+
+			mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(tt.odr, tt.repoErr)
+			if tt.repoErr != nil {
+				mocks.mockLogger.EXPECT().Error("Fail to retrieve the order", tt.repoErr, zap.String("store_id", storeId.String()), zap.String("order_id", orderId.String()))
+			}
+
+			odr, err := orderService.GetOrderDetail(storeId, orderId)
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Fatalf("GetOrderDetail() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && odr != tt.odr {
+				t.Errorf("GetOrderDetail() = %v, want %v", odr, tt.odr)
+			}
+		})
+	}
+}
+
+// TestResumeSync tests the retry of the listings whose quantity failed to be set.
+// It verifies:
+// 1. An order without failed actions isn't changed
+// 2. The failed actions are resumed and the order is read again
+// 3. The orders of another store aren't found
+// 4. A failure to resume is returned
+func TestResumeSync(t *testing.T) {
+	storeId, orderId, accountId := entity.NewID(), entity.NewID(), entity.NewID()
+	failed := *entity.NewSyncAction(accountId, "MLB2", 0, 3, errors.New("meli error"))
+	partial := &entity.Order{
+		ID:          orderId,
+		SyncActions: []entity.SyncAction{*entity.NewSyncAction(accountId, "MLB1", 0, 3, nil), failed},
+	}
+
+	t.Run("nothing to resume", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		synced := &entity.Order{ID: orderId, SyncActions: partial.SyncActions[:1]}
+		mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(synced, nil)
+
+		odr, err := mocks.newOrderService().ResumeSync(storeId, orderId)
+		if err != nil || odr != synced {
+			t.Errorf("ResumeSync() = %v, %v, want the order", odr, err)
+		}
+	})
+
+	t.Run("failed listings resumed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		resumed := &entity.Order{ID: orderId, SyncActions: []entity.SyncAction{partial.SyncActions[0], failed}}
+		resumed.SyncActions[1].Status = entity.SyncActionPending
+		gomock.InOrder(
+			mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(partial, nil),
+			mocks.mockOrderRepo.EXPECT().ResumeSyncActions(orderId).Return(1, nil),
+			mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(resumed, nil),
+		)
+
+		odr, err := mocks.newOrderService().ResumeSync(storeId, orderId)
+		if err != nil {
+			t.Fatalf("ResumeSync() error = %v", err)
+		}
+		if progress := entity.NewSyncProgress(odr.SyncActions); progress.Status != entity.SyncProgressPending || progress.Done != 1 {
+			t.Errorf("ResumeSync() progress = %+v, want the failed listing pending", progress)
+		}
+	})
+
+	t.Run("order of another store", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(nil, nil)
+
+		if _, err := mocks.newOrderService().ResumeSync(storeId, orderId); !errors.Is(err, order.ErrOrderNotFound) {
+			t.Errorf("ResumeSync() error = %v, want %v", err, order.ErrOrderNotFound)
+		}
+	})
+
+	t.Run("sync can't be resumed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		repoErr := errors.New("db error")
+		mocks.mockOrderRepo.EXPECT().GetOrderDetail(storeId, orderId).Return(partial, nil)
+		mocks.mockOrderRepo.EXPECT().ResumeSyncActions(orderId).Return(0, repoErr)
+		mocks.mockLogger.EXPECT().Error("Fail to resume the sync of the order", repoErr, zap.String("order_id", orderId.String()))
+
+		if _, err := mocks.newOrderService().ResumeSync(storeId, orderId); !errors.Is(err, repoErr) {
+			t.Errorf("ResumeSync() error = %v, want %v", err, repoErr)
+		}
+	})
+}
+
+// TestProcessOrderEvaluatesStock verifies the stock of the synchronized items is evaluated
+// with the highest quantity set on the clones, and that a failure doesn't stop the order.
+func TestProcessOrderEvaluatesStock(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 1},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 4},
+				{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 3},
+				{ID: "3", Title: "test-title", Sku: "test-sku", Quantity: 5},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		evaluateErr error
+	}{
+		{name: "stock evaluated"},
+		{name: "evaluation fails", evaluateErr: errors.New("alert error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mocks := newMocks(ctrl)
+			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreEvents()
+			mocks.ignoreKits()
+			mocks.ignoreAllocations()
+			mocks.ignoreAcknowledgements()
+
+			mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+			mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+			mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+			mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+			mocks.serveListings(*clones)
+			mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+			mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 2, (*credentials)[0]).Return(nil)
+			mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "3", 4, (*credentials)[0]).Return(nil)
+			mocks.mockAlertUseCase.EXPECT().EvaluateStock(storeId, "test-sku", 4).Return(tt.evaluateErr)
+			if tt.evaluateErr != nil {
+				mocks.mockLogger.EXPECT().Warn("Fail to evaluate the stock",
+					zap.String("sku", "test-sku"),
+					zap.Int("quantity", 4),
+					zap.Error(tt.evaluateErr),
+				)
+			}
+			mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+			mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+			mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+			if err := orderService.ProcessOrder(orderMessage); err != nil {
+				t.Errorf("ProcessOrder() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestProcessOrderVariationMatching verifies only the counterparts of the sold variation are updated.
+// The variations are matched by the SKU of the product in their account, or by their attribute
+// combination when they don't have one.
+func TestProcessOrderVariationMatching(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	aliasAccountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+		{
+			ID:      aliasAccountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token-2",
+				UserID:      "2",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{
+				ID:          "1",
+				Title:       "test-shirt",
+				Sku:         "shirt-blue-m",
+				Quantity:    1,
+				VariationID: 111,
+				VariationAttributes: []common.MeliVariationAttribute{
+					{ID: "COLOR", ValueName: "Azul"},
+					{ID: "SIZE", ValueName: "M"},
+				},
+			},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-shirt", Sku: "shirt", Variations: []common.MeliVariation{
+					{ID: 111, AvailableQuantity: 2, Sku: "shirt-blue-m"},
+					{ID: 112, AvailableQuantity: 3, Sku: "shirt-blue-l"},
+				}},
+				{ID: "2", Title: "test-shirt", Sku: "shirt", Variations: []common.MeliVariation{
+					{ID: 221, AvailableQuantity: 3, Sku: "shirt-blue-m"},
+					{ID: 222, AvailableQuantity: 3, Sku: "shirt-blue-l"},
+				}},
+				// The variations don't have a SKU
+				{ID: "3", Title: "test-shirt", Sku: "shirt", Variations: []common.MeliVariation{
+					{ID: 331, AvailableQuantity: 3, Attributes: []common.MeliVariationAttribute{
+						{ID: "SIZE", ValueName: "m"},
+						{ID: "COLOR", ValueName: "azul"},
+					}},
+					{ID: 332, AvailableQuantity: 3, Attributes: []common.MeliVariationAttribute{
+						{ID: "COLOR", ValueName: "Azul"},
+						{ID: "SIZE", ValueName: "L"},
+					}},
+				}},
+			},
+		},
+		// The account uses an alias of the SKU
+		{
+			AccountID: aliasAccountId,
+			Sku:       "camisa-azul-m",
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "4", Title: "test-camisa", Sku: "camisa", Variations: []common.MeliVariation{
+					{ID: 441, AvailableQuantity: 3, Sku: "camisa-azul-m"},
+					{ID: 442, AvailableQuantity: 3, Sku: "shirt-blue-m"},
+				}},
+				// Pinned to the product, none of its variations is the sold one
+				{ID: "5", Title: "test-camisa", Sku: "camisa", Variations: []common.MeliVariation{
+					{ID: 551, AvailableQuantity: 3, Sku: "camisa-azul-g"},
+				}},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	orderService := mocks.newOrderService()
+	mocks.ignoreClaims()
+	mocks.ignoreEvents()
+	mocks.ignoreKits()
+	mocks.ignoreAllocations()
+	mocks.ignoreAcknowledgements()
+	mocks.ignoreStockEvaluation()
+
+	mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+	mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+	mocks.serveListings(*clones)
+	mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("shirt-blue-m", credentials).Return(clones, nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 2, (*credentials)[0], 221).Return(nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "3", 2, (*credentials)[0], 331).Return(nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "4", 2, (*credentials)[1], 441).Return(nil)
+	mocks.mockLogger.EXPECT().Warn("No variation of the listing matches the sold one",
+		zap.String("announcement_id", "5"),
+		zap.String("sku", "shirt-blue-m"),
+		zap.String("account_sku", "camisa-azul-m"),
+	)
+	mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+	if err := orderService.ProcessOrder(orderMessage); err != nil {
+		t.Errorf("ProcessOrder() error = %v", err)
+	}
+}
+
+// TestProcessOrderKeepsSoldItems tests an order with a SKU sold in two lines with different prices,
+// the order is registered with both lines while the sale is planned once with their total
+func TestProcessOrderKeepsSoldItems(t *testing.T) {
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: entity.NewID(),
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 1, UnitPrice: 10, CurrencyID: "BRL"},
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 2, UnitPrice: 12, CurrencyID: "BRL"},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-title", Quantity: 4, Sku: "test-sku"},
+				{ID: "2", Title: "test-title", Quantity: 5, Sku: "test-sku"},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	orderService := mocks.newOrderService()
+	mocks.ignoreClaims()
+	mocks.ignoreEvents()
+	mocks.ignoreKits()
+	mocks.ignoreAllocations()
+	mocks.ignoreAcknowledgements()
+	mocks.ignoreStockEvaluation()
+
+	mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+	mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+	mocks.serveListings(*clones)
+	mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 2, (*credentials)[0]).Return(nil)
+	mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, odr *entity.Order) error {
+		want := []entity.OrderItem{
+			{Title: "test-title", Sku: "test-sku", Quantity: 1, UnitPrice: 10, CurrencyID: "BRL"},
+			{Title: "test-title", Sku: "test-sku", Quantity: 2, UnitPrice: 12, CurrencyID: "BRL"},
+		}
+		if !cmp.Equal(odr.Items, want, cmpopts.IgnoreFields(entity.OrderItem{}, "ID")) {
+			t.Errorf("RegisterOrder() items diff: %v", cmp.Diff(odr.Items, want, cmpopts.IgnoreFields(entity.OrderItem{}, "ID")))
+		}
+		return nil
+	})
+	mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+	if err := orderService.ProcessOrder(orderMessage); err != nil {
+		t.Errorf("ProcessOrder() error = %v", err)
+	}
+}
+
+// TestProcessOrderVariationsWithSameSku tests an order with two variations that share the SKU of
+// their product, each one is synchronized with its own counterparts and quantity
+func TestProcessOrderVariationsWithSameSku(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{
+				ID:          "1",
+				Title:       "test-shirt",
+				Sku:         "shirt",
+				Quantity:    1,
+				VariationID: 111,
+				VariationAttributes: []common.MeliVariationAttribute{
+					{ID: "COLOR", ValueName: "Azul"},
+					{ID: "SIZE", ValueName: "M"},
+				},
+			},
+			{
+				ID:          "1",
+				Title:       "test-shirt",
+				Sku:         "shirt",
+				Quantity:    2,
+				VariationID: 112,
+				VariationAttributes: []common.MeliVariationAttribute{
+					{ID: "COLOR", ValueName: "Azul"},
+					{ID: "SIZE", ValueName: "L"},
+				},
+			},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-shirt", Sku: "shirt", Variations: []common.MeliVariation{
+					{ID: 111, AvailableQuantity: 2, Attributes: []common.MeliVariationAttribute{{ID: "COLOR", ValueName: "Azul"}, {ID: "SIZE", ValueName: "M"}}},
+					{ID: 112, AvailableQuantity: 3, Attributes: []common.MeliVariationAttribute{{ID: "COLOR", ValueName: "Azul"}, {ID: "SIZE", ValueName: "L"}}},
+				}},
+				{ID: "2", Title: "test-shirt", Sku: "shirt", Variations: []common.MeliVariation{
+					{ID: 221, AvailableQuantity: 5, Attributes: []common.MeliVariationAttribute{{ID: "COLOR", ValueName: "Azul"}, {ID: "SIZE", ValueName: "M"}}},
+					{ID: 222, AvailableQuantity: 5, Attributes: []common.MeliVariationAttribute{{ID: "COLOR", ValueName: "Azul"}, {ID: "SIZE", ValueName: "L"}}},
+				}},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	orderService := mocks.newOrderService()
+	mocks.ignoreClaims()
+	mocks.ignoreEvents()
+	mocks.ignoreKits()
+	mocks.ignoreAllocations()
+	mocks.ignoreAcknowledgements()
+	mocks.ignoreStockEvaluation()
+
+	mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+	mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+	mocks.serveListings(*clones)
+	mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("shirt", credentials).Return(clones, nil).Times(2)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 4, (*credentials)[0], 221).Return(nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 3, (*credentials)[0], 222).Return(nil)
+	mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+	if err := orderService.ProcessOrder(orderMessage); err != nil {
+		t.Errorf("ProcessOrder() error = %v", err)
+	}
+}
+
+// TestProcessOrderPack tests the processing of an order that is part of a pack.
+// It verifies:
+// 1. The items of the orders of the pack are merged, so a SKU is synchronized once
+// 2. The orders of the pack that were already registered are left out
+// 3. The orders are registered together, with the sync actions on the notified order
+// 4. A failure to fetch the pack stops the processing, so the notification is retried
+func TestProcessOrderPack(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000001",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000001",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		PackID:      "2000000000000001",
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 1},
+		},
+	}
+	otherMeliOrder := &common.MeliOrder{
+		ID:          "20210101000002",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		PackID:      "2000000000000001",
+		Items: []common.OrderItem{
+			{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 2},
+			{ID: "4", Title: "test-title-another-item", Sku: "test-sku-another-item", Quantity: 1},
+		},
+	}
+	pack := &common.MeliPack{
+		ID:       "2000000000000001",
+		OrderIDs: []string{"20210101000001", "20210101000002", "20210101000003"},
+	}
+
+	t.Run("orders of the pack processed as one unit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+		mocks.ignoreClaims()
+		mocks.ignoreEvents()
+		mocks.ignoreKits()
+		mocks.ignoreAllocations()
+		mocks.ignoreAcknowledgements()
+		mocks.ignoreStockEvaluation()
+
+		mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+		mocks.mockMercadoLivre.EXPECT().FetchPack(gomock.Any(), pack.ID, "test-access-token").Return(pack, nil)
+		mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), "20210101000002").Return(nil, nil)
+		mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), "20210101000002", "test-access-token").Return(otherMeliOrder, nil)
+		// Already registered by the notification of the order
+		mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), "20210101000003").Return(&entity.Order{}, nil)
+
+		clones := []announcement.Announcements{
+			{
+				AccountID: accountId,
+				Announcements: &[]common.MeliAnnouncement{
+					{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 5},
+					{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 5},
+					{ID: "3", Title: "test-title", Sku: "test-sku", Quantity: 5},
+				},
+			},
+		}
+		otherClones := []announcement.Announcements{
+			{
+				AccountID: accountId,
+				Announcements: &[]common.MeliAnnouncement{
+					{ID: "4", Title: "test-title-another-item", Sku: "test-sku-another-item", Quantity: 2},
+					{ID: "5", Title: "test-title-another-item", Sku: "test-sku-another-item", Quantity: 2},
+				},
+			},
+		}
+		mocks.serveListings(clones, otherClones)
+		mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(&clones, nil)
+		mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku-another-item", credentials).Return(&otherClones, nil)
+		// The units sold by both orders are taken from the listing once
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "3", 2, (*credentials)[0]).Return(nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "5", 1, (*credentials)[0]).Return(nil)
+
+		mocks.mockOrderRepo.EXPECT().RegisterPack(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, orders []*entity.Order) error {
+			if len(orders) != 2 {
+				t.Fatalf("RegisterPack() orders = %d, want 2", len(orders))
+			}
+			if orders[0].MarketplaceID != meliOrder.ID || orders[1].MarketplaceID != otherMeliOrder.ID {
+				t.Errorf("RegisterPack() orders = %s, %s", orders[0].MarketplaceID, orders[1].MarketplaceID)
+			}
+			// Two quantities and two kit sales
+			if len(orders[0].SyncActions) != 4 || len(orders[1].SyncActions) != 0 {
+				t.Errorf("RegisterPack() sync actions = %d, %d, want 4, 0", len(orders[0].SyncActions), len(orders[1].SyncActions))
+			}
+			for _, o := range orders {
+				if o.PackID != pack.ID {
+					t.Errorf("RegisterPack() pack = %s, want %s", o.PackID, pack.ID)
+				}
+			}
+			return nil
+		})
+		mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+		if err := orderService.ProcessOrder(orderMessage); err != nil {
+			t.Errorf("ProcessOrder() error = %v", err)
+		}
+	})
+
+	t.Run("error fetching the pack", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+		mocks.ignoreClaims()
+
+		packErr := errors.New("error to fetch pack")
+		mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+		mocks.mockMercadoLivre.EXPECT().FetchPack(gomock.Any(), pack.ID, "test-access-token").Return(nil, packErr)
+		mocks.mockLogger.EXPECT().Error("Error to fetch the pack", packErr,
+			zap.String("order_id", meliOrder.ID),
+			zap.String("pack_id", pack.ID),
+		)
+
+		if err := orderService.ProcessOrder(orderMessage); err != order.ErrProcessingOrder {
+			t.Errorf("ProcessOrder() error = %v, want %v", err, order.ErrProcessingOrder)
+		}
+	})
+}
+
+// eventMatcher matches the events by their store and type
+type eventMatcher struct {
+	store     entity.ID
+	eventType entity.EventType
+}
+
+func (e eventMatcher) Matches(x interface{}) bool {
+	event, ok := x.(*entity.Event)
+	return ok && event.StoreID == e.store && event.Type == e.eventType
+}
+
+func (e eventMatcher) String() string {
+	return "event " + string(e.eventType) + " of the store " + e.store.String()
+}
+
+// TestProcessOrderPublishesEvents tests the events published to the webhooks of the store.
+// It verifies:
+// 1. The synchronized quantities and the processed order are published
+// 2. A failed synchronization is published
+// 3. A failure to publish doesn't stop the processing of the order
+func TestProcessOrderPublishesEvents(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 1},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 4},
+				{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 5},
+			},
+		},
+	}
+
+	setup := func(m *Mocks) {
+		m.ignoreStockEvaluation()
+		m.ignoreKits()
+		m.ignoreAllocations()
+		m.ignoreAcknowledgements()
+		m.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		m.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+		m.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+		m.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+	}
+
+	t.Run("quantities synced and order processed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+		mocks.ignoreClaims()
+		setup(mocks)
+
+		mocks.serveListings(*clones)
+		mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 4, (*credentials)[0]).Return(nil)
+		mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+		mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+		mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+		gomock.InOrder(
+			mocks.mockPublisher.EXPECT().Publish(eventMatcher{storeId, entity.QuantitySynced}).DoAndReturn(func(event *entity.Event) error {
+				data := event.Data.(entity.QuantitySyncedData)
+				if data.Sku != "test-sku" || len(data.Clones) != 1 || data.Clones[0].AnnouncementID != "2" || data.Clones[0].Quantity != 4 {
+					t.Errorf("quantity synced data = %+v", data)
+				}
+				return nil
+			}),
+			mocks.mockPublisher.EXPECT().Publish(eventMatcher{storeId, entity.OrderProcessed}).DoAndReturn(func(event *entity.Event) error {
+				data := event.Data.(entity.OrderProcessedData)
+				if data.MarketplaceID != orderMessage.OrderId || data.AccountID != accountId || len(data.Items) != 1 {
+					t.Errorf("order processed data = %+v", data)
+				}
+				return nil
+			}),
+		)
+
+		if err := orderService.ProcessOrder(orderMessage); err != nil {
+			t.Errorf("ProcessOrder() error = %v", err)
+		}
+	})
+
+	t.Run("sync failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+		mocks.ignoreClaims()
+		setup(mocks)
+
+		annErr := errors.New("announcement error")
+		mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(nil, annErr)
+		mocks.mockLogger.EXPECT().Error("Error in retrieving the order product clones", annErr, zap.String("sku", "test-sku"))
+		mocks.mockPublisher.EXPECT().Publish(eventMatcher{storeId, entity.SyncFailed}).Return(nil)
+
+		if err := orderService.ProcessOrder(orderMessage); err != order.ErrProcessingOrder {
+			t.Errorf("ProcessOrder() error = %v, want %v", err, order.ErrProcessingOrder)
+		}
+	})
+
+	t.Run("publishing fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		orderService := mocks.newOrderService()
+		mocks.ignoreClaims()
+		setup(mocks)
+
+		publishErr := errors.New("webhook error")
+		mocks.serveListings(*clones)
+		mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 4, (*credentials)[0]).Return(nil)
+		mocks.mockPublisher.EXPECT().Publish(gomock.Any()).Return(publishErr).Times(2)
+		mocks.mockLogger.EXPECT().Warn("Fail to publish the event", gomock.Any(), zap.String("store_id", storeId.String()), zap.Error(publishErr)).Times(2)
+		mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil)
+		mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+		mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+		if err := orderService.ProcessOrder(orderMessage); err != nil {
+			t.Errorf("ProcessOrder() error = %v", err)
+		}
+	})
+}
+
+// TestProcessOrderAppliesKits tests the propagation of the sales to the kits of the store.
+// It verifies:
+// 1. The sale is registered as a kit sale, dispatched after the quantities of the clones
+// 2. The changes made to the kits are acknowledged with the kit sale
+// 3. A failure on the kits doesn't stop the processing of the order, and it isn't retried
+func TestProcessOrderAppliesKits(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 2},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 4},
+				{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 6},
+			},
+		},
+	}
+	kitAction := *entity.NewSyncAction(accountId, "3", 0, 2, nil)
+
+	tests := []struct {
+		name       string
+		kitActions []entity.SyncAction
+		kitErr     error
+		wantStatus entity.SyncActionStatus
+	}{
+		{name: "kits recomputed", kitActions: []entity.SyncAction{kitAction}, wantStatus: entity.SyncActionDone},
+		// The components decremented before the failure are acknowledged, the sale is retried later
+		{name: "kits fail", kitActions: []entity.SyncAction{kitAction}, kitErr: errors.New("kit error"), wantStatus: entity.SyncActionPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mocks := newMocks(ctrl)
+			orderService := mocks.newOrderService()
+			mocks.ignoreClaims()
+			mocks.ignoreStockEvaluation()
+			mocks.ignoreEvents()
+			mocks.ignoreAllocations()
+
+			mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+			mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+			mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+			mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+			mocks.serveListings(*clones)
+			mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+			gomock.InOrder(
+				mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, odr *entity.Order) error {
+					if len(odr.SyncActions) != 2 || odr.SyncActions[1].Kind != entity.SyncKitSale || odr.SyncActions[1].Quantity != 2 {
+						t.Errorf("sync actions = %+v, want the quantity of the clone and the kit sale", odr.SyncActions)
+					}
+					return nil
+				}),
+				mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 4, (*credentials)[0]).Return(nil),
+				mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(gomock.Any(), gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 1}, gomock.Nil()).Return(true, nil),
+				mocks.mockKitUseCase.EXPECT().ApplySale(storeId, gomock.Any(), "test-sku", 2).Return(tt.kitActions, tt.kitErr),
+				mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(gomock.Any(), gomock.Any(), syncActionMatcher{entity.SyncKitSale, tt.wantStatus, 1}, tt.kitActions).Return(true, nil),
+			)
+			if tt.kitErr != nil {
+				mocks.mockLogger.EXPECT().Error("Fail to apply the sale to the kits", tt.kitErr,
+					zap.String("sku", "test-sku"),
+					zap.Int("quantity", 2),
+					zap.Int("attempts", 1),
+				)
+			}
+			mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+			mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+			if err := orderService.ProcessOrder(orderMessage); err != nil {
+				t.Errorf("ProcessOrder() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestProcessOrderSkipsClaimedActions verifies the order only executes the sync actions it claims,
+// the ones claimed by the dispatcher meanwhile aren't executed twice.
+func TestProcessOrderSkipsClaimedActions(t *testing.T) {
+	storeId := entity.NewID()
+	accountId := entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{
+			ID:      accountId,
+			OwnerID: storeId,
+			MeliCredential: &common.MeliCredential{
+				AccessToken: "test-access-token",
+				UserID:      "1",
+			},
+		},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 2},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: accountId,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 4},
+				{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 6},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	orderService := mocks.newOrderService()
+	mocks.ignoreStockEvaluation()
+	mocks.ignoreEvents()
+	mocks.ignoreAllocations()
+
+	mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+	mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "test-access-token").Return(meliOrder, nil)
+	mocks.serveListings(*clones)
+	mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+	gomock.InOrder(
+		mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).Return(nil),
+		// The quantity of the clone was claimed by the dispatcher
+		mocks.mockOrderRepo.EXPECT().ClaimSyncAction(syncActionMatcher{entity.SyncQuantity, entity.SyncActionPending, 0}, gomock.Any(), 2*time.Minute).Return(false, nil),
+		mocks.mockOrderRepo.EXPECT().ClaimSyncAction(syncActionMatcher{entity.SyncKitSale, entity.SyncActionPending, 0}, gomock.Any(), 2*time.Minute).Return(true, nil),
+		mocks.mockKitUseCase.EXPECT().ApplySale(storeId, gomock.Any(), "test-sku", 2).Return(nil, nil),
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(gomock.Any(), gomock.Any(), syncActionMatcher{entity.SyncKitSale, entity.SyncActionDone, 1}, gomock.Nil()).Return(true, nil),
+	)
+	mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+	if err := orderService.ProcessOrder(orderMessage); err != nil {
+		t.Errorf("ProcessOrder() error = %v", err)
+	}
+}
+
+// TestProcessOrderAllocatesStock verifies the sale is registered as a change of the true stock of a SKU
+// with an allocation policy, and each clone publishes the quantity allocated to its account.
+func TestProcessOrderAllocatesStock(t *testing.T) {
+	storeId := entity.NewID()
+	mainAccount, secondAccount := entity.NewID(), entity.NewID()
+	orderMessage := order.OrderMessage{
+		Store:         "1",
+		OrderId:       "20210101000000",
+		ReceiptHandle: "test-receipt-handle",
+	}
+	credentials := &[]store.Credentials{
+		{ID: mainAccount, OwnerID: storeId, MeliCredential: &common.MeliCredential{AccessToken: "main-token", UserID: "1"}},
+		{ID: secondAccount, OwnerID: storeId, MeliCredential: &common.MeliCredential{AccessToken: "second-token", UserID: "2"}},
+	}
+	meliOrder := &common.MeliOrder{
+		ID:          "20210101000000",
+		DateCreated: "2022-10-30T16:19:20.129Z",
+		Status:      common.Paid,
+		Items: []common.OrderItem{
+			{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 2},
+		},
+	}
+	clones := &[]announcement.Announcements{
+		{
+			AccountID: mainAccount,
+			Announcements: &[]common.MeliAnnouncement{
+				// Already decremented by Mercado Livre
+				{ID: "1", Title: "test-title", Sku: "test-sku", Quantity: 4},
+				{ID: "2", Title: "test-title", Sku: "test-sku", Quantity: 6},
+			},
+		},
+		{
+			AccountID: secondAccount,
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "3", Title: "test-title", Sku: "test-sku", Quantity: 2},
+			},
+		},
+	}
+	policy, _ := entity.NewAllocationPolicy(storeId, "test-sku", nil, []entity.AccountAllocation{
+		{AccountID: secondAccount, Buffer: 2, Percentage: 50},
+	})
+
+	ctrl := gomock.NewController(t)
+	mocks := newMocks(ctrl)
+	orderService := mocks.newOrderService()
+	mocks.ignoreClaims()
+	mocks.ignoreEvents()
+	mocks.ignoreKits()
+	mocks.ignoreAcknowledgements()
+
+	mocks.mockOrderCache.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockOrderRepo.EXPECT().GetOrder(gomock.Any(), orderMessage.OrderId).Return(nil, nil)
+	mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromMeliUserID(orderMessage.Store).Return(credentials, nil)
+	mocks.mockMercadoLivre.EXPECT().FetchOrder(gomock.Any(), orderMessage.OrderId, "main-token").Return(meliOrder, nil)
+	mocks.serveListings(*clones)
+	mocks.mockAnnUseCase.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(clones, nil)
+	// The true stock is only read, the sale is removed from it with the registration of the order
+	mocks.mockAllocator.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 6}).
+		Return(&allocation.Allocation{Policy: policy, Stock: 6}, nil)
+	mocks.mockOrderRepo.EXPECT().RegisterOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, odr *entity.Order) error {
+		want := []entity.SyncAction{
+			*entity.NewPendingSyncAction(entity.SyncQuantity, mainAccount, "2", 0, "test-sku", 0, time.Time{}),
+			*entity.NewPendingSyncAction(entity.SyncQuantity, secondAccount, "3", 0, "test-sku", 0, time.Time{}),
+			*entity.NewPendingSyncAction(entity.SyncKitSale, mainAccount, "1", 0, "test-sku", 2, time.Time{}),
+		}
+		want[0].Delta, want[1].Delta = -2, -2
+		if diff := cmp.Diff(want, odr.SyncActions, cmpopts.IgnoreFields(entity.SyncAction{}, "ID", "CreatedAt", "NextAttemptAt")); diff != "" {
+			t.Errorf("sync actions mismatch (-want +got):\n%s", diff)
+		}
+		wantChanges := []entity.StockChange{{StoreID: storeId, Sku: "test-sku", Delta: -2}}
+		if diff := cmp.Diff(wantChanges, odr.StockChanges); diff != "" {
+			t.Errorf("stock changes mismatch (-want +got):\n%s", diff)
+		}
+		return nil
+	})
+	// The allocated quantities are computed again from the true stock when the actions are executed
+	mocks.mockAllocator.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 6}).
+		Return(&allocation.Allocation{Policy: policy, Stock: 4}, nil)
+	mocks.mockAllocator.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 2}).
+		Return(&allocation.Allocation{Policy: policy, Stock: 4}, nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "2", 4, (*credentials)[0]).Return(nil)
+	mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "3", 1, (*credentials)[1]).Return(nil)
+	mocks.mockAlertUseCase.EXPECT().EvaluateStock(storeId, "test-sku", 4).Return(nil)
+	mocks.mockOrderCache.EXPECT().SetOrder(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mockOrderQueue.EXPECT().DeleteOrderNotification(orderMessage.ReceiptHandle).Return(nil)
+
+	if err := orderService.ProcessOrder(orderMessage); err != nil {
+		t.Errorf("ProcessOrder() error = %v", err)
+	}
+}
+
+// TestDispatchSyncActions tests the dispatch of the sync actions that weren't acknowledged.
+// It verifies:
+// 1. A quantity is set with the credentials of the account of the action
+// 2. A sale is removed from the quantity the listing has when it's executed, once, even when it's resumed
+// 3. A sale whose update was rejected is removed again, only an unanswered update may be on the listing
+// 4. A failed attempt is retried later, until the last one fails the action
+// 5. The changes made by a kit sale are acknowledged with it
+// 6. The result of an action whose claim expired is discarded
+// 7. A failure to claim the actions is returned
+func TestDispatchSyncActions(t *testing.T) {
+	storeId, accountId, orderId := entity.NewID(), entity.NewID(), entity.NewID()
+	credentials := &[]store.Credentials{
+		{ID: accountId, OwnerID: storeId, MeliCredential: &common.MeliCredential{AccessToken: "test-access-token", UserID: "1"}},
+	}
+	due := func(action *entity.SyncAction) []order.DueSyncAction {
+		return []order.DueSyncAction{{SyncAction: *action, OrderID: orderId, MarketplaceID: "20210101000000", StoreID: storeId}}
+	}
+	quantityAction := func(attempts int) *entity.SyncAction {
+		action := entity.NewPendingSyncAction(entity.SyncQuantity, accountId, "MLB1", 10, "test-sku", 3, time.Now())
+		action.Attempts = attempts
+		return action
+	}
+	saleAction := func(attempts, quantity int, lastError string, unconfirmed bool) *entity.SyncAction {
+		action := entity.NewPendingSyncAction(entity.SyncQuantity, accountId, "MLB1", 10, "test-sku", quantity, time.Now())
+		action.Delta = -2
+		action.Attempts = attempts
+		action.Error = lastError
+		action.Unconfirmed = unconfirmed
+		return action
+	}
+
+	t.Run("quantity set", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), 50, 2*time.Minute).Return(due(quantityAction(0)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 3, (*credentials)[0], 10).Return(nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 1}, gomock.Nil()).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("sale removed from the current quantity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		mocks.ignoreAllocations()
+
+		// Another sale decremented the listing after the action was planned
+		listing := &common.MeliAnnouncement{ID: "MLB1", Variations: []common.MeliVariation{{ID: 10, AvailableQuantity: 7}}}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(saleAction(0, 0, "", false)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().GetAnnouncement("MLB1", "test-access-token").Return(listing, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 5, (*credentials)[0], 10).Return(nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 1}, gomock.Nil()).
+			DoAndReturn(func(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+				if action.Quantity != 5 {
+					t.Errorf("quantity = %d, want 5", action.Quantity)
+				}
+				return true, nil
+			})
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("sale already applied by the failed attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		listing := &common.MeliAnnouncement{ID: "MLB1", Variations: []common.MeliVariation{{ID: 10, AvailableQuantity: 5}}}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(saleAction(1, 5, "timeout", true)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().GetAnnouncement("MLB1", "test-access-token").Return(listing, nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 2}, gomock.Nil()).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("resumed sale already applied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		listing := &common.MeliAnnouncement{ID: "MLB1", Variations: []common.MeliVariation{{ID: 10, AvailableQuantity: 5}}}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(saleAction(0, 5, "", true)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().GetAnnouncement("MLB1", "test-access-token").Return(listing, nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 1}, gomock.Nil()).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("sale on the listing by coincidence after a rejected attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		mocks.ignoreAllocations()
+
+		// Mercado Livre rejected the update, another change left the listing with the computed quantity
+		listing := &common.MeliAnnouncement{ID: "MLB1", Variations: []common.MeliVariation{{ID: 10, AvailableQuantity: 5}}}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(saleAction(1, 5, "fail to update the quantity", false)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().GetAnnouncement("MLB1", "test-access-token").Return(listing, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 3, (*credentials)[0], 10).Return(nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionDone, 2}, gomock.Nil()).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("unanswered update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+		mocks.ignoreAllocations()
+
+		updateErr := &announcement.AnnouncementError{Message: "Error to update quantity", AnnouncementID: "MLB1", Err: common.ErrMeliTimeout}
+		listing := &common.MeliAnnouncement{ID: "MLB1", Variations: []common.MeliVariation{{ID: 10, AvailableQuantity: 7}}}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(saleAction(0, 0, "", false)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockMercadoLivre.EXPECT().GetAnnouncement("MLB1", "test-access-token").Return(listing, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 5, (*credentials)[0], 10).Return(updateErr)
+		mocks.mockLogger.EXPECT().Error("Error updating announcements", updateErr, zap.String("announcement_id", "MLB1"), zap.Int("variation_id", 10), zap.Int("attempts", 1))
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionPending, 1}, gomock.Nil()).
+			DoAndReturn(func(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+				if !action.Unconfirmed || action.Quantity != 5 {
+					t.Errorf("action = %+v, want the unconfirmed quantity 5", action)
+				}
+				return true, nil
+			})
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("failed attempt is retried", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		updateErr := errors.New("meli error")
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(quantityAction(1)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 3, (*credentials)[0], 10).Return(updateErr)
+		mocks.mockLogger.EXPECT().Error("Error updating announcements", updateErr, zap.String("announcement_id", "MLB1"), zap.Int("variation_id", 10), zap.Int("attempts", 2))
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionPending, 2}, gomock.Nil()).
+			DoAndReturn(func(orderId, claim entity.ID, action *entity.SyncAction, followUps []entity.SyncAction) (bool, error) {
+				if !action.NextAttemptAt.After(time.Now()) {
+					t.Errorf("next attempt = %v, want a later attempt", action.NextAttemptAt)
+				}
+				return true, nil
+			})
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("last attempt fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		updateErr := errors.New("meli error")
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(quantityAction(4)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 3, (*credentials)[0], 10).Return(updateErr)
+		mocks.mockLogger.EXPECT().Error("Error updating announcements", updateErr, gomock.Any(), gomock.Any(), gomock.Any())
+		mocks.mockLogger.EXPECT().Warn("Sync action failed", gomock.Any(), zap.String("order_id", "20210101000000"), zap.Int("attempts", 5))
+		mocks.mockPublisher.EXPECT().Publish(eventMatcher{storeId, entity.SyncFailed}).Return(nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionFailed, 5}, gomock.Nil()).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("kit sale", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		kitSale := entity.NewPendingSyncAction(entity.SyncKitSale, accountId, "MLB1", 0, "test-sku", 2, time.Now())
+		followUps := []entity.SyncAction{*entity.NewSyncAction(accountId, "MLB2", 0, 1, nil)}
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(kitSale), nil)
+		mocks.mockKitUseCase.EXPECT().ApplySale(storeId, gomock.Any(), "test-sku", 2).Return(followUps, nil)
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncKitSale, entity.SyncActionDone, 1}, followUps).Return(true, nil)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("expired claim discards the result", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		updateErr := errors.New("meli error")
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(due(quantityAction(4)), nil)
+		mocks.mockStoreUseCase.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mocks.mockAnnUseCase.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 3, (*credentials)[0], 10).Return(updateErr)
+		mocks.mockLogger.EXPECT().Error("Error updating announcements", updateErr, gomock.Any(), gomock.Any(), gomock.Any())
+		// Another dispatcher holds the action, the failure isn't published
+		mocks.mockOrderRepo.EXPECT().AcknowledgeSyncAction(orderId, gomock.Any(), syncActionMatcher{entity.SyncQuantity, entity.SyncActionFailed, 5}, gomock.Nil()).Return(false, nil)
+		mocks.mockLogger.EXPECT().Warn("The claim of the sync action expired, its result was discarded", gomock.Any())
+
+		if err := mocks.newOrderService().DispatchSyncActions(); err != nil {
+			t.Errorf("DispatchSyncActions() error = %v", err)
+		}
+	})
+
+	t.Run("actions can't be claimed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mocks := newMocks(ctrl)
+
+		repoErr := errors.New("db error")
+		mocks.mockOrderRepo.EXPECT().ClaimDueSyncActions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, repoErr)
+		mocks.mockLogger.EXPECT().Error("Fail to claim the due sync actions", repoErr)
+
+		if err := mocks.newOrderService().DispatchSyncActions(); !errors.Is(err, repoErr) {
+			t.Errorf("DispatchSyncActions() error = %v, want %v", err, repoErr)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package stock
+
+import "github.com/Vractos/kloni/entity"
+
+type AdjustStockDtoInput struct {
+	Store entity.ID `json:"-"`
+	Sku   string    `json:"sku" validate:"required"`
+	// Restricts the adjustment to a variation, otherwise the variations with the SKU are adjusted
+	VariationID int `json:"variation_id"`
+	// Either the absolute quantity or the delta is informed
+	Quantity *int `json:"quantity" validate:"omitempty,min=0"`
+	Delta    *int `json:"delta"`
+}
+
+type PreviewImportDtoInput struct {
+	Store    entity.ID
+	FileName string
+	// Records of the file, with or without a header
+	Records [][]string
+}
@@ -0,0 +1,184 @@
+package stock
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+type UseCase interface {
+	// AdjustStock changes the quantity of a SKU on every listing of every account of the store,
+	// on a listing with variations only the variations with the SKU are changed.
+	//
+	// Parameters:
+	//   - input: AdjustStockDtoInput containing the SKU and the absolute quantity or the delta
+	//
+	// Returns:
+	//   - *Adjustment: The result of each listing
+	//   - error: ErrInvalidSku, ErrInvalidAdjustment or ErrSkuNotFound
+	AdjustStock(input AdjustStockDtoInput) (*Adjustment, error)
+	// PreviewImport validates the records of a stock file, the changes are only applied after the confirmation.
+	// The SKUs of the valid rows are searched in the background, the import is previewed once they're found.
+	//
+	// Parameters:
+	//   - input: PreviewImportDtoInput containing the records of the file
+	//
+	// Returns:
+	//   - *Import: The uploaded import with the rows waiting for their SKUs to be searched
+	//   - error: ErrMissingColumns or ErrTooManyRows if the file is invalid
+	PreviewImport(input PreviewImportDtoInput) (*Import, error)
+	// ConfirmImport schedules the valid rows of a previewed import to be applied in the background.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - importId: ID of the import
+	//
+	// Returns:
+	//   - *Import: The confirmed import
+	//   - error: ErrImportNotFound, ErrImportValidating, ErrImportNotPreviewed, ErrImportExpired or ErrNothingToImport
+	ConfirmImport(storeId, importId entity.ID) (*Import, error)
+	// GetImport retrieves an import with the result of each row.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - importId: ID of the import
+	//
+	// Returns:
+	//   - *Import: The import
+	//   - error: ErrImportNotFound if the import doesn't exist or belongs to another store
+	GetImport(storeId, importId entity.ID) (*Import, error)
+	// ListImports lists the imports of a store without their rows, the most recent first.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []Import: The imports with the count of rows by status
+	//   - error: Error if the imports can't be retrieved
+	ListImports(storeId entity.ID) ([]Import, error)
+	// RunImports applies the confirmed imports, throttling the updates of the listings.
+	//
+	// Returns:
+	//   - error: Error if the imports can't be retrieved
+	RunImports() error
+	// ValidateImports searches the SKUs of the uploaded imports on every account, throttling the searches.
+	//
+	// Returns:
+	//   - error: Error if the imports can't be retrieved
+	ValidateImports() error
+}
+
+type ListingStatus string
+
+const (
+	ListingUpdated   ListingStatus = "updated"
+	ListingUnchanged ListingStatus = "unchanged"
+	ListingFailed    ListingStatus = "failed"
+)
+
+// ListingAdjustment is the result of an adjustment on a listing, or on one of its variations
+type ListingAdjustment struct {
+	AccountID      entity.ID
+	AccountName    string
+	AnnouncementID string
+	// Zero when the listing doesn't have variations
+	VariationID      int
+	PreviousQuantity int
+	Quantity         int
+	Status           ListingStatus
+	Error            string
+}
+
+type Adjustment struct {
+	Sku string
+	// True stock of the SKU, nil when it doesn't have an allocation policy
+	Stock    *int
+	Listings []ListingAdjustment
+}
+
+type ImportStatus string
+
+const (
+	// The SKUs of the rows wait to be searched on the accounts
+	ImportUploaded   ImportStatus = "uploaded"
+	ImportValidating ImportStatus = "validating"
+	ImportPreviewed  ImportStatus = "previewed"
+	ImportConfirmed  ImportStatus = "confirmed"
+	ImportRunning    ImportStatus = "running"
+	ImportDone       ImportStatus = "done"
+)
+
+type RowStatus string
+
+const (
+	// The row will be applied when the import is confirmed
+	RowValid RowStatus = "valid"
+	// The SKU of the row wasn't searched yet
+	RowPending  RowStatus = "pending"
+	RowInvalid  RowStatus = "invalid"
+	RowNotFound RowStatus = "not_found"
+	RowApplied  RowStatus = "applied"
+	// Some listings of the row couldn't be updated, or its SKU couldn't be searched
+	RowFailed RowStatus = "failed"
+)
+
+// ImportRow is a SKU of a stock file with the quantity to be set on its listings
+type ImportRow struct {
+	// Position of the record in the file, starting at 1
+	Line     int
+	Sku      string
+	Quantity int
+	Status   RowStatus
+	Error    string
+	// Number of listings found with the SKU
+	Listings int
+	// Filled when the row is applied
+	Updated int
+	Failed  int
+}
+
+// Import is a stock file uploaded by a store
+type Import struct {
+	ID          entity.ID
+	Store       entity.ID
+	FileName    string
+	Status      ImportStatus
+	Rows        []ImportRow
+	Counts      map[RowStatus]int
+	CreatedAt   time.Time
+	ConfirmedAt *time.Time
+	FinishedAt  *time.Time
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	RegisterImport(i *Import) error
+	ConfirmImport(importId entity.ID, confirmedAt time.Time) error
+	UpdateImportRow(importId entity.ID, row *ImportRow) error
+	FinishImport(importId entity.ID, finishedAt time.Time) error
+	// Marks a validated import as previewed, so it can be confirmed
+	FinishValidation(importId entity.ID) error
+}
+
+type RepoReader interface {
+	// Nil when the import doesn't exist or belongs to another store
+	GetImport(storeId, importId entity.ID) (*Import, error)
+	// The imports don't have their rows, only their counts
+	ListImports(storeId entity.ID) ([]Import, error)
+	// Claims a confirmed import, or a running one not updated after staleAfter. Nil when there's none
+	ClaimImport(staleAfter time.Duration) (*Import, error)
+	// Claims an uploaded import, or a validating one not updated after staleAfter. Nil when there's none
+	ClaimValidation(staleAfter time.Duration) (*Import, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
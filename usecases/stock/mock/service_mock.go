@@ -0,0 +1,474 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/stock/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/stock/interface.go -destination=usecases/stock/mock/service_mock.go
+//
+
+// Package mock_stock is a generated GoMock package.
+package mock_stock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/Vractos/kloni/entity"
+	stock "github.com/Vractos/kloni/usecases/stock"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AdjustStock mocks base method.
+func (m *MockUseCase) AdjustStock(input stock.AdjustStockDtoInput) (*stock.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", input)
+	ret0, _ := ret[0].(*stock.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockUseCaseMockRecorder) AdjustStock(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockUseCase)(nil).AdjustStock), input)
+}
+
+// ConfirmImport mocks base method.
+func (m *MockUseCase) ConfirmImport(storeId, importId entity.ID) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmImport", storeId, importId)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmImport indicates an expected call of ConfirmImport.
+func (mr *MockUseCaseMockRecorder) ConfirmImport(storeId, importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmImport", reflect.TypeOf((*MockUseCase)(nil).ConfirmImport), storeId, importId)
+}
+
+// GetImport mocks base method.
+func (m *MockUseCase) GetImport(storeId, importId entity.ID) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", storeId, importId)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockUseCaseMockRecorder) GetImport(storeId, importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockUseCase)(nil).GetImport), storeId, importId)
+}
+
+// ListImports mocks base method.
+func (m *MockUseCase) ListImports(storeId entity.ID) ([]stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImports", storeId)
+	ret0, _ := ret[0].([]stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImports indicates an expected call of ListImports.
+func (mr *MockUseCaseMockRecorder) ListImports(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockUseCase)(nil).ListImports), storeId)
+}
+
+// PreviewImport mocks base method.
+func (m *MockUseCase) PreviewImport(input stock.PreviewImportDtoInput) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewImport", input)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewImport indicates an expected call of PreviewImport.
+func (mr *MockUseCaseMockRecorder) PreviewImport(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewImport", reflect.TypeOf((*MockUseCase)(nil).PreviewImport), input)
+}
+
+// RunImports mocks base method.
+func (m *MockUseCase) RunImports() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunImports")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunImports indicates an expected call of RunImports.
+func (mr *MockUseCaseMockRecorder) RunImports() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunImports", reflect.TypeOf((*MockUseCase)(nil).RunImports))
+}
+
+// ValidateImports mocks base method.
+func (m *MockUseCase) ValidateImports() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateImports")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateImports indicates an expected call of ValidateImports.
+func (mr *MockUseCaseMockRecorder) ValidateImports() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateImports", reflect.TypeOf((*MockUseCase)(nil).ValidateImports))
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// ConfirmImport mocks base method.
+func (m *MockRepoWriter) ConfirmImport(importId entity.ID, confirmedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmImport", importId, confirmedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmImport indicates an expected call of ConfirmImport.
+func (mr *MockRepoWriterMockRecorder) ConfirmImport(importId, confirmedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmImport", reflect.TypeOf((*MockRepoWriter)(nil).ConfirmImport), importId, confirmedAt)
+}
+
+// FinishImport mocks base method.
+func (m *MockRepoWriter) FinishImport(importId entity.ID, finishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImport", importId, finishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishImport indicates an expected call of FinishImport.
+func (mr *MockRepoWriterMockRecorder) FinishImport(importId, finishedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImport", reflect.TypeOf((*MockRepoWriter)(nil).FinishImport), importId, finishedAt)
+}
+
+// FinishValidation mocks base method.
+func (m *MockRepoWriter) FinishValidation(importId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishValidation", importId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishValidation indicates an expected call of FinishValidation.
+func (mr *MockRepoWriterMockRecorder) FinishValidation(importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishValidation", reflect.TypeOf((*MockRepoWriter)(nil).FinishValidation), importId)
+}
+
+// RegisterImport mocks base method.
+func (m *MockRepoWriter) RegisterImport(i *stock.Import) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterImport", i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterImport indicates an expected call of RegisterImport.
+func (mr *MockRepoWriterMockRecorder) RegisterImport(i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterImport", reflect.TypeOf((*MockRepoWriter)(nil).RegisterImport), i)
+}
+
+// UpdateImportRow mocks base method.
+func (m *MockRepoWriter) UpdateImportRow(importId entity.ID, row *stock.ImportRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportRow", importId, row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImportRow indicates an expected call of UpdateImportRow.
+func (mr *MockRepoWriterMockRecorder) UpdateImportRow(importId, row any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportRow", reflect.TypeOf((*MockRepoWriter)(nil).UpdateImportRow), importId, row)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// ClaimImport mocks base method.
+func (m *MockRepoReader) ClaimImport(staleAfter time.Duration) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimImport", staleAfter)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimImport indicates an expected call of ClaimImport.
+func (mr *MockRepoReaderMockRecorder) ClaimImport(staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimImport", reflect.TypeOf((*MockRepoReader)(nil).ClaimImport), staleAfter)
+}
+
+// ClaimValidation mocks base method.
+func (m *MockRepoReader) ClaimValidation(staleAfter time.Duration) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimValidation", staleAfter)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimValidation indicates an expected call of ClaimValidation.
+func (mr *MockRepoReaderMockRecorder) ClaimValidation(staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimValidation", reflect.TypeOf((*MockRepoReader)(nil).ClaimValidation), staleAfter)
+}
+
+// GetImport mocks base method.
+func (m *MockRepoReader) GetImport(storeId, importId entity.ID) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", storeId, importId)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockRepoReaderMockRecorder) GetImport(storeId, importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockRepoReader)(nil).GetImport), storeId, importId)
+}
+
+// ListImports mocks base method.
+func (m *MockRepoReader) ListImports(storeId entity.ID) ([]stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImports", storeId)
+	ret0, _ := ret[0].([]stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImports indicates an expected call of ListImports.
+func (mr *MockRepoReaderMockRecorder) ListImports(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockRepoReader)(nil).ListImports), storeId)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimImport mocks base method.
+func (m *MockRepository) ClaimImport(staleAfter time.Duration) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimImport", staleAfter)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimImport indicates an expected call of ClaimImport.
+func (mr *MockRepositoryMockRecorder) ClaimImport(staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimImport", reflect.TypeOf((*MockRepository)(nil).ClaimImport), staleAfter)
+}
+
+// ClaimValidation mocks base method.
+func (m *MockRepository) ClaimValidation(staleAfter time.Duration) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimValidation", staleAfter)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimValidation indicates an expected call of ClaimValidation.
+func (mr *MockRepositoryMockRecorder) ClaimValidation(staleAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimValidation", reflect.TypeOf((*MockRepository)(nil).ClaimValidation), staleAfter)
+}
+
+// ConfirmImport mocks base method.
+func (m *MockRepository) ConfirmImport(importId entity.ID, confirmedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmImport", importId, confirmedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmImport indicates an expected call of ConfirmImport.
+func (mr *MockRepositoryMockRecorder) ConfirmImport(importId, confirmedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmImport", reflect.TypeOf((*MockRepository)(nil).ConfirmImport), importId, confirmedAt)
+}
+
+// FinishImport mocks base method.
+func (m *MockRepository) FinishImport(importId entity.ID, finishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImport", importId, finishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishImport indicates an expected call of FinishImport.
+func (mr *MockRepositoryMockRecorder) FinishImport(importId, finishedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImport", reflect.TypeOf((*MockRepository)(nil).FinishImport), importId, finishedAt)
+}
+
+// FinishValidation mocks base method.
+func (m *MockRepository) FinishValidation(importId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishValidation", importId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishValidation indicates an expected call of FinishValidation.
+func (mr *MockRepositoryMockRecorder) FinishValidation(importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishValidation", reflect.TypeOf((*MockRepository)(nil).FinishValidation), importId)
+}
+
+// GetImport mocks base method.
+func (m *MockRepository) GetImport(storeId, importId entity.ID) (*stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", storeId, importId)
+	ret0, _ := ret[0].(*stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockRepositoryMockRecorder) GetImport(storeId, importId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockRepository)(nil).GetImport), storeId, importId)
+}
+
+// ListImports mocks base method.
+func (m *MockRepository) ListImports(storeId entity.ID) ([]stock.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImports", storeId)
+	ret0, _ := ret[0].([]stock.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImports indicates an expected call of ListImports.
+func (mr *MockRepositoryMockRecorder) ListImports(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImports", reflect.TypeOf((*MockRepository)(nil).ListImports), storeId)
+}
+
+// RegisterImport mocks base method.
+func (m *MockRepository) RegisterImport(i *stock.Import) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterImport", i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterImport indicates an expected call of RegisterImport.
+func (mr *MockRepositoryMockRecorder) RegisterImport(i any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterImport", reflect.TypeOf((*MockRepository)(nil).RegisterImport), i)
+}
+
+// UpdateImportRow mocks base method.
+func (m *MockRepository) UpdateImportRow(importId entity.ID, row *stock.ImportRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportRow", importId, row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImportRow indicates an expected call of UpdateImportRow.
+func (mr *MockRepositoryMockRecorder) UpdateImportRow(importId, row any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportRow", reflect.TypeOf((*MockRepository)(nil).UpdateImportRow), importId, row)
+}
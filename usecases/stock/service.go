@@ -0,0 +1,623 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/allocation"
+	"github.com/Vractos/kloni/usecases/announcement"
+	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/store"
+	"go.uber.org/zap"
+)
+
+// Error definitions for stock operations
+var (
+	// ErrInvalidSku is returned when the SKU is empty
+	ErrInvalidSku = errors.New("invalid sku")
+	// ErrInvalidAdjustment is returned when neither or both the quantity and the delta are informed,
+	// or when the quantity is negative
+	ErrInvalidAdjustment = errors.New("invalid adjustment")
+	// ErrSkuNotFound is returned when no listing of the store has the SKU
+	ErrSkuNotFound = errors.New("sku not found")
+	// ErrMissingColumns is returned when the header of a stock file doesn't have the SKU and the quantity columns
+	ErrMissingColumns = errors.New("the file must have a sku and a quantity column")
+	// ErrTooManyRows is returned when a stock file has more rows than maxImportRows
+	ErrTooManyRows = fmt.Errorf("the file can't have more than %d rows", maxImportRows)
+	// ErrImportNotFound is returned when the import doesn't exist or belongs to another store
+	ErrImportNotFound = errors.New("import not found")
+	// ErrImportValidating is returned when an import is confirmed before its SKUs are searched
+	ErrImportValidating = errors.New("import still being validated")
+	// ErrImportNotPreviewed is returned when an import is confirmed twice
+	ErrImportNotPreviewed = errors.New("import already confirmed")
+	// ErrImportExpired is returned when an import is confirmed after importExpiration
+	ErrImportExpired = errors.New("import expired")
+	// ErrNothingToImport is returned when an import without valid rows is confirmed
+	ErrNothingToImport = errors.New("nothing to import")
+)
+
+const (
+	// maxImportRows is the maximum number of rows of a stock file
+	maxImportRows = 2000
+	// importExpiration is how long an import can be confirmed after the preview,
+	// the quantities of the file are likely outdated after that
+	importExpiration = 24 * time.Hour
+	// staleImport is how long a running or validating import isn't updated before it's resumed by another worker
+	staleImport = 10 * time.Minute
+)
+
+// Names of the columns of the stock files, compared in lowercase
+var (
+	skuColumns      = []string{"sku", "seller_sku", "codigo", "código"}
+	quantityColumns = []string{"quantity", "qty", "quantidade", "estoque", "stock"}
+)
+
+type StockService struct {
+	repo     Repository
+	meli     common.MercadoLivre
+	store    store.UseCase
+	announce announcement.UseCase
+	allocate allocation.Allocator
+	throttle time.Duration
+	logger   common.Logger
+}
+
+// NewStockService creates a new instance of StockService.
+//
+// Parameters:
+//   - repository: Repository of the stock imports
+//   - mercadolivre: Mercado Livre API client, used to validate the SKUs of the imports
+//   - storeUseCase: Store management use case, used to retrieve the credentials of the accounts
+//   - announceUseCase: Announcement management use case, used to retrieve and update the listings
+//   - allocator: Allocator of the true stock of the SKUs with an allocation policy
+//   - throttle: Delay between the SKUs of an import, so the Mercado Livre rate limits aren't reached
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *StockService: A new instance of StockService
+func NewStockService(
+	repository Repository,
+	mercadolivre common.MercadoLivre,
+	storeUseCase store.UseCase,
+	announceUseCase announcement.UseCase,
+	allocator allocation.Allocator,
+	throttle time.Duration,
+	logger common.Logger,
+) *StockService {
+	return &StockService{
+		repo:     repository,
+		meli:     mercadolivre,
+		store:    storeUseCase,
+		announce: announceUseCase,
+		allocate: allocator,
+		throttle: throttle,
+		logger:   logger,
+	}
+}
+
+// AdjustStock changes the quantity of a SKU on every listing of every account of the store.
+// Only the variations with the SKU of the product in their account are adjusted, unless a variation is given.
+// A delta is applied to the current quantity of each listing, which never goes below zero.
+// When the SKU has an allocation policy, the quantity or the delta is applied to its true stock
+// instead, and each listing gets the quantity allocated to its account.
+// A failure on a listing doesn't stop the others, it's reported in its result.
+//
+// Parameters:
+//   - input: AdjustStockDtoInput containing the SKU and the absolute quantity or the delta
+//
+// Returns:
+//   - *Adjustment: The result of each listing
+//   - error: ErrInvalidSku, ErrInvalidAdjustment or ErrSkuNotFound
+func (s *StockService) AdjustStock(input AdjustStockDtoInput) (*Adjustment, error) {
+	sku := strings.TrimSpace(input.Sku)
+	if sku == "" {
+		return nil, ErrInvalidSku
+	}
+	if (input.Quantity == nil) == (input.Delta == nil) || (input.Quantity != nil && *input.Quantity < 0) {
+		return nil, ErrInvalidAdjustment
+	}
+
+	credentials, err := s.store.RetrieveMeliCredentialsFromStoreID(input.Store)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", input.Store.String()))
+		return nil, err
+	}
+	credentialsByAccount := make(map[string]store.Credentials, len(*credentials))
+	for _, c := range *credentials {
+		credentialsByAccount[c.ID.String()] = c
+	}
+
+	accounts, err := s.announce.RetrieveAnnouncementsFromAllAccounts(sku, credentials)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the announcements of the SKU", err, zap.String("sku", sku))
+		return nil, err
+	}
+
+	published, found := publishedQuantity(accounts, sku)
+	if !found {
+		return nil, ErrSkuNotFound
+	}
+	alloc, err := s.allocate.Allocate(allocation.AllocateDtoInput{
+		Store:     input.Store,
+		Sku:       sku,
+		Published: published,
+		Quantity:  input.Quantity,
+		Delta:     input.Delta,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adjustment := &Adjustment{Sku: sku, Listings: []ListingAdjustment{}}
+	if alloc != nil {
+		adjustment.Stock = &alloc.Stock
+	}
+	for _, account := range *accounts {
+		if account.Announcements == nil {
+			continue
+		}
+		accountCredentials := credentialsByAccount[account.AccountID.String()]
+
+		for _, ann := range *account.Announcements {
+			listing := ListingAdjustment{
+				AccountID:      account.AccountID,
+				AccountName:    account.AccountName,
+				AnnouncementID: ann.ID,
+			}
+
+			if len(ann.Variations) == 0 {
+				if input.VariationID != 0 {
+					continue
+				}
+				listing.PreviousQuantity = ann.Quantity
+				adjustment.Listings = append(adjustment.Listings, s.apply(listing, input, alloc, accountCredentials))
+				continue
+			}
+
+			matched := false
+			for _, variation := range ann.Variations {
+				if input.VariationID != 0 && variation.ID != input.VariationID {
+					continue
+				}
+				if input.VariationID == 0 && !account.HasSku(variation, sku) {
+					continue
+				}
+				matched = true
+				listing.VariationID = variation.ID
+				listing.PreviousQuantity = variation.AvailableQuantity
+				adjustment.Listings = append(adjustment.Listings, s.apply(listing, input, alloc, accountCredentials))
+			}
+			if !matched && input.VariationID == 0 {
+				s.logger.Warn("No variation of the listing has the SKU",
+					zap.String("announcement_id", ann.ID),
+					zap.String("sku", sku),
+					zap.String("account_sku", account.Sku),
+				)
+			}
+		}
+	}
+
+	if len(adjustment.Listings) == 0 {
+		return nil, ErrSkuNotFound
+	}
+	return adjustment, nil
+}
+
+// apply updates the quantity of a listing, or of one of its variations.
+//
+// Parameters:
+//   - listing: The listing with its current quantity
+//   - input: The adjustment
+//   - alloc: Allocation of the SKU, nil when it doesn't have an allocation policy
+//   - credentials: Credentials of the account of the listing
+//
+// Returns:
+//   - ListingAdjustment: The listing with the result of the update
+func (s *StockService) apply(
+	listing ListingAdjustment,
+	input AdjustStockDtoInput,
+	alloc *allocation.Allocation,
+	credentials store.Credentials,
+) ListingAdjustment {
+	if alloc != nil {
+		listing.Quantity = alloc.Quantity(listing.AccountID)
+	} else if input.Quantity != nil {
+		listing.Quantity = *input.Quantity
+	} else {
+		listing.Quantity = listing.PreviousQuantity + *input.Delta
+		if listing.Quantity < 0 {
+			listing.Quantity = 0
+		}
+	}
+
+	if listing.Quantity == listing.PreviousQuantity {
+		listing.Status = ListingUnchanged
+		return listing
+	}
+
+	var variationIDs []int
+	if listing.VariationID != 0 {
+		variationIDs = append(variationIDs, listing.VariationID)
+	}
+	// The error is logged by the announcement use case
+	if err := s.announce.UpdateQuantity(context.Background(), listing.AnnouncementID, listing.Quantity, credentials, variationIDs...); err != nil {
+		listing.Status = ListingFailed
+		listing.Error = err.Error()
+		return listing
+	}
+
+	listing.Status = ListingUpdated
+	return listing
+}
+
+// PreviewImport validates the records of a stock file, the changes are only applied after the confirmation.
+// The SKUs of the valid rows are searched on every account by ValidateImports, in the background,
+// since a file with many rows takes longer than a request.
+//
+// Parameters:
+//   - input: PreviewImportDtoInput containing the records of the file
+//
+// Returns:
+//   - *Import: The uploaded import with the rows waiting for their SKUs to be searched
+//   - error: ErrMissingColumns or ErrTooManyRows if the file is invalid
+func (s *StockService) PreviewImport(input PreviewImportDtoInput) (*Import, error) {
+	rows, err := parseRecords(input.Records)
+	if err != nil {
+		return nil, err
+	}
+	for r := range rows {
+		if rows[r].Status == RowValid {
+			rows[r].Status = RowPending
+		}
+	}
+
+	i := &Import{
+		ID:        entity.NewID(),
+		Store:     input.Store,
+		FileName:  input.FileName,
+		Status:    ImportUploaded,
+		Rows:      rows,
+		CreatedAt: time.Now().UTC(),
+	}
+	i.Counts = countRows(rows)
+	if err := s.repo.RegisterImport(i); err != nil {
+		s.logger.Error("Fail to register the import", err, zap.String("store_id", input.Store.String()))
+		return nil, err
+	}
+	return i, nil
+}
+
+// ConfirmImport schedules the valid rows of a previewed import to be applied in the background.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - importId: ID of the import
+//
+// Returns:
+//   - *Import: The confirmed import
+//   - error: ErrImportNotFound, ErrImportNotPreviewed, ErrImportExpired or ErrNothingToImport
+func (s *StockService) ConfirmImport(storeId, importId entity.ID) (*Import, error) {
+	i, err := s.GetImport(storeId, importId)
+	if err != nil {
+		return nil, err
+	}
+	if i.Status == ImportUploaded || i.Status == ImportValidating {
+		return nil, ErrImportValidating
+	}
+	if i.Status != ImportPreviewed {
+		return nil, ErrImportNotPreviewed
+	}
+	if time.Since(i.CreatedAt) > importExpiration {
+		return nil, ErrImportExpired
+	}
+	if i.Counts[RowValid] == 0 {
+		return nil, ErrNothingToImport
+	}
+
+	confirmedAt := time.Now().UTC()
+	if err := s.repo.ConfirmImport(importId, confirmedAt); err != nil {
+		s.logger.Error("Fail to confirm the import", err, zap.String("import_id", importId.String()))
+		return nil, err
+	}
+	i.Status = ImportConfirmed
+	i.ConfirmedAt = &confirmedAt
+	return i, nil
+}
+
+// GetImport retrieves an import with the result of each row.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - importId: ID of the import
+//
+// Returns:
+//   - *Import: The import
+//   - error: ErrImportNotFound if the import doesn't exist or belongs to another store
+func (s *StockService) GetImport(storeId, importId entity.ID) (*Import, error) {
+	i, err := s.repo.GetImport(storeId, importId)
+	if err != nil {
+		s.logger.Error("Fail to retrieve the import", err, zap.String("import_id", importId.String()))
+		return nil, err
+	}
+	if i == nil {
+		return nil, ErrImportNotFound
+	}
+	i.Counts = countRows(i.Rows)
+	return i, nil
+}
+
+// ListImports lists the imports of a store without their rows, the most recent first.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []Import: The imports with the count of rows by status
+//   - error: Error if the imports can't be retrieved
+func (s *StockService) ListImports(storeId entity.ID) ([]Import, error) {
+	imports, err := s.repo.ListImports(storeId)
+	if err != nil {
+		s.logger.Error("Fail to list the imports", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	return imports, nil
+}
+
+// RunImports applies the confirmed imports, throttling the updates of the listings.
+// The rows are saved as they're applied, so an interrupted import is resumed from where it stopped.
+//
+// Returns:
+//   - error: Error if the imports can't be retrieved
+func (s *StockService) RunImports() error {
+	for {
+		i, err := s.repo.ClaimImport(staleImport)
+		if err != nil {
+			s.logger.Error("Fail to claim an import", err)
+			return err
+		}
+		if i == nil {
+			return nil
+		}
+
+		s.logger.Info("Running the import", zap.String("import_id", i.ID.String()))
+		for r := range i.Rows {
+			row := &i.Rows[r]
+			if row.Status != RowValid {
+				continue
+			}
+			s.applyRow(i.Store, row)
+			if err := s.repo.UpdateImportRow(i.ID, row); err != nil {
+				s.logger.Error("Fail to update the row of the import", err,
+					zap.String("import_id", i.ID.String()),
+					zap.Int("line", row.Line),
+				)
+				return err
+			}
+			time.Sleep(s.throttle)
+		}
+
+		if err := s.repo.FinishImport(i.ID, time.Now().UTC()); err != nil {
+			s.logger.Error("Fail to finish the import", err, zap.String("import_id", i.ID.String()))
+			return err
+		}
+	}
+}
+
+// ValidateImports searches the SKUs of the uploaded imports on every account, throttling the searches.
+// The rows are saved as they're searched, so an interrupted validation is resumed from where it stopped.
+//
+// Returns:
+//   - error: Error if the imports can't be retrieved
+func (s *StockService) ValidateImports() error {
+	for {
+		i, err := s.repo.ClaimValidation(staleImport)
+		if err != nil {
+			s.logger.Error("Fail to claim an import to validate", err)
+			return err
+		}
+		if i == nil {
+			return nil
+		}
+
+		credentials, err := s.store.RetrieveMeliCredentialsFromStoreID(i.Store)
+		if err != nil {
+			s.logger.Error("Fail to retrieve the credentials of the store", err, zap.String("store_id", i.Store.String()))
+			return err
+		}
+
+		s.logger.Info("Validating the import", zap.String("import_id", i.ID.String()))
+		for r := range i.Rows {
+			row := &i.Rows[r]
+			if row.Status != RowPending {
+				continue
+			}
+			s.searchRow(row, credentials)
+			if err := s.repo.UpdateImportRow(i.ID, row); err != nil {
+				s.logger.Error("Fail to update the row of the import", err,
+					zap.String("import_id", i.ID.String()),
+					zap.Int("line", row.Line),
+				)
+				return err
+			}
+			time.Sleep(s.throttle)
+		}
+
+		if err := s.repo.FinishValidation(i.ID); err != nil {
+			s.logger.Error("Fail to finish the validation of the import", err, zap.String("import_id", i.ID.String()))
+			return err
+		}
+	}
+}
+
+// searchRow counts the listings of the SKU of a row on every account.
+//
+// Parameters:
+//   - row: The row, which receives the result
+//   - credentials: Credentials of the accounts of the store
+func (s *StockService) searchRow(row *ImportRow, credentials *[]store.Credentials) {
+	row.Listings = 0
+	for _, c := range *credentials {
+		ids, err := s.meli.GetAnnouncementsIDsViaSKU(row.Sku, c.UserID, c.AccessToken)
+		if err != nil {
+			s.logger.Error("Fail to search the listings of the imported SKU", err,
+				zap.String("sku", row.Sku),
+				zap.String("account_id", c.ID.String()),
+			)
+			row.Status = RowFailed
+			row.Error = "fail to search the listings of the SKU"
+			return
+		}
+		row.Listings += len(ids)
+	}
+
+	row.Status = RowValid
+	if row.Listings == 0 {
+		row.Status = RowNotFound
+		row.Error = "no listing has the SKU"
+	}
+}
+
+// applyRow sets the quantity of a row on the listings of its SKU.
+//
+// Parameters:
+//   - storeId: ID of the store of the import
+//   - row: The row, which receives the result
+func (s *StockService) applyRow(storeId entity.ID, row *ImportRow) {
+	quantity := row.Quantity
+	adjustment, err := s.AdjustStock(AdjustStockDtoInput{
+		Store:    storeId,
+		Sku:      row.Sku,
+		Quantity: &quantity,
+	})
+	if err != nil {
+		row.Status = RowFailed
+		row.Error = err.Error()
+		return
+	}
+
+	row.Updated, row.Failed = 0, 0
+	for _, listing := range adjustment.Listings {
+		if listing.Status == ListingFailed {
+			row.Failed++
+			row.Error = listing.Error
+		} else {
+			row.Updated++
+		}
+	}
+	row.Status = RowApplied
+	if row.Failed > 0 {
+		row.Status = RowFailed
+	}
+}
+
+// publishedQuantity is the largest quantity published on the listings of a SKU.
+// The quantity of a listing with variations is the sum of its variations, only the variations with the SKU count.
+//
+// Parameters:
+//   - accounts: The announcements of the SKU on each account
+//   - sku: The searched SKU
+//
+// Returns:
+//   - int: The largest quantity of a listing or a variation
+//   - bool: False when the SKU doesn't have listings
+func publishedQuantity(accounts *[]announcement.Announcements, sku string) (int, bool) {
+	published, found := 0, false
+	for _, account := range *accounts {
+		if account.Announcements == nil {
+			continue
+		}
+		for _, ann := range *account.Announcements {
+			found = true
+			if len(ann.Variations) == 0 && ann.Quantity > published {
+				published = ann.Quantity
+			}
+			for _, variation := range ann.Variations {
+				if account.HasSku(variation, sku) && variation.AvailableQuantity > published {
+					published = variation.AvailableQuantity
+				}
+			}
+		}
+	}
+	return published, found
+}
+
+// parseRecords parses the records of a stock file.
+// The first record is the header when it names the columns, otherwise the SKU is
+// the first column and the quantity is the second one.
+//
+// Parameters:
+//   - records: Records of the file
+//
+// Returns:
+//   - []ImportRow: The rows, the invalid ones have the reason
+//   - error: ErrMissingColumns or ErrTooManyRows
+func parseRecords(records [][]string) ([]ImportRow, error) {
+	skuColumn, quantityColumn, first := 0, 1, 0
+	if len(records) > 0 {
+		header := records[0]
+		if column := findColumn(header, skuColumns); column >= 0 {
+			skuColumn = column
+			if quantityColumn = findColumn(header, quantityColumns); quantityColumn < 0 {
+				return nil, ErrMissingColumns
+			}
+			first = 1
+		}
+	}
+	if len(records)-first > maxImportRows {
+		return nil, ErrTooManyRows
+	}
+
+	rows := []ImportRow{}
+	firstLine := make(map[string]int)
+	for i := first; i < len(records); i++ {
+		row := ImportRow{Line: i + 1, Status: RowValid}
+		record := records[i]
+		if skuColumn < len(record) {
+			row.Sku = strings.TrimSpace(record[skuColumn])
+		}
+		var rawQuantity string
+		if quantityColumn < len(record) {
+			rawQuantity = strings.TrimSpace(record[quantityColumn])
+		}
+
+		quantity, err := strconv.Atoi(rawQuantity)
+		switch {
+		case row.Sku == "":
+			row.Status, row.Error = RowInvalid, "empty SKU"
+		case err != nil || quantity < 0:
+			row.Status, row.Error = RowInvalid, fmt.Sprintf("invalid quantity %q", rawQuantity)
+		case firstLine[row.Sku] != 0:
+			row.Status, row.Error = RowInvalid, fmt.Sprintf("duplicated SKU, first on line %d", firstLine[row.Sku])
+		default:
+			row.Quantity = quantity
+			firstLine[row.Sku] = row.Line
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func findColumn(header []string, names []string) int {
+	for i, cell := range header {
+		cell = strings.ToLower(strings.TrimSpace(cell))
+		for _, name := range names {
+			if cell == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func countRows(rows []ImportRow) map[RowStatus]int {
+	counts := make(map[RowStatus]int)
+	for _, row := range rows {
+		counts[row.Status]++
+	}
+	return counts
+}
@@ -0,0 +1,448 @@
+package stock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/allocation"
+	mock_allocation "github.com/Vractos/kloni/usecases/allocation/mock"
+	"github.com/Vractos/kloni/usecases/announcement"
+	mock_announcement "github.com/Vractos/kloni/usecases/announcement/mock"
+	common "github.com/Vractos/kloni/usecases/common"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/stock"
+	mock_stock "github.com/Vractos/kloni/usecases/stock/mock"
+	"github.com/Vractos/kloni/usecases/store"
+	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type Mocks struct {
+	repo     *mock_stock.MockRepository
+	meli     *common_mock.MockMercadoLivre
+	store    *mock_store.MockUseCase
+	announce *mock_announcement.MockUseCase
+	allocate *mock_allocation.MockAllocator
+	logger   *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:     mock_stock.NewMockRepository(ctrl),
+		meli:     common_mock.NewMockMercadoLivre(ctrl),
+		store:    mock_store.NewMockUseCase(ctrl),
+		announce: mock_announcement.NewMockUseCase(ctrl),
+		allocate: mock_allocation.NewMockAllocator(ctrl),
+		logger:   common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newStockService() *stock.StockService {
+	return stock.NewStockService(m.repo, m.meli, m.store, m.announce, m.allocate, 0, m.logger)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestAdjustStock(t *testing.T) {
+	storeId := entity.NewID()
+	mainAccount, secondAccount := entity.NewID(), entity.NewID()
+	credentials := &[]store.Credentials{
+		{ID: mainAccount, MeliCredential: &common.MeliCredential{AccessToken: "main-token"}},
+		{ID: secondAccount, MeliCredential: &common.MeliCredential{AccessToken: "second-token"}},
+	}
+
+	// The second account uses an alias of the SKU, the other variation is another product
+	variated := common.MeliAnnouncement{ID: "MLB3", Sku: "test-sku"}
+	variated.Variations = append(variated.Variations,
+		common.MeliVariation{ID: 10, AvailableQuantity: 1, Sku: "test-sku-alias"},
+		common.MeliVariation{ID: 11, AvailableQuantity: 6, Sku: "test-sku"},
+	)
+	announcements := &[]announcement.Announcements{
+		{
+			AccountID:   mainAccount,
+			AccountName: "Main",
+			Announcements: &[]common.MeliAnnouncement{
+				{ID: "MLB1", Sku: "test-sku", Quantity: 3},
+				{ID: "MLB2", Sku: "test-sku", Quantity: 5},
+			},
+		},
+		{
+			AccountID:     secondAccount,
+			AccountName:   "Second",
+			Sku:           "test-sku-alias",
+			Announcements: &[]common.MeliAnnouncement{variated},
+		},
+	}
+
+	t.Run("absolute quantity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		updateErr := errors.New("meli error")
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(announcements, nil)
+		m.allocate.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 5, Quantity: intPtr(5)}).Return(nil, nil)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 5, (*credentials)[0]).Return(updateErr)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB3", 5, (*credentials)[1], 10).Return(nil)
+
+		adjustment, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{
+			Store:    storeId,
+			Sku:      " test-sku ",
+			Quantity: intPtr(5),
+		})
+		if err != nil {
+			t.Fatalf("AdjustStock() error = %v", err)
+		}
+
+		want := &stock.Adjustment{
+			Sku: "test-sku",
+			Listings: []stock.ListingAdjustment{
+				{AccountID: mainAccount, AccountName: "Main", AnnouncementID: "MLB1", PreviousQuantity: 3, Quantity: 5, Status: stock.ListingFailed, Error: "meli error"},
+				{AccountID: mainAccount, AccountName: "Main", AnnouncementID: "MLB2", PreviousQuantity: 5, Quantity: 5, Status: stock.ListingUnchanged},
+				{AccountID: secondAccount, AccountName: "Second", AnnouncementID: "MLB3", VariationID: 10, PreviousQuantity: 1, Quantity: 5, Status: stock.ListingUpdated},
+			},
+		}
+		if !cmp.Equal(adjustment, want) {
+			t.Errorf("AdjustStock() diff: %v", cmp.Diff(adjustment, want))
+		}
+	})
+
+	t.Run("delta on a variation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(announcements, nil)
+		m.allocate.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 5, Delta: intPtr(-2)}).Return(nil, nil)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB3", 0, (*credentials)[1], 10).Return(nil)
+
+		adjustment, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{
+			Store:       storeId,
+			Sku:         "test-sku",
+			VariationID: 10,
+			Delta:       intPtr(-2),
+		})
+		if err != nil {
+			t.Fatalf("AdjustStock() error = %v", err)
+		}
+		if len(adjustment.Listings) != 1 || adjustment.Listings[0].Quantity != 0 || adjustment.Listings[0].Status != stock.ListingUpdated {
+			t.Errorf("AdjustStock() = %+v", adjustment.Listings)
+		}
+	})
+
+	t.Run("allocation policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		policy, _ := entity.NewAllocationPolicy(storeId, "test-sku", nil, []entity.AccountAllocation{
+			{AccountID: secondAccount, Buffer: 2, Percentage: 50},
+		})
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(announcements, nil)
+		m.allocate.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 5, Delta: intPtr(4)}).
+			Return(&allocation.Allocation{Policy: policy, Stock: 12}, nil)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 12, (*credentials)[0]).Return(nil)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB2", 12, (*credentials)[0]).Return(nil)
+		m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB3", 5, (*credentials)[1], 10).Return(nil)
+
+		adjustment, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: "test-sku", Delta: intPtr(4)})
+		if err != nil {
+			t.Fatalf("AdjustStock() error = %v", err)
+		}
+
+		want := &stock.Adjustment{
+			Sku:   "test-sku",
+			Stock: intPtr(12),
+			Listings: []stock.ListingAdjustment{
+				{AccountID: mainAccount, AccountName: "Main", AnnouncementID: "MLB1", PreviousQuantity: 3, Quantity: 12, Status: stock.ListingUpdated},
+				{AccountID: mainAccount, AccountName: "Main", AnnouncementID: "MLB2", PreviousQuantity: 5, Quantity: 12, Status: stock.ListingUpdated},
+				{AccountID: secondAccount, AccountName: "Second", AnnouncementID: "MLB3", VariationID: 10, PreviousQuantity: 1, Quantity: 5, Status: stock.ListingUpdated},
+			},
+		}
+		if !cmp.Equal(adjustment, want) {
+			t.Errorf("AdjustStock() diff: %v", cmp.Diff(adjustment, want))
+		}
+	})
+
+	t.Run("listing without a variation of the sku", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		unmatched := common.MeliAnnouncement{ID: "MLB4", Sku: "test-sku"}
+		unmatched.Variations = append(unmatched.Variations, common.MeliVariation{ID: 20, AvailableQuantity: 4, Sku: "another-sku"})
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(&[]announcement.Announcements{
+			{AccountID: mainAccount, Announcements: &[]common.MeliAnnouncement{unmatched}},
+		}, nil)
+		m.allocate.EXPECT().Allocate(allocation.AllocateDtoInput{Store: storeId, Sku: "test-sku", Published: 0, Delta: intPtr(1)}).Return(nil, nil)
+		m.logger.EXPECT().Warn("No variation of the listing has the SKU",
+			zap.String("announcement_id", "MLB4"),
+			zap.String("sku", "test-sku"),
+			zap.String("account_sku", ""),
+		)
+
+		_, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: "test-sku", Delta: intPtr(1)})
+		if !errors.Is(err, stock.ErrSkuNotFound) {
+			t.Errorf("AdjustStock() error = %v, want %v", err, stock.ErrSkuNotFound)
+		}
+	})
+
+	t.Run("sku not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("unknown-sku", credentials).Return(&[]announcement.Announcements{
+			{AccountID: mainAccount}, {AccountID: secondAccount},
+		}, nil)
+
+		_, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: "unknown-sku", Delta: intPtr(1)})
+		if !errors.Is(err, stock.ErrSkuNotFound) {
+			t.Errorf("AdjustStock() error = %v, want %v", err, stock.ErrSkuNotFound)
+		}
+	})
+
+	t.Run("announcements can't be retrieved", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		annErr := errors.New("announcement error")
+		m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("test-sku", credentials).Return(nil, annErr)
+		m.logger.EXPECT().Error("Fail to retrieve the announcements of the SKU", annErr, zap.String("sku", "test-sku"))
+
+		if _, err := m.newStockService().AdjustStock(stock.AdjustStockDtoInput{Store: storeId, Sku: "test-sku", Delta: intPtr(1)}); err != annErr {
+			t.Errorf("AdjustStock() error = %v, want %v", err, annErr)
+		}
+	})
+
+	invalid := []struct {
+		name    string
+		input   stock.AdjustStockDtoInput
+		wantErr error
+	}{
+		{"empty sku", stock.AdjustStockDtoInput{Sku: " ", Quantity: intPtr(1)}, stock.ErrInvalidSku},
+		{"neither quantity nor delta", stock.AdjustStockDtoInput{Sku: "test-sku"}, stock.ErrInvalidAdjustment},
+		{"quantity and delta", stock.AdjustStockDtoInput{Sku: "test-sku", Quantity: intPtr(1), Delta: intPtr(1)}, stock.ErrInvalidAdjustment},
+		{"negative quantity", stock.AdjustStockDtoInput{Sku: "test-sku", Quantity: intPtr(-1)}, stock.ErrInvalidAdjustment},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := newMocks(ctrl)
+
+			if _, err := m.newStockService().AdjustStock(tt.input); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AdjustStock() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreviewImport(t *testing.T) {
+	storeId := entity.NewID()
+
+	t.Run("rows parsed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().RegisterImport(gomock.Any()).Return(nil)
+
+		i, err := m.newStockService().PreviewImport(stock.PreviewImportDtoInput{
+			Store:    storeId,
+			FileName: "stock.csv",
+			Records: [][]string{
+				{"Descrição", "Quantidade", "SKU"},
+				{"Filter", "10", "A-1"},
+				{"Oil", "3", "B-2"},
+				{"Filter", "4", "A-1"},
+				{"Belt", "-1", "C-3"},
+				{"Belt", "2"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("PreviewImport() error = %v", err)
+		}
+
+		want := []stock.ImportRow{
+			{Line: 2, Sku: "A-1", Quantity: 10, Status: stock.RowPending},
+			{Line: 3, Sku: "B-2", Quantity: 3, Status: stock.RowPending},
+			{Line: 4, Sku: "A-1", Status: stock.RowInvalid, Error: "duplicated SKU, first on line 2"},
+			{Line: 5, Sku: "C-3", Status: stock.RowInvalid, Error: `invalid quantity "-1"`},
+			{Line: 6, Status: stock.RowInvalid, Error: "empty SKU"},
+		}
+		if !cmp.Equal(i.Rows, want) {
+			t.Errorf("PreviewImport() diff: %v", cmp.Diff(i.Rows, want))
+		}
+		if i.Status != stock.ImportUploaded || i.Counts[stock.RowPending] != 2 || i.Counts[stock.RowInvalid] != 3 {
+			t.Errorf("PreviewImport() = %+v", i)
+		}
+	})
+
+	t.Run("header without quantity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMocks(ctrl)
+
+		_, err := m.newStockService().PreviewImport(stock.PreviewImportDtoInput{
+			Store:   storeId,
+			Records: [][]string{{"sku", "title"}, {"A-1", "Filter"}},
+		})
+		if !errors.Is(err, stock.ErrMissingColumns) {
+			t.Errorf("PreviewImport() error = %v, want %v", err, stock.ErrMissingColumns)
+		}
+	})
+}
+
+func TestConfirmImport(t *testing.T) {
+	storeId, importId := entity.NewID(), entity.NewID()
+	validRows := []stock.ImportRow{{Line: 1, Sku: "A-1", Quantity: 1, Status: stock.RowValid}}
+
+	tests := []struct {
+		name    string
+		stored  *stock.Import
+		wantErr error
+	}{
+		{
+			name:   "confirmed",
+			stored: &stock.Import{ID: importId, Status: stock.ImportPreviewed, Rows: validRows, CreatedAt: time.Now()},
+		},
+		{
+			name:    "not found",
+			wantErr: stock.ErrImportNotFound,
+		},
+		{
+			name:    "still validating",
+			stored:  &stock.Import{ID: importId, Status: stock.ImportValidating, Rows: validRows, CreatedAt: time.Now()},
+			wantErr: stock.ErrImportValidating,
+		},
+		{
+			name:    "already confirmed",
+			stored:  &stock.Import{ID: importId, Status: stock.ImportRunning, Rows: validRows, CreatedAt: time.Now()},
+			wantErr: stock.ErrImportNotPreviewed,
+		},
+		{
+			name:    "expired",
+			stored:  &stock.Import{ID: importId, Status: stock.ImportPreviewed, Rows: validRows, CreatedAt: time.Now().Add(-25 * time.Hour)},
+			wantErr: stock.ErrImportExpired,
+		},
+		{
+			name: "without valid rows",
+			stored: &stock.Import{ID: importId, Status: stock.ImportPreviewed, CreatedAt: time.Now(), Rows: []stock.ImportRow{
+				{Line: 1, Status: stock.RowInvalid},
+			}},
+			wantErr: stock.ErrNothingToImport,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := newMocks(ctrl)
+
+			m.repo.EXPECT().GetImport(storeId, importId).Return(tt.stored, nil)
+			if tt.wantErr == nil {
+				m.repo.EXPECT().ConfirmImport(importId, gomock.Any()).Return(nil)
+			}
+
+			i, err := m.newStockService().ConfirmImport(storeId, importId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConfirmImport() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (i.Status != stock.ImportConfirmed || i.ConfirmedAt == nil) {
+				t.Errorf("ConfirmImport() = %+v", i)
+			}
+		})
+	}
+}
+
+func TestValidateImports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMocks(ctrl)
+
+	storeId, importId := entity.NewID(), entity.NewID()
+	credentials := &[]store.Credentials{
+		{ID: entity.NewID(), MeliCredential: &common.MeliCredential{UserID: "1", AccessToken: "main-token"}},
+		{ID: entity.NewID(), MeliCredential: &common.MeliCredential{UserID: "2", AccessToken: "second-token"}},
+	}
+	claimed := &stock.Import{
+		ID:     importId,
+		Store:  storeId,
+		Status: stock.ImportValidating,
+		Rows: []stock.ImportRow{
+			{Line: 2, Sku: "A-1", Quantity: 10, Status: stock.RowPending},
+			{Line: 3, Sku: "B-2", Quantity: 3, Status: stock.RowPending},
+			{Line: 4, Sku: "C-3", Quantity: 1, Status: stock.RowValid, Listings: 1},
+			{Line: 5, Sku: "D-4", Quantity: 2, Status: stock.RowPending},
+			{Line: 6, Status: stock.RowInvalid, Error: "empty SKU"},
+		},
+	}
+
+	gomock.InOrder(
+		m.repo.EXPECT().ClaimValidation(gomock.Any()).Return(claimed, nil),
+		m.logger.EXPECT().Info("Validating the import", zap.String("import_id", importId.String())),
+		m.repo.EXPECT().UpdateImportRow(importId, &stock.ImportRow{Line: 2, Sku: "A-1", Quantity: 10, Status: stock.RowValid, Listings: 3}).Return(nil),
+		m.repo.EXPECT().UpdateImportRow(importId, &stock.ImportRow{Line: 3, Sku: "B-2", Quantity: 3, Status: stock.RowNotFound, Error: "no listing has the SKU"}).Return(nil),
+		m.repo.EXPECT().UpdateImportRow(importId, &stock.ImportRow{Line: 5, Sku: "D-4", Quantity: 2, Status: stock.RowFailed, Error: "fail to search the listings of the SKU"}).Return(nil),
+		m.repo.EXPECT().FinishValidation(importId).Return(nil),
+		m.repo.EXPECT().ClaimValidation(gomock.Any()).Return(nil, nil),
+	)
+	m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+	m.meli.EXPECT().GetAnnouncementsIDsViaSKU("A-1", "1", "main-token").Return([]string{"MLB1"}, nil)
+	m.meli.EXPECT().GetAnnouncementsIDsViaSKU("A-1", "2", "second-token").Return([]string{"MLB2", "MLB3"}, nil)
+	m.meli.EXPECT().GetAnnouncementsIDsViaSKU("B-2", "1", "main-token").Return(nil, nil)
+	m.meli.EXPECT().GetAnnouncementsIDsViaSKU("B-2", "2", "second-token").Return([]string{}, nil)
+	m.meli.EXPECT().GetAnnouncementsIDsViaSKU("D-4", "1", "main-token").Return(nil, errors.New("meli error"))
+	m.logger.EXPECT().Error("Fail to search the listings of the imported SKU", gomock.Any(), gomock.Any(), gomock.Any())
+
+	if err := m.newStockService().ValidateImports(); err != nil {
+		t.Errorf("ValidateImports() error = %v", err)
+	}
+}
+
+func TestRunImports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMocks(ctrl)
+
+	storeId, importId := entity.NewID(), entity.NewID()
+	credentials := &[]store.Credentials{
+		{ID: entity.NewID(), AccountName: nil, MeliCredential: &common.MeliCredential{AccessToken: "main-token"}},
+	}
+	claimed := &stock.Import{
+		ID:     importId,
+		Store:  storeId,
+		Status: stock.ImportRunning,
+		Rows: []stock.ImportRow{
+			{Line: 2, Sku: "A-1", Quantity: 7, Status: stock.RowValid},
+			{Line: 3, Sku: "B-2", Quantity: 1, Status: stock.RowApplied, Updated: 1},
+			{Line: 4, Sku: "C-3", Quantity: 2, Status: stock.RowValid},
+		},
+	}
+
+	gomock.InOrder(
+		m.repo.EXPECT().ClaimImport(gomock.Any()).Return(claimed, nil),
+		m.logger.EXPECT().Info("Running the import", zap.String("import_id", importId.String())),
+		m.repo.EXPECT().UpdateImportRow(importId, &stock.ImportRow{Line: 2, Sku: "A-1", Quantity: 7, Status: stock.RowApplied, Updated: 2}).Return(nil),
+		m.repo.EXPECT().UpdateImportRow(importId, &stock.ImportRow{Line: 4, Sku: "C-3", Quantity: 2, Status: stock.RowFailed, Error: "meli error", Failed: 1}).Return(nil),
+		m.repo.EXPECT().FinishImport(importId, gomock.Any()).Return(nil),
+		m.repo.EXPECT().ClaimImport(gomock.Any()).Return(nil, nil),
+	)
+	m.store.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil).Times(2)
+	m.allocate.EXPECT().Allocate(gomock.Any()).Return(nil, nil).Times(2)
+	m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("A-1", credentials).Return(&[]announcement.Announcements{
+		{AccountID: (*credentials)[0].ID, Announcements: &[]common.MeliAnnouncement{{ID: "MLB1", Quantity: 3}, {ID: "MLB2", Quantity: 7}}},
+	}, nil)
+	m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB1", 7, (*credentials)[0]).Return(nil)
+	m.announce.EXPECT().RetrieveAnnouncementsFromAllAccounts("C-3", credentials).Return(&[]announcement.Announcements{
+		{AccountID: (*credentials)[0].ID, Announcements: &[]common.MeliAnnouncement{{ID: "MLB3", Quantity: 5}}},
+	}, nil)
+	m.announce.EXPECT().UpdateQuantity(gomock.Any(), "MLB3", 2, (*credentials)[0]).Return(errors.New("meli error"))
+
+	if err := m.newStockService().RunImports(); err != nil {
+		t.Errorf("RunImports() error = %v", err)
+	}
+}
@@ -3,12 +3,25 @@ package store
 import "github.com/Vractos/kloni/entity"
 
 type RegisterStoreDtoInput struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name" validate:"required"`
 }
 
-type RegisterMeliCredentialsDtoInput struct {
-	Code        string    `json:"code"`
-	Store       entity.ID `json:"store_id"`
+type RenameAccountDtoInput struct {
+	Store       entity.ID `json:"-"`
+	Account     entity.ID `json:"-"`
 	AccountName string    `json:"account_name"`
 }
+
+type StartMeliAuthorizationDtoInput struct {
+	Store       entity.ID
+	Account     *entity.ID
+	AccountName string
+	// Site of the seller, defines the authorization domain
+	Site string
+}
+
+type CompleteMeliAuthorizationDtoInput struct {
+	Code  string
+	State string
+}
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"github.com/Vractos/kloni/entity"
 	"github.com/Vractos/kloni/usecases/common"
 )
@@ -12,15 +14,63 @@ type Credentials struct {
 	*common.MeliCredential
 }
 
+// AccountStatus describes the health of a linked Mercado Livre account
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "active"
+	// The access token is older than its lifetime and wasn't refreshed yet
+	AccountExpired AccountStatus = "expired"
+	// The last attempt to refresh the access token failed
+	AccountError        AccountStatus = "error"
+	AccountDisconnected AccountStatus = "disconnected"
+)
+
+// Account is a Mercado Livre account linked to a store, without its tokens
+type Account struct {
+	ID                 entity.ID
+	OwnerID            entity.ID
+	AccountName        *string
+	UserID             string
+	SiteID             string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	LastRefreshError   *string
+	LastRefreshErrorAt *time.Time
+	DisconnectedAt     *time.Time
+	// Filled by the service
+	TokenAge time.Duration
+	Status   AccountStatus
+}
+
+// AuthorizationState is the payload carried by the state of a Mercado Livre authorization
+type AuthorizationState struct {
+	Store entity.ID `json:"store_id"`
+	// Set when an already linked account is re-authorized
+	Account     *entity.ID `json:"account_id,omitempty"`
+	AccountName string     `json:"account_name,omitempty"`
+}
+
 // UseCase interface
 type UseCase interface {
 	RegisterStore(input RegisterStoreDtoInput) (entity.ID, error)
-	RegisterMeliCredentials(input RegisterMeliCredentialsDtoInput) error
 	// Retrieve all the meli credentials from a store
 	RetrieveMeliCredentialsFromStoreID(id entity.ID) (*[]Credentials, error)
 	// Retrieve all meli credentials from a meli user id
 	RetrieveMeliCredentialsFromMeliUserID(id string) (*[]Credentials, error)
 	RefreshMeliCredential(accountId entity.ID, refreshToken string) (*Credentials, error)
+	// Retrieve the accounts linked to a store with their health
+	RetrieveAccounts(storeId entity.ID) (*[]Account, error)
+	RetrieveAccount(storeId, accountId entity.ID) (*Account, error)
+	RenameAccount(input RenameAccountDtoInput) error
+	// Unlink an account from the store. Its orders are kept
+	DisconnectAccount(storeId, accountId entity.ID) error
+	// Returns the URL where the seller authorizes the application, with a signed state bound to the store
+	StartMeliAuthorization(input StartMeliAuthorizationDtoInput) (string, error)
+	// Handles the authorization callback, linking a new account or re-authorizing an existing one
+	CompleteMeliAuthorization(input CompleteMeliAuthorizationDtoInput) (*AuthorizationState, error)
+	// Checks if the meli user is linked to a store, e.g. to verify the notifications
+	IsMeliUserLinked(userId string) (bool, error)
 }
 
 /*
@@ -50,13 +100,29 @@ type RepoReader interface {
 	// 	}
 	//
 	RetrieveMeliCredentialsFromMeliUserID(accountId string) (*[]Credentials, error)
+	// Retrieves the connected accounts of a store
+	RetrieveAccounts(ownerId entity.ID) (*[]Account, error)
+	// Retrieves a connected account of a store, returns ErrAccountNotFound if it doesn't exist
+	RetrieveAccount(ownerId, accountId entity.ID) (*Account, error)
+	// Retrieves the connected account of a meli user, from any store.
+	// Returns ErrAccountNotFound if it isn't linked
+	RetrieveAccountFromMeliUserID(userId string) (*Account, error)
 }
 
 // Repository writer interface
 type RepoWriter interface {
 	Create(e *entity.Store) (entity.ID, error)
+	// Returns ErrAccountAlreadyLinked if the meli user is already linked
 	RegisterMeliCredential(id entity.ID, owner_id entity.ID, c *common.MeliCredential, account_name string) error
+	// Updates the tokens and clears the last refresh error
 	UpdateMeliCredentials(accountId entity.ID, c *common.MeliCredential) error
+	// Records the error of a failed refresh, until the credentials are updated.
+	// Returns true when the account didn't have an error, so it starts failing
+	RegisterRefreshError(accountId entity.ID, refreshErr string) (bool, error)
+	RenameAccount(ownerId, accountId entity.ID, accountName string) error
+	// Marks the account as disconnected and wipes its tokens.
+	// The row is kept, since orders reference it.
+	DisconnectAccount(ownerId, accountId entity.ID) error
 	Update(e *entity.Store) error
 	Delete(id entity.ID) error
 }
@@ -41,33 +41,63 @@ func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
 	return m.recorder
 }
 
-// RefreshMeliCredential mocks base method.
-func (m *MockUseCase) RefreshMeliCredential(accountId entity.ID, refreshToken string) (*store.Credentials, error) {
+// CompleteMeliAuthorization mocks base method.
+func (m *MockUseCase) CompleteMeliAuthorization(input store.CompleteMeliAuthorizationDtoInput) (*store.AuthorizationState, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshMeliCredential", accountId, refreshToken)
-	ret0, _ := ret[0].(*store.Credentials)
+	ret := m.ctrl.Call(m, "CompleteMeliAuthorization", input)
+	ret0, _ := ret[0].(*store.AuthorizationState)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RefreshMeliCredential indicates an expected call of RefreshMeliCredential.
-func (mr *MockUseCaseMockRecorder) RefreshMeliCredential(accountId, refreshToken any) *gomock.Call {
+// CompleteMeliAuthorization indicates an expected call of CompleteMeliAuthorization.
+func (mr *MockUseCaseMockRecorder) CompleteMeliAuthorization(input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshMeliCredential", reflect.TypeOf((*MockUseCase)(nil).RefreshMeliCredential), accountId, refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMeliAuthorization", reflect.TypeOf((*MockUseCase)(nil).CompleteMeliAuthorization), input)
 }
 
-// RegisterMeliCredentials mocks base method.
-func (m *MockUseCase) RegisterMeliCredentials(input store.RegisterMeliCredentialsDtoInput) error {
+// DisconnectAccount mocks base method.
+func (m *MockUseCase) DisconnectAccount(storeId, accountId entity.ID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterMeliCredentials", input)
+	ret := m.ctrl.Call(m, "DisconnectAccount", storeId, accountId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RegisterMeliCredentials indicates an expected call of RegisterMeliCredentials.
-func (mr *MockUseCaseMockRecorder) RegisterMeliCredentials(input any) *gomock.Call {
+// DisconnectAccount indicates an expected call of DisconnectAccount.
+func (mr *MockUseCaseMockRecorder) DisconnectAccount(storeId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectAccount", reflect.TypeOf((*MockUseCase)(nil).DisconnectAccount), storeId, accountId)
+}
+
+// IsMeliUserLinked mocks base method.
+func (m *MockUseCase) IsMeliUserLinked(userId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMeliUserLinked", userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMeliUserLinked indicates an expected call of IsMeliUserLinked.
+func (mr *MockUseCaseMockRecorder) IsMeliUserLinked(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMeliUserLinked", reflect.TypeOf((*MockUseCase)(nil).IsMeliUserLinked), userId)
+}
+
+// RefreshMeliCredential mocks base method.
+func (m *MockUseCase) RefreshMeliCredential(accountId entity.ID, refreshToken string) (*store.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshMeliCredential", accountId, refreshToken)
+	ret0, _ := ret[0].(*store.Credentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshMeliCredential indicates an expected call of RefreshMeliCredential.
+func (mr *MockUseCaseMockRecorder) RefreshMeliCredential(accountId, refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMeliCredentials", reflect.TypeOf((*MockUseCase)(nil).RegisterMeliCredentials), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshMeliCredential", reflect.TypeOf((*MockUseCase)(nil).RefreshMeliCredential), accountId, refreshToken)
 }
 
 // RegisterStore mocks base method.
@@ -85,6 +115,50 @@ func (mr *MockUseCaseMockRecorder) RegisterStore(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterStore", reflect.TypeOf((*MockUseCase)(nil).RegisterStore), input)
 }
 
+// RenameAccount mocks base method.
+func (m *MockUseCase) RenameAccount(input store.RenameAccountDtoInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameAccount", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameAccount indicates an expected call of RenameAccount.
+func (mr *MockUseCaseMockRecorder) RenameAccount(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameAccount", reflect.TypeOf((*MockUseCase)(nil).RenameAccount), input)
+}
+
+// RetrieveAccount mocks base method.
+func (m *MockUseCase) RetrieveAccount(storeId, accountId entity.ID) (*store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccount", storeId, accountId)
+	ret0, _ := ret[0].(*store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccount indicates an expected call of RetrieveAccount.
+func (mr *MockUseCaseMockRecorder) RetrieveAccount(storeId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccount", reflect.TypeOf((*MockUseCase)(nil).RetrieveAccount), storeId, accountId)
+}
+
+// RetrieveAccounts mocks base method.
+func (m *MockUseCase) RetrieveAccounts(storeId entity.ID) (*[]store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccounts", storeId)
+	ret0, _ := ret[0].(*[]store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccounts indicates an expected call of RetrieveAccounts.
+func (mr *MockUseCaseMockRecorder) RetrieveAccounts(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccounts", reflect.TypeOf((*MockUseCase)(nil).RetrieveAccounts), storeId)
+}
+
 // RetrieveMeliCredentialsFromMeliUserID mocks base method.
 func (m *MockUseCase) RetrieveMeliCredentialsFromMeliUserID(id string) (*[]store.Credentials, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +189,21 @@ func (mr *MockUseCaseMockRecorder) RetrieveMeliCredentialsFromStoreID(id any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveMeliCredentialsFromStoreID", reflect.TypeOf((*MockUseCase)(nil).RetrieveMeliCredentialsFromStoreID), id)
 }
 
+// StartMeliAuthorization mocks base method.
+func (m *MockUseCase) StartMeliAuthorization(input store.StartMeliAuthorizationDtoInput) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartMeliAuthorization", input)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartMeliAuthorization indicates an expected call of StartMeliAuthorization.
+func (mr *MockUseCaseMockRecorder) StartMeliAuthorization(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartMeliAuthorization", reflect.TypeOf((*MockUseCase)(nil).StartMeliAuthorization), input)
+}
+
 // MockRepoReader is a mock of RepoReader interface.
 type MockRepoReader struct {
 	ctrl     *gomock.Controller
@@ -153,6 +242,51 @@ func (mr *MockRepoReaderMockRecorder) Get(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepoReader)(nil).Get), id)
 }
 
+// RetrieveAccount mocks base method.
+func (m *MockRepoReader) RetrieveAccount(ownerId, accountId entity.ID) (*store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccount", ownerId, accountId)
+	ret0, _ := ret[0].(*store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccount indicates an expected call of RetrieveAccount.
+func (mr *MockRepoReaderMockRecorder) RetrieveAccount(ownerId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccount", reflect.TypeOf((*MockRepoReader)(nil).RetrieveAccount), ownerId, accountId)
+}
+
+// RetrieveAccountFromMeliUserID mocks base method.
+func (m *MockRepoReader) RetrieveAccountFromMeliUserID(userId string) (*store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccountFromMeliUserID", userId)
+	ret0, _ := ret[0].(*store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccountFromMeliUserID indicates an expected call of RetrieveAccountFromMeliUserID.
+func (mr *MockRepoReaderMockRecorder) RetrieveAccountFromMeliUserID(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccountFromMeliUserID", reflect.TypeOf((*MockRepoReader)(nil).RetrieveAccountFromMeliUserID), userId)
+}
+
+// RetrieveAccounts mocks base method.
+func (m *MockRepoReader) RetrieveAccounts(ownerId entity.ID) (*[]store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccounts", ownerId)
+	ret0, _ := ret[0].(*[]store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccounts indicates an expected call of RetrieveAccounts.
+func (mr *MockRepoReaderMockRecorder) RetrieveAccounts(ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccounts", reflect.TypeOf((*MockRepoReader)(nil).RetrieveAccounts), ownerId)
+}
+
 // RetrieveMeliCredentialsFromMeliUserID mocks base method.
 func (m *MockRepoReader) RetrieveMeliCredentialsFromMeliUserID(accountId string) (*[]store.Credentials, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +369,20 @@ func (mr *MockRepoWriterMockRecorder) Delete(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepoWriter)(nil).Delete), id)
 }
 
+// DisconnectAccount mocks base method.
+func (m *MockRepoWriter) DisconnectAccount(ownerId, accountId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisconnectAccount", ownerId, accountId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisconnectAccount indicates an expected call of DisconnectAccount.
+func (mr *MockRepoWriterMockRecorder) DisconnectAccount(ownerId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectAccount", reflect.TypeOf((*MockRepoWriter)(nil).DisconnectAccount), ownerId, accountId)
+}
+
 // RegisterMeliCredential mocks base method.
 func (m *MockRepoWriter) RegisterMeliCredential(id, owner_id entity.ID, c *common.MeliCredential, account_name string) error {
 	m.ctrl.T.Helper()
@@ -249,6 +397,35 @@ func (mr *MockRepoWriterMockRecorder) RegisterMeliCredential(id, owner_id, c, ac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMeliCredential", reflect.TypeOf((*MockRepoWriter)(nil).RegisterMeliCredential), id, owner_id, c, account_name)
 }
 
+// RegisterRefreshError mocks base method.
+func (m *MockRepoWriter) RegisterRefreshError(accountId entity.ID, refreshErr string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterRefreshError", accountId, refreshErr)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterRefreshError indicates an expected call of RegisterRefreshError.
+func (mr *MockRepoWriterMockRecorder) RegisterRefreshError(accountId, refreshErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRefreshError", reflect.TypeOf((*MockRepoWriter)(nil).RegisterRefreshError), accountId, refreshErr)
+}
+
+// RenameAccount mocks base method.
+func (m *MockRepoWriter) RenameAccount(ownerId, accountId entity.ID, accountName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameAccount", ownerId, accountId, accountName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameAccount indicates an expected call of RenameAccount.
+func (mr *MockRepoWriterMockRecorder) RenameAccount(ownerId, accountId, accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameAccount", reflect.TypeOf((*MockRepoWriter)(nil).RenameAccount), ownerId, accountId, accountName)
+}
+
 // Update mocks base method.
 func (m *MockRepoWriter) Update(e *entity.Store) error {
 	m.ctrl.T.Helper()
@@ -329,6 +506,20 @@ func (mr *MockRepositoryMockRecorder) Delete(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), id)
 }
 
+// DisconnectAccount mocks base method.
+func (m *MockRepository) DisconnectAccount(ownerId, accountId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisconnectAccount", ownerId, accountId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisconnectAccount indicates an expected call of DisconnectAccount.
+func (mr *MockRepositoryMockRecorder) DisconnectAccount(ownerId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectAccount", reflect.TypeOf((*MockRepository)(nil).DisconnectAccount), ownerId, accountId)
+}
+
 // Get mocks base method.
 func (m *MockRepository) Get(id string) (*entity.Store, error) {
 	m.ctrl.T.Helper()
@@ -358,6 +549,80 @@ func (mr *MockRepositoryMockRecorder) RegisterMeliCredential(id, owner_id, c, ac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterMeliCredential", reflect.TypeOf((*MockRepository)(nil).RegisterMeliCredential), id, owner_id, c, account_name)
 }
 
+// RegisterRefreshError mocks base method.
+func (m *MockRepository) RegisterRefreshError(accountId entity.ID, refreshErr string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterRefreshError", accountId, refreshErr)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterRefreshError indicates an expected call of RegisterRefreshError.
+func (mr *MockRepositoryMockRecorder) RegisterRefreshError(accountId, refreshErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRefreshError", reflect.TypeOf((*MockRepository)(nil).RegisterRefreshError), accountId, refreshErr)
+}
+
+// RenameAccount mocks base method.
+func (m *MockRepository) RenameAccount(ownerId, accountId entity.ID, accountName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameAccount", ownerId, accountId, accountName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameAccount indicates an expected call of RenameAccount.
+func (mr *MockRepositoryMockRecorder) RenameAccount(ownerId, accountId, accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameAccount", reflect.TypeOf((*MockRepository)(nil).RenameAccount), ownerId, accountId, accountName)
+}
+
+// RetrieveAccount mocks base method.
+func (m *MockRepository) RetrieveAccount(ownerId, accountId entity.ID) (*store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccount", ownerId, accountId)
+	ret0, _ := ret[0].(*store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccount indicates an expected call of RetrieveAccount.
+func (mr *MockRepositoryMockRecorder) RetrieveAccount(ownerId, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccount", reflect.TypeOf((*MockRepository)(nil).RetrieveAccount), ownerId, accountId)
+}
+
+// RetrieveAccountFromMeliUserID mocks base method.
+func (m *MockRepository) RetrieveAccountFromMeliUserID(userId string) (*store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccountFromMeliUserID", userId)
+	ret0, _ := ret[0].(*store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccountFromMeliUserID indicates an expected call of RetrieveAccountFromMeliUserID.
+func (mr *MockRepositoryMockRecorder) RetrieveAccountFromMeliUserID(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccountFromMeliUserID", reflect.TypeOf((*MockRepository)(nil).RetrieveAccountFromMeliUserID), userId)
+}
+
+// RetrieveAccounts mocks base method.
+func (m *MockRepository) RetrieveAccounts(ownerId entity.ID) (*[]store.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAccounts", ownerId)
+	ret0, _ := ret[0].(*[]store.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveAccounts indicates an expected call of RetrieveAccounts.
+func (mr *MockRepositoryMockRecorder) RetrieveAccounts(ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAccounts", reflect.TypeOf((*MockRepository)(nil).RetrieveAccounts), ownerId)
+}
+
 // RetrieveMeliCredentialsFromMeliUserID mocks base method.
 func (m *MockRepository) RetrieveMeliCredentialsFromMeliUserID(accountId string) (*[]store.Credentials, error) {
 	m.ctrl.T.Helper()
@@ -1,24 +1,52 @@
 package store
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/pkg/metrics"
 	"github.com/Vractos/kloni/usecases/common"
+	"github.com/Vractos/kloni/usecases/webhook"
 	"go.uber.org/zap"
 )
 
+// Error definitions for store-related operations
+var (
+	// ErrAccountNotFound is returned when the account doesn't exist or doesn't belong to the store
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountMismatch is returned when a re-authorization was granted by a different Mercado Livre user
+	ErrAccountMismatch = errors.New("authorization belongs to another mercado livre account")
+	// ErrInvalidAccountName is returned when the account name is empty or too long
+	ErrInvalidAccountName = errors.New("invalid account name")
+	// ErrAccountAlreadyLinked is returned when the Mercado Livre user is already linked to a store
+	ErrAccountAlreadyLinked = errors.New("mercado livre account already linked")
+	// ErrInvalidSite is returned when the Mercado Livre site isn't supported
+	ErrInvalidSite = errors.New("invalid site")
+	// ErrInvalidState is returned when the state of an authorization is invalid or expired
+	ErrInvalidState = errors.New("invalid authorization state")
+)
+
+// Lifetime of a Mercado Livre access token
+const tokenLifetime = 6 * time.Hour
+
 type StoreService struct {
-	repo   Repository
-	meli   common.MercadoLivre
-	logger common.Logger
+	repo    Repository
+	meli    common.MercadoLivre
+	state   common.StateSigner
+	webhook webhook.Publisher
+	logger  common.Logger
 }
 
-func NewStoreService(repository Repository, mercadolivre common.MercadoLivre, logger common.Logger) *StoreService {
+func NewStoreService(repository Repository, mercadolivre common.MercadoLivre, state common.StateSigner, publisher webhook.Publisher, logger common.Logger) *StoreService {
 	return &StoreService{
-		repo:   repository,
-		meli:   mercadolivre,
-		logger: logger,
+		repo:    repository,
+		meli:    mercadolivre,
+		state:   state,
+		webhook: publisher,
+		logger:  logger,
 	}
 }
 
@@ -31,47 +59,159 @@ func (s *StoreService) RegisterStore(input RegisterStoreDtoInput) (entity.ID, er
 	return s.repo.Create(store)
 }
 
-func (s *StoreService) RegisterMeliCredentials(input RegisterMeliCredentialsDtoInput) error {
-	credentials, err := s.meli.RegisterCredential(input.Code)
+// Links a new Mercado Livre account to the store.
+// A Mercado Livre user can only be linked to one store, once.
+func (s *StoreService) linkAccount(storeId entity.ID, accountName string, credentials *common.MeliCredential) (entity.ID, error) {
+	linked, err := s.repo.RetrieveAccountFromMeliUserID(credentials.UserID)
+	if err != nil && !errors.Is(err, ErrAccountNotFound) {
+		s.logger.Error(
+			"Fail to check if the meli user is already linked",
+			err,
+			zap.String("store_id", storeId.String()),
+		)
+		return entity.ID{}, err
+	}
+	if linked != nil {
+		s.logger.Warn(
+			"Meli user is already linked",
+			zap.String("store_id", storeId.String()),
+			zap.String("linked_store_id", linked.OwnerID.String()),
+			zap.String("user_id", credentials.UserID),
+		)
+		return entity.ID{}, ErrAccountAlreadyLinked
+	}
+
+	// The site of the account defines the currency and the categories of its announcements
+	user, err := s.meli.GetUser(credentials.AccessToken)
 	if err != nil {
 		s.logger.Error(
-			"Fail to register meli's credentials",
+			"Fail to retrieve the meli user",
 			err,
-			zap.String("store_id", input.Store.String()),
+			zap.String("store_id", storeId.String()),
+			zap.String("user_id", credentials.UserID),
 		)
-		return err
+		return entity.ID{}, err
 	}
+	credentials.SiteID = user.SiteID
 
 	id := entity.NewID()
 
-	if err := s.repo.RegisterMeliCredential(id, input.Store, credentials, input.AccountName); err != nil {
+	if err := s.repo.RegisterMeliCredential(id, storeId, credentials, accountName); err != nil {
+		if !errors.Is(err, ErrAccountAlreadyLinked) {
+			s.logger.Error(
+				"Fail to store meli's credentials",
+				err,
+				zap.String("store_id", storeId.String()),
+			)
+		}
+		return entity.ID{}, err
+	}
+	return id, nil
+}
+
+func (s *StoreService) IsMeliUserLinked(userId string) (bool, error) {
+	if _, err := s.repo.RetrieveAccountFromMeliUserID(userId); err != nil {
+		if errors.Is(err, ErrAccountNotFound) {
+			return false, nil
+		}
+		s.logger.Error("Fail to retrieve the account of the meli user", err, zap.String("user_id", userId))
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *StoreService) StartMeliAuthorization(input StartMeliAuthorizationDtoInput) (string, error) {
+	if input.Site != "" && !entity.Site(input.Site).IsValid() {
+		return "", ErrInvalidSite
+	}
+
+	if input.Account != nil {
+		// Only the accounts of the store can be re-authorized
+		if _, err := s.RetrieveAccount(input.Store, *input.Account); err != nil {
+			return "", err
+		}
+	}
+
+	payload, err := json.Marshal(AuthorizationState{
+		Store:       input.Store,
+		Account:     input.Account,
+		AccountName: input.AccountName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	state, err := s.state.Sign(payload)
+	if err != nil {
+		s.logger.Error("Fail to sign the authorization state", err, zap.String("store_id", input.Store.String()))
+		return "", err
+	}
+
+	return s.meli.AuthorizationURL(input.Site, state, s.state.CodeVerifier(state)), nil
+}
+
+func (s *StoreService) CompleteMeliAuthorization(input CompleteMeliAuthorizationDtoInput) (*AuthorizationState, error) {
+	payload, err := s.state.Verify(input.State)
+	if err != nil {
+		s.logger.Warn("Invalid authorization state", zap.Error(err))
+		return nil, ErrInvalidState
+	}
+
+	state := &AuthorizationState{}
+	if err := json.Unmarshal(payload, state); err != nil {
+		s.logger.Warn("Invalid authorization state", zap.Error(err))
+		return nil, ErrInvalidState
+	}
+
+	credentials, err := s.meli.RegisterCredential(input.Code, s.state.CodeVerifier(input.State))
+	if err != nil {
 		s.logger.Error(
-			"Fail to store meli's credentials",
+			"Fail to register meli's credentials",
 			err,
-			zap.String("store_id", input.Store.String()),
+			zap.String("store_id", state.Store.String()),
 		)
-		return err
+		return nil, err
 	}
-	return nil
+
+	if state.Account != nil {
+		account, err := s.RetrieveAccount(state.Store, *state.Account)
+		if err != nil {
+			return nil, err
+		}
+		return state, s.reauthorizeAccount(account, credentials)
+	}
+
+	accountId, err := s.linkAccount(state.Store, state.AccountName, credentials)
+	if err != nil {
+		return nil, err
+	}
+	state.Account = &accountId
+
+	s.logger.Info(
+		"Meli account was linked",
+		zap.String("store_id", state.Store.String()),
+		zap.String("account_id", accountId.String()),
+	)
+	return state, nil
 }
 
-// Checks if the credentials are still valid
-func (s *StoreService) validateCredentials(credentials *Credentials) (*Credentials, error) {
+// Checks if the credentials are still valid, the bool is true when a failed refresh made a healthy account fail
+func (s *StoreService) validateCredentials(credentials *Credentials) (*Credentials, bool, error) {
 	timeNowUTC := time.Now().UTC()
 	if timeNowUTC.Sub(credentials.UpdatedAt.UTC()).Hours() >= 5 {
-		credentialsData, err := s.RefreshMeliCredential(credentials.ID, credentials.RefreshToken)
+		credentialsData, failing, err := s.refreshMeliCredential(credentials.ID, credentials.RefreshToken)
 		if err != nil {
 			s.logger.Error(
 				"Fail to refresh meli's credentials",
 				err,
 				zap.String("account_id", credentials.ID.String()),
 			)
-			return nil, err
+			return nil, failing, err
 		}
 
-		return credentialsData, nil
+		return credentialsData, false, nil
 	} else {
-		return credentials, nil
+		return credentials, false, nil
 	}
 }
 
@@ -89,21 +229,28 @@ func (s *StoreService) RetrieveMeliCredentialsFromStoreID(id entity.ID) (*[]Cred
 	// Check if the credentials are still valid, if not, refresh them
 	// Also, format the data to be returned
 	for i, credential := range *credentials {
-		credentialsData, err := s.validateCredentials(&credential)
+		credentialsData, failing, err := s.validateCredentials(&credential)
 		if err != nil {
 			s.logger.Error(
 				"Fail to validate meli's credentials",
 				err,
 				zap.String("account_id", credential.ID.String()),
 			)
+			if failing {
+				s.publishCredentialExpired(id, &credential, err)
+			}
+			// Keep the stored token, the failure is reported in the account's health
+			credentialsData = &credential
 		}
 
 		(*credentials)[i] = Credentials{
 			ID:          credential.ID,
+			OwnerID:     id,
 			AccountName: credential.AccountName,
 			MeliCredential: &common.MeliCredential{
 				AccessToken: credentialsData.AccessToken,
 				UserID:      credentialsData.UserID,
+				SiteID:      credential.SiteID,
 			},
 		}
 	}
@@ -125,13 +272,18 @@ func (s *StoreService) RetrieveMeliCredentialsFromMeliUserID(id string) (*[]Cred
 	// Check if the credentials are still valid, if not, refresh them
 	// Also, format the data to be returned
 	for i, credential := range *credentials {
-		credentialsData, err := s.validateCredentials(&credential)
+		credentialsData, failing, err := s.validateCredentials(&credential)
 		if err != nil {
 			s.logger.Error(
 				"Fail to validate meli's credentials",
 				err,
 				zap.String("account_id", credential.ID.String()),
 			)
+			if failing {
+				s.publishCredentialExpired(credential.OwnerID, &credential, err)
+			}
+			// Keep the stored token, the failure is reported in the account's health
+			credentialsData = &credential
 		}
 
 		(*credentials)[i] = Credentials{
@@ -141,6 +293,7 @@ func (s *StoreService) RetrieveMeliCredentialsFromMeliUserID(id string) (*[]Cred
 			MeliCredential: &common.MeliCredential{
 				AccessToken: credentialsData.AccessToken,
 				UserID:      credentialsData.UserID,
+				SiteID:      credential.SiteID,
 			},
 		}
 	}
@@ -148,15 +301,55 @@ func (s *StoreService) RetrieveMeliCredentialsFromMeliUserID(id string) (*[]Cred
 	return credentials, nil
 }
 
+// Publishes that the credentials of an account couldn't be refreshed, so the store can re-authorize it.
+// It's published once, when the account starts failing, the next failed refreshes aren't published
+// until the credentials are refreshed or re-authorized.
+func (s *StoreService) publishCredentialExpired(storeId entity.ID, credentials *Credentials, refreshErr error) {
+	data := entity.CredentialExpiredData{
+		AccountID: credentials.ID,
+		Error:     refreshErr.Error(),
+	}
+	if credentials.MeliCredential != nil {
+		data.UserID = credentials.UserID
+	}
+	if credentials.AccountName != nil {
+		data.AccountName = *credentials.AccountName
+	}
+
+	event := entity.NewEvent(storeId, entity.CredentialExpired, data)
+	if err := s.webhook.Publish(event); err != nil {
+		s.logger.Warn("Fail to publish the event",
+			zap.String("event_type", string(event.Type)),
+			zap.String("account_id", credentials.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
 func (s *StoreService) RefreshMeliCredential(accountId entity.ID, refreshToken string) (*Credentials, error) {
+	credentials, _, err := s.refreshMeliCredential(accountId, refreshToken)
+	return credentials, err
+}
+
+// Refreshes the credentials of an account, the bool is true when the refresh failed and the account was healthy
+func (s *StoreService) refreshMeliCredential(accountId entity.ID, refreshToken string) (*Credentials, bool, error) {
 	credentials, err := s.meli.RefreshCredentials(refreshToken)
+	metrics.CountTokenRefresh(err)
 	if err != nil {
 		s.logger.Error(
 			"Fail to refresh meli's credentials",
 			err,
 			zap.String("account_id", accountId.String()),
 		)
-		return nil, err
+		failing, regErr := s.repo.RegisterRefreshError(accountId, err.Error())
+		if regErr != nil {
+			s.logger.Error(
+				"Fail to register the refresh error",
+				regErr,
+				zap.String("account_id", accountId.String()),
+			)
+		}
+		return nil, failing, err
 	}
 
 	s.logger.Info("Meli's credentials were updated", zap.String("account_id", accountId.String()))
@@ -167,7 +360,7 @@ func (s *StoreService) RefreshMeliCredential(accountId entity.ID, refreshToken s
 			err,
 			zap.String("account_id", accountId.String()),
 		)
-		return nil, err
+		return nil, false, err
 	}
 
 	return &Credentials{
@@ -176,7 +369,127 @@ func (s *StoreService) RefreshMeliCredential(accountId entity.ID, refreshToken s
 			AccessToken: credentials.AccessToken,
 			UserID:      credentials.UserID,
 		},
-	}, nil
+	}, false, nil
+}
+
+// Fills the token age and the status of an account
+func accountHealth(account *Account, now time.Time) {
+	account.TokenAge = now.Sub(account.UpdatedAt.UTC())
+
+	switch {
+	case account.DisconnectedAt != nil:
+		account.Status = AccountDisconnected
+	case account.LastRefreshError != nil:
+		account.Status = AccountError
+	case account.TokenAge >= tokenLifetime:
+		account.Status = AccountExpired
+	default:
+		account.Status = AccountActive
+	}
+}
+
+func (s *StoreService) RetrieveAccounts(storeId entity.ID) (*[]Account, error) {
+	accounts, err := s.repo.RetrieveAccounts(storeId)
+	if err != nil {
+		s.logger.Error(
+			"Fail to retrieve the accounts",
+			err,
+			zap.String("store_id", storeId.String()),
+		)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i := range *accounts {
+		accountHealth(&(*accounts)[i], now)
+	}
+
+	return accounts, nil
+}
+
+func (s *StoreService) RetrieveAccount(storeId, accountId entity.ID) (*Account, error) {
+	account, err := s.repo.RetrieveAccount(storeId, accountId)
+	if err != nil {
+		if !errors.Is(err, ErrAccountNotFound) {
+			s.logger.Error(
+				"Fail to retrieve the account",
+				err,
+				zap.String("store_id", storeId.String()),
+				zap.String("account_id", accountId.String()),
+			)
+		}
+		return nil, err
+	}
+
+	accountHealth(account, time.Now().UTC())
+	return account, nil
+}
+
+func (s *StoreService) RenameAccount(input RenameAccountDtoInput) error {
+	name := strings.TrimSpace(input.AccountName)
+	if name == "" || len(name) > 80 {
+		return ErrInvalidAccountName
+	}
+
+	if err := s.repo.RenameAccount(input.Store, input.Account, name); err != nil {
+		if !errors.Is(err, ErrAccountNotFound) {
+			s.logger.Error(
+				"Fail to rename the account",
+				err,
+				zap.String("store_id", input.Store.String()),
+				zap.String("account_id", input.Account.String()),
+			)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *StoreService) DisconnectAccount(storeId, accountId entity.ID) error {
+	if err := s.repo.DisconnectAccount(storeId, accountId); err != nil {
+		if !errors.Is(err, ErrAccountNotFound) {
+			s.logger.Error(
+				"Fail to disconnect the account",
+				err,
+				zap.String("store_id", storeId.String()),
+				zap.String("account_id", accountId.String()),
+			)
+		}
+		return err
+	}
+
+	s.logger.Info(
+		"Account was disconnected",
+		zap.String("store_id", storeId.String()),
+		zap.String("account_id", accountId.String()),
+	)
+	return nil
+}
+
+// Replaces the tokens of an account with the ones of a new authorization
+func (s *StoreService) reauthorizeAccount(account *Account, credentials *common.MeliCredential) error {
+	// The new grant must come from the same Mercado Livre user, otherwise
+	// the orders of the account would be mixed with another seller's
+	if credentials.UserID != account.UserID {
+		s.logger.Warn(
+			"Re-authorization granted by another meli user",
+			zap.String("account_id", account.ID.String()),
+			zap.String("user_id", credentials.UserID),
+		)
+		return ErrAccountMismatch
+	}
+
+	if err := s.repo.UpdateMeliCredentials(account.ID, credentials); err != nil {
+		s.logger.Error(
+			"Fail to update meli's credentials",
+			err,
+			zap.String("account_id", account.ID.String()),
+		)
+		return err
+	}
+
+	s.logger.Info("Meli's credentials were re-authorized", zap.String("account_id", account.ID.String()))
+	return nil
 }
 
 // Exported for testing purposes
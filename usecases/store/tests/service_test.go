@@ -1,6 +1,7 @@
 package store
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	common_mock "github.com/Vractos/kloni/usecases/common/mock"
 	"github.com/Vractos/kloni/usecases/store"
 	mock_store "github.com/Vractos/kloni/usecases/store/mock"
+	mock_webhook "github.com/Vractos/kloni/usecases/webhook/mock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
@@ -21,8 +23,10 @@ func TestStoreUseCase(t *testing.T) {
 	mockStoreRepo := mock_store.NewMockRepository(ctrl)
 	mockMercadoLivre := common_mock.NewMockMercadoLivre(ctrl)
 	mockLogger := common_mock.NewMockLogger(ctrl)
+	mockState := common_mock.NewMockStateSigner(ctrl)
+	mockPublisher := mock_webhook.NewMockPublisher(ctrl)
 
-	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockLogger)
+	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockState, mockPublisher, mockLogger)
 
 	t.Run("register store", func(t *testing.T) {
 		storeInput := store.RegisterStoreDtoInput{
@@ -46,24 +50,6 @@ func TestStoreUseCase(t *testing.T) {
 		}
 	})
 
-	t.Run("register meli credentials", func(t *testing.T) {
-		// credentialID -> Can have any value but must be a valid entity.ID
-		credentialID := entity.ID(uuid.New())
-		inputMeliCredentials := store.RegisterMeliCredentialsDtoInput{
-			Store:       entity.ID(uuid.New()),
-			Code:        "test-code",
-			AccountName: "Test Account",
-		}
-
-		mockMercadoLivre.EXPECT().RegisterCredential(inputMeliCredentials.Code).Return(&common.MeliCredential{}, nil)
-		mockStoreRepo.EXPECT().RegisterMeliCredential(gomock.AssignableToTypeOf(credentialID), inputMeliCredentials.Store, gomock.AssignableToTypeOf(&common.MeliCredential{}), inputMeliCredentials.AccountName).Return(nil)
-
-		err := storeService.RegisterMeliCredentials(inputMeliCredentials)
-		if err != nil {
-			t.Errorf("Error: %v", err)
-		}
-	})
-
 	t.Run("retrieve meli credentials from store id - one account", func(t *testing.T) {
 		storeId := entity.ID(uuid.New())
 		accountId := entity.ID(uuid.New())
@@ -87,6 +73,7 @@ func TestStoreUseCase(t *testing.T) {
 		expectedCredentials := &[]store.Credentials{
 			{
 				ID:          accountId,
+				OwnerID:     storeId,
 				AccountName: &accountName,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token",
@@ -145,6 +132,7 @@ func TestStoreUseCase(t *testing.T) {
 		expectedCredentials := &[]store.Credentials{
 			{
 				ID:          accountId1,
+				OwnerID:     storeId,
 				AccountName: &accountName1,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token-1",
@@ -153,6 +141,7 @@ func TestStoreUseCase(t *testing.T) {
 			},
 			{
 				ID:          accountId2,
+				OwnerID:     storeId,
 				AccountName: &accountName2,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token-2",
@@ -202,6 +191,7 @@ func TestStoreUseCase(t *testing.T) {
 		expectedCredentials := &[]store.Credentials{
 			{
 				ID:          accountId,
+				OwnerID:     storeId,
 				AccountName: &accountName,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token-refreshed",
@@ -275,6 +265,7 @@ func TestStoreUseCase(t *testing.T) {
 		expectedCredentials := &[]store.Credentials{
 			{
 				ID:          accountId1,
+				OwnerID:     storeId,
 				AccountName: &accountName1,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token-refreshed-1",
@@ -283,6 +274,7 @@ func TestStoreUseCase(t *testing.T) {
 			},
 			{
 				ID:          accountId2,
+				OwnerID:     storeId,
 				AccountName: &accountName2,
 				MeliCredential: &common.MeliCredential{
 					AccessToken: "test-access-token-refreshed-2",
@@ -309,6 +301,81 @@ func TestStoreUseCase(t *testing.T) {
 		}
 	})
 
+	t.Run("retrieve meli credentials from store id with credentials that can't be refreshed", func(t *testing.T) {
+		storeId := entity.ID(uuid.New())
+		accountId := entity.ID(uuid.New())
+		accountName := "test-account-name"
+		refreshErr := errors.New("invalid_grant")
+
+		credentials := &[]store.Credentials{
+			{
+				ID:          accountId,
+				AccountName: &accountName,
+				MeliCredential: &common.MeliCredential{
+					UserID:       "test-user-id",
+					AccessToken:  "test-access-token",
+					RefreshToken: "test-refresh-token",
+					UpdatedAt:    time.Now().UTC().Add(-8 * time.Hour),
+				},
+			},
+		}
+
+		mockStoreRepo.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mockMercadoLivre.EXPECT().RefreshCredentials("test-refresh-token").Return(nil, refreshErr)
+		mockLogger.EXPECT().Error("Fail to refresh meli's credentials", refreshErr, zap.String("account_id", accountId.String())).Times(2)
+		mockStoreRepo.EXPECT().RegisterRefreshError(accountId, "invalid_grant").Return(true, nil)
+		mockLogger.EXPECT().Error("Fail to validate meli's credentials", refreshErr, zap.String("account_id", accountId.String()))
+		mockPublisher.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event *entity.Event) error {
+			want := entity.CredentialExpiredData{
+				AccountID:   accountId,
+				UserID:      "test-user-id",
+				AccountName: accountName,
+				Error:       "invalid_grant",
+			}
+			if event.StoreID != storeId || event.Type != entity.CredentialExpired || !cmp.Equal(event.Data, want) {
+				t.Errorf("got event %+v, want credential expired of %v", event, want)
+			}
+			return nil
+		})
+
+		cred, err := storeService.RetrieveMeliCredentialsFromStoreID(storeId)
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+		if (*cred)[0].AccessToken != "test-access-token" {
+			t.Errorf("got access token %s, want the stored one", (*cred)[0].AccessToken)
+		}
+	})
+
+	t.Run("retrieve meli credentials of an account that was already failing", func(t *testing.T) {
+		storeId := entity.ID(uuid.New())
+		accountId := entity.ID(uuid.New())
+		refreshErr := errors.New("invalid_grant")
+
+		credentials := &[]store.Credentials{
+			{
+				ID: accountId,
+				MeliCredential: &common.MeliCredential{
+					UserID:       "test-user-id",
+					AccessToken:  "test-access-token",
+					RefreshToken: "test-refresh-token",
+					UpdatedAt:    time.Now().UTC().Add(-8 * time.Hour),
+				},
+			},
+		}
+
+		// The credential expired event was published when the account started failing
+		mockStoreRepo.EXPECT().RetrieveMeliCredentialsFromStoreID(storeId).Return(credentials, nil)
+		mockMercadoLivre.EXPECT().RefreshCredentials("test-refresh-token").Return(nil, refreshErr)
+		mockLogger.EXPECT().Error("Fail to refresh meli's credentials", refreshErr, zap.String("account_id", accountId.String())).Times(2)
+		mockStoreRepo.EXPECT().RegisterRefreshError(accountId, "invalid_grant").Return(false, nil)
+		mockLogger.EXPECT().Error("Fail to validate meli's credentials", refreshErr, zap.String("account_id", accountId.String()))
+
+		if _, err := storeService.RetrieveMeliCredentialsFromStoreID(storeId); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	})
+
 	t.Run("retrieve meli credentials from meli user id - one account", func(t *testing.T) {
 		storeId := entity.ID(uuid.New())
 		accountId := entity.ID(uuid.New())
@@ -620,8 +687,10 @@ func TestValidateCredentials(t *testing.T) {
 	mockStoreRepo := mock_store.NewMockRepository(ctrl)
 	mockMercadoLivre := common_mock.NewMockMercadoLivre(ctrl)
 	mockLogger := common_mock.NewMockLogger(ctrl)
+	mockState := common_mock.NewMockStateSigner(ctrl)
+	mockPublisher := mock_webhook.NewMockPublisher(ctrl)
 
-	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockLogger)
+	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockState, mockPublisher, mockLogger)
 
 	accountId := entity.ID(uuid.New())
 	refreshToken := "test-refresh-token"
@@ -657,7 +726,7 @@ func TestValidateCredentials(t *testing.T) {
 	mockStoreRepo.EXPECT().UpdateMeliCredentials(accountId, refreshedCredentials)
 	mockLogger.EXPECT().Info("Meli's credentials were updated", zap.String("account_id", accountId.String()))
 
-	cred, err := store.ValidateCredentialsTest(storeService, credentials)
+	cred, _, err := store.ValidateCredentialsTest(storeService, credentials)
 	if err != nil {
 		t.Errorf("Error: %v", err)
 	}
@@ -665,3 +734,263 @@ func TestValidateCredentials(t *testing.T) {
 		t.Errorf("Error refreshing and retrieving credentials. diff: %v", cmp.Diff(cred, refreshedCredentials))
 	}
 }
+
+func TestAccountManagement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockStoreRepo := mock_store.NewMockRepository(ctrl)
+	mockMercadoLivre := common_mock.NewMockMercadoLivre(ctrl)
+	mockLogger := common_mock.NewMockLogger(ctrl)
+	mockState := common_mock.NewMockStateSigner(ctrl)
+	mockPublisher := mock_webhook.NewMockPublisher(ctrl)
+
+	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockState, mockPublisher, mockLogger)
+
+	storeId := entity.ID(uuid.New())
+
+	t.Run("retrieve accounts with their health", func(t *testing.T) {
+		refreshErr := "invalid_grant"
+		now := time.Now().UTC()
+		accounts := &[]store.Account{
+			{ID: entity.ID(uuid.New()), UpdatedAt: now.Add(-1 * time.Hour)},
+			{ID: entity.ID(uuid.New()), UpdatedAt: now.Add(-7 * time.Hour)},
+			{ID: entity.ID(uuid.New()), UpdatedAt: now.Add(-7 * time.Hour), LastRefreshError: &refreshErr},
+		}
+
+		mockStoreRepo.EXPECT().RetrieveAccounts(storeId).Return(accounts, nil)
+
+		result, err := storeService.RetrieveAccounts(storeId)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		expected := []store.AccountStatus{store.AccountActive, store.AccountExpired, store.AccountError}
+		for i, account := range *result {
+			if account.Status != expected[i] {
+				t.Errorf("account %d: got status %s, want %s", i, account.Status, expected[i])
+			}
+			if account.TokenAge <= 0 {
+				t.Errorf("account %d: token age wasn't filled", i)
+			}
+		}
+	})
+
+	t.Run("rename account", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+
+		mockStoreRepo.EXPECT().RenameAccount(storeId, accountId, "New name").Return(nil)
+
+		err := storeService.RenameAccount(store.RenameAccountDtoInput{
+			Store:       storeId,
+			Account:     accountId,
+			AccountName: "  New name ",
+		})
+		if err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	})
+
+	t.Run("rename account with an empty name", func(t *testing.T) {
+		err := storeService.RenameAccount(store.RenameAccountDtoInput{
+			Store:       storeId,
+			Account:     entity.ID(uuid.New()),
+			AccountName: " ",
+		})
+		if err != store.ErrInvalidAccountName {
+			t.Errorf("got %v, want %v", err, store.ErrInvalidAccountName)
+		}
+	})
+
+	t.Run("disconnect account", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+
+		mockStoreRepo.EXPECT().DisconnectAccount(storeId, accountId).Return(nil)
+		mockLogger.EXPECT().Info(
+			"Account was disconnected",
+			zap.String("store_id", storeId.String()),
+			zap.String("account_id", accountId.String()),
+		)
+
+		if err := storeService.DisconnectAccount(storeId, accountId); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	})
+
+	t.Run("disconnect account from another store", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+
+		mockStoreRepo.EXPECT().DisconnectAccount(storeId, accountId).Return(store.ErrAccountNotFound)
+
+		if err := storeService.DisconnectAccount(storeId, accountId); err != store.ErrAccountNotFound {
+			t.Errorf("got %v, want %v", err, store.ErrAccountNotFound)
+		}
+	})
+
+	t.Run("register the error of a failed refresh", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+		refreshErr := errors.New("invalid_grant")
+
+		mockMercadoLivre.EXPECT().RefreshCredentials("test-refresh-token").Return(nil, refreshErr)
+		mockLogger.EXPECT().Error("Fail to refresh meli's credentials", refreshErr, zap.String("account_id", accountId.String()))
+		mockStoreRepo.EXPECT().RegisterRefreshError(accountId, "invalid_grant").Return(true, nil)
+
+		if _, err := storeService.RefreshMeliCredential(accountId, "test-refresh-token"); err != refreshErr {
+			t.Errorf("got %v, want %v", err, refreshErr)
+		}
+	})
+}
+
+func TestMeliAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockStoreRepo := mock_store.NewMockRepository(ctrl)
+	mockMercadoLivre := common_mock.NewMockMercadoLivre(ctrl)
+	mockLogger := common_mock.NewMockLogger(ctrl)
+	mockState := common_mock.NewMockStateSigner(ctrl)
+	mockPublisher := mock_webhook.NewMockPublisher(ctrl)
+
+	storeService := store.NewStoreService(mockStoreRepo, mockMercadoLivre, mockState, mockPublisher, mockLogger)
+
+	storeId := entity.ID(uuid.New())
+
+	t.Run("meli user linked", func(t *testing.T) {
+		mockStoreRepo.EXPECT().RetrieveAccountFromMeliUserID("linked-user-id").Return(&store.Account{UserID: "linked-user-id"}, nil)
+		mockStoreRepo.EXPECT().RetrieveAccountFromMeliUserID("unknown-user-id").Return(nil, store.ErrAccountNotFound)
+
+		if linked, err := storeService.IsMeliUserLinked("linked-user-id"); err != nil || !linked {
+			t.Errorf("got %v, %v, want true", linked, err)
+		}
+		if linked, err := storeService.IsMeliUserLinked("unknown-user-id"); err != nil || linked {
+			t.Errorf("got %v, %v, want false", linked, err)
+		}
+	})
+
+	t.Run("start authorization", func(t *testing.T) {
+		payload := []byte(`{"store_id":"` + storeId.String() + `","account_name":"Main"}`)
+
+		mockState.EXPECT().Sign(payload).Return("signed-state", nil)
+		mockState.EXPECT().CodeVerifier("signed-state").Return("code-verifier")
+		mockMercadoLivre.EXPECT().AuthorizationURL("MLA", "signed-state", "code-verifier").Return("https://auth.test/authorization")
+
+		url, err := storeService.StartMeliAuthorization(store.StartMeliAuthorizationDtoInput{
+			Store:       storeId,
+			AccountName: "Main",
+			Site:        "MLA",
+		})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if url != "https://auth.test/authorization" {
+			t.Errorf("got url %s", url)
+		}
+	})
+
+	t.Run("start authorization in an unsupported site", func(t *testing.T) {
+		_, err := storeService.StartMeliAuthorization(store.StartMeliAuthorizationDtoInput{
+			Store: storeId,
+			Site:  "XXX",
+		})
+		if err != store.ErrInvalidSite {
+			t.Errorf("got %v, want %v", err, store.ErrInvalidSite)
+		}
+	})
+
+	t.Run("start re-authorization of an account from another store", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+
+		mockStoreRepo.EXPECT().RetrieveAccount(storeId, accountId).Return(nil, store.ErrAccountNotFound)
+
+		_, err := storeService.StartMeliAuthorization(store.StartMeliAuthorizationDtoInput{
+			Store:   storeId,
+			Account: &accountId,
+		})
+		if err != store.ErrAccountNotFound {
+			t.Errorf("got %v, want %v", err, store.ErrAccountNotFound)
+		}
+	})
+
+	t.Run("complete authorization of a new account", func(t *testing.T) {
+		credentials := &common.MeliCredential{AccessToken: "access-token", UserID: "test-user-id"}
+
+		mockState.EXPECT().Verify("signed-state").Return([]byte(`{"store_id":"`+storeId.String()+`","account_name":"Main"}`), nil)
+		mockState.EXPECT().CodeVerifier("signed-state").Return("code-verifier")
+		mockMercadoLivre.EXPECT().RegisterCredential("test-code", "code-verifier").Return(credentials, nil)
+		mockStoreRepo.EXPECT().RetrieveAccountFromMeliUserID("test-user-id").Return(nil, store.ErrAccountNotFound)
+		mockMercadoLivre.EXPECT().GetUser("access-token").Return(&common.MeliUser{ID: "test-user-id", SiteID: "MLA"}, nil)
+		mockStoreRepo.EXPECT().RegisterMeliCredential(gomock.Any(), storeId, credentials, "Main").Return(nil)
+		mockLogger.EXPECT().Info("Meli account was linked", gomock.Any(), gomock.Any())
+
+		state, err := storeService.CompleteMeliAuthorization(store.CompleteMeliAuthorizationDtoInput{
+			Code:  "test-code",
+			State: "signed-state",
+		})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if state.Store != storeId || state.Account == nil {
+			t.Errorf("unexpected state %+v", state)
+		}
+		if credentials.SiteID != "MLA" {
+			t.Errorf("got site %s, want MLA", credentials.SiteID)
+		}
+	})
+
+	t.Run("complete authorization with an invalid state", func(t *testing.T) {
+		mockState.EXPECT().Verify("forged-state").Return(nil, errors.New("invalid state"))
+		mockLogger.EXPECT().Warn("Invalid authorization state", gomock.Any())
+
+		_, err := storeService.CompleteMeliAuthorization(store.CompleteMeliAuthorizationDtoInput{
+			Code:  "test-code",
+			State: "forged-state",
+		})
+		if err != store.ErrInvalidState {
+			t.Errorf("got %v, want %v", err, store.ErrInvalidState)
+		}
+	})
+
+	t.Run("complete authorization of a meli user linked to another store", func(t *testing.T) {
+		credentials := &common.MeliCredential{AccessToken: "access-token", UserID: "test-user-id"}
+
+		mockState.EXPECT().Verify("signed-state").Return([]byte(`{"store_id":"`+storeId.String()+`"}`), nil)
+		mockState.EXPECT().CodeVerifier("signed-state").Return("code-verifier")
+		mockMercadoLivre.EXPECT().RegisterCredential("test-code", "code-verifier").Return(credentials, nil)
+		mockStoreRepo.EXPECT().RetrieveAccountFromMeliUserID("test-user-id").Return(&store.Account{
+			ID:      entity.ID(uuid.New()),
+			OwnerID: entity.ID(uuid.New()),
+			UserID:  "test-user-id",
+		}, nil)
+		mockLogger.EXPECT().Warn("Meli user is already linked", gomock.Any(), gomock.Any(), gomock.Any())
+
+		_, err := storeService.CompleteMeliAuthorization(store.CompleteMeliAuthorizationDtoInput{
+			Code:  "test-code",
+			State: "signed-state",
+		})
+		if err != store.ErrAccountAlreadyLinked {
+			t.Errorf("got %v, want %v", err, store.ErrAccountAlreadyLinked)
+		}
+	})
+
+	t.Run("complete re-authorization with another meli user", func(t *testing.T) {
+		accountId := entity.ID(uuid.New())
+		credentials := &common.MeliCredential{AccessToken: "access-token", UserID: "another-user-id"}
+
+		mockState.EXPECT().Verify("signed-state").Return([]byte(`{"store_id":"`+storeId.String()+`","account_id":"`+accountId.String()+`"}`), nil)
+		mockState.EXPECT().CodeVerifier("signed-state").Return("code-verifier")
+		mockMercadoLivre.EXPECT().RegisterCredential("test-code", "code-verifier").Return(credentials, nil)
+		mockStoreRepo.EXPECT().RetrieveAccount(storeId, accountId).Return(&store.Account{
+			ID:        accountId,
+			OwnerID:   storeId,
+			UserID:    "test-user-id",
+			UpdatedAt: time.Now().UTC(),
+		}, nil)
+		mockLogger.EXPECT().Warn("Re-authorization granted by another meli user", gomock.Any(), gomock.Any())
+
+		_, err := storeService.CompleteMeliAuthorization(store.CompleteMeliAuthorizationDtoInput{
+			Code:  "test-code",
+			State: "signed-state",
+		})
+		if err != store.ErrAccountMismatch {
+			t.Errorf("got %v, want %v", err, store.ErrAccountMismatch)
+		}
+	})
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "github.com/Vractos/kloni/entity"
+
+type RegisterSubscriptionDtoInput struct {
+	Store  entity.ID          `json:"-"`
+	URL    string             `json:"url" validate:"required,url"`
+	Events []entity.EventType `json:"events" validate:"required,min=1"`
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+)
+
+// Publisher is used by the other use cases to publish the events of the stores
+type Publisher interface {
+	// Publish schedules the delivery of an event to the subscriptions of its store.
+	//
+	// Parameters:
+	//   - event: The event to be delivered
+	//
+	// Returns:
+	//   - error: Error if the deliveries can't be scheduled
+	Publish(event *entity.Event) error
+}
+
+type UseCase interface {
+	Publisher
+	// RegisterSubscription subscribes a URL to events of a store.
+	// The secret used to sign the payloads is only returned here.
+	//
+	// Parameters:
+	//   - input: RegisterSubscriptionDtoInput containing the URL and the events
+	//
+	// Returns:
+	//   - *Subscription: The subscription with its secret
+	//   - error: ErrInvalidURL or ErrInvalidEvents if the input is invalid
+	RegisterSubscription(input RegisterSubscriptionDtoInput) (*Subscription, error)
+	// ListSubscriptions lists the subscriptions of a store, without their secrets.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//
+	// Returns:
+	//   - []Subscription: The subscriptions, the most recent first
+	//   - error: Error if the subscriptions can't be retrieved
+	ListSubscriptions(storeId entity.ID) ([]Subscription, error)
+	// DisableSubscription stops the deliveries to a subscription, including the pending ones.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - subscriptionId: ID of the subscription
+	//
+	// Returns:
+	//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+	DisableSubscription(storeId, subscriptionId entity.ID) error
+	// TestSubscription sends a ping event to a subscription right away, without retries.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - subscriptionId: ID of the subscription
+	//
+	// Returns:
+	//   - *Delivery: The delivery of the ping, with the response of the subscription
+	//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+	TestSubscription(storeId, subscriptionId entity.ID) (*Delivery, error)
+	// ListDeliveries lists the latest deliveries to a subscription.
+	//
+	// Parameters:
+	//   - storeId: ID of the store
+	//   - subscriptionId: ID of the subscription
+	//
+	// Returns:
+	//   - []Delivery: The deliveries, the most recent first
+	//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+	ListDeliveries(storeId, subscriptionId entity.ID) ([]Delivery, error)
+	// DeliverPending sends the deliveries that are due, failures are retried with an exponential backoff.
+	//
+	// Returns:
+	//   - error: Error if the deliveries can't be retrieved
+	DeliverPending() error
+}
+
+type Subscription struct {
+	ID    entity.ID
+	Store entity.ID
+	URL   string
+	// Used to sign the payloads
+	Secret    string
+	Events    []entity.EventType
+	Active    bool
+	CreatedAt time.Time
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the sending of an event to a subscription
+type Delivery struct {
+	ID             entity.ID
+	SubscriptionID entity.ID
+	EventID        entity.ID
+	EventType      entity.EventType
+	// Body of the requests
+	Payload  []byte
+	Status   DeliveryStatus
+	Attempts int
+	// Zero when the subscription didn't respond
+	LastStatusCode int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DueDelivery is a delivery that is due with the subscription it's sent to
+type DueDelivery struct {
+	Delivery
+	URL    string
+	Secret string
+}
+
+/*
+#########################################
+#########################################
+-----------------SENDER------------------
+#########################################
+#########################################
+*/
+
+// Sender sends the payloads to the subscriptions
+type Sender interface {
+	// Returns the status code of the response, the error is only about the request
+	Send(url string, headers map[string]string, body []byte) (int, error)
+}
+
+/*
+#########################################
+#########################################
+---------------REPOSITORY---------------
+#########################################
+#########################################
+*/
+
+type RepoWriter interface {
+	RegisterSubscription(subscription *Subscription) error
+	// Also fails the pending deliveries of the subscription.
+	// Returns ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+	DisableSubscription(storeId, subscriptionId entity.ID) error
+	RegisterDeliveries(deliveries []Delivery) error
+	UpdateDelivery(delivery *Delivery) error
+}
+
+type RepoReader interface {
+	ListSubscriptions(storeId entity.ID) ([]Subscription, error)
+	// Nil when the subscription doesn't exist or belongs to another store
+	GetSubscription(storeId, subscriptionId entity.ID) (*Subscription, error)
+	// Active subscriptions of the store to the event
+	SubscribedTo(storeId entity.ID, eventType entity.EventType) ([]Subscription, error)
+	// Claims the pending deliveries that are due, the claimed ones aren't due again until the lease ends
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]DueDelivery, error)
+	ListDeliveries(subscriptionId entity.ID, limit int) ([]Delivery, error)
+}
+
+type Repository interface {
+	RepoWriter
+	RepoReader
+}
@@ -0,0 +1,550 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecases/webhook/interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecases/webhook/interface.go -destination=usecases/webhook/mock/service_mock.go
+//
+
+// Package mock_webhook is a generated GoMock package.
+package mock_webhook
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/Vractos/kloni/entity"
+	webhook "github.com/Vractos/kloni/usecases/webhook"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(event *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), event)
+}
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// DeliverPending mocks base method.
+func (m *MockUseCase) DeliverPending() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverPending")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverPending indicates an expected call of DeliverPending.
+func (mr *MockUseCaseMockRecorder) DeliverPending() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverPending", reflect.TypeOf((*MockUseCase)(nil).DeliverPending))
+}
+
+// DisableSubscription mocks base method.
+func (m *MockUseCase) DisableSubscription(storeId, subscriptionId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableSubscription indicates an expected call of DisableSubscription.
+func (mr *MockUseCaseMockRecorder) DisableSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableSubscription", reflect.TypeOf((*MockUseCase)(nil).DisableSubscription), storeId, subscriptionId)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockUseCase) ListDeliveries(storeId, subscriptionId entity.ID) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", storeId, subscriptionId)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockUseCaseMockRecorder) ListDeliveries(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockUseCase)(nil).ListDeliveries), storeId, subscriptionId)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockUseCase) ListSubscriptions(storeId entity.ID) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", storeId)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockUseCaseMockRecorder) ListSubscriptions(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockUseCase)(nil).ListSubscriptions), storeId)
+}
+
+// Publish mocks base method.
+func (m *MockUseCase) Publish(event *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockUseCaseMockRecorder) Publish(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockUseCase)(nil).Publish), event)
+}
+
+// RegisterSubscription mocks base method.
+func (m *MockUseCase) RegisterSubscription(input webhook.RegisterSubscriptionDtoInput) (*webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterSubscription", input)
+	ret0, _ := ret[0].(*webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterSubscription indicates an expected call of RegisterSubscription.
+func (mr *MockUseCaseMockRecorder) RegisterSubscription(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSubscription", reflect.TypeOf((*MockUseCase)(nil).RegisterSubscription), input)
+}
+
+// TestSubscription mocks base method.
+func (m *MockUseCase) TestSubscription(storeId, subscriptionId entity.ID) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestSubscription indicates an expected call of TestSubscription.
+func (mr *MockUseCaseMockRecorder) TestSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestSubscription", reflect.TypeOf((*MockUseCase)(nil).TestSubscription), storeId, subscriptionId)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(url string, headers map[string]string, body []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", url, headers, body)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(url, headers, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), url, headers, body)
+}
+
+// MockRepoWriter is a mock of RepoWriter interface.
+type MockRepoWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWriterMockRecorder
+}
+
+// MockRepoWriterMockRecorder is the mock recorder for MockRepoWriter.
+type MockRepoWriterMockRecorder struct {
+	mock *MockRepoWriter
+}
+
+// NewMockRepoWriter creates a new mock instance.
+func NewMockRepoWriter(ctrl *gomock.Controller) *MockRepoWriter {
+	mock := &MockRepoWriter{ctrl: ctrl}
+	mock.recorder = &MockRepoWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoWriter) EXPECT() *MockRepoWriterMockRecorder {
+	return m.recorder
+}
+
+// DisableSubscription mocks base method.
+func (m *MockRepoWriter) DisableSubscription(storeId, subscriptionId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableSubscription indicates an expected call of DisableSubscription.
+func (mr *MockRepoWriterMockRecorder) DisableSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableSubscription", reflect.TypeOf((*MockRepoWriter)(nil).DisableSubscription), storeId, subscriptionId)
+}
+
+// RegisterDeliveries mocks base method.
+func (m *MockRepoWriter) RegisterDeliveries(deliveries []webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterDeliveries", deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterDeliveries indicates an expected call of RegisterDeliveries.
+func (mr *MockRepoWriterMockRecorder) RegisterDeliveries(deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDeliveries", reflect.TypeOf((*MockRepoWriter)(nil).RegisterDeliveries), deliveries)
+}
+
+// RegisterSubscription mocks base method.
+func (m *MockRepoWriter) RegisterSubscription(subscription *webhook.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterSubscription", subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterSubscription indicates an expected call of RegisterSubscription.
+func (mr *MockRepoWriterMockRecorder) RegisterSubscription(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSubscription", reflect.TypeOf((*MockRepoWriter)(nil).RegisterSubscription), subscription)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockRepoWriter) UpdateDelivery(delivery *webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockRepoWriterMockRecorder) UpdateDelivery(delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockRepoWriter)(nil).UpdateDelivery), delivery)
+}
+
+// MockRepoReader is a mock of RepoReader interface.
+type MockRepoReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoReaderMockRecorder
+}
+
+// MockRepoReaderMockRecorder is the mock recorder for MockRepoReader.
+type MockRepoReaderMockRecorder struct {
+	mock *MockRepoReader
+}
+
+// NewMockRepoReader creates a new mock instance.
+func NewMockRepoReader(ctrl *gomock.Controller) *MockRepoReader {
+	mock := &MockRepoReader{ctrl: ctrl}
+	mock.recorder = &MockRepoReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepoReader) EXPECT() *MockRepoReaderMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockRepoReader) ClaimDueDeliveries(limit int, lease time.Duration) ([]webhook.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", limit, lease)
+	ret0, _ := ret[0].([]webhook.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockRepoReaderMockRecorder) ClaimDueDeliveries(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockRepoReader)(nil).ClaimDueDeliveries), limit, lease)
+}
+
+// GetSubscription mocks base method.
+func (m *MockRepoReader) GetSubscription(storeId, subscriptionId entity.ID) (*webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(*webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockRepoReaderMockRecorder) GetSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockRepoReader)(nil).GetSubscription), storeId, subscriptionId)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockRepoReader) ListDeliveries(subscriptionId entity.ID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", subscriptionId, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockRepoReaderMockRecorder) ListDeliveries(subscriptionId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockRepoReader)(nil).ListDeliveries), subscriptionId, limit)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockRepoReader) ListSubscriptions(storeId entity.ID) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", storeId)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockRepoReaderMockRecorder) ListSubscriptions(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockRepoReader)(nil).ListSubscriptions), storeId)
+}
+
+// SubscribedTo mocks base method.
+func (m *MockRepoReader) SubscribedTo(storeId entity.ID, eventType entity.EventType) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribedTo", storeId, eventType)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribedTo indicates an expected call of SubscribedTo.
+func (mr *MockRepoReaderMockRecorder) SubscribedTo(storeId, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribedTo", reflect.TypeOf((*MockRepoReader)(nil).SubscribedTo), storeId, eventType)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]webhook.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", limit, lease)
+	ret0, _ := ret[0].([]webhook.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimDueDeliveries(limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimDueDeliveries), limit, lease)
+}
+
+// DisableSubscription mocks base method.
+func (m *MockRepository) DisableSubscription(storeId, subscriptionId entity.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableSubscription indicates an expected call of DisableSubscription.
+func (mr *MockRepositoryMockRecorder) DisableSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableSubscription", reflect.TypeOf((*MockRepository)(nil).DisableSubscription), storeId, subscriptionId)
+}
+
+// GetSubscription mocks base method.
+func (m *MockRepository) GetSubscription(storeId, subscriptionId entity.ID) (*webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", storeId, subscriptionId)
+	ret0, _ := ret[0].(*webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockRepositoryMockRecorder) GetSubscription(storeId, subscriptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockRepository)(nil).GetSubscription), storeId, subscriptionId)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockRepository) ListDeliveries(subscriptionId entity.ID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", subscriptionId, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockRepositoryMockRecorder) ListDeliveries(subscriptionId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockRepository)(nil).ListDeliveries), subscriptionId, limit)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockRepository) ListSubscriptions(storeId entity.ID) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", storeId)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockRepositoryMockRecorder) ListSubscriptions(storeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockRepository)(nil).ListSubscriptions), storeId)
+}
+
+// RegisterDeliveries mocks base method.
+func (m *MockRepository) RegisterDeliveries(deliveries []webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterDeliveries", deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterDeliveries indicates an expected call of RegisterDeliveries.
+func (mr *MockRepositoryMockRecorder) RegisterDeliveries(deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDeliveries", reflect.TypeOf((*MockRepository)(nil).RegisterDeliveries), deliveries)
+}
+
+// RegisterSubscription mocks base method.
+func (m *MockRepository) RegisterSubscription(subscription *webhook.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterSubscription", subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterSubscription indicates an expected call of RegisterSubscription.
+func (mr *MockRepositoryMockRecorder) RegisterSubscription(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSubscription", reflect.TypeOf((*MockRepository)(nil).RegisterSubscription), subscription)
+}
+
+// SubscribedTo mocks base method.
+func (m *MockRepository) SubscribedTo(storeId entity.ID, eventType entity.EventType) ([]webhook.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribedTo", storeId, eventType)
+	ret0, _ := ret[0].([]webhook.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribedTo indicates an expected call of SubscribedTo.
+func (mr *MockRepositoryMockRecorder) SubscribedTo(storeId, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribedTo", reflect.TypeOf((*MockRepository)(nil).SubscribedTo), storeId, eventType)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockRepository) UpdateDelivery(delivery *webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockRepositoryMockRecorder) UpdateDelivery(delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockRepository)(nil).UpdateDelivery), delivery)
+}
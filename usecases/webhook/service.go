@@ -0,0 +1,407 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	"github.com/Vractos/kloni/usecases/common"
+	"go.uber.org/zap"
+)
+
+// Error definitions for webhook operations
+var (
+	// ErrInvalidURL is returned when the URL of a subscription isn't an absolute https URL
+	// resolving to public addresses
+	ErrInvalidURL = errors.New("invalid url")
+	// ErrInvalidEvents is returned when a subscription has no events or an unknown one
+	ErrInvalidEvents = errors.New("invalid events")
+	// ErrSubscriptionNotFound is returned when the subscription doesn't exist or belongs to another store
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+const (
+	// maxAttempts is the number of attempts before a delivery fails
+	maxAttempts = 8
+	// firstRetryDelay is the delay of the first retry, it doubles on each attempt
+	firstRetryDelay = 30 * time.Second
+	// maxRetryDelay is the longest delay between two attempts
+	maxRetryDelay = 6 * time.Hour
+	// deliveriesBatch is the number of deliveries claimed at once. A request takes up to 10 seconds,
+	// so a batch is sent well within its lease
+	deliveriesBatch = 5
+	// deliveryLease is how long a claimed delivery isn't claimed again
+	deliveryLease = 2 * time.Minute
+	// deliveriesLogSize is the number of deliveries returned by ListDeliveries
+	deliveriesLogSize = 50
+)
+
+// payload is the body of the requests sent to the subscriptions
+type payload struct {
+	ID         entity.ID        `json:"id"`
+	Type       entity.EventType `json:"type"`
+	StoreID    entity.ID        `json:"store_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       any              `json:"data"`
+}
+
+type WebhookService struct {
+	repo   Repository
+	sender Sender
+	guard  common.URLGuard
+	logger common.Logger
+}
+
+// NewWebhookService creates a new instance of WebhookService.
+//
+// Parameters:
+//   - repository: Repository of the subscriptions and the deliveries
+//   - sender: Sender of the payloads
+//   - guard: Guard of the URLs of the subscriptions
+//   - logger: Logger for error and info messages
+//
+// Returns:
+//   - *WebhookService: A new instance of WebhookService
+func NewWebhookService(repository Repository, sender Sender, guard common.URLGuard, logger common.Logger) *WebhookService {
+	return &WebhookService{
+		repo:   repository,
+		sender: sender,
+		guard:  guard,
+		logger: logger,
+	}
+}
+
+// RegisterSubscription subscribes a URL to events of a store.
+//
+// Parameters:
+//   - input: RegisterSubscriptionDtoInput containing the URL and the events
+//
+// Returns:
+//   - *Subscription: The subscription with its secret
+//   - error: ErrInvalidURL or ErrInvalidEvents if the input is invalid
+func (w *WebhookService) RegisterSubscription(input RegisterSubscriptionDtoInput) (*Subscription, error) {
+	rawUrl := strings.TrimSpace(input.URL)
+	if err := w.guard.Check(rawUrl); err != nil {
+		w.logger.Warn("The URL of the subscription was refused", zap.String("store_id", input.Store.String()), zap.Error(err))
+		return nil, ErrInvalidURL
+	}
+
+	if len(input.Events) == 0 {
+		return nil, ErrInvalidEvents
+	}
+	events := []entity.EventType{}
+	seen := make(map[entity.EventType]bool)
+	for _, event := range input.Events {
+		if !event.IsValid() {
+			return nil, ErrInvalidEvents
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		w.logger.Error("Fail to generate the secret of the subscription", err)
+		return nil, err
+	}
+
+	subscription := &Subscription{
+		ID:        entity.NewID(),
+		Store:     input.Store,
+		URL:       rawUrl,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := w.repo.RegisterSubscription(subscription); err != nil {
+		w.logger.Error("Fail to register the subscription", err, zap.String("store_id", input.Store.String()))
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// ListSubscriptions lists the subscriptions of a store, without their secrets.
+//
+// Parameters:
+//   - storeId: ID of the store
+//
+// Returns:
+//   - []Subscription: The subscriptions, the most recent first
+//   - error: Error if the subscriptions can't be retrieved
+func (w *WebhookService) ListSubscriptions(storeId entity.ID) ([]Subscription, error) {
+	subscriptions, err := w.repo.ListSubscriptions(storeId)
+	if err != nil {
+		w.logger.Error("Fail to list the subscriptions", err, zap.String("store_id", storeId.String()))
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// DisableSubscription stops the deliveries to a subscription, including the pending ones.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - subscriptionId: ID of the subscription
+//
+// Returns:
+//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+func (w *WebhookService) DisableSubscription(storeId, subscriptionId entity.ID) error {
+	if err := w.repo.DisableSubscription(storeId, subscriptionId); err != nil {
+		if !errors.Is(err, ErrSubscriptionNotFound) {
+			w.logger.Error("Fail to disable the subscription", err, zap.String("subscription_id", subscriptionId.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// TestSubscription sends a ping event to a subscription right away, without retries.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - subscriptionId: ID of the subscription
+//
+// Returns:
+//   - *Delivery: The delivery of the ping, with the response of the subscription
+//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+func (w *WebhookService) TestSubscription(storeId, subscriptionId entity.ID) (*Delivery, error) {
+	subscription, err := w.subscription(storeId, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	event := entity.NewEvent(storeId, entity.Ping, map[string]string{"subscription_id": subscriptionId.String()})
+	body, err := marshalEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := newDelivery(subscription.ID, event, body)
+	w.attempt(&DueDelivery{Delivery: *delivery, URL: subscription.URL, Secret: subscription.Secret}, delivery)
+	// A ping isn't retried
+	if delivery.Status == DeliveryPending {
+		delivery.Status = DeliveryFailed
+	}
+
+	if err := w.repo.RegisterDeliveries([]Delivery{*delivery}); err != nil {
+		w.logger.Error("Fail to register the test delivery", err, zap.String("subscription_id", subscriptionId.String()))
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries lists the latest deliveries to a subscription.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - subscriptionId: ID of the subscription
+//
+// Returns:
+//   - []Delivery: The deliveries, the most recent first
+//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+func (w *WebhookService) ListDeliveries(storeId, subscriptionId entity.ID) ([]Delivery, error) {
+	if _, err := w.subscription(storeId, subscriptionId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := w.repo.ListDeliveries(subscriptionId, deliveriesLogSize)
+	if err != nil {
+		w.logger.Error("Fail to list the deliveries", err, zap.String("subscription_id", subscriptionId.String()))
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Publish schedules the delivery of an event to the subscriptions of its store.
+//
+// Parameters:
+//   - event: The event to be delivered
+//
+// Returns:
+//   - error: Error if the deliveries can't be scheduled
+func (w *WebhookService) Publish(event *entity.Event) error {
+	subscriptions, err := w.repo.SubscribedTo(event.StoreID, event.Type)
+	if err != nil {
+		w.logger.Error("Fail to retrieve the subscriptions of the event", err,
+			zap.String("store_id", event.StoreID.String()),
+			zap.String("event_type", string(event.Type)),
+		)
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	body, err := marshalEvent(event)
+	if err != nil {
+		w.logger.Error("Fail to marshal the event", err, zap.String("event_type", string(event.Type)))
+		return err
+	}
+
+	deliveries := make([]Delivery, len(subscriptions))
+	for i, subscription := range subscriptions {
+		deliveries[i] = *newDelivery(subscription.ID, event, body)
+	}
+
+	if err := w.repo.RegisterDeliveries(deliveries); err != nil {
+		w.logger.Error("Fail to register the deliveries of the event", err,
+			zap.String("store_id", event.StoreID.String()),
+			zap.String("event_type", string(event.Type)),
+		)
+		return err
+	}
+	return nil
+}
+
+// DeliverPending sends the deliveries that are due, failures are retried with an exponential backoff.
+// The deliveries are claimed in small batches until none is due, so each batch is sent before its lease ends.
+//
+// Returns:
+//   - error: Error if the deliveries can't be retrieved
+func (w *WebhookService) DeliverPending() error {
+	for {
+		due, err := w.repo.ClaimDueDeliveries(deliveriesBatch, deliveryLease)
+		if err != nil {
+			w.logger.Error("Fail to claim the due deliveries", err)
+			return err
+		}
+
+		for i := range due {
+			delivery := due[i].Delivery
+			w.attempt(&due[i], &delivery)
+			if err := w.repo.UpdateDelivery(&delivery); err != nil {
+				w.logger.Error("Fail to update the delivery", err, zap.String("delivery_id", delivery.ID.String()))
+			}
+		}
+		if len(due) < deliveriesBatch {
+			return nil
+		}
+	}
+}
+
+// attempt sends a delivery once and records the result on it.
+//
+// Parameters:
+//   - due: The delivery with the URL and the secret of its subscription
+//   - delivery: The delivery that receives the result
+func (w *WebhookService) attempt(due *DueDelivery, delivery *Delivery) {
+	now := time.Now().UTC()
+	headers := map[string]string{
+		"Content-Type":     "application/json",
+		"X-Kloni-Event":    string(delivery.EventType),
+		"X-Kloni-Delivery": delivery.ID.String(),
+		SignatureHeader:    Sign(due.Secret, now, delivery.Payload),
+	}
+
+	statusCode, err := w.sender.Send(due.URL, headers, delivery.Payload)
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	if err == nil && statusCode >= 200 && statusCode <= 299 {
+		delivery.Status = DeliverySucceeded
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else {
+		delivery.LastError = "unexpected status code"
+	}
+
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = DeliveryFailed
+		w.logger.Warn("Webhook delivery failed",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.String("subscription_id", delivery.SubscriptionID.String()),
+			zap.Int("attempts", delivery.Attempts),
+		)
+		return
+	}
+	delivery.Status = DeliveryPending
+	delivery.NextAttemptAt = now.Add(RetryDelay(delivery.Attempts))
+}
+
+// subscription retrieves a subscription of a store.
+//
+// Parameters:
+//   - storeId: ID of the store
+//   - subscriptionId: ID of the subscription
+//
+// Returns:
+//   - *Subscription: The subscription
+//   - error: ErrSubscriptionNotFound if the subscription doesn't exist or belongs to another store
+func (w *WebhookService) subscription(storeId, subscriptionId entity.ID) (*Subscription, error) {
+	subscription, err := w.repo.GetSubscription(storeId, subscriptionId)
+	if err != nil {
+		w.logger.Error("Fail to retrieve the subscription", err, zap.String("subscription_id", subscriptionId.String()))
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// RetryDelay is the delay before the next attempt of a delivery.
+//
+// Parameters:
+//   - attempts: Number of attempts already made
+//
+// Returns:
+//   - time.Duration: The delay, doubled on each attempt up to maxRetryDelay
+func RetryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+func newDelivery(subscriptionId entity.ID, event *entity.Event, body []byte) *Delivery {
+	now := time.Now().UTC()
+	return &Delivery{
+		ID:             entity.NewID(),
+		SubscriptionID: subscriptionId,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        body,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+func marshalEvent(event *entity.Event) ([]byte, error) {
+	return json.Marshal(payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		StoreID:    event.StoreID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+}
+
+// newSecret generates the secret of a subscription
+func newSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(key), nil
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries the signature of the payloads
+const SignatureHeader = "X-Kloni-Signature"
+
+// Sign signs a payload with the secret of a subscription.
+// The signature is the HMAC-SHA256 of "<unix timestamp>.<body>", so the
+// subscribers can reject old payloads that are replayed.
+//
+// Parameters:
+//   - secret: Secret of the subscription
+//   - timestamp: When the payload is sent
+//   - body: The payload
+//
+// Returns:
+//   - string: The value of the signature header, in the format t=<unix timestamp>,v1=<hex signature>
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,383 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vractos/kloni/entity"
+	common_mock "github.com/Vractos/kloni/usecases/common/mock"
+	"github.com/Vractos/kloni/usecases/webhook"
+	mock_webhook "github.com/Vractos/kloni/usecases/webhook/mock"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type Mocks struct {
+	repo   *mock_webhook.MockRepository
+	sender *mock_webhook.MockSender
+	guard  *common_mock.MockURLGuard
+	logger *common_mock.MockLogger
+}
+
+func newMocks(ctrl *gomock.Controller) *Mocks {
+	return &Mocks{
+		repo:   mock_webhook.NewMockRepository(ctrl),
+		sender: mock_webhook.NewMockSender(ctrl),
+		guard:  common_mock.NewMockURLGuard(ctrl),
+		logger: common_mock.NewMockLogger(ctrl),
+	}
+}
+
+func (m *Mocks) newWebhookService() *webhook.WebhookService {
+	return webhook.NewWebhookService(m.repo, m.sender, m.guard, m.logger)
+}
+
+func TestRegisterSubscription(t *testing.T) {
+	storeId := entity.NewID()
+	refused := errors.New("the url must resolve to a public address")
+
+	tests := []struct {
+		name     string
+		input    webhook.RegisterSubscriptionDtoInput
+		guardErr error
+		events   []entity.EventType
+		wantErr  error
+	}{
+		{
+			name: "valid subscription",
+			input: webhook.RegisterSubscriptionDtoInput{
+				Store:  storeId,
+				URL:    " https://erp.example.com/kloni ",
+				Events: []entity.EventType{entity.OrderProcessed, entity.SyncFailed, entity.OrderProcessed},
+			},
+			events: []entity.EventType{entity.OrderProcessed, entity.SyncFailed},
+		},
+		{
+			name: "invalid url",
+			input: webhook.RegisterSubscriptionDtoInput{
+				Store:  storeId,
+				URL:    "http://erp.example.com",
+				Events: []entity.EventType{entity.OrderProcessed},
+			},
+			guardErr: refused,
+			wantErr:  webhook.ErrInvalidURL,
+		},
+		{
+			name: "internal url",
+			input: webhook.RegisterSubscriptionDtoInput{
+				Store:  storeId,
+				URL:    "https://169.254.169.254/latest/meta-data",
+				Events: []entity.EventType{entity.OrderProcessed},
+			},
+			guardErr: refused,
+			wantErr:  webhook.ErrInvalidURL,
+		},
+		{
+			name: "no events",
+			input: webhook.RegisterSubscriptionDtoInput{
+				Store: storeId,
+				URL:   "https://erp.example.com/kloni",
+			},
+			wantErr: webhook.ErrInvalidEvents,
+		},
+		{
+			name: "unknown event",
+			input: webhook.RegisterSubscriptionDtoInput{
+				Store:  storeId,
+				URL:    "https://erp.example.com/kloni",
+				Events: []entity.EventType{entity.Ping},
+			},
+			wantErr: webhook.ErrInvalidEvents,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMocks(ctrl)
+
+			m.guard.EXPECT().Check(strings.TrimSpace(tt.input.URL)).Return(tt.guardErr)
+			if tt.guardErr != nil {
+				m.logger.EXPECT().Warn("The URL of the subscription was refused", zap.String("store_id", storeId.String()), zap.Error(tt.guardErr))
+			}
+			if tt.wantErr == nil {
+				m.repo.EXPECT().RegisterSubscription(gomock.Any()).Return(nil)
+			}
+
+			subscription, err := m.newWebhookService().RegisterSubscription(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RegisterSubscription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if subscription.URL != "https://erp.example.com/kloni" || !subscription.Active {
+				t.Errorf("RegisterSubscription() = %+v", subscription)
+			}
+			if !strings.HasPrefix(subscription.Secret, "whsec_") {
+				t.Errorf("RegisterSubscription() secret = %q", subscription.Secret)
+			}
+			if len(subscription.Events) != len(tt.events) {
+				t.Fatalf("RegisterSubscription() events = %v, want %v", subscription.Events, tt.events)
+			}
+			for i := range tt.events {
+				if subscription.Events[i] != tt.events[i] {
+					t.Errorf("RegisterSubscription() events = %v, want %v", subscription.Events, tt.events)
+				}
+			}
+		})
+	}
+}
+
+func TestListSubscriptionsHidesSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMocks(ctrl)
+
+	storeId := entity.NewID()
+	m.repo.EXPECT().ListSubscriptions(storeId).Return([]webhook.Subscription{
+		{ID: entity.NewID(), Store: storeId, Secret: "whsec_secret"},
+	}, nil)
+
+	subscriptions, err := m.newWebhookService().ListSubscriptions(storeId)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	if subscriptions[0].Secret != "" {
+		t.Errorf("ListSubscriptions() secret = %q, want empty", subscriptions[0].Secret)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	storeId := entity.NewID()
+	event := entity.NewEvent(storeId, entity.OrderProcessed, map[string]string{"order_id": "2000003508419013"})
+
+	t.Run("schedules a delivery per subscription", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := newMocks(ctrl)
+
+		subscriptions := []webhook.Subscription{{ID: entity.NewID()}, {ID: entity.NewID()}}
+		m.repo.EXPECT().SubscribedTo(storeId, entity.OrderProcessed).Return(subscriptions, nil)
+		m.repo.EXPECT().RegisterDeliveries(gomock.Any()).DoAndReturn(func(deliveries []webhook.Delivery) error {
+			if len(deliveries) != 2 {
+				t.Fatalf("RegisterDeliveries() got %d deliveries, want 2", len(deliveries))
+			}
+			for i, delivery := range deliveries {
+				if delivery.SubscriptionID != subscriptions[i].ID || delivery.Status != webhook.DeliveryPending {
+					t.Errorf("delivery = %+v", delivery)
+				}
+				var body map[string]any
+				if err := json.Unmarshal(delivery.Payload, &body); err != nil {
+					t.Fatalf("payload isn't JSON: %v", err)
+				}
+				if body["type"] != string(entity.OrderProcessed) || body["id"] != event.ID.String() {
+					t.Errorf("payload = %s", delivery.Payload)
+				}
+			}
+			return nil
+		})
+
+		if err := m.newWebhookService().Publish(event); err != nil {
+			t.Errorf("Publish() error = %v", err)
+		}
+	})
+
+	t.Run("no subscriptions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().SubscribedTo(storeId, entity.OrderProcessed).Return(nil, nil)
+
+		if err := m.newWebhookService().Publish(event); err != nil {
+			t.Errorf("Publish() error = %v", err)
+		}
+	})
+}
+
+func TestDeliverPending(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		statusCode int
+		sendErr    error
+		wantStatus webhook.DeliveryStatus
+		wantWarn   bool
+	}{
+		{
+			name:       "delivered",
+			statusCode: 204,
+			wantStatus: webhook.DeliverySucceeded,
+		},
+		{
+			name:       "retried after an error status",
+			statusCode: 500,
+			wantStatus: webhook.DeliveryPending,
+		},
+		{
+			name:       "retried after a request error",
+			sendErr:    errors.New("connection refused"),
+			wantStatus: webhook.DeliveryPending,
+		},
+		{
+			name:       "failed after the last attempt",
+			attempts:   7,
+			statusCode: 502,
+			wantStatus: webhook.DeliveryFailed,
+			wantWarn:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := newMocks(ctrl)
+
+			due := webhook.DueDelivery{
+				Delivery: webhook.Delivery{
+					ID:             entity.NewID(),
+					SubscriptionID: entity.NewID(),
+					EventType:      entity.QuantitySynced,
+					Payload:        []byte(`{"type":"quantity.synced"}`),
+					Status:         webhook.DeliveryPending,
+					Attempts:       tt.attempts,
+				},
+				URL:    "https://erp.example.com/kloni",
+				Secret: "whsec_secret",
+			}
+
+			m.repo.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any()).Return([]webhook.DueDelivery{due}, nil)
+			m.sender.EXPECT().Send(due.URL, gomock.Any(), due.Payload).DoAndReturn(
+				func(url string, headers map[string]string, body []byte) (int, error) {
+					signature := headers[webhook.SignatureHeader]
+					unix, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+					if err != nil || signature != webhook.Sign(due.Secret, time.Unix(unix, 0), body) {
+						t.Fatalf("signature header = %q", signature)
+					}
+					if headers["X-Kloni-Event"] != string(entity.QuantitySynced) {
+						t.Errorf("event header = %q", headers["X-Kloni-Event"])
+					}
+					return tt.statusCode, tt.sendErr
+				})
+			if tt.wantWarn {
+				m.logger.EXPECT().Warn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			}
+			m.repo.EXPECT().UpdateDelivery(gomock.Any()).DoAndReturn(func(delivery *webhook.Delivery) error {
+				if delivery.Status != tt.wantStatus || delivery.Attempts != tt.attempts+1 {
+					t.Errorf("delivery status = %s, attempts = %d", delivery.Status, delivery.Attempts)
+				}
+				if tt.wantStatus == webhook.DeliveryPending && !delivery.NextAttemptAt.After(time.Now()) {
+					t.Errorf("next attempt = %v, want in the future", delivery.NextAttemptAt)
+				}
+				if tt.wantStatus == webhook.DeliverySucceeded && delivery.DeliveredAt == nil {
+					t.Errorf("delivered at isn't set")
+				}
+				return nil
+			})
+
+			if err := m.newWebhookService().DeliverPending(); err != nil {
+				t.Errorf("DeliverPending() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDeliverPendingInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newMocks(ctrl)
+
+	batch := func(size int) []webhook.DueDelivery {
+		due := make([]webhook.DueDelivery, size)
+		for i := range due {
+			due[i] = webhook.DueDelivery{
+				Delivery: webhook.Delivery{ID: entity.NewID(), EventType: entity.QuantitySynced, Status: webhook.DeliveryPending},
+				URL:      "https://erp.example.com/kloni",
+				Secret:   "whsec_secret",
+			}
+		}
+		return due
+	}
+
+	// A full batch is sent before the next one is claimed, so each one is sent within its lease
+	gomock.InOrder(
+		m.repo.EXPECT().ClaimDueDeliveries(5, 2*time.Minute).Return(batch(5), nil),
+		m.repo.EXPECT().ClaimDueDeliveries(5, 2*time.Minute).Return(batch(2), nil),
+	)
+	m.sender.EXPECT().Send("https://erp.example.com/kloni", gomock.Any(), gomock.Any()).Return(204, nil).Times(7)
+	m.repo.EXPECT().UpdateDelivery(gomock.Any()).Return(nil).Times(7)
+
+	if err := m.newWebhookService().DeliverPending(); err != nil {
+		t.Errorf("DeliverPending() error = %v", err)
+	}
+}
+
+func TestTestSubscription(t *testing.T) {
+	storeId := entity.NewID()
+	subscriptionId := entity.NewID()
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetSubscription(storeId, subscriptionId).Return(nil, nil)
+
+		if _, err := m.newWebhookService().TestSubscription(storeId, subscriptionId); !errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			t.Errorf("TestSubscription() error = %v, want %v", err, webhook.ErrSubscriptionNotFound)
+		}
+	})
+
+	t.Run("ping isn't retried", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := newMocks(ctrl)
+
+		m.repo.EXPECT().GetSubscription(storeId, subscriptionId).Return(&webhook.Subscription{
+			ID: subscriptionId, Store: storeId, URL: "https://erp.example.com/kloni", Secret: "whsec_secret", Active: true,
+		}, nil)
+		m.sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(500, nil)
+		m.repo.EXPECT().RegisterDeliveries(gomock.Len(1)).Return(nil)
+
+		delivery, err := m.newWebhookService().TestSubscription(storeId, subscriptionId)
+		if err != nil {
+			t.Fatalf("TestSubscription() error = %v", err)
+		}
+		if delivery.Status != webhook.DeliveryFailed || delivery.LastStatusCode != 500 || delivery.EventType != entity.Ping {
+			t.Errorf("TestSubscription() = %+v", delivery)
+		}
+	})
+}
+
+func TestSign(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+	// HMAC-SHA256 of "1700000000.{}" with the key "secret"
+	want := "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := webhook.Sign("secret", timestamp, []byte("{}")); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhook.RetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}